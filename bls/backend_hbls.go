@@ -0,0 +1,16 @@
+//go:build bignum_hbls
+// +build bignum_hbls
+
+package bls
+
+// CurveBackend is the name of the library providing the curve operations, selected with build tags.
+// The backend cannot be switched at runtime, since it determines the representation of the field elements
+// and points: to pick an implementation per machine, register several in kzg4844 and use SelectFastestBackend.
+const CurveBackend = "herumi"
+
+// AcceleratedArithmetic reports if the field arithmetic is known to use CPU-specific code.
+// The Herumi (mcl) library selects its code for the CPU internally, which its Go bindings do not expose,
+// so this always reports false.
+func AcceleratedArithmetic() bool {
+	return false
+}
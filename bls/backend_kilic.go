@@ -0,0 +1,19 @@
+//go:build !bignum_hol256 && !bignum_hbls
+// +build !bignum_hol256,!bignum_hbls
+
+package bls
+
+import "golang.org/x/sys/cpu"
+
+// CurveBackend is the name of the library providing the curve operations, selected with build tags.
+// The backend cannot be switched at runtime, since it determines the representation of the field elements
+// and points: to pick an implementation per machine, register several in kzg4844 and use SelectFastestBackend.
+const CurveBackend = "kilic"
+
+// AcceleratedArithmetic reports if the field arithmetic runs the ADX/BMI2 assembly of the Kilic library.
+// This package does no CPU dispatch of its own: the library selects that assembly at startup on the same CPU
+// flags, and falls back to generic x86-64 assembly otherwise. Other architectures, ARM64 included,
+// use the pure Go fallback, and report false.
+func AcceleratedArithmetic() bool {
+	return cpu.X86.HasADX && cpu.X86.HasBMI2
+}
@@ -2,8 +2,12 @@ package bls
 
 import (
 	"encoding/binary"
+	"math/bits"
 )
 
+// Fr modulus as little-endian 64 bit limbs
+var modulusLimbs = [4]uint64{0xffffffff00000001, 0x53bda402fffe5bfe, 0x3339d80809a1d805, 0x73eda753299d7d48}
+
 func (fr *Fr) String() string {
 	return FrStr(fr)
 }
@@ -33,3 +37,96 @@ func ValidFr(val [32]byte) bool {
 	}
 	return binary.LittleEndian.Uint64(val[0:8]) <= 0xffffffff00000000
 }
+
+// Checks if a *big endian* uint256 is within the Fr modulus
+func ValidFrBE(val [32]byte) bool {
+	if val[0] == 0 {
+		return true
+	}
+	if a := binary.BigEndian.Uint64(val[0:8]); a > 0x73eda753299d7d48 {
+		return false
+	} else if a < 0x73eda753299d7d48 {
+		return true
+	}
+	if b := binary.BigEndian.Uint64(val[8:16]); b > 0x3339d80809a1d805 {
+		return false
+	} else if b < 0x3339d80809a1d805 {
+		return true
+	}
+	if c := binary.BigEndian.Uint64(val[16:24]); c > 0x53bda402fffe5bfe {
+		return false
+	} else if c < 0x53bda402fffe5bfe {
+		return true
+	}
+	return binary.BigEndian.Uint64(val[24:32]) <= 0xffffffff00000000
+}
+
+// FrFrom32Mod sets dst to the *little endian* uint256 v reduced modulo the Fr modulus.
+// Since 2**256 < 3 * modulus, at most two subtractions of the modulus are needed.
+func FrFrom32Mod(dst *Fr, v [32]byte) {
+	var limbs [4]uint64
+	for i := range limbs {
+		limbs[i] = binary.LittleEndian.Uint64(v[i*8:])
+	}
+	for j := 0; j < 2; j++ {
+		var diff [4]uint64
+		var borrow uint64
+		for i := range limbs {
+			diff[i], borrow = bits.Sub64(limbs[i], modulusLimbs[i], borrow)
+		}
+		if borrow != 0 {
+			break
+		}
+		limbs = diff
+	}
+	for i := range limbs {
+		binary.LittleEndian.PutUint64(v[i*8:], limbs[i])
+	}
+	FrFrom32(dst, v)
+}
+
+// FrFrom32ModBE sets dst to the *big endian* uint256 v reduced modulo the Fr modulus.
+func FrFrom32ModBE(dst *Fr, v [32]byte) {
+	for i := 0; i < 16; i++ {
+		v[i], v[31-i] = v[31-i], v[i]
+	}
+	FrFrom32Mod(dst, v)
+}
+
+// BatchInvModFr sets dst[i] to the inverse of src[i], with a single field inversion (Montgomery's trick).
+// Zero has no inverse, and is mapped to zero. dst and src must have the same length, and may be the same slice.
+// Otherwise they must not overlap: dst then doubles as the scratch space, and nothing is allocated.
+func BatchInvModFr(dst, src []Fr) {
+	if len(dst) != len(src) {
+		panic("BatchInvModFr: dst and src length mismatch")
+	}
+	if len(src) == 0 {
+		return
+	}
+	// prefixes[i] is the product of the non-zero inputs before i
+	prefixes := dst
+	if &dst[0] == &src[0] {
+		prefixes = make([]Fr, len(src))
+	}
+	var acc Fr
+	CopyFr(&acc, &ONE)
+	for i := range src {
+		CopyFr(&prefixes[i], &acc)
+		if !EqualZero(&src[i]) {
+			MulModFr(&acc, &acc, &src[i])
+		}
+	}
+	var inv Fr
+	InvModFr(&inv, &acc)
+	// inv is the inverse of the product of the non-zero inputs up to i, peeled off one input at a time
+	for i := len(src) - 1; i >= 0; i-- {
+		if EqualZero(&src[i]) {
+			CopyFr(&dst[i], &ZERO)
+			continue
+		}
+		var out Fr
+		MulModFr(&out, &inv, &prefixes[i])
+		MulModFr(&inv, &inv, &src[i])
+		CopyFr(&dst[i], &out)
+	}
+}
@@ -52,6 +52,24 @@ func FrTo32(src *Fr) (v [32]byte) {
 	return
 }
 
+// FrFrom32BE mutates the fr num. The value v is big-endian 32-bytes.
+// Returns false, without modifying dst, if the value is out of range.
+func FrFrom32BE(dst *Fr, v [32]byte) (ok bool) {
+	if !ValidFrBE(v) {
+		return false
+	}
+	if err := (*hbls.Fr)(dst).Deserialize(v[:]); err != nil {
+		return false
+	}
+	return true
+}
+
+// FrTo32BE serializes a fr number to 32 bytes. Encoded big-endian.
+func FrTo32BE(src *Fr) (v [32]byte) {
+	copy(v[:], (*hbls.Fr)(src).Serialize())
+	return
+}
+
 func CopyFr(dst *Fr, v *Fr) {
 	*dst = *v
 }
@@ -105,14 +123,6 @@ func InvModFr(dst *Fr, v *Fr) {
 	hbls.FrInv((*hbls.Fr)(dst), (*hbls.Fr)(v))
 }
 
-// BatchInvModFr computes the inverse for each input.
-// Warning: this does not actually batch, this is just here for compatibility with other BLS backends that do.
-func BatchInvModFr(f []Fr) {
-	for i := 0; i < len(f); i++ {
-		hbls.FrInv((*hbls.Fr)(&f[i]), (*hbls.Fr)(&f[i]))
-	}
-}
-
 //func SqrModFr(dst *Fr, v *Fr) {
 //	hbls.FrSqr((*hbls.Fr)(dst), (*hbls.Fr)(v))
 //}
@@ -45,16 +45,29 @@ func FrFrom32(dst *Fr, v [32]byte) (ok bool) {
 
 // FrTo32 serializes a fr number to 32 bytes. Encoded little-endian.
 func FrTo32(src *Fr) (v [32]byte) {
-	b := (*u256.Int)(src).Bytes()
-	last := len(b) - 1
+	v = (*u256.Int)(src).Bytes32()
 	// reverse endianness, u256.Int outputs big-endian bytes
 	for i := 0; i < 16; i++ {
-		b[i], b[last-i] = b[last-i], b[i]
+		v[i], v[31-i] = v[31-i], v[i]
 	}
-	copy(v[:], b)
 	return
 }
 
+// FrFrom32BE mutates the fr num. The value v is big-endian 32-bytes.
+// Returns false, without modifying dst, if the value is out of range.
+func FrFrom32BE(dst *Fr, v [32]byte) (ok bool) {
+	if !ValidFrBE(v) {
+		return false
+	}
+	(*u256.Int)(dst).SetBytes(v[:])
+	return true
+}
+
+// FrTo32BE serializes a fr number to 32 bytes. Encoded big-endian.
+func FrTo32BE(src *Fr) (v [32]byte) {
+	return (*u256.Int)(src).Bytes32()
+}
+
 func CopyFr(dst *Fr, v *Fr) {
 	*dst = *v
 }
@@ -122,14 +135,6 @@ func InvModFr(dst *Fr, v *Fr) {
 	(*u256.Int)(dst).SetFromBig(&tmp)
 }
 
-// BatchInvModFr computes the inverse for each input.
-// Warning: this does not actually batch, this is just here for compatibility with other BLS backends that do.
-func BatchInvModFr(f []Fr) {
-	for i := 0; i < len(f); i++ {
-		InvModFr(&f[i], &f[i])
-	}
-}
-
 //func SqrModFr(dst *Fr, v *Fr) {
 //
 //}
@@ -7,7 +7,6 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"math/big"
-	"unsafe"
 
 	kbls "github.com/kilic/bls12-381"
 )
@@ -54,6 +53,29 @@ func FrTo32(src *Fr) (v [32]byte) {
 	return
 }
 
+// FrFrom32BE mutates the fr num. The value v is big-endian 32-bytes.
+// Returns false, without modifying dst, if the value is out of range.
+func FrFrom32BE(dst *Fr, v [32]byte) (ok bool) {
+	if !ValidFrBE(v) {
+		return false
+	}
+	(*kbls.Fr)(dst).RedFromBytes(v[:])
+	return true
+}
+
+// FrTo32BE serializes a fr number to 32 bytes. Encoded big-endian.
+func FrTo32BE(src *Fr) (v [32]byte) {
+	copy(v[:], (*kbls.Fr)(src).RedToBytes())
+	return
+}
+
+// kilicScalar converts the Fr to a Kilic scalar, for the Kilic curve operations.
+func kilicScalar(v *Fr) kbls.Fr {
+	tmp := (kbls.Fr)(*v) // copy, we want to leave the original in mont-red form
+	(&tmp).FromRed()
+	return tmp
+}
+
 func CopyFr(dst *Fr, v *Fr) {
 	*dst = *v
 }
@@ -114,10 +136,6 @@ func InvModFr(dst *Fr, v *Fr) {
 	(*kbls.Fr)(dst).RedInverse((*kbls.Fr)(v))
 }
 
-func BatchInvModFr(f []Fr) {
-	kbls.RedInverseBatchFr(*(*[]kbls.Fr)(unsafe.Pointer(&f)))
-}
-
 //func SqrModFr(dst *Fr, v *Fr) {
 //	kbls.FrSqr((*kbls.Fr)(dst), (*kbls.Fr)(v))
 //}
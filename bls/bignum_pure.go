@@ -6,6 +6,8 @@ package bls
 import (
 	"crypto/rand"
 	"math/big"
+
+	kbls "github.com/kilic/bls12-381"
 )
 
 var _modulus big.Int
@@ -13,6 +15,8 @@ var _modulus big.Int
 func init() {
 	SetFr((*Fr)(&_modulus), ModulusStr)
 	initGlobals()
+	ClearG1(&ZERO_G1)
+	initG1G2()
 }
 
 type Fr big.Int
@@ -33,16 +37,38 @@ func FrFrom32(dst *Fr, v [32]byte) (ok bool) {
 
 // FrTo32 serializes a fr number to 32 bytes. Encoded little-endian.
 func FrTo32(src *Fr) (v [32]byte) {
-	b := (*big.Int)(src).Bytes()
-	last := len(b) - 1
-	// reverse endianness, u256.Int outputs big-endian bytes
+	(*big.Int)(src).FillBytes(v[:])
+	// reverse endianness, big.Int outputs big-endian bytes
 	for i := 0; i < 16; i++ {
-		b[i], b[last-i] = b[last-i], b[i]
+		v[i], v[31-i] = v[31-i], v[i]
 	}
-	copy(v[:], b)
 	return
 }
 
+// FrFrom32BE mutates the fr num. The value v is big-endian 32-bytes.
+// Returns false, without modifying dst, if the value is out of range.
+func FrFrom32BE(dst *Fr, v [32]byte) (ok bool) {
+	if !ValidFrBE(v) {
+		return false
+	}
+	(*big.Int)(dst).SetBytes(v[:])
+	return true
+}
+
+// FrTo32BE serializes a fr number to 32 bytes. Encoded big-endian.
+func FrTo32BE(src *Fr) (v [32]byte) {
+	(*big.Int)(src).FillBytes(v[:])
+	return
+}
+
+// kilicScalar converts the Fr to a Kilic scalar, for the Kilic curve operations.
+func kilicScalar(v *Fr) kbls.Fr {
+	b := FrTo32BE(v)
+	var out kbls.Fr
+	out.FromBytes(b[:])
+	return out
+}
+
 func CopyFr(dst *Fr, v *Fr) {
 	(*big.Int)(dst).Set((*big.Int)(v))
 }
@@ -111,14 +137,6 @@ func InvModFr(dst *Fr, v *Fr) {
 	(*big.Int)(dst).ModInverse((*big.Int)(v), &_modulus)
 }
 
-// BatchInvModFr computes the inverse for each input.
-// Warning: this does not actually batch, this is just here for compatibility with other BLS backends that do.
-func BatchInvModFr(f []Fr) {
-	for i := 0; i < len(f); i++ {
-		InvModFr(&f[i], &f[i])
-	}
-}
-
 //func sqrModFr(dst *Fr, v *Fr) {
 //	(*big.Int)(dst).ModSqrt((*big.Int)(v), &_modulus)
 //}
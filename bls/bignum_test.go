@@ -1,6 +1,10 @@
 package bls
 
-import "testing"
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
 
 // These are sanity tests, to see if whatever bignum library that is being
 // used actually handles dst/arg overlaps well.
@@ -114,3 +118,91 @@ func TestValidFr(t *testing.T) {
 		t.Fatal("expected zero to be valid")
 	}
 }
+
+func TestFr32BE(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		v := RandomFr()
+		le := FrTo32(v)
+		be := FrTo32BE(v)
+		for j := 0; j < 32; j++ {
+			if le[j] != be[31-j] {
+				t.Fatalf("big-endian encoding is not the reverse of little-endian: %x %x", le, be)
+			}
+		}
+		var out Fr
+		if !FrFrom32BE(&out, be) || !EqualFr(&out, v) {
+			t.Fatal("big-endian round trip failed")
+		}
+	}
+	// modulus - 1 is valid, modulus is not
+	var minusOne Fr
+	SubModFr(&minusOne, &ZERO, &ONE)
+	max := FrTo32BE(&minusOne)
+	var out Fr
+	if !FrFrom32BE(&out, max) {
+		t.Fatal("expected modulus - 1 to be valid")
+	}
+	max[31]++
+	if FrFrom32BE(&out, max) {
+		t.Fatal("expected modulus to be invalid")
+	}
+}
+
+func TestFrFrom32Mod(t *testing.T) {
+	modulus, _ := new(big.Int).SetString(ModulusStr, 10)
+	inputs := make([][32]byte, 0, 20)
+	var max [32]byte
+	for i := range max {
+		max[i] = 0xff
+	}
+	inputs = append(inputs, [32]byte{}, max)
+	for i := 0; i < 18; i++ {
+		var v [32]byte
+		rand.Read(v[:])
+		inputs = append(inputs, v)
+	}
+	for _, v := range inputs {
+		var be [32]byte
+		for i := range v {
+			be[31-i] = v[i]
+		}
+		expected := new(big.Int).Mod(new(big.Int).SetBytes(be[:]), modulus)
+		var out Fr
+		FrFrom32Mod(&out, v)
+		got := FrTo32BE(&out)
+		if new(big.Int).SetBytes(got[:]).Cmp(expected) != 0 {
+			t.Fatalf("reduction of %x: expected %s, got %x", v, expected, got)
+		}
+	}
+}
+
+func TestBatchInvModFr(t *testing.T) {
+	src := make([]Fr, 5)
+	for i := range src {
+		CopyFr(&src[i], RandomFr())
+	}
+	CopyFr(&src[2], &ZERO)
+	dst := make([]Fr, len(src))
+	BatchInvModFr(dst, src)
+	for i := range src {
+		if i == 2 {
+			if !EqualZero(&dst[i]) {
+				t.Fatal("expected zero to map to zero")
+			}
+			continue
+		}
+		var expected Fr
+		InvModFr(&expected, &src[i])
+		if !EqualFr(&dst[i], &expected) {
+			t.Fatalf("inverse mismatch at %d", i)
+		}
+	}
+	// in place
+	BatchInvModFr(src, src)
+	for i := range src {
+		if !EqualFr(&src[i], &dst[i]) {
+			t.Fatalf("in-place inverse mismatch at %d", i)
+		}
+	}
+	BatchInvModFr(nil, nil)
+}
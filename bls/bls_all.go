@@ -1,13 +1,73 @@
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package bls
 
 import (
 	"encoding/hex"
 	"errors"
+	"runtime"
+	"sync"
 )
 
+// PairingCheck is a pairing equation e(A1, A2) == e(B1, B2), see PairingsVerifyBatch.
+type PairingCheck struct {
+	A1 G1Point
+	A2 G2Point
+	B1 G1Point
+	B2 G2Point
+}
+
+// batchRandomizers returns the randomizers for n pairing checks: the given ones if there are n of them,
+// or fresh random scalars if nil.
+func batchRandomizers(n int, randomizers []Fr) ([]Fr, bool) {
+	if randomizers == nil {
+		randomizers = make([]Fr, n)
+		for i := range randomizers {
+			CopyFr(&randomizers[i], RandomFr())
+		}
+	}
+	return randomizers, len(randomizers) == n
+}
+
+// compressG1BatchChunk is the minimum number of points ToCompressedG1Batch encodes per goroutine,
+// below which the goroutine costs more than the encoding.
+const compressG1BatchChunk = 256
+
+// ToCompressedG1Batch returns the compressed encodings of the points, as ToCompressedG1 does, e.g. for the proofs
+// of all the points or cells of a blob, tens of thousands of points per batch of blobs. The points are split
+// in up to workers ranges (GOMAXPROCS if zero or less), encoded in parallel, and the Kilic backend normalizes
+// every range to affine coordinates with a single batch inversion, instead of an inversion per point.
+// The points are not modified.
+func ToCompressedG1Batch(points []G1Point, workers int) [][48]byte {
+	out := make([][48]byte, len(points))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if chunks := (len(points) + compressG1BatchChunk - 1) / compressG1BatchChunk; workers > chunks {
+		workers = chunks
+	}
+	if workers <= 1 {
+		toCompressedG1Range(out, points)
+		return out
+	}
+	size := (len(points) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for from := 0; from < len(points); from += size {
+		to := from + size
+		if to > len(points) {
+			to = len(points)
+		}
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			toCompressedG1Range(out[from:to], points[from:to])
+		}(from, to)
+	}
+	wg.Wait()
+	return out
+}
+
 func (p *G1Point) String() string {
 	return StrG1(p)
 }
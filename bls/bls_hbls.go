@@ -4,6 +4,7 @@
 package bls
 
 import (
+	"errors"
 	"fmt"
 	hbls "github.com/herumi/bls-eth-go-binary/bls"
 	"strings"
@@ -114,11 +115,34 @@ func EqualG2(a *G2Point, b *G2Point) bool {
 	return (*hbls.G2)(a).IsEqual((*hbls.G2)(b))
 }
 
+// IsZeroG1 returns true if the point is the point at infinity (the identity).
+func IsZeroG1(p *G1Point) bool {
+	return (*hbls.G1)(p).IsZero()
+}
+
 func ToCompressedG1(p *G1Point) []byte {
 	return hbls.CastToPublicKey((*hbls.G1)(p)).Serialize()
 }
 
+// toCompressedG1Range encodes the points into out. Herumi normalizes every point as it serializes it.
+func toCompressedG1Range(out [][48]byte, points []G1Point) {
+	for i := range points {
+		copy(out[i][:], ToCompressedG1(&points[i]))
+	}
+}
+
 func FromCompressedG1(v []byte) (*G1Point, error) {
+	// Herumi ignores the remaining bits when the infinity flag is set, only accept the canonical encoding.
+	if len(v) > 0 && v[0]&0x40 != 0 {
+		if v[0] != 0xc0 {
+			return nil, errors.New("invalid flags for the point at infinity")
+		}
+		for _, b := range v[1:] {
+			if b != 0 {
+				return nil, errors.New("point at infinity must be encoded with zero bytes")
+			}
+		}
+	}
 	var pub hbls.PublicKey
 	if err := pub.Deserialize(v); err != nil {
 		return nil, err
@@ -127,6 +151,35 @@ func FromCompressedG1(v []byte) (*G1Point, error) {
 	return (*G1Point)(p), nil
 }
 
+// InSubgroupG1 returns true if the point is in the prime order subgroup of G1, the check FromCompressedG1 does.
+func InSubgroupG1(p *G1Point) bool {
+	return (*hbls.G1)(p).IsValidOrder()
+}
+
+// FromCompressedG1Unchecked is FromCompressedG1: Herumi checks the subgroup of the points it decodes,
+// a process-wide setting which is not changed per call.
+func FromCompressedG1Unchecked(v []byte) (*G1Point, error) {
+	return FromCompressedG1(v)
+}
+
+// ToUncompressedG1 returns the 96-byte uncompressed (x, y) encoding of the point.
+func ToUncompressedG1(p *G1Point) []byte {
+	return (*hbls.G1)(p).SerializeUncompressed()
+}
+
+// FromUncompressedG1 decodes a 96-byte uncompressed point, without the square root of the compressed form.
+// The point is still checked to be on the curve and in the subgroup.
+func FromUncompressedG1(v []byte) (*G1Point, error) {
+	if len(v) != 96 {
+		return nil, errors.New("input string length must be equal to 96 bytes")
+	}
+	var p hbls.G1
+	if err := p.DeserializeUncompressed(v); err != nil {
+		return nil, err
+	}
+	return (*G1Point)(&p), nil
+}
+
 func ToCompressedG2(p *G2Point) []byte {
 	return hbls.CastToSign((*hbls.G2)(p)).Serialize()
 }
@@ -141,6 +194,7 @@ func FromCompressedG2(v []byte) (*G2Point, error) {
 }
 
 func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
+	defer StartTraceRegion("kzg msm").End()
 	var out G1Point
 	// We're just using unsafe to cast elements that are an alias anyway, no problem.
 	// Go doesn't let us do the cast otherwise without copy.
@@ -148,8 +202,18 @@ func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
 	return &out
 }
 
+// LinCombG2 computes the linear combination of the G2 points with the factors, with the Pippenger
+// multi-scalar multiplication of the backend.
+func LinCombG2(numbers []G2Point, factors []Fr) *G2Point {
+	defer StartTraceRegion("kzg msm").End()
+	var out G2Point
+	hbls.G2MulVec((*hbls.G2)(&out), *(*[]hbls.G2)(unsafe.Pointer(&numbers)), *(*[]hbls.Fr)(unsafe.Pointer(&factors)))
+	return &out
+}
+
 // e(a1^(-1), a2) * e(b1,  b2) = 1_T
 func PairingsVerify(a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) bool {
+	defer StartTraceRegion("kzg pairing").End()
 	var tmp hbls.GT
 	hbls.Pairing(&tmp, (*hbls.G1)(a1), (*hbls.G2)(a2))
 	//fmt.Println("tmp", tmp.GetString(10))
@@ -176,6 +240,134 @@ func PairingsVerify(a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) bool {
 	//return tmp.IsEqual(&tmp2)
 }
 
+// PreparedG2 is a G2 point with precomputed Miller loop lines, for repeated pairings, see PairingsVerifyPrepared.
+type PreparedG2 struct {
+	lines []uint64
+}
+
+// PrepareG2 prepares a fixed G2 input of pairings.
+func PrepareG2(p *G2Point) *PreparedG2 {
+	lines := make([]uint64, hbls.GetUint64NumToPrecompute())
+	hbls.PrecomputeG2(lines, (*hbls.G2)(p))
+	return &PreparedG2{lines: lines}
+}
+
+// PairingsVerifyPrepared is PairingsVerify, with prepared G2 points: e(a1^(-1), a2) * e(b1, b2) = 1_T
+func PairingsVerifyPrepared(a1 *G1Point, a2 *PreparedG2, b1 *G1Point, b2 *PreparedG2) bool {
+	defer StartTraceRegion("kzg pairing").End()
+	var negA1 hbls.G1
+	hbls.G1Neg(&negA1, (*hbls.G1)(a1))
+	// Not using PrecomputedMillerLoop2: the binding passes its first pair twice.
+	var mlA, mlB, ml, out hbls.GT
+	hbls.PrecomputedMillerLoop(&mlA, &negA1, a2.lines)
+	hbls.PrecomputedMillerLoop(&mlB, (*hbls.G1)(b1), b2.lines)
+	hbls.GTMul(&ml, &mlA, &mlB)
+	hbls.FinalExp(&out, &ml)
+	return out.IsOne()
+}
+
+// PairingsVerifyBatch checks e(A1, A2) == e(B1, B2) for all the pairs at once, with a single final exponentiation:
+//
+//	prod(e(r_i * A1_i, A2_i)^(-1) * e(r_i * B1_i, B2_i)) = 1_T
+//
+// The randomizers r_i must be unpredictable to the party that produced the pairs, and there must be one per pair.
+// If nil, fresh random randomizers are used.
+func PairingsVerifyBatch(pairs []PairingCheck, randomizers []Fr) bool {
+	defer StartTraceRegion("kzg pairing").End()
+	randomizers, ok := batchRandomizers(len(pairs), randomizers)
+	if !ok {
+		return false
+	}
+	if len(pairs) == 0 {
+		return true
+	}
+	g1s := make([]hbls.G1, 2*len(pairs))
+	g2s := make([]hbls.G2, 2*len(pairs))
+	for i := range pairs {
+		var a1 G1Point
+		MulG1(&a1, &pairs[i].A1, &randomizers[i])
+		NegG1(&a1)
+		g1s[2*i] = hbls.G1(a1)
+		g2s[2*i] = hbls.G2(pairs[i].A2)
+		var b1 G1Point
+		MulG1(&b1, &pairs[i].B1, &randomizers[i])
+		g1s[2*i+1] = hbls.G1(b1)
+		g2s[2*i+1] = hbls.G2(pairs[i].B2)
+	}
+	var ml, out hbls.GT
+	hbls.MillerLoopVec(&ml, g1s, g2s)
+	hbls.FinalExp(&out, &ml)
+	return out.IsOne()
+}
+
+// GT is an element of the target group of the pairing.
+type GT hbls.GT
+
+// IsOneGT returns true if the element is the identity of the target group.
+func IsOneGT(v *GT) bool {
+	return (*hbls.GT)(v).IsOne()
+}
+
+// EqualGT returns true if the elements are equal.
+func EqualGT(a *GT, b *GT) bool {
+	return (*hbls.GT)(a).IsEqual((*hbls.GT)(b))
+}
+
+// Pairing sets dst to e(a, b).
+func Pairing(dst *GT, a *G1Point, b *G2Point) {
+	defer StartTraceRegion("kzg pairing").End()
+	hbls.Pairing((*hbls.GT)(dst), (*hbls.G1)(a), (*hbls.G2)(b))
+}
+
+// MillerLoopResult is the product of the Miller loops of pairings, before the final exponentiation,
+// see FinalExp. The zero value is the empty product.
+type MillerLoopResult struct {
+	v hbls.GT
+	// set is false for the empty product: the zero value of hbls.GT is not the identity.
+	set bool
+}
+
+// MillerLoop sets dst to the Miller loop of e(a, b).
+func MillerLoop(dst *MillerLoopResult, a *G1Point, b *G2Point) {
+	hbls.MillerLoop(&dst.v, (*hbls.G1)(a), (*hbls.G2)(b))
+	dst.set = true
+}
+
+// PairingsMillerLoop sets dst to the Miller loop of the check of PairingsVerify, e(a1^(-1), a2) * e(b1, b2):
+// the check holds if the final exponentiation of the product of such results is the identity,
+// so several checks can share a single final exponentiation.
+func PairingsMillerLoop(dst *MillerLoopResult, a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) {
+	var g1s [2]hbls.G1
+	hbls.G1Neg(&g1s[0], (*hbls.G1)(a1))
+	g1s[1] = hbls.G1(*b1)
+	g2s := [2]hbls.G2{hbls.G2(*a2), hbls.G2(*b2)}
+	hbls.MillerLoopVec(&dst.v, g1s[:], g2s[:])
+	dst.set = true
+}
+
+// MulMillerLoop sets dst to a * b.
+func MulMillerLoop(dst *MillerLoopResult, a *MillerLoopResult, b *MillerLoopResult) {
+	switch {
+	case !a.set:
+		*dst = *b
+	case !b.set:
+		*dst = *a
+	default:
+		hbls.GTMul(&dst.v, &a.v, &b.v)
+		dst.set = true
+	}
+}
+
+// FinalExp sets dst to the final exponentiation of the Miller loop result.
+func FinalExp(dst *GT, ml *MillerLoopResult) {
+	defer StartTraceRegion("kzg pairing").End()
+	if !ml.set {
+		(*hbls.GT)(dst).SetInt64(1)
+		return
+	}
+	hbls.FinalExp((*hbls.GT)(dst), &ml.v)
+}
+
 func DebugG1s(msg string, values []G1Point) {
 	var out strings.Builder
 	for i := range values {
@@ -183,3 +375,43 @@ func DebugG1s(msg string, values []G1Point) {
 	}
 	fmt.Println(out.String())
 }
+
+// normalizeG1s converts the points to affine form in-place, so they can be used in mixed additions.
+func normalizeG1s(points []G1Point) {
+	for i := range points {
+		hbls.G1Normalize((*hbls.G1)(&points[i]), (*hbls.G1)(&points[i]))
+	}
+}
+
+// normalizeG2s converts the points to affine form in-place, so they can be used in mixed additions.
+func normalizeG2s(points []G2Point) {
+	for i := range points {
+		hbls.G2Normalize((*hbls.G2)(&points[i]), (*hbls.G2)(&points[i]))
+	}
+}
+
+// sumDigitsG1 computes the sum of digits[i] * points[i], using a bucket per digit value.
+func sumDigitsG1(out *G1Point, points []G1Point, digits []byte) {
+	var buckets [255]hbls.G1
+	for i, d := range digits {
+		if d != 0 {
+			hbls.G1Add(&buckets[d-1], &buckets[d-1], (*hbls.G1)(&points[i]))
+		}
+	}
+	var acc, sum hbls.G1
+	for i := len(buckets) - 1; i >= 0; i-- {
+		hbls.G1Add(&sum, &sum, &buckets[i])
+		hbls.G1Add(&acc, &acc, &sum)
+	}
+	*out = G1Point(acc)
+}
+
+const g1RawSize = 96
+
+func g1ToRawBytes(p *G1Point) []byte {
+	return (*hbls.G1)(p).SerializeUncompressed()
+}
+
+func g1FromRawBytes(dst *G1Point, v []byte) error {
+	return (*hbls.G1)(dst).DeserializeUncompressed(v)
+}
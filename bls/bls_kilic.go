@@ -1,5 +1,8 @@
-//go:build !bignum_pure && !bignum_hol256 && !bignum_hbls
-// +build !bignum_pure,!bignum_hol256,!bignum_hbls
+//go:build !bignum_hol256 && !bignum_hbls
+// +build !bignum_hol256,!bignum_hbls
+
+// The Kilic curve implementation is pure Go, and is used both with the Kilic Fr (default),
+// and with the big.Int Fr of bignum_pure. The Fr representation is only accessed through kilicScalar.
 
 package bls
 
@@ -39,8 +42,7 @@ func CopyG1(dst *G1Point, v *G1Point) {
 }
 
 func MulG1(dst *G1Point, a *G1Point, b *Fr) {
-	tmp := (kbls.Fr)(*b) // copy, we want to leave the original in mont-red form
-	(&tmp).FromRed()
+	tmp := kilicScalar(b)
 	kbls.NewG1().MulScalar((*kbls.PointG1)(dst), (*kbls.PointG1)(a), &tmp)
 }
 
@@ -77,8 +79,7 @@ func CopyG2(dst *G2Point, v *G2Point) {
 }
 
 func MulG2(dst *G2Point, a *G2Point, b *Fr) {
-	tmp := (kbls.Fr)(*b) // copy, we want to leave the original in mont-red form
-	(&tmp).FromRed()
+	tmp := kilicScalar(b)
 	kbls.NewG2().MulScalar((*kbls.PointG2)(dst), (*kbls.PointG2)(a), &tmp)
 }
 
@@ -111,15 +112,102 @@ func EqualG2(a *G2Point, b *G2Point) bool {
 	return kbls.NewG2().Equal((*kbls.PointG2)(a), (*kbls.PointG2)(b))
 }
 
+// IsZeroG1 returns true if the point is the point at infinity (the identity).
+func IsZeroG1(p *G1Point) bool {
+	return kbls.NewG1().IsZero((*kbls.PointG1)(p))
+}
+
 func ToCompressedG1(p *G1Point) []byte {
 	return kbls.NewG1().ToCompressed((*kbls.PointG1)(p))
 }
 
+// toCompressedG1Range encodes the points into out, normalizing copies of them to affine coordinates
+// with a single batch inversion, so that the encoding of every point skips its own inversion.
+func toCompressedG1Range(out [][48]byte, points []G1Point) {
+	g := kbls.NewG1()
+	normalized := make([]kbls.PointG1, len(points))
+	ptrs := make([]*kbls.PointG1, len(points))
+	for i := range points {
+		normalized[i] = kbls.PointG1(points[i])
+		ptrs[i] = &normalized[i]
+	}
+	g.AffineBatch(ptrs)
+	for i := range normalized {
+		copy(out[i][:], g.ToCompressed(&normalized[i]))
+	}
+}
+
 func FromCompressedG1(v []byte) (*G1Point, error) {
 	p, err := kbls.NewG1().FromCompressed(v)
 	return (*G1Point)(p), err
 }
 
+// fpModulus is the modulus of the base field, and fpSqrtExp = (fpModulus + 1) / 4 the exponent of the square root,
+// as fpModulus = 3 mod 4.
+var fpModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+var fpSqrtExp = new(big.Int).Rsh(new(big.Int).Add(fpModulus, big.NewInt(1)), 2)
+
+// InSubgroupG1 returns true if the point is in the prime order subgroup of G1, the check FromCompressedG1 does
+// and FromCompressedG1Unchecked skips.
+func InSubgroupG1(p *G1Point) bool {
+	return kbls.NewG1().InCorrectSubgroup((*kbls.PointG1)(p))
+}
+
+// FromCompressedG1Unchecked is FromCompressedG1 without the subgroup check: the point is only checked to be
+// on the curve. It is only sound for points from a trusted source, and saves the scalar multiplication of the check.
+func FromCompressedG1Unchecked(v []byte) (*G1Point, error) {
+	if len(v) != 48 {
+		return nil, fmt.Errorf("input string length must be equal to 48 bytes")
+	}
+	if v[0]&0x80 == 0 {
+		return nil, fmt.Errorf("compression flag must be set")
+	}
+	if v[0]&0x40 != 0 {
+		// only the canonical encoding of the point at infinity, checked as FromCompressedG1 does
+		return FromCompressedG1(v)
+	}
+	largest := v[0]&0x20 != 0
+	var in [48]byte
+	copy(in[:], v)
+	in[0] &= 0x1f
+	x := new(big.Int).SetBytes(in[:])
+	if x.Cmp(fpModulus) >= 0 {
+		return nil, fmt.Errorf("must be less than modulus")
+	}
+	// y**2 = x**3 + 4
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Mul(rhs, x)
+	rhs.Add(rhs, big.NewInt(4))
+	rhs.Mod(rhs, fpModulus)
+	y := new(big.Int).Exp(rhs, fpSqrtExp, fpModulus)
+	if new(big.Int).Mod(new(big.Int).Mul(y, y), fpModulus).Cmp(rhs) != 0 {
+		return nil, fmt.Errorf("point is not on curve")
+	}
+	negY := new(big.Int).Sub(fpModulus, y)
+	if (y.Cmp(negY) > 0) != largest {
+		y = negY
+	}
+	var raw [96]byte
+	x.FillBytes(raw[:48])
+	y.FillBytes(raw[48:])
+	p, err := kbls.NewG1().FromBytes(raw[:])
+	return (*G1Point)(p), err
+}
+
+// ToUncompressedG1 returns the 96-byte uncompressed (x, y) encoding of the point.
+func ToUncompressedG1(p *G1Point) []byte {
+	var tmp kbls.PointG1
+	tmp.Set((*kbls.PointG1)(p))
+	return kbls.NewG1().ToUncompressed(&tmp)
+}
+
+// FromUncompressedG1 decodes a 96-byte uncompressed point, without the square root of the compressed form.
+// The point is still checked to be on the curve and in the subgroup.
+func FromUncompressedG1(v []byte) (*G1Point, error) {
+	p, err := kbls.NewG1().FromUncompressed(v)
+	return (*G1Point)(p), err
+}
+
 func ToCompressedG2(p *G2Point) []byte {
 	return kbls.NewG2().ToCompressed((*kbls.PointG2)(p))
 }
@@ -130,6 +218,7 @@ func FromCompressedG2(v []byte) (*G2Point, error) {
 }
 
 func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
+	defer StartTraceRegion("kzg msm").End()
 	if len(numbers) != len(factors) {
 		panic("got LinCombG1 numbers/factors length mismatch")
 	}
@@ -140,23 +229,166 @@ func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
 	}
 	tmpFrs := make([]*kbls.Fr, len(factors), len(factors))
 	for i := 0; i < len(factors); i++ {
-		// copy, since we have to change from mont-red form to regular again, and don't want to mutate the input.
-		v := *(*kbls.Fr)(&factors[i])
-		v.FromRed()
+		v := kilicScalar(&factors[i])
 		tmpFrs[i] = &v
 	}
 	_, _ = kbls.NewG1().MultiExp((*kbls.PointG1)(&out), tmpG1s, tmpFrs)
 	return &out
 }
 
+// LinCombG2 computes the linear combination of the G2 points with the factors, with the Pippenger
+// multi-scalar multiplication of the backend.
+func LinCombG2(numbers []G2Point, factors []Fr) *G2Point {
+	defer StartTraceRegion("kzg msm").End()
+	if len(numbers) != len(factors) {
+		panic("got LinCombG2 numbers/factors length mismatch")
+	}
+	var out G2Point
+	tmpG2s := make([]*kbls.PointG2, len(numbers), len(numbers))
+	for i := 0; i < len(numbers); i++ {
+		tmpG2s[i] = (*kbls.PointG2)(&numbers[i])
+	}
+	tmpFrs := make([]*kbls.Fr, len(factors), len(factors))
+	for i := 0; i < len(factors); i++ {
+		v := kilicScalar(&factors[i])
+		tmpFrs[i] = &v
+	}
+	_, _ = kbls.NewG2().MultiExp((*kbls.PointG2)(&out), tmpG2s, tmpFrs)
+	return &out
+}
+
 // e(a1^(-1), a2) * e(b1,  b2) = 1_T
 func PairingsVerify(a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) bool {
+	defer StartTraceRegion("kzg pairing").End()
+	// the engine normalizes its inputs in place, work on copies so shared inputs (e.g. GenG2) are not written to
+	a1p, a2p := *(*kbls.PointG1)(a1), *(*kbls.PointG2)(a2)
+	b1p, b2p := *(*kbls.PointG1)(b1), *(*kbls.PointG2)(b2)
+	pairingEngine := kbls.NewEngine()
+	pairingEngine.AddPairInv(&a1p, &a2p)
+	pairingEngine.AddPair(&b1p, &b2p)
+	return pairingEngine.Check()
+}
+
+// PreparedG2 is a G2 point prepared for repeated pairings, see PairingsVerifyPrepared.
+// The kilic backend does not expose its Miller loop lines, so only the affine form of the point is cached.
+type PreparedG2 struct {
+	p kbls.PointG2
+}
+
+// PrepareG2 prepares a fixed G2 input of pairings.
+func PrepareG2(p *G2Point) *PreparedG2 {
+	var out PreparedG2
+	out.p.Set((*kbls.PointG2)(p))
+	kbls.NewG2().Affine(&out.p)
+	return &out
+}
+
+// PairingsVerifyPrepared is PairingsVerify, with prepared G2 points: e(a1^(-1), a2) * e(b1, b2) = 1_T
+func PairingsVerifyPrepared(a1 *G1Point, a2 *PreparedG2, b1 *G1Point, b2 *PreparedG2) bool {
+	defer StartTraceRegion("kzg pairing").End()
+	// the engine normalizes its inputs in place, work on copies so the prepared points can be shared
+	a1p, a2p := *(*kbls.PointG1)(a1), a2.p
+	b1p, b2p := *(*kbls.PointG1)(b1), b2.p
 	pairingEngine := kbls.NewEngine()
-	pairingEngine.AddPairInv((*kbls.PointG1)(a1), (*kbls.PointG2)(a2))
-	pairingEngine.AddPair((*kbls.PointG1)(b1), (*kbls.PointG2)(b2))
+	pairingEngine.AddPairInv(&a1p, &a2p)
+	pairingEngine.AddPair(&b1p, &b2p)
 	return pairingEngine.Check()
 }
 
+// PairingsVerifyBatch checks e(A1, A2) == e(B1, B2) for all the pairs at once, with a single final exponentiation:
+//
+//	prod(e(r_i * A1_i, A2_i)^(-1) * e(r_i * B1_i, B2_i)) = 1_T
+//
+// The randomizers r_i must be unpredictable to the party that produced the pairs, and there must be one per pair.
+// If nil, fresh random randomizers are used.
+func PairingsVerifyBatch(pairs []PairingCheck, randomizers []Fr) bool {
+	defer StartTraceRegion("kzg pairing").End()
+	randomizers, ok := batchRandomizers(len(pairs), randomizers)
+	if !ok {
+		return false
+	}
+	// the engine normalizes its inputs in place, and keeps pointers to them until Check: every pair gets its own copies
+	g1s := make([]G1Point, 2*len(pairs))
+	g2s := make([]kbls.PointG2, 2*len(pairs))
+	pairingEngine := kbls.NewEngine()
+	for i := range pairs {
+		a1, b1 := &g1s[2*i], &g1s[2*i+1]
+		a2, b2 := &g2s[2*i], &g2s[2*i+1]
+		MulG1(a1, &pairs[i].A1, &randomizers[i])
+		MulG1(b1, &pairs[i].B1, &randomizers[i])
+		*a2, *b2 = *(*kbls.PointG2)(&pairs[i].A2), *(*kbls.PointG2)(&pairs[i].B2)
+		pairingEngine.AddPairInv((*kbls.PointG1)(a1), a2)
+		pairingEngine.AddPair((*kbls.PointG1)(b1), b2)
+	}
+	return pairingEngine.Check()
+}
+
+// GT is an element of the target group of the pairing.
+type GT kbls.E
+
+// IsOneGT returns true if the element is the identity of the target group.
+func IsOneGT(v *GT) bool {
+	return (*kbls.E)(v).IsOne()
+}
+
+// EqualGT returns true if the elements are equal.
+func EqualGT(a *GT, b *GT) bool {
+	return (*kbls.E)(a).Equal((*kbls.E)(b))
+}
+
+// Pairing sets dst to e(a, b).
+func Pairing(dst *GT, a *G1Point, b *G2Point) {
+	var ml MillerLoopResult
+	MillerLoop(&ml, a, b)
+	FinalExp(dst, &ml)
+}
+
+// MillerLoopResult is the product of the Miller loops of pairings, before the final exponentiation,
+// see FinalExp. The zero value is the empty product.
+// The kilic backend does not expose its Miller loop, so the pairs are kept, and all of them go through
+// a single multi-Miller loop in FinalExp.
+type MillerLoopResult struct {
+	g1s []kbls.PointG1
+	g2s []kbls.PointG2
+}
+
+// MillerLoop sets dst to the Miller loop of e(a, b).
+func MillerLoop(dst *MillerLoopResult, a *G1Point, b *G2Point) {
+	dst.g1s = append(dst.g1s[:0], *(*kbls.PointG1)(a))
+	dst.g2s = append(dst.g2s[:0], *(*kbls.PointG2)(b))
+}
+
+// PairingsMillerLoop sets dst to the Miller loop of the check of PairingsVerify, e(a1^(-1), a2) * e(b1, b2):
+// the check holds if the final exponentiation of the product of such results is the identity,
+// so several checks can share a single final exponentiation.
+func PairingsMillerLoop(dst *MillerLoopResult, a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) {
+	var negA1 kbls.PointG1
+	kbls.NewG1().Neg(&negA1, (*kbls.PointG1)(a1))
+	dst.g1s = append(dst.g1s[:0], negA1, *(*kbls.PointG1)(b1))
+	dst.g2s = append(dst.g2s[:0], *(*kbls.PointG2)(a2), *(*kbls.PointG2)(b2))
+}
+
+// MulMillerLoop sets dst to a * b.
+func MulMillerLoop(dst *MillerLoopResult, a *MillerLoopResult, b *MillerLoopResult) {
+	g1s := make([]kbls.PointG1, 0, len(a.g1s)+len(b.g1s))
+	g2s := make([]kbls.PointG2, 0, len(a.g2s)+len(b.g2s))
+	dst.g1s = append(append(g1s, a.g1s...), b.g1s...)
+	dst.g2s = append(append(g2s, a.g2s...), b.g2s...)
+}
+
+// FinalExp sets dst to the final exponentiation of the Miller loop result.
+func FinalExp(dst *GT, ml *MillerLoopResult) {
+	defer StartTraceRegion("kzg pairing").End()
+	// the engine normalizes its inputs in place, work on copies so the result can be reused
+	g1s := append([]kbls.PointG1(nil), ml.g1s...)
+	g2s := append([]kbls.PointG2(nil), ml.g2s...)
+	pairingEngine := kbls.NewEngine()
+	for i := range g1s {
+		pairingEngine.AddPair(&g1s[i], &g2s[i])
+	}
+	(*kbls.E)(dst).Set(pairingEngine.Result())
+}
+
 func DebugG1s(msg string, values []G1Point) {
 	var out strings.Builder
 	for i := range values {
@@ -164,3 +396,56 @@ func DebugG1s(msg string, values []G1Point) {
 	}
 	fmt.Println(out.String())
 }
+
+// normalizeG1s converts the points to affine form in-place, so they can be used in mixed additions.
+func normalizeG1s(points []G1Point) {
+	ptrs := make([]*kbls.PointG1, len(points))
+	for i := range points {
+		ptrs[i] = (*kbls.PointG1)(&points[i])
+	}
+	kbls.NewG1().AffineBatch(ptrs)
+}
+
+// normalizeG2s converts the points to affine form in-place, so they can be used in mixed additions.
+func normalizeG2s(points []G2Point) {
+	ptrs := make([]*kbls.PointG2, len(points))
+	for i := range points {
+		ptrs[i] = (*kbls.PointG2)(&points[i])
+	}
+	kbls.NewG2().AffineBatch(ptrs)
+}
+
+// sumDigitsG1 computes the sum of digits[i] * points[i], using a bucket per digit value.
+func sumDigitsG1(out *G1Point, points []G1Point, digits []byte) {
+	g := kbls.NewG1()
+	var buckets [255]kbls.PointG1
+	for i := range buckets {
+		buckets[i].Zero()
+	}
+	for i, d := range digits {
+		if d != 0 {
+			g.Add(&buckets[d-1], &buckets[d-1], (*kbls.PointG1)(&points[i]))
+		}
+	}
+	acc, sum := g.Zero(), g.Zero()
+	for i := len(buckets) - 1; i >= 0; i-- {
+		g.Add(sum, sum, &buckets[i])
+		g.Add(acc, acc, sum)
+	}
+	*out = G1Point(*acc)
+}
+
+const g1RawSize = 96
+
+func g1ToRawBytes(p *G1Point) []byte {
+	return kbls.NewG1().ToBytes((*kbls.PointG1)(p))
+}
+
+func g1FromRawBytes(dst *G1Point, v []byte) error {
+	p, err := kbls.NewG1().FromBytes(v)
+	if err != nil {
+		return err
+	}
+	*dst = G1Point(*p)
+	return nil
+}
@@ -1,5 +1,5 @@
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package bls
 
@@ -102,3 +102,312 @@ func TestPolyLincomb(t *testing.T) {
 		t.Fatal("Expected error, got none")
 	}
 }
+
+func TestPolyLinCombParallel(t *testing.T) {
+	// more than one block, with a partial last one
+	const n = 3*polyLinCombBlock + 5
+	vectors := make([][]Fr, 5)
+	scalars := make([]Fr, len(vectors))
+	for j := range vectors {
+		vectors[j] = make([]Fr, n)
+		for i := range vectors[j] {
+			CopyFr(&vectors[j][i], RandomFr())
+		}
+		CopyFr(&scalars[j], RandomFr())
+	}
+	expected, err := PolyLinComb(vectors, scalars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{1, 2, 16} {
+		r, err := PolyLinCombParallel(vectors, scalars, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range r {
+			if !EqualFr(&r[i], &expected[i]) {
+				t.Fatalf("%d workers: element %d does not match", workers, i)
+			}
+		}
+	}
+
+	// accumulating twice doubles the combination
+	acc := make([]Fr, n)
+	for k := 0; k < 2; k++ {
+		if err := PolyLinCombAccumulate(acc, vectors, scalars); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range acc {
+		var doubled Fr
+		AddModFr(&doubled, &expected[i], &expected[i])
+		if !EqualFr(&acc[i], &doubled) {
+			t.Fatalf("accumulated element %d does not match", i)
+		}
+	}
+
+	if _, err := PolyLinCombParallel(nil, nil, 4); err == nil {
+		t.Fatal("expected empty input to be rejected")
+	}
+	if err := PolyLinCombAccumulate(make([]Fr, n-1), vectors, scalars); err == nil {
+		t.Fatal("expected a short output to be rejected")
+	}
+	if _, err := PolyLinCombParallel(vectors, scalars[1:], 4); err == nil {
+		t.Fatal("expected mismatching scalars to be rejected")
+	}
+}
+
+func TestPointAtInfinityCompression(t *testing.T) {
+	expected := make([]byte, 48)
+	expected[0] = 0xc0
+	if got := ToCompressedG1(&ZeroG1); !bytes.Equal(got, expected) {
+		t.Fatalf("unexpected encoding of the point at infinity: %x", got)
+	}
+	p, err := FromCompressedG1(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsZeroG1(p) || IsZeroG1(&GenG1) {
+		t.Fatal("expected to decode the point at infinity")
+	}
+	// the infinity flag requires the compression flag, and no other bits
+	noncanonical := append([]byte{}, expected...)
+	noncanonical[47] = 1
+	for _, v := range [][]byte{{0x40}, {0xe0}, noncanonical} {
+		in := make([]byte, 48)
+		copy(in, v)
+		if _, err := FromCompressedG1(in); err == nil {
+			t.Fatalf("expected non-canonical infinity %x to be rejected", in)
+		}
+	}
+}
+
+func TestFromCompressedG1Unchecked(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		var point G1Point
+		MulG1(&point, &GenG1, RandomFr())
+		if i%2 == 1 {
+			SubG1(&point, &ZeroG1, &point)
+		}
+		compressed := ToCompressedG1(&point)
+		p, err := FromCompressedG1Unchecked(compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !EqualG1(p, &point) {
+			t.Fatalf("point %d decodes to a different point", i)
+		}
+		// points off the subgroup, or off the curve, are rejected by the checked decoding;
+		// the unchecked one only rejects points off the curve
+		for j := 0; j < 8; j++ {
+			compressed[47]++
+			p, err := FromCompressedG1Unchecked(compressed)
+			if err != nil {
+				continue
+			}
+			if !bytes.Equal(ToCompressedG1(p), compressed) {
+				t.Fatalf("point %x does not round-trip", compressed)
+			}
+			if _, err := FromCompressedG1(compressed); InSubgroupG1(p) != (err == nil) {
+				t.Fatalf("point %x: subgroup check mismatch with the checked decoding: %v", compressed, err)
+			}
+		}
+	}
+	infinity := make([]byte, 48)
+	infinity[0] = 0xc0
+	if p, err := FromCompressedG1Unchecked(infinity); err != nil || !IsZeroG1(p) {
+		t.Fatalf("expected to decode the point at infinity: %v", err)
+	}
+	infinity[47] = 1
+	if _, err := FromCompressedG1Unchecked(infinity); err == nil {
+		t.Fatal("expected non-canonical infinity to be rejected")
+	}
+}
+
+func TestPointUncompressed(t *testing.T) {
+	var point G1Point
+	MulG1(&point, &GenG1, RandomFr())
+	enc := ToUncompressedG1(&point)
+	if len(enc) != 96 {
+		t.Fatalf("unexpected length %d", len(enc))
+	}
+	// the x coordinate is the compressed encoding without the flags
+	compressed := ToCompressedG1(&point)
+	if !bytes.Equal(enc[1:48], compressed[1:]) || enc[0] != compressed[0]&0x1f {
+		t.Fatalf("x coordinate mismatch: %x, %x", enc, compressed)
+	}
+	p, err := FromUncompressedG1(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !EqualG1(p, &point) {
+		t.Fatal("round trip mismatch")
+	}
+	inf := ToUncompressedG1(&ZeroG1)
+	if inf[0] != 0x40 {
+		t.Fatalf("unexpected encoding of the point at infinity: %x", inf)
+	}
+	if p, err := FromUncompressedG1(inf); err != nil || !IsZeroG1(p) {
+		t.Fatalf("expected to decode the point at infinity: %v", err)
+	}
+	// flip a bit of y, the point is no longer on the curve
+	enc[95] ^= 1
+	if _, err := FromUncompressedG1(enc); err == nil {
+		t.Fatal("expected invalid point to be rejected")
+	}
+	if _, err := FromUncompressedG1(compressed); err == nil {
+		t.Fatal("expected short input to be rejected")
+	}
+}
+
+func TestPairingsVerifyBatch(t *testing.T) {
+	// e(a*G1, b*G2) == e(ab*G1, G2)
+	pairs := make([]PairingCheck, 3)
+	for i := range pairs {
+		a, b := RandomFr(), RandomFr()
+		var ab Fr
+		MulModFr(&ab, a, b)
+		MulG1(&pairs[i].A1, &GenG1, a)
+		MulG2(&pairs[i].A2, &GenG2, b)
+		MulG1(&pairs[i].B1, &GenG1, &ab)
+		CopyG2(&pairs[i].B2, &GenG2)
+	}
+	if !PairingsVerifyBatch(pairs, nil) {
+		t.Fatal("expected valid pairs to verify")
+	}
+	if !PairingsVerifyBatch(nil, nil) {
+		t.Fatal("expected empty batch to verify")
+	}
+	if PairingsVerifyBatch(pairs, make([]Fr, 2)) {
+		t.Fatal("expected missing randomizers to fail")
+	}
+	AddG1(&pairs[1].B1, &pairs[1].B1, &GenG1)
+	if PairingsVerifyBatch(pairs, nil) {
+		t.Fatal("expected invalid pair to fail the batch")
+	}
+}
+
+func TestPairingsVerifyPrepared(t *testing.T) {
+	// e(a*G1, b*G2) == e(ab*G1, G2)
+	a, b := RandomFr(), RandomFr()
+	var ab Fr
+	MulModFr(&ab, a, b)
+	var a1, b1 G1Point
+	var a2 G2Point
+	MulG1(&a1, &GenG1, a)
+	MulG2(&a2, &GenG2, b)
+	MulG1(&b1, &GenG1, &ab)
+	preparedA2, preparedG2 := PrepareG2(&a2), PrepareG2(&GenG2)
+	for i := 0; i < 2; i++ {
+		if !PairingsVerifyPrepared(&a1, preparedA2, &b1, preparedG2) {
+			t.Fatal("expected valid pairing to verify")
+		}
+	}
+	AddG1(&b1, &b1, &GenG1)
+	if PairingsVerifyPrepared(&a1, preparedA2, &b1, preparedG2) {
+		t.Fatal("expected invalid pairing to fail")
+	}
+}
+
+func TestMillerLoop(t *testing.T) {
+	// e(a*G1, b*G2) == e(ab*G1, G2), for several checks sharing a single final exponentiation
+	var acc MillerLoopResult
+	for i := 0; i < 3; i++ {
+		a, b := RandomFr(), RandomFr()
+		var ab Fr
+		MulModFr(&ab, a, b)
+		var a1, b1 G1Point
+		var a2 G2Point
+		MulG1(&a1, &GenG1, a)
+		MulG2(&a2, &GenG2, b)
+		MulG1(&b1, &GenG1, &ab)
+		var ml MillerLoopResult
+		PairingsMillerLoop(&ml, &a1, &a2, &b1, &GenG2)
+		MulMillerLoop(&acc, &acc, &ml)
+
+		var left, right GT
+		Pairing(&left, &a1, &a2)
+		Pairing(&right, &b1, &GenG2)
+		if !EqualGT(&left, &right) {
+			t.Fatal("expected pairings to be equal")
+		}
+	}
+	var out GT
+	FinalExp(&out, &acc)
+	if !IsOneGT(&out) {
+		t.Fatal("expected accumulated checks to verify")
+	}
+
+	var empty MillerLoopResult
+	FinalExp(&out, &empty)
+	if !IsOneGT(&out) {
+		t.Fatal("expected empty product to be the identity")
+	}
+
+	var bad MillerLoopResult
+	var b1 G1Point
+	MulG1(&b1, &GenG1, RandomFr())
+	PairingsMillerLoop(&bad, &GenG1, &GenG2, &b1, &GenG2)
+	MulMillerLoop(&acc, &acc, &bad)
+	FinalExp(&out, &acc)
+	if IsOneGT(&out) {
+		t.Fatal("expected invalid check to fail the product")
+	}
+
+	var single MillerLoopResult
+	MillerLoop(&single, &GenG1, &GenG2)
+	var viaLoop, direct GT
+	FinalExp(&viaLoop, &single)
+	Pairing(&direct, &GenG1, &GenG2)
+	if !EqualGT(&viaLoop, &direct) || IsOneGT(&direct) {
+		t.Fatal("expected final exponentiation of the Miller loop to be the pairing")
+	}
+}
+
+func TestLinCombG2(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 40} {
+		points := make([]G2Point, n)
+		factors := make([]Fr, n)
+		var expected, tmp G2Point
+		ClearG2(&expected)
+		for i := 0; i < n; i++ {
+			MulG2(&points[i], &GenG2, RandomFr())
+			CopyFr(&factors[i], RandomFr())
+			MulG2(&tmp, &points[i], &factors[i])
+			AddG2(&expected, &expected, &tmp)
+		}
+		if got := LinCombG2(points, factors); !EqualG2(got, &expected) {
+			t.Fatalf("unexpected linear combination of %d points", n)
+		}
+	}
+}
+
+func TestToCompressedG1Batch(t *testing.T) {
+	// sums of points, which are not normalized, and the point at infinity
+	points := make([]G1Point, 3*compressG1BatchChunk+5)
+	CopyG1(&points[0], &GenG1)
+	for i := 1; i < len(points); i++ {
+		AddG1(&points[i], &points[i-1], &GenG1)
+	}
+	CopyG1(&points[7], &ZeroG1)
+	before := append([]G1Point(nil), points...)
+	for _, workers := range []int{0, 1, 3} {
+		for _, n := range []int{0, 1, 10, len(points)} {
+			got := ToCompressedG1Batch(points[:n], workers)
+			if len(got) != n {
+				t.Fatalf("got %d encodings of %d points", len(got), n)
+			}
+			for i := range got {
+				p := points[i]
+				if !bytes.Equal(got[i][:], ToCompressedG1(&p)) {
+					t.Fatalf("%d workers, %d points: point %d encodes differently", workers, n, i)
+				}
+			}
+		}
+	}
+	for i := range points {
+		if points[i] != before[i] {
+			t.Fatalf("point %d was modified", i)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package bls
+
+import "sync"
+
+// G2FixedBaseTable holds precomputed multiples of a fixed G2 base, to speed up repeated scalar multiplications
+// of that same base, like the multiplications of the generator when verifying proofs.
+//
+// For every byte position j of the scalar and every digit d, d * 2**(8*j) * B is stored,
+// so that a multiplication only needs one mixed addition per byte, and no doublings.
+// The table holds 32*255 points, about 2.3MB.
+type G2FixedBaseTable struct {
+	// points[j*255+d-1] = d * 2**(8*j) * base, in affine form
+	points []G2Point
+}
+
+// NewG2FixedBaseTable precomputes the window table for the given base.
+func NewG2FixedBaseTable(base *G2Point) *G2FixedBaseTable {
+	points := make([]G2Point, linCombTableWindows*255)
+	var windowBase G2Point
+	CopyG2(&windowBase, base)
+	for j := 0; j < linCombTableWindows; j++ {
+		row := points[j*255 : (j+1)*255]
+		CopyG2(&row[0], &windowBase)
+		for d := 1; d < 255; d++ {
+			AddG2(&row[d], &row[d-1], &windowBase)
+		}
+		// 256 * windowBase
+		AddG2(&windowBase, &row[254], &windowBase)
+	}
+	normalizeG2s(points)
+	return &G2FixedBaseTable{points: points}
+}
+
+// Mul sets dst to scalar * base. This is not constant-time: the table lookups depend on the scalar.
+func (t *G2FixedBaseTable) Mul(dst *G2Point, scalar *Fr) {
+	var out G2Point
+	ClearG2(&out)
+	digits := FrTo32(scalar)
+	for j, d := range digits {
+		if d != 0 {
+			AddG2(&out, &out, &t.points[j*255+int(d)-1])
+		}
+	}
+	CopyG2(dst, &out)
+}
+
+var (
+	genG2Table     *G2FixedBaseTable
+	genG2TableOnce sync.Once
+)
+
+// MulGenG2 sets dst to scalar * GenG2, with a window table of the generator built on first use.
+func MulGenG2(dst *G2Point, scalar *Fr) {
+	genG2TableOnce.Do(func() {
+		genG2Table = NewG2FixedBaseTable(&GenG2)
+	})
+	genG2Table.Mul(dst, scalar)
+}
@@ -0,0 +1,47 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package bls
+
+import "testing"
+
+func TestG2FixedBaseTable(t *testing.T) {
+	var minusOne Fr
+	SubModFr(&minusOne, &ZERO, &ONE)
+	scalars := []Fr{ZERO, ONE, minusOne}
+	for i := 0; i < 10; i++ {
+		scalars = append(scalars, *RandomFr())
+	}
+	var base G2Point
+	MulG2(&base, &GenG2, RandomFr())
+	table := NewG2FixedBaseTable(&base)
+	for i := range scalars {
+		var expected, got G2Point
+		MulG2(&expected, &base, &scalars[i])
+		table.Mul(&got, &scalars[i])
+		if !EqualG2(&expected, &got) {
+			t.Fatalf("scalar %d: table multiplication does not match", i)
+		}
+		MulG2(&expected, &GenG2, &scalars[i])
+		MulGenG2(&got, &scalars[i])
+		if !EqualG2(&expected, &got) {
+			t.Fatalf("scalar %d: generator multiplication does not match", i)
+		}
+	}
+}
+
+func BenchmarkMulGenG2(b *testing.B) {
+	scalar := RandomFr()
+	var out G2Point
+	b.Run("plain", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MulG2(&out, &GenG2, scalar)
+		}
+	})
+	MulGenG2(&out, scalar)
+	b.Run("table", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MulGenG2(&out, scalar)
+		}
+	})
+}
@@ -103,10 +103,17 @@ func EvalPolyAtUnoptimized(dst *Fr, coeffs []Fr, x *Fr) {
 //
 // Scale is used to indicate the power of 2 to use to stride through the domain values.
 // Note: scale == 0 when the roots of unity length matches the polynomial.
+// When x is in the domain, the formula is undefined, and the evaluation is the matching element of the polynomial.
 func EvaluatePolyInEvaluationForm(yFr *Fr, poly []Fr, x *Fr, rootsOfUnity []Fr, scale uint8) {
 	if len(poly) != len(rootsOfUnity)>>scale {
 		panic(fmt.Errorf("expected roots of unity (len %d >> %d == %d) to match polynomial size (len %d)", len(rootsOfUnity), scale, len(rootsOfUnity)>>scale, len(poly)))
 	}
+	for i := range poly {
+		if EqualFr(x, &rootsOfUnity[i<<scale]) {
+			CopyFr(yFr, &poly[i])
+			return
+		}
+	}
 
 	width := big.NewInt(int64(len(poly)))
 	var widthFr Fr
@@ -121,7 +128,7 @@ func EvaluatePolyInEvaluationForm(yFr *Fr, poly []Fr, x *Fr, rootsOfUnity []Fr,
 		SubModFr(&invDenom[i], x, &rootsOfUnity[i<<scale])
 	}
 	// now each value becomes 1 / (x - DOMAIN[i])
-	BatchInvModFr(invDenom)
+	BatchInvModFr(invDenom, invDenom)
 
 	// sum_(i=0)^WIDTH  (f(DOMAIN[i]) * DOMAIN[i]) / (x - DOMAIN[i])
 	var y Fr
@@ -0,0 +1,208 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package bls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Number of 8-bit windows needed to cover a 32-byte scalar.
+const linCombTableWindows = 32
+
+// G1LinCombTable holds precomputed multiples of a fixed list of G1 bases,
+// to speed up repeated linear combinations over those same bases (fixed-base MSM).
+//
+// For every base B_i and every byte position j of the scalar, 2**(8*j) * B_i is stored,
+// so that a linear combination only needs additions into 255 buckets, and no doublings.
+// The table takes 32 times the memory of the bases themselves.
+type G1LinCombTable struct {
+	n int
+	// points[i*linCombTableWindows+j] = 2**(8*j) * bases[i], in affine form
+	points []G1Point
+}
+
+// NewG1LinCombTable precomputes the window table for the given bases.
+func NewG1LinCombTable(bases []G1Point) *G1LinCombTable {
+	points := make([]G1Point, len(bases)*linCombTableWindows)
+	for i := range bases {
+		row := points[i*linCombTableWindows : (i+1)*linCombTableWindows]
+		CopyG1(&row[0], &bases[i])
+		for j := 1; j < linCombTableWindows; j++ {
+			CopyG1(&row[j], &row[j-1])
+			for k := 0; k < 8; k++ {
+				AddG1(&row[j], &row[j], &row[j])
+			}
+		}
+	}
+	normalizeG1s(points)
+	return &G1LinCombTable{n: len(bases), points: points}
+}
+
+// Len returns the number of bases covered by the table.
+func (t *G1LinCombTable) Len() int {
+	return t.n
+}
+
+// Base returns the i'th base the table was built from.
+func (t *G1LinCombTable) Base(i int) *G1Point {
+	return &t.points[i*linCombTableWindows]
+}
+
+// LinComb computes the linear combination of the table bases with the given factors,
+// equivalent to LinCombG1(bases, factors).
+func (t *G1LinCombTable) LinComb(factors []Fr) *G1Point {
+	var out G1Point
+	t.LinCombScratch(&out, factors, nil)
+	return &out
+}
+
+// LinCombScratch is LinComb, writing the result to out and using scratch for the scalar digits
+// if it is large enough. The (possibly grown) scratch space is returned, to be passed to the next call.
+func (t *G1LinCombTable) LinCombScratch(out *G1Point, factors []Fr, scratch []byte) []byte {
+	defer StartTraceRegion("kzg msm").End()
+	if len(factors) != t.n {
+		panic("got G1LinCombTable bases/factors length mismatch")
+	}
+	size := len(factors) * linCombTableWindows
+	if cap(scratch) < size {
+		scratch = make([]byte, size)
+	}
+	digits := scratch[:size]
+	for i := range factors {
+		// little-endian, every byte is a digit of the matching window
+		b := FrTo32(&factors[i])
+		copy(digits[i*linCombTableWindows:], b[:])
+	}
+	sumDigitsG1(out, t.points, digits)
+	return scratch
+}
+
+// WriteTo writes the table in a raw (uncompressed, unchecked subgroup) format.
+// The output is only meant to be read back by ReadG1LinCombTable, with the same BLS backend.
+func (t *G1LinCombTable) WriteTo(w io.Writer) (int64, error) {
+	var header [4]byte
+	binary.LittleEndian.PutUint32(header[:], uint32(t.n))
+	n, err := w.Write(header[:])
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+	for i := range t.points {
+		n, err := w.Write(g1ToRawBytes(&t.points[i]))
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// ReadG1LinCombTable reads a table previously written with WriteTo, of at most maxBases bases,
+// so that a corrupted header cannot make it allocate more than the caller expects.
+// The points are checked to be on the curve, but not to be in the right subgroup:
+// callers should only load tables from trusted storage, or check the table against a trusted setup with CheckBases.
+func ReadG1LinCombTable(r io.Reader, maxBases int) (*G1LinCombTable, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("failed to read table header: %v", err)
+	}
+	n := uint64(binary.LittleEndian.Uint32(header[:]))
+	if n == 0 {
+		return nil, errors.New("table has no bases")
+	}
+	if maxBases < 0 || n > uint64(maxBases) {
+		return nil, fmt.Errorf("table has %d bases, expected at most %d", n, maxBases)
+	}
+	points := make([]G1Point, n*linCombTableWindows)
+	buf := make([]byte, g1RawSize)
+	for i := range points {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("failed to read table point %d: %v", i, err)
+		}
+		if err := g1FromRawBytes(&points[i], buf); err != nil {
+			return nil, fmt.Errorf("invalid table point %d: %v", i, err)
+		}
+	}
+	return &G1LinCombTable{n: int(n), points: points}, nil
+}
+
+// CheckBases checks that the table was built from the given bases: every precomputed multiple is recomputed
+// and compared, not only the bases themselves, so that a table with tampered windows is rejected too.
+// This costs about as much as building the table.
+func (t *G1LinCombTable) CheckBases(bases []G1Point) error {
+	if t.n != len(bases) {
+		return fmt.Errorf("table has %d bases, expected %d", t.n, len(bases))
+	}
+	var expected G1Point
+	for i := range bases {
+		row := t.points[i*linCombTableWindows : (i+1)*linCombTableWindows]
+		CopyG1(&expected, &bases[i])
+		for j := range row {
+			if j > 0 {
+				for k := 0; k < 8; k++ {
+					AddG1(&expected, &expected, &expected)
+				}
+			}
+			if !EqualG1(&row[j], &expected) {
+				return fmt.Errorf("table point %d of base %d does not match", j, i)
+			}
+		}
+	}
+	return nil
+}
+
+// Header of a table memory image: magic (8 bytes), MemoryLayout (48 bytes, zero padded), number of bases (8 bytes).
+const (
+	linCombTableImageMagic      = "KZGLCT01"
+	linCombTableImageHeaderSize = 64
+)
+
+// WriteMemoryImage writes the table as a raw memory image, see G1LinCombTableFromMemoryImage.
+// Unlike WriteTo, the image can only be read back on a machine with the same MemoryLayout.
+func (t *G1LinCombTable) WriteMemoryImage(w io.Writer) (int64, error) {
+	layout := MemoryLayout()
+	if len(layout) > 48 {
+		return 0, fmt.Errorf("memory layout %q is too long", layout)
+	}
+	var header [linCombTableImageHeaderSize]byte
+	copy(header[:8], linCombTableImageMagic)
+	copy(header[8:56], layout)
+	binary.LittleEndian.PutUint64(header[56:], uint64(t.n))
+	n, err := w.Write(header[:])
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+	n, err = w.Write(G1PointsMemory(t.points))
+	return total + int64(n), err
+}
+
+// G1LinCombTableFromMemoryImage uses a table image written by WriteMemoryImage in place, without copying
+// or validating the points. This is meant for memory-mapped files, so that processes share the table through
+// the page cache, and only the pages that are used are read. The data must come from trusted storage,
+// and must not be modified by others or unmapped while the table is in use.
+func G1LinCombTableFromMemoryImage(data []byte) (*G1LinCombTable, error) {
+	if len(data) < linCombTableImageHeaderSize || string(data[:8]) != linCombTableImageMagic {
+		return nil, errors.New("not a table memory image")
+	}
+	if layout := string(bytes.TrimRight(data[8:56], "\x00")); layout != MemoryLayout() {
+		return nil, fmt.Errorf("table memory layout %q does not match %q", layout, MemoryLayout())
+	}
+	n := binary.LittleEndian.Uint64(data[56:64])
+	if n == 0 {
+		return nil, errors.New("table has no bases")
+	}
+	points, err := G1PointsFromMemory(data[linCombTableImageHeaderSize:])
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(points)) != n*linCombTableWindows {
+		return nil, fmt.Errorf("table has %d points, expected %d", len(points), n*linCombTableWindows)
+	}
+	return &G1LinCombTable{n: int(n), points: points}, nil
+}
@@ -0,0 +1,87 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package bls
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testLinCombInputs(n int) ([]G1Point, []Fr) {
+	bases := make([]G1Point, n)
+	factors := make([]Fr, n)
+	for i := 0; i < n; i++ {
+		MulG1(&bases[i], &GenG1, RandomFr())
+		CopyFr(&factors[i], RandomFr())
+	}
+	// edge cases: zero and max factors
+	CopyFr(&factors[0], &ZERO)
+	CopyFr(&factors[n-1], &MODULUS_MINUS1)
+	return bases, factors
+}
+
+func TestG1LinCombTable(t *testing.T) {
+	bases, factors := testLinCombInputs(17)
+	expected := LinCombG1(bases, factors)
+	table := NewG1LinCombTable(bases)
+	if table.Len() != len(bases) {
+		t.Fatalf("unexpected table length %d", table.Len())
+	}
+	if got := table.LinComb(factors); !EqualG1(got, expected) {
+		t.Fatalf("table lincomb mismatch:\n%s\n%s", StrG1(got), StrG1(expected))
+	}
+
+	var buf bytes.Buffer
+	if _, err := table.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	loaded, err := ReadG1LinCombTable(bytes.NewReader(data), len(bases))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.LinComb(factors); !EqualG1(got, expected) {
+		t.Fatalf("loaded table lincomb mismatch:\n%s\n%s", StrG1(got), StrG1(expected))
+	}
+	if err := loaded.CheckBases(bases); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadG1LinCombTable(bytes.NewReader(data), len(bases)-1); err == nil {
+		t.Fatal("expected table with too many bases to fail")
+	}
+	if _, err := ReadG1LinCombTable(bytes.NewReader([]byte{1, 0, 0, 0, 42}), 1); err == nil {
+		t.Fatal("expected truncated table to fail")
+	}
+
+	// a table with the right bases, but a tampered window
+	CopyG1(&table.points[3], &table.points[2])
+	if err := table.CheckBases(bases); err == nil {
+		t.Fatal("expected tampered table window to fail")
+	}
+}
+
+func TestG1LinCombTableMemoryImage(t *testing.T) {
+	bases, factors := testLinCombInputs(5)
+	expected := LinCombG1(bases, factors)
+	var buf bytes.Buffer
+	if _, err := NewG1LinCombTable(bases).WriteMemoryImage(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	loaded, err := G1LinCombTableFromMemoryImage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.LinComb(factors); !EqualG1(got, expected) {
+		t.Fatalf("image table lincomb mismatch:\n%s\n%s", StrG1(got), StrG1(expected))
+	}
+	if _, err := G1LinCombTableFromMemoryImage(data[:len(data)-8]); err == nil {
+		t.Fatal("expected truncated image to fail")
+	}
+	other := append([]byte{}, data...)
+	other[8] ^= 1
+	if _, err := G1LinCombTableFromMemoryImage(other); err == nil {
+		t.Fatal("expected mismatched memory layout to fail")
+	}
+}
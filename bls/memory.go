@@ -0,0 +1,71 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// MemoryLayout identifies the in-memory representation of points: the curve backend, the architecture,
+// and the size of the point types. Raw memory images of points can only be used with the same layout.
+func MemoryLayout() string {
+	return fmt.Sprintf("%s-%s-g1:%d-g2:%d", CurveBackend, runtime.GOARCH,
+		unsafe.Sizeof(G1Point{}), unsafe.Sizeof(G2Point{}))
+}
+
+// G1PointsMemory returns the raw memory of the points, without copying, see G1PointsFromMemory.
+func G1PointsMemory(points []G1Point) []byte {
+	if len(points) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&points[0])), len(points)*int(unsafe.Sizeof(G1Point{})))
+}
+
+// G1PointsFromMemory interprets data written by G1PointsMemory as points, without copying or validating them,
+// e.g. to use a memory-mapped file shared between processes. The data must have the layout of MemoryLayout,
+// come from trusted storage, and not be modified by others while the points are in use.
+// The memory must be writable: some backends normalize the bases of an MSM in place.
+func G1PointsFromMemory(data []byte) ([]G1Point, error) {
+	size := int(unsafe.Sizeof(G1Point{}))
+	if err := checkMemory(data, size, unsafe.Alignof(G1Point{})); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return unsafe.Slice((*G1Point)(unsafe.Pointer(&data[0])), len(data)/size), nil
+}
+
+// G2PointsMemory returns the raw memory of the points, without copying, see G2PointsFromMemory.
+func G2PointsMemory(points []G2Point) []byte {
+	if len(points) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&points[0])), len(points)*int(unsafe.Sizeof(G2Point{})))
+}
+
+// G2PointsFromMemory is G1PointsFromMemory for G2 points.
+func G2PointsFromMemory(data []byte) ([]G2Point, error) {
+	size := int(unsafe.Sizeof(G2Point{}))
+	if err := checkMemory(data, size, unsafe.Alignof(G2Point{})); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return unsafe.Slice((*G2Point)(unsafe.Pointer(&data[0])), len(data)/size), nil
+}
+
+func checkMemory(data []byte, size int, align uintptr) error {
+	if len(data)%size != 0 {
+		return fmt.Errorf("data length %d is not a multiple of the point size %d", len(data), size)
+	}
+	if len(data) > 0 && uintptr(unsafe.Pointer(&data[0]))%align != 0 {
+		return errors.New("data is not aligned for points")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package bls
+
+import (
+	"errors"
+	"sync"
+)
+
+// Number of elements of the output accumulated over all the vectors at once by the blocked linear combinations,
+// so that the block of the output stays in cache.
+const polyLinCombBlock = 256
+
+func checkPolyLinComb(vectors [][]Fr, scalars []Fr, vlen int) error {
+	if len(scalars) != len(vectors) {
+		return errors.New("scalars should have same length as input vectors")
+	}
+	for _, v := range vectors {
+		if len(v) != vlen {
+			return errors.New("input vectors should all be of identical length")
+		}
+	}
+	return nil
+}
+
+// polyLinCombRange adds sum(scalars[j] * vectors[j][i]) to dst[i], for i in [from, to).
+func polyLinCombRange(dst []Fr, vectors [][]Fr, scalars []Fr, from, to int) {
+	var tmp Fr
+	for j, v := range vectors {
+		s := &scalars[j]
+		for i := from; i < to; i++ {
+			MulModFr(&tmp, s, &v[i])
+			AddModFr(&dst[i], &dst[i], &tmp)
+		}
+	}
+}
+
+// PolyLinCombAccumulate adds the linear combination of the vectors with the scalars to dst in place,
+// which must have the length of the vectors. It allocates nothing.
+func PolyLinCombAccumulate(dst []Fr, vectors [][]Fr, scalars []Fr) error {
+	if err := checkPolyLinComb(vectors, scalars, len(dst)); err != nil {
+		return err
+	}
+	for from := 0; from < len(dst); from += polyLinCombBlock {
+		to := from + polyLinCombBlock
+		if to > len(dst) {
+			to = len(dst)
+		}
+		polyLinCombRange(dst, vectors, scalars, from, to)
+	}
+	return nil
+}
+
+// PolyLinCombParallel is PolyLinComb, computing blocks of the output on up to the given number of goroutines.
+// Every block is accumulated over all the vectors at once, so that it stays in cache.
+func PolyLinCombParallel(vectors [][]Fr, scalars []Fr, workers int) ([]Fr, error) {
+	if len(vectors) == 0 {
+		return nil, errors.New("input vectors can't be empty")
+	}
+	vlen := len(vectors[0])
+	if err := checkPolyLinComb(vectors, scalars, vlen); err != nil {
+		return nil, err
+	}
+	r := make([]Fr, vlen)
+	blocks := (vlen + polyLinCombBlock - 1) / polyLinCombBlock
+	if workers > blocks {
+		workers = blocks
+	}
+	if workers <= 1 {
+		return r, PolyLinCombAccumulate(r, vectors, scalars)
+	}
+	jobs := make(chan int, blocks)
+	for b := 0; b < blocks; b++ {
+		jobs <- b
+	}
+	close(jobs)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				to := (b + 1) * polyLinCombBlock
+				if to > vlen {
+					to = vlen
+				}
+				polyLinCombRange(r, vectors, scalars, b*polyLinCombBlock, to)
+			}
+		}()
+	}
+	wg.Wait()
+	return r, nil
+}
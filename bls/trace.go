@@ -0,0 +1,67 @@
+package bls
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync/atomic"
+)
+
+// tracing is 1 when the hot paths of this module are traced, see SetTracing.
+var tracing int32
+
+// SetTracing enables or disables the tracing of the hot paths of this module: MSMs, pairings, FFTs, hashing,
+// and the operations of the packages on top of this one, e.g. "kzg commit", "kzg verify" or "kzg fk20".
+// Sections are recorded as runtime/trace regions, visible in `go tool trace`, and operations also set the pprof
+// label "kzg" of their goroutine, so that CPU profiles can be filtered by operation. Disabled, the tracing costs
+// an atomic load per section.
+func SetTracing(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&tracing, v)
+}
+
+// Tracing returns whether tracing is enabled, see SetTracing.
+func Tracing() bool {
+	return atomic.LoadInt32(&tracing) != 0
+}
+
+// TraceRegion is a traced section of work, see StartTraceRegion.
+type TraceRegion struct {
+	region  *trace.Region
+	labeled bool
+}
+
+// StartTraceRegion starts a runtime/trace region of the given name when tracing is enabled.
+// It must be ended on the same goroutine.
+func StartTraceRegion(name string) TraceRegion {
+	if atomic.LoadInt32(&tracing) == 0 {
+		return TraceRegion{}
+	}
+	return TraceRegion{region: trace.StartRegion(context.Background(), name)}
+}
+
+// StartTraceOperation is StartTraceRegion, also setting the pprof label "kzg" of the goroutine to the name
+// of the operation, which the goroutines it starts inherit. Go does not expose the labels of a goroutine,
+// so they are cleared when the operation ends, rather than restored: operations must not nest,
+// and callers labeling their own goroutines should do so again after the call.
+func StartTraceOperation(name string) TraceRegion {
+	if atomic.LoadInt32(&tracing) == 0 {
+		return TraceRegion{}
+	}
+	pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels("kzg", name)))
+	return TraceRegion{region: trace.StartRegion(context.Background(), name), labeled: true}
+}
+
+// End ends the region.
+func (r TraceRegion) End() {
+	if r.region == nil {
+		return
+	}
+	r.region.End()
+	if r.labeled {
+		pprof.SetGoroutineLabels(context.Background())
+	}
+}
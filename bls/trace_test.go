@@ -0,0 +1,36 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package bls
+
+import (
+	"bytes"
+	"runtime/trace"
+	"testing"
+)
+
+func TestTracing(t *testing.T) {
+	bases, factors := testLinCombInputs(8)
+	expected := LinCombG1(bases, factors)
+
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skipf("tracing is unavailable: %v", err)
+	}
+	SetTracing(true)
+	defer SetTracing(false)
+	if !Tracing() {
+		t.Fatal("tracing is not enabled")
+	}
+	op := StartTraceOperation("kzg test")
+	got := LinCombG1(bases, factors)
+	op.End()
+	trace.Stop()
+
+	if !EqualG1(expected, got) {
+		t.Fatal("traced MSM differs")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("kzg msm")) || !bytes.Contains(buf.Bytes(), []byte("kzg test")) {
+		t.Fatal("trace does not contain the regions")
+	}
+}
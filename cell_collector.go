@@ -0,0 +1,187 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Cell is a column of an extended line of data: the extension of a polynomial of degree < n to 2n evaluations,
+// in reverse bit order as DAUsingFK20Multi orders its proofs, split into cells of the chunk length
+// of the FK20 settings. Cell i holds the evaluations [i*chunkLen, (i+1)*chunkLen) of that order.
+type Cell struct {
+	Index uint64
+	Data  []bls.Fr
+	// Proof is the multi-proof of the cell, the proof of the same index of DAUsingFK20Multi.
+	Proof bls.G1Point
+}
+
+// CheckCellProof checks the proof of a cell of the line of data committed to.
+func (ks *FK20MultiSettings) CheckCellProof(commitment *bls.G1Point, cell *Cell) bool {
+	cellCount := ks.n2 / ks.chunkLen
+	if cell.Index >= cellCount || uint64(len(cell.Data)) != ks.chunkLen {
+		return false
+	}
+	x := ks.cellCosetShift(cell.Index)
+	ys := make([]bls.Fr, ks.chunkLen, ks.chunkLen)
+	for i := range ys {
+		bls.CopyFr(&ys[i], &cell.Data[i])
+	}
+	reverseBitOrderFr(ys)
+	return ks.CheckProofMulti(commitment, &cell.Proof, x, ys)
+}
+
+// CellCollector collects the cells of an extended line of data (see Cell) one at a time, and recovers
+// the line once it has half of the cells, the least to recover from: all the missing cells are reconstructed,
+// with their proofs computed, see CellProofs, and the recovered data is passed to the callback of the collector.
+// The cells are expected to be verified already, e.g. with CheckCellProof.
+// A collector is safe for concurrent use.
+type CellCollector struct {
+	fk          *FK20MultiSettings
+	onRecovered func(blob []bls.Fr, cells []Cell)
+
+	mu        sync.Mutex
+	cells     []*Cell
+	count     uint64
+	recovered bool
+}
+
+// NewCellCollector creates a collector for the cells of a line of data extended with the FK20 settings,
+// of n2/chunkLen cells. onRecovered is called once, after the recovery, from the AddCell call that reaches
+// half of the cells, with the original data (the first half of the extended line, i.e. the polynomial
+// in evaluation form in reverse bit order) and all the cells in order.
+func NewCellCollector(fk *FK20MultiSettings, onRecovered func(blob []bls.Fr, cells []Cell)) *CellCollector {
+	return &CellCollector{
+		fk:          fk,
+		onRecovered: onRecovered,
+		cells:       make([]*Cell, fk.n2/fk.chunkLen),
+	}
+}
+
+// AddCell adds a cell. A cell that is already known, or added after the recovery, is ignored.
+// An error is returned for a malformed cell, or if the recovery fails, which means that the cells
+// are not of the same line of data.
+func (c *CellCollector) AddCell(cell Cell) error {
+	if cell.Index >= uint64(len(c.cells)) {
+		return fmt.Errorf("cell index %d is out of range for %d cells", cell.Index, len(c.cells))
+	}
+	if uint64(len(cell.Data)) != c.fk.chunkLen {
+		return fmt.Errorf("cell %d has %d elements, expected %d", cell.Index, len(cell.Data), c.fk.chunkLen)
+	}
+	c.mu.Lock()
+	if c.recovered || c.cells[cell.Index] != nil {
+		c.mu.Unlock()
+		return nil
+	}
+	stored := &Cell{Index: cell.Index, Data: make([]bls.Fr, len(cell.Data))}
+	for i := range cell.Data {
+		bls.CopyFr(&stored.Data[i], &cell.Data[i])
+	}
+	bls.CopyG1(&stored.Proof, &cell.Proof)
+	c.cells[cell.Index] = stored
+	c.count++
+	if 2*c.count < uint64(len(c.cells)) {
+		c.mu.Unlock()
+		return nil
+	}
+	blob, cells, err := c.recover()
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.recovered = true
+	c.mu.Unlock()
+	if c.onRecovered != nil {
+		c.onRecovered(blob, cells)
+	}
+	return nil
+}
+
+// Has returns whether the cell of the given index is known, received or recovered.
+func (c *CellCollector) Has(index uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return index < uint64(len(c.cells)) && c.cells[index] != nil
+}
+
+// Missing returns the indices of the cells that are not known yet, in increasing order.
+func (c *CellCollector) Missing() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]uint64, 0, uint64(len(c.cells))-c.count)
+	for i, cell := range c.cells {
+		if cell == nil {
+			out = append(out, uint64(i))
+		}
+	}
+	return out
+}
+
+// Recovered returns whether all the cells are known.
+func (c *CellCollector) Recovered() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recovered
+}
+
+// recover reconstructs the missing cells, of which there are at most half, and fills them in.
+// It must be called with the lock held.
+func (c *CellCollector) recover() ([]bls.Fr, []Cell, error) {
+	fk := c.fk
+	n2, chunkLen := fk.n2, fk.chunkLen
+	// the samples of the recovery are in natural order
+	samples := make([]*bls.Fr, n2, n2)
+	for _, cell := range c.cells {
+		if cell == nil {
+			continue
+		}
+		for i := range cell.Data {
+			samples[reverseBitsLimited(uint32(n2), uint32(cell.Index*chunkLen+uint64(i)))] = &cell.Data[i]
+		}
+	}
+	extended, err := fk.RecoverPolyFromSamples(samples, fk.ZeroPolynomial)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to recover the cells: %v", err)
+	}
+	coeffs, err := fk.FFT(extended, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := n2 / 2; i < n2; i++ {
+		if !bls.EqualZero(&coeffs[i]) {
+			return nil, nil, fmt.Errorf("recovered data is not the extension of a polynomial of degree < %d", n2/2)
+		}
+	}
+	// only the proofs of the missing cells are computed, the received ones were verified
+	var missing []uint64
+	for i := range c.cells {
+		if c.cells[i] == nil {
+			missing = append(missing, uint64(i))
+		}
+	}
+	proofs, err := fk.CellProofs(coeffs[:n2/2], missing)
+	if err != nil {
+		return nil, nil, err
+	}
+	reverseBitOrderFr(extended)
+
+	for j, i := range missing {
+		cell := &Cell{Index: i, Data: extended[i*chunkLen : (i+1)*chunkLen]}
+		bls.CopyG1(&cell.Proof, &proofs[j])
+		c.cells[i] = cell
+	}
+	cells := make([]Cell, len(c.cells))
+	for i := range cells {
+		cells[i] = *c.cells[i]
+	}
+	c.count = uint64(len(c.cells))
+	blob := make([]bls.Fr, n2/2, n2/2)
+	for i := range blob {
+		bls.CopyFr(&blob[i], &extended[i])
+	}
+	return blob, cells, nil
+}
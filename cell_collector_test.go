@@ -0,0 +1,97 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestCellCollector(t *testing.T) {
+	fs := NewFFTSettings(8)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 1<<8)
+	ks := NewKZGSettings(fs, s1, s2)
+	n := uint64(64)
+	chunkLen := uint64(4)
+	fk := NewFK20MultiSettings(ks, n*2, chunkLen)
+
+	polynomial := make([]bls.Fr, n, n)
+	for i := range polynomial {
+		bls.CopyFr(&polynomial[i], bls.RandomFr())
+	}
+	commitment := ks.CommitToPoly(polynomial)
+	extended := make([]bls.Fr, n*2, n*2)
+	for i := uint64(0); i < n; i++ {
+		bls.CopyFr(&extended[i], &polynomial[i])
+	}
+	for i := n; i < n*2; i++ {
+		bls.CopyFr(&extended[i], &bls.ZERO)
+	}
+	if err := fs.FFTInPlace(extended, false); err != nil {
+		t.Fatal(err)
+	}
+	reverseBitOrderFr(extended)
+	proofs := fk.DAUsingFK20Multi(polynomial)
+	cellCount := n * 2 / chunkLen
+	cells := make([]Cell, cellCount)
+	for i := range cells {
+		cells[i] = Cell{Index: uint64(i), Data: extended[uint64(i)*chunkLen : uint64(i+1)*chunkLen]}
+		bls.CopyG1(&cells[i].Proof, &proofs[i])
+		if !fk.CheckCellProof(commitment, &cells[i]) {
+			t.Fatalf("cell %d does not verify", i)
+		}
+	}
+
+	var calls int
+	var recoveredBlob []bls.Fr
+	var recoveredCells []Cell
+	c := NewCellCollector(fk, func(blob []bls.Fr, cells []Cell) {
+		calls++
+		recoveredBlob, recoveredCells = blob, cells
+	})
+	order := rand.New(rand.NewSource(1)).Perm(int(cellCount))
+	for i, index := range order[:cellCount/2] {
+		if c.Recovered() {
+			t.Fatalf("recovered after %d cells", i)
+		}
+		if err := c.AddCell(cells[index]); err != nil {
+			t.Fatal(err)
+		}
+		// duplicates are ignored
+		if err := c.AddCell(cells[index]); err != nil {
+			t.Fatal(err)
+		}
+		if !c.Has(uint64(index)) {
+			t.Fatalf("expected cell %d to be present", index)
+		}
+		if i+1 < int(cellCount/2) && len(c.Missing()) != int(cellCount)-(i+1) {
+			t.Fatalf("expected %d missing cells, got %d", int(cellCount)-(i+1), len(c.Missing()))
+		}
+	}
+	if !c.Recovered() || calls != 1 || len(c.Missing()) != 0 {
+		t.Fatalf("expected a single recovery of all the cells, got %d calls and %d missing cells", calls, len(c.Missing()))
+	}
+	for i := range recoveredBlob {
+		if !bls.EqualFr(&recoveredBlob[i], &extended[i]) {
+			t.Fatalf("recovered blob differs at %d", i)
+		}
+	}
+	for i := range recoveredCells {
+		if !fk.CheckCellProof(commitment, &recoveredCells[i]) {
+			t.Fatalf("recovered cell %d does not verify", i)
+		}
+	}
+	if err := c.AddCell(cells[order[cellCount-1]]); err != nil || calls != 1 {
+		t.Fatal("expected cells after the recovery to be ignored")
+	}
+
+	if err := NewCellCollector(fk, nil).AddCell(Cell{Index: cellCount, Data: cells[0].Data}); err == nil {
+		t.Fatal("expected an out of range cell index to be rejected")
+	}
+	if err := NewCellCollector(fk, nil).AddCell(Cell{Index: 0, Data: cells[0].Data[:1]}); err == nil {
+		t.Fatal("expected a cell of the wrong size to be rejected")
+	}
+}
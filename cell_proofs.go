@@ -0,0 +1,81 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// cellCosetShift returns the shift x of the coset of the cell of the given index: the cell holds
+// the evaluations at x*w**j, for the chunkLen-th roots of unity w, in reverse bit order.
+func (ks *FK20MultiSettings) cellCosetShift(index uint64) *bls.Fr {
+	cellCount := ks.n2 / ks.chunkLen
+	domainPos := reverseBitsLimited(uint32(cellCount), uint32(index))
+	return &ks.ExpandedRootsOfUnity[uint64(domainPos)*(ks.MaxWidth/ks.n2)]
+}
+
+// cellProof computes the proof of a single cell, the commitment to the quotient of the polynomial
+// by the vanishing polynomial X**chunkLen - x**chunkLen of the coset of the cell.
+func (ks *FK20MultiSettings) cellProof(polynomial []bls.Fr, index uint64) bls.G1Point {
+	l := int(ks.chunkLen)
+	n := len(polynomial)
+	var proof bls.G1Point
+	if n <= l {
+		// the polynomial is its own remainder, and the proof the identity
+		bls.ClearG1(&proof)
+		return proof
+	}
+	var xPow bls.Fr
+	bls.CopyFr(&xPow, ks.cellCosetShift(index))
+	for s := 1; s < l; s <<= 1 {
+		bls.MulModFr(&xPow, &xPow, &xPow)
+	}
+	// q_i = p_(i+l) + x**l * q_(i+l), from the top coefficient down
+	quotient := make([]bls.Fr, n-l)
+	var tmp bls.Fr
+	for i := n - l - 1; i >= 0; i-- {
+		bls.CopyFr(&quotient[i], &polynomial[i+l])
+		if i+l < n-l {
+			bls.MulModFr(&tmp, &quotient[i+l], &xPow)
+			bls.AddModFr(&quotient[i], &quotient[i], &tmp)
+		}
+	}
+	bls.CopyG1(&proof, bls.LinCombG1(ks.SecretG1[:len(quotient)], quotient))
+	return proof
+}
+
+// cellProofsDirectLimit is the number of cells up to which CellProofs proves the cells one at a time,
+// rather than all of them with FK20, which costs about as much as 6 to 9 single cell proofs for 128 cells.
+const cellProofsDirectLimit = 4
+
+// CellProofs computes the proofs of the cells of the given indices, as DAUsingFK20Multi computes them,
+// of the line of data extended from the polynomial in coefficient form. This is for the proofs of the cells that
+// were missing before a recovery, while the proofs of the other cells, already verified, are kept: a few cells
+// are proven one at a time, with a division and a multi-scalar multiplication each, and more with FK20,
+// of which only the proofs of the cells are kept.
+func (ks *FK20MultiSettings) CellProofs(polynomial []bls.Fr, indices []uint64) ([]bls.G1Point, error) {
+	cellCount := ks.n2 / ks.chunkLen
+	if uint64(len(polynomial)) != ks.n2/2 {
+		return nil, fmt.Errorf("polynomial has %d coefficients, expected %d", len(polynomial), ks.n2/2)
+	}
+	for _, index := range indices {
+		if index >= cellCount {
+			return nil, fmt.Errorf("cell index %d is out of range for %d cells", index, cellCount)
+		}
+	}
+	out := make([]bls.G1Point, len(indices))
+	if len(indices) <= cellProofsDirectLimit {
+		for i, index := range indices {
+			out[i] = ks.cellProof(polynomial, index)
+		}
+		return out, nil
+	}
+	all := ks.DAUsingFK20Multi(polynomial)
+	for i, index := range indices {
+		bls.CopyG1(&out[i], &all[index])
+	}
+	return out, nil
+}
@@ -0,0 +1,75 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestCellProofs(t *testing.T) {
+	fs := NewFFTSettings(8)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 1<<8)
+	ks := NewKZGSettings(fs, s1, s2)
+	n := uint64(64)
+	fk := NewFK20MultiSettings(ks, n*2, 4)
+
+	polynomial := make([]bls.Fr, n, n)
+	for i := range polynomial {
+		bls.CopyFr(&polynomial[i], bls.RandomFr())
+	}
+	expected := fk.DAUsingFK20Multi(polynomial)
+	cellCount := uint64(len(expected))
+	// one at a time, and with FK20
+	for _, indices := range [][]uint64{{0, 5, cellCount - 1}, {1, 2, 3, 7, 11, 13, 17, 19, 23, 29}} {
+		proofs, err := fk.CellProofs(polynomial, indices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, index := range indices {
+			if !bls.EqualG1(&proofs[i], &expected[index]) {
+				t.Fatalf("proof of cell %d of %d cells mismatch", index, len(indices))
+			}
+		}
+	}
+	if _, err := fk.CellProofs(polynomial, []uint64{cellCount}); err == nil {
+		t.Fatal("expected out of range cell to be rejected")
+	}
+	if _, err := fk.CellProofs(polynomial[:n/2], []uint64{0}); err == nil {
+		t.Fatal("expected polynomial of the wrong size to be rejected")
+	}
+}
+
+func TestCellProofsSingleChunk(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 1<<4)
+	ks := NewKZGSettings(fs, s1, s2)
+	n := uint64(4)
+	// a chunk as long as the polynomial: every cell is its own remainder, with the identity as proof
+	fk := NewFK20MultiSettings(ks, n*2, n)
+
+	polynomial := make([]bls.Fr, n, n)
+	for i := range polynomial {
+		bls.CopyFr(&polynomial[i], bls.RandomFr())
+	}
+	commitment := ks.CommitToPoly(polynomial)
+	proofs, err := fk.CellProofs(polynomial, []uint64{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stride := ks.MaxWidth / n
+	for i := range proofs {
+		x := fk.cellCosetShift(uint64(i))
+		ys := make([]bls.Fr, n, n)
+		for j := uint64(0); j < n; j++ {
+			var z bls.Fr
+			bls.MulModFr(&z, x, &ks.ExpandedRootsOfUnity[j*stride])
+			bls.EvalPolyAt(&ys[j], polynomial, &z)
+		}
+		if !ks.CheckProofMulti(commitment, &proofs[i], x, ys) {
+			t.Fatalf("could not verify proof of cell %d", i)
+		}
+	}
+}
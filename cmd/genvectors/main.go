@@ -0,0 +1,62 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+// Command genvectors generates KZG test vectors in the layout of the consensus-spec-tests
+// (general/deneb/kzg/<handler>/<suite>/<case>/data.yaml), with the outputs computed by this module,
+// for cross-implementation testing. Invalid inputs are included, with a null output.
+//
+// Usage:
+//
+//	genvectors -out ./tests/general/deneb/kzg [-setup trusted_setup.json] [-suite kzg-mainnet] [-format json,yaml] [-seed 0]
+//
+// Without -setup, the embedded mainnet trusted setup is used.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/protolambda/go-kzg/eth"
+	"github.com/protolambda/go-kzg/eth/testvectors"
+)
+
+func main() {
+	out := flag.String("out", "", "output directory of the handlers")
+	setup := flag.String("setup", "", "trusted setup in JSON format, instead of the embedded mainnet setup")
+	suite := flag.String("suite", "kzg-mainnet", "name of the test suite directory")
+	format := flag.String("format", "json,yaml", "comma-separated output formats: json, yaml")
+	seed := flag.Int64("seed", 0, "seed of the random inputs")
+	flag.Parse()
+	if *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*out, *setup, *suite, strings.Split(*format, ","), *seed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(out, setup, suite string, formats []string, seed int64) error {
+	ctx := eth.DefaultContext()
+	if setup != "" {
+		data, err := os.ReadFile(setup)
+		if err != nil {
+			return err
+		}
+		if ctx, err = eth.NewContextFromJSON(data); err != nil {
+			return err
+		}
+	}
+	cases, err := testvectors.Generate(ctx, seed)
+	if err != nil {
+		return fmt.Errorf("failed to generate test vectors: %v", err)
+	}
+	if err := testvectors.WriteTestCases(out, suite, cases, formats...); err != nil {
+		return fmt.Errorf("failed to write test vectors: %v", err)
+	}
+	fmt.Printf("wrote %d test cases to %s\n", len(cases), out)
+	return nil
+}
@@ -0,0 +1,192 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// DAS2DSettings extends a matrix of Rows x Cols field elements (e.g. one blob per row) into the 2D Reed-Solomon
+// extension of 2*Rows x 2*Cols elements, as in Danksharding, with commitments and proofs for every cell.
+//
+// Rows and columns are in evaluation form over the roots of unity, in natural order: the extended row i holds
+// the evaluations of the row polynomial at the 2*Cols-th roots of unity, and likewise for the columns.
+// The original element (i, j) ends up at (2*i, 2*j) of the extended matrix. Every cell has a proof against its row commitment and its column commitment.
+type DAS2DSettings struct {
+	*KZGSettings
+	Rows uint64
+	Cols uint64
+
+	rowFK *FK20SingleSettings
+	colFK *FK20SingleSettings
+}
+
+// NewDAS2DSettings prepares the extension of a matrix of rows x cols elements. Both must be powers of two,
+// and at least 2. The KZG settings must support twice the largest of both.
+func NewDAS2DSettings(ks *KZGSettings, rows uint64, cols uint64) *DAS2DSettings {
+	if !bls.IsPowerOfTwo(rows) || !bls.IsPowerOfTwo(cols) || rows < 2 || cols < 2 {
+		panic("rows and cols must be powers of two, and at least 2")
+	}
+	if 2*rows > ks.MaxWidth || 2*cols > ks.MaxWidth {
+		panic("extended matrix is larger than kzg settings supports")
+	}
+	return &DAS2DSettings{
+		KZGSettings: ks,
+		Rows:        rows,
+		Cols:        cols,
+		rowFK:       NewFK20SingleSettings(ks, 2*cols),
+		colFK:       NewFK20SingleSettings(ks, 2*rows),
+	}
+}
+
+// Extended2D is the 2D extension of a matrix, see DAS2DSettings.
+type Extended2D struct {
+	// 2*Rows x 2*Cols extended data
+	Data [][]bls.Fr
+	// Commitments to the 2*Rows row polynomials
+	RowCommitments []bls.G1Point
+	// Commitments to the 2*Cols column polynomials
+	ColumnCommitments []bls.G1Point
+	// RowProofs[i][j] proves Data[i][j] against RowCommitments[i]
+	RowProofs [][]bls.G1Point
+	// ColumnProofs[i][j] proves Data[i][j] against ColumnCommitments[j]
+	ColumnProofs [][]bls.G1Point
+}
+
+// Sample2D is a single cell of the extended matrix, with its proofs.
+type Sample2D struct {
+	Row         uint64
+	Col         uint64
+	Value       bls.Fr
+	RowProof    bls.G1Point
+	ColumnProof bls.G1Point
+}
+
+// Sample returns the cell at the given position of the extended matrix.
+func (e *Extended2D) Sample(row uint64, col uint64) *Sample2D {
+	s := &Sample2D{Row: row, Col: col}
+	bls.CopyFr(&s.Value, &e.Data[row][col])
+	bls.CopyG1(&s.RowProof, &e.RowProofs[row][col])
+	bls.CopyG1(&s.ColumnProof, &e.ColumnProofs[row][col])
+	return s
+}
+
+// extendLine interpolates the n values over the n-th roots of unity, and returns the coefficients of the polynomial
+// padded with n zeroes, and its evaluations over the 2n-th roots of unity.
+func (ds *DAS2DSettings) extendLine(vals []bls.Fr) (paddedCoeffs []bls.Fr, extended []bls.Fr, err error) {
+	n := uint64(len(vals))
+	coeffs, err := ds.FFT(vals, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	paddedCoeffs = make([]bls.Fr, 2*n)
+	for i := uint64(0); i < n; i++ {
+		bls.CopyFr(&paddedCoeffs[i], &coeffs[i])
+	}
+	for i := n; i < 2*n; i++ {
+		bls.CopyFr(&paddedCoeffs[i], &bls.ZERO)
+	}
+	extended, err = ds.FFT(paddedCoeffs, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return paddedCoeffs, extended, nil
+}
+
+// Extend computes the 2D extension of the Rows x Cols matrix, with all the commitments and proofs.
+func (ds *DAS2DSettings) Extend(data [][]bls.Fr) (*Extended2D, error) {
+	if uint64(len(data)) != ds.Rows {
+		return nil, fmt.Errorf("expected %d rows, got %d", ds.Rows, len(data))
+	}
+	rows2, cols2 := 2*ds.Rows, 2*ds.Cols
+	e := &Extended2D{
+		Data:              make([][]bls.Fr, rows2),
+		RowCommitments:    make([]bls.G1Point, rows2),
+		ColumnCommitments: make([]bls.G1Point, cols2),
+		RowProofs:         make([][]bls.G1Point, rows2),
+		ColumnProofs:      make([][]bls.G1Point, rows2),
+	}
+	for i := range e.ColumnProofs {
+		e.ColumnProofs[i] = make([]bls.G1Point, cols2)
+	}
+	// extend the original rows horizontally
+	horizontal := make([][]bls.Fr, ds.Rows)
+	for i := uint64(0); i < ds.Rows; i++ {
+		if uint64(len(data[i])) != ds.Cols {
+			return nil, fmt.Errorf("row %d: expected %d elements, got %d", i, ds.Cols, len(data[i]))
+		}
+		_, extended, err := ds.extendLine(data[i])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %v", i, err)
+		}
+		horizontal[i] = extended
+	}
+	for i := range e.Data {
+		e.Data[i] = make([]bls.Fr, cols2)
+	}
+	// extend every column vertically, the original rows end up at the even rows
+	col := make([]bls.Fr, ds.Rows)
+	for j := uint64(0); j < cols2; j++ {
+		for i := uint64(0); i < ds.Rows; i++ {
+			bls.CopyFr(&col[i], &horizontal[i][j])
+		}
+		paddedCoeffs, extended, err := ds.extendLine(col)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: %v", j, err)
+		}
+		for i := uint64(0); i < rows2; i++ {
+			bls.CopyFr(&e.Data[i][j], &extended[i])
+		}
+		bls.CopyG1(&e.ColumnCommitments[j], ds.CommitToPoly(paddedCoeffs[:ds.Rows]))
+		proofs := ds.colFK.FK20SingleDAOptimized(paddedCoeffs)
+		for i := uint64(0); i < rows2; i++ {
+			bls.CopyG1(&e.ColumnProofs[i][j], &proofs[i])
+		}
+	}
+	// all the rows, original or extended, are polynomials of degree < Cols
+	for i := uint64(0); i < rows2; i++ {
+		coeffs, err := ds.FFT(e.Data[i], true)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %v", i, err)
+		}
+		bls.CopyG1(&e.RowCommitments[i], ds.CommitToPoly(coeffs[:ds.Cols]))
+		e.RowProofs[i] = ds.rowFK.FK20SingleDAOptimized(coeffs)
+	}
+	return e, nil
+}
+
+// root returns the i-th power of the n-th root of unity.
+func (ds *DAS2DSettings) root(i uint64, n uint64) *bls.Fr {
+	return &ds.ExpandedRootsOfUnity[i*(ds.MaxWidth/n)]
+}
+
+// VerifySample checks a cell against the commitments of its row and its column.
+func (ds *DAS2DSettings) VerifySample(rowCommitment *bls.G1Point, columnCommitment *bls.G1Point, s *Sample2D) bool {
+	if s.Row >= 2*ds.Rows || s.Col >= 2*ds.Cols {
+		return false
+	}
+	return ds.CheckProofSingle(rowCommitment, &s.RowProof, ds.root(s.Col, 2*ds.Cols), &s.Value) &&
+		ds.CheckProofSingle(columnCommitment, &s.ColumnProof, ds.root(s.Row, 2*ds.Rows), &s.Value)
+}
+
+// VerifyRowCommitments checks that the 2*Rows row commitments are a valid extension of the original ones,
+// i.e. that they are the evaluations of a polynomial of degree < Rows with coefficients in G1.
+// Sampling nodes can use it to check the commitments before sampling.
+func (ds *DAS2DSettings) VerifyRowCommitments(commitments []bls.G1Point) bool {
+	if uint64(len(commitments)) != 2*ds.Rows {
+		return false
+	}
+	coeffs, err := ds.FFTG1(commitments, true)
+	if err != nil {
+		return false
+	}
+	for i := ds.Rows; i < 2*ds.Rows; i++ {
+		if !bls.EqualG1(&coeffs[i], &bls.ZeroG1) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestDAS2DSettings_Extend(t *testing.T) {
+	rows, cols := uint64(4), uint64(8)
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16)
+	ks := NewKZGSettings(fs, s1, s2)
+	ds := NewDAS2DSettings(ks, rows, cols)
+
+	data := make([][]bls.Fr, rows)
+	for i := range data {
+		data[i] = make([]bls.Fr, cols)
+		for j := range data[i] {
+			bls.CopyFr(&data[i][j], bls.RandomFr())
+		}
+	}
+	ext, err := ds.Extend(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the original data is at the even positions
+	for i := uint64(0); i < rows; i++ {
+		for j := uint64(0); j < cols; j++ {
+			if !bls.EqualFr(&ext.Data[2*i][2*j], &data[i][j]) {
+				t.Fatalf("original data missing at row %d col %d", i, j)
+			}
+		}
+	}
+	if !ds.VerifyRowCommitments(ext.RowCommitments) {
+		t.Fatal("expected row commitments to be a valid extension")
+	}
+	for i := uint64(0); i < 2*rows; i++ {
+		for j := uint64(0); j < 2*cols; j++ {
+			s := ext.Sample(i, j)
+			if !ds.VerifySample(&ext.RowCommitments[i], &ext.ColumnCommitments[j], s) {
+				t.Fatalf("sample at row %d col %d does not verify", i, j)
+			}
+		}
+	}
+
+	s := ext.Sample(3, 5)
+	bls.AddModFr(&s.Value, &s.Value, &bls.ONE)
+	if ds.VerifySample(&ext.RowCommitments[3], &ext.ColumnCommitments[5], s) {
+		t.Fatal("expected modified sample to fail")
+	}
+	if ds.VerifySample(&ext.RowCommitments[2], &ext.ColumnCommitments[5], ext.Sample(3, 5)) {
+		t.Fatal("expected sample to fail against another row")
+	}
+
+	bls.AddG1(&ext.RowCommitments[7], &ext.RowCommitments[7], &bls.GenG1)
+	if ds.VerifyRowCommitments(ext.RowCommitments) {
+		t.Fatal("expected modified row commitments to fail")
+	}
+}
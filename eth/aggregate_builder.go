@@ -0,0 +1,103 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// AggregateProofBuilder computes the aggregate proof of compute_aggregate_kzg_proof incrementally,
+// as the blobs of a block are produced: every added blob is validated and absorbed into the Fiat-Shamir hash
+// right away, leaving only the linear combination and the proof to Finalize.
+//
+// The number of blobs is hashed before the blobs, so it must be known upfront. The linear combination depends
+// on the challenge, i.e. on all the blobs, so the polynomials are kept until Finalize.
+// A builder must not be used concurrently.
+type AggregateProofBuilder struct {
+	ctx         *Context
+	h           *Transcript
+	count       int
+	polys       Polynomials
+	commitments KZGCommitmentSequenceImpl
+	finalized   bool
+}
+
+// NewAggregateProofBuilder returns a builder for the aggregate proof of count blobs.
+func (ctx *Context) NewAggregateProofBuilder(count int) (*AggregateProofBuilder, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("cannot aggregate %d blobs", count)
+	}
+	b := &AggregateProofBuilder{
+		ctx:         ctx,
+		h:           ctx.newTranscript(false),
+		count:       count,
+		polys:       make(Polynomials, 0, count),
+		commitments: make(KZGCommitmentSequenceImpl, 0, count),
+	}
+	ctx.absorbAggregateHeader(b.h, count)
+	return b, nil
+}
+
+// NewAggregateProofBuilder calls NewAggregateProofBuilder on the default context.
+func NewAggregateProofBuilder(count int) (*AggregateProofBuilder, error) {
+	return defaultContext().NewAggregateProofBuilder(count)
+}
+
+// Add absorbs the next blob, with its commitment. The commitment is only checked to be a valid point:
+// it must be the commitment to the blob for the proof to verify.
+func (b *AggregateProofBuilder) Add(blob Blob, commitment KZGCommitment) error {
+	i := len(b.polys)
+	if b.finalized {
+		return errors.New("aggregate proof is already finalized")
+	}
+	if i >= b.count {
+		return fmt.Errorf("all the %d blobs have been added", b.count)
+	}
+	n := blob.Len()
+	if n != b.ctx.FieldElementsPerBlob() {
+		return fmt.Errorf("%w: blob %d has %d field elements, expected %d", ErrWrongBlobLength, i, n, b.ctx.FieldElementsPerBlob())
+	}
+	poly := make(Polynomial, n)
+	for j := 0; j < n; j++ {
+		if !bls.FrFrom32(&poly[j], blob.At(j)) {
+			return fmt.Errorf("blob %d: field element %d: %w", i, j, ErrNonCanonicalScalar)
+		}
+	}
+	if _, err := b.ctx.decodeCommitment(commitment); err != nil {
+		return fmt.Errorf("blob %d: %w: %v", i, ErrInvalidCommitment, err)
+	}
+	b.ctx.absorbAggregatePolynomial(b.h, i, poly)
+	b.polys = append(b.polys, poly)
+	b.commitments = append(b.commitments, commitment)
+	return nil
+}
+
+// Finalize computes the aggregate proof, once all the blobs have been added.
+// It is the same proof as ComputeAggregateKZGProof over all the blobs.
+func (b *AggregateProofBuilder) Finalize() (KZGProof, error) {
+	if b.finalized {
+		return KZGProof{}, errors.New("aggregate proof is already finalized")
+	}
+	if len(b.polys) != b.count {
+		return KZGProof{}, fmt.Errorf("only %d of the %d blobs have been added", len(b.polys), b.count)
+	}
+	b.finalized = true
+	for i, c := range b.commitments {
+		absorbAggregateCommitment(b.h, i, c)
+	}
+	r := b.h.challenge(false)
+	powers := ComputePowers(r, len(b.polys))
+	var evaluationChallenge bls.Fr
+	bls.MulModFr(&evaluationChallenge, r, &powers[len(powers)-1])
+	aggregatedPoly, err := bls.PolyLinCombParallel(b.polys, powers, MaxWorkers())
+	if err != nil {
+		return KZGProof{}, err
+	}
+	// the polynomials are not needed anymore
+	b.polys = nil
+	return b.ctx.ComputeKZGProof(aggregatedPoly, &evaluationChallenge)
+}
@@ -0,0 +1,79 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+)
+
+func TestAggregateProofBuilder(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		blobs = append(blobs, polynomialToBlob(poly))
+		commitments = append(commitments, ctx.PolynomialToKZGCommitment(poly))
+	}
+	b, err := ctx.NewAggregateProofBuilder(len(blobs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range blobs {
+		if _, err := b.Finalize(); err == nil {
+			t.Fatal("expected finalizing before all the blobs are added to fail")
+		}
+		if err := b.Add(blobs[i], commitments[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Add(blobs[0], commitments[0]); err == nil {
+		t.Fatal("expected an extra blob to be rejected")
+	}
+	proof, err := b.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := ctx.ComputeAggregateKZGProof(blobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof != expected {
+		t.Fatal("expected the same proof as ComputeAggregateKZGProof")
+	}
+	ok, err := ctx.VerifyAggregateKZGProof(blobs, commitments, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected the aggregate proof to verify")
+	}
+	if _, err := b.Finalize(); err == nil {
+		t.Fatal("expected a second Finalize to fail")
+	}
+}
+
+func TestAggregateProofBuilderInvalidInput(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	if _, err := ctx.NewAggregateProofBuilder(0); err == nil {
+		t.Fatal("expected empty aggregation to be rejected")
+	}
+	b, err := ctx.NewAggregateProofBuilder(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poly := randomPolynomialN(16)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	if err := b.Add(polynomialToBlob(randomPolynomialN(8)), commitment); err == nil {
+		t.Fatal("expected blob of the wrong size to be rejected")
+	}
+	blob := polynomialToBlob(poly)
+	blob[2] = [32]byte{31: 0xff}
+	if err := b.Add(blob, commitment); err == nil {
+		t.Fatal("expected non-canonical field element to be rejected")
+	}
+	if err := b.Add(polynomialToBlob(poly), KZGCommitment{0x80, 47: 0x01}); err == nil {
+		t.Fatal("expected invalid commitment to be rejected")
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"math/bits"
+
+	"github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// fk20Settings returns the FK20 settings over the domain of the context. The Toeplitz products of FK20
+// are over twice the domain, so the settings are built on FFT settings of twice the width.
+func (ctx *Context) fk20Settings() *kzg.FK20SingleSettings {
+	ctx.fk20Once.Do(func() {
+		n := uint64(len(ctx.domain))
+		fs := kzg.NewFFTSettings(uint8(bits.TrailingZeros64(n)) + 1)
+		// FK20 only uses the first n-1 powers of the G1 setup, the G2 setup is not used
+		ks := &kzg.KZGSettings{FFTSettings: fs, SecretG1: ctx.setupG1, SecretG2: ctx.setupG2}
+		ctx.fk20 = kzg.NewFK20SingleSettings(ks, 2*n)
+	})
+	return ctx.fk20
+}
+
+// ComputeAllKZGProofs computes the proofs of the openings of the polynomial at every point of the domain,
+// with FK20 in O(n log n), instead of n proofs of O(n) each. The proofs are in the order of the elements
+// of the polynomial, i.e. proof i opens the polynomial at the point of element i (see BlobElementPoint),
+// to the value of element i, as ProveBlobElement would. It needs the monomial G1 setup.
+// The FK20 settings are computed on the first call, which takes longer.
+func (ctx *Context) ComputeAllKZGProofs(poly Polynomial) ([]KZGProof, error) {
+	if err := ctx.checkSetupG1(len(ctx.domain)); err != nil {
+		return nil, err
+	}
+	coeffs, err := ctx.PolynomialToCoefficients(poly)
+	if err != nil {
+		return nil, err
+	}
+	// the proofs at the roots of unity in natural order, while the domain is in bit-reversed order
+	proofs := bitReversalPermutation(ctx.fk20Settings().FK20Single(coeffs))
+	encoded := bls.ToCompressedG1Batch(proofs, MaxWorkers())
+	out := make([]KZGProof, len(encoded))
+	for i := range encoded {
+		out[i] = encoded[i]
+	}
+	return out, nil
+}
+
+// ComputeAllKZGProofs calls ComputeAllKZGProofs on the default context.
+func ComputeAllKZGProofs(poly Polynomial) ([]KZGProof, error) {
+	return defaultContext().ComputeAllKZGProofs(poly)
+}
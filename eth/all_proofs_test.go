@@ -0,0 +1,44 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestComputeAllKZGProofs(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	blob := polynomialToBlob(poly)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	proofs, err := ctx.ComputeAllKZGProofs(poly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proofs) != 16 {
+		t.Fatalf("expected 16 proofs, got %d", len(proofs))
+	}
+	for i := range proofs {
+		value, proof, err := ctx.ProveBlobElement(blob, uint64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proofs[i] != proof {
+			t.Fatalf("proof %d differs from the single proof", i)
+		}
+		if ok, err := ctx.VerifyBlobElement(commitment, uint64(i), value, proofs[i]); err != nil || !ok {
+			t.Fatalf("proof %d does not verify: %v", i, err)
+		}
+	}
+
+	if _, err := ctx.ComputeAllKZGProofs(poly[:8]); !errors.Is(err, ErrWrongBlobLength) {
+		t.Fatalf("expected wrong blob length error, got %v", err)
+	}
+	verifierCtx := newTestContext(t, 4)
+	verifierCtx.setupG1 = nil
+	if _, err := verifierCtx.ComputeAllKZGProofs(poly); !errors.Is(err, ErrSetupNotLoaded) {
+		t.Fatalf("expected setup error, got %v", err)
+	}
+}
@@ -0,0 +1,375 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// AggregateProofBatch is the input of verify_aggregate_kzg_proof for a single block.
+type AggregateProofBatch struct {
+	Blobs       BlobSequence
+	Commitments KZGCommitmentSequence
+	Proof       KZGProof
+}
+
+// VerifyAggregateKZGProofBatch verifies the aggregate proofs of many blocks at once, e.g. during sync or backfill.
+// It returns true only if all of the proofs are valid, like calling VerifyAggregateKZGProof on each batch,
+// but with a constant number of pairings, by combining all the checks with random scalars.
+func (ctx *Context) VerifyAggregateKZGProofBatch(batches []AggregateProofBatch) (bool, error) {
+	if err := ctx.checkOpeningLimit(len(batches)); err != nil {
+		return false, err
+	}
+	commitments := make([]bls.G1Point, len(batches))
+	proofs := make([]bls.G1Point, len(batches))
+	zs := make([]bls.Fr, len(batches))
+	ys := make([]bls.Fr, len(batches))
+	for i := range batches {
+		b := &batches[i]
+		if err := checkBlockBlobCounts(b.Blobs.Len(), b.Commitments.Len()); err != nil {
+			return false, fmt.Errorf("batch %d: %w", i, err)
+		}
+		polynomials, err := ctx.blobsToPolynomials(b.Blobs)
+		if err != nil {
+			return false, fmt.Errorf("batch %d: %w", i, err)
+		}
+		aggregatedPoly, aggregatedPolyCommitment, evaluationChallenge, err :=
+			ctx.ComputeAggregatedPolyAndCommitment(polynomials, b.Commitments)
+		if err != nil {
+			return false, fmt.Errorf("batch %d: %w", i, err)
+		}
+		// decodeProof takes a copy of the proof, as cgo backends must not be passed pointers into the batches,
+		// which hold Go pointers
+		proof, err := ctx.decodeProof(b.Proof)
+		if err != nil {
+			return false, fmt.Errorf("batch %d: %w", i, err)
+		}
+		bls.CopyG1(&commitments[i], aggregatedPolyCommitment)
+		bls.CopyG1(&proofs[i], proof)
+		bls.CopyFr(&zs[i], evaluationChallenge)
+		bls.CopyFr(&ys[i], ctx.EvaluatePolynomialInEvaluationForm(aggregatedPoly, evaluationChallenge))
+	}
+	return ctx.verifyKZGProofBatch(commitments, zs, ys, proofs, nil)
+}
+
+// VerifyAggregateKZGProofBatch calls VerifyAggregateKZGProofBatch on the default context.
+func VerifyAggregateKZGProofBatch(batches []AggregateProofBatch) (bool, error) {
+	return defaultContext().VerifyAggregateKZGProofBatch(batches)
+}
+
+// VerifyBlobKZGProofBatch implements verify_blob_kzg_proof_batch from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
+// It returns true only if every proof is valid for its blob and commitment, with a constant number of pairings.
+// Batches larger than the maximum batch size are split into chunks, see SetMaxBatchSize.
+func (ctx *Context) VerifyBlobKZGProofBatch(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (bool, error) {
+	return ctx.verifyBlobKZGProofBatch(blobs, commitments, proofs, nil)
+}
+
+// VerifyBlobKZGProofBatch calls VerifyBlobKZGProofBatch on the default context.
+func VerifyBlobKZGProofBatch(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (bool, error) {
+	return defaultContext().VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+}
+
+// FindInvalidBlobKZGProof is VerifyBlobKZGProofBatch, returning the index of the first invalid proof,
+// or -1 if all of them are valid, so that a failure can be attributed to a specific blob, e.g. for peer scoring.
+// The combined check is done first; only if it fails are the proofs verified one by one,
+// spreading the work over a pool of workers (see SetMaxWorkers). With a maximum batch size (see SetMaxBatchSize),
+// the combined check is done per chunk, and only the proofs of the failing chunks are verified one by one.
+func (ctx *Context) FindInvalidBlobKZGProof(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (int, error) {
+	n, err := ctx.checkBlobKZGProofBatch(blobs, commitments, proofs, nil)
+	if err != nil {
+		return -1, err
+	}
+	chunks := ctx.batchChunks(n)
+	invalid := make([]int, len(chunks))
+	errs := make([]error, len(chunks))
+	parallelFor(len(chunks), func(c int) {
+		invalid[c], errs[c] = ctx.findInvalidBlobKZGProof(blobs, commitments, proofs, chunks[c][0], chunks[c][1])
+	})
+	for c := range chunks {
+		if errs[c] != nil {
+			return -1, errs[c]
+		}
+	}
+	for c := range chunks {
+		if invalid[c] >= 0 {
+			return invalid[c], nil
+		}
+	}
+	return -1, nil
+}
+
+// findInvalidBlobKZGProof is FindInvalidBlobKZGProof over the blobs [start, end) of the batch.
+func (ctx *Context) findInvalidBlobKZGProof(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence,
+	start, end int) (int, error) {
+	o, err := ctx.blobKZGProofBatchOpenings(blobs, commitments, proofs, start, end, nil)
+	if err != nil {
+		return -1, err
+	}
+	ok, err := ctx.verifyKZGProofBatch(o.commitments, o.zs, o.ys, o.proofs, o.first)
+	if err != nil {
+		return -1, err
+	}
+	if ok {
+		return -1, nil
+	}
+	n := len(o.commitments)
+	valid := make([]bool, n)
+	parallelFor(n, func(i int) {
+		valid[i] = ctx.VerifyKZGProofFromPoints(&o.commitments[i], &o.zs[i], &o.ys[i], &o.proofs[i])
+	})
+	// a dropped duplicate is only invalid if its first occurrence, which comes before it, is invalid
+	for i, ok := range valid {
+		if !ok {
+			return o.indices[i], nil
+		}
+	}
+	// only possible if the random combination rejected valid proofs, which has negligible probability
+	return -1, errors.New("batch check failed but all proofs are valid")
+}
+
+// FindInvalidBlobKZGProof calls FindInvalidBlobKZGProof on the default context.
+func FindInvalidBlobKZGProof(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (int, error) {
+	return defaultContext().FindInvalidBlobKZGProof(blobs, commitments, proofs)
+}
+
+// batchOpenings are the decoded openings of a batch, after deduplication.
+type batchOpenings struct {
+	commitments []bls.G1Point
+	zs, ys      []bls.Fr
+	proofs      []bls.G1Point
+	// first[i] is the index of the first opening with the same commitment as opening i, see verifyKZGProofBatchWith
+	first []int
+	// indices[i] is the index in the batch of opening i, as duplicate openings are dropped
+	indices []int
+}
+
+// firstOccurrences returns, for each of the n keys, the index of the first equal key.
+func firstOccurrences(n int, key func(i int) interface{}) []int {
+	first := make([]int, n)
+	seen := make(map[interface{}]int, n)
+	for i := 0; i < n; i++ {
+		k := key(i)
+		if j, ok := seen[k]; ok {
+			first[i] = j
+		} else {
+			seen[k] = i
+			first[i] = i
+		}
+	}
+	return first
+}
+
+func equalPolynomials(a, b Polynomial) bool {
+	for i := range a {
+		if !bls.EqualFr(&a[i], &b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkBlobKZGProofBatch checks the lengths of a blob proof batch, and its size against the limits of the context
+// and the options, nil for the defaults, and returns the number of blobs.
+func (ctx *Context) checkBlobKZGProofBatch(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence,
+	opts *VerifyOpts) (int, error) {
+	n := blobs.Len()
+	if commitments.Len() != n || proofs.Len() != n {
+		return 0, fmt.Errorf("%w: %d blobs, %d commitments, %d proofs",
+			ErrLengthMismatch, n, commitments.Len(), proofs.Len())
+	}
+	if err := ctx.checkBlobLimit(n); err != nil {
+		return 0, err
+	}
+	if opts != nil && opts.MaxBlobs > 0 && n > opts.MaxBlobs {
+		return 0, fmt.Errorf("%w: %d blobs, at most %d", ErrTooManyBlobs, n, opts.MaxBlobs)
+	}
+	return n, nil
+}
+
+// blobKZGProofBatchOpenings decodes the commitments and proofs of the blobs [start, end) of a blob proof batch,
+// validated with the options, nil for the defaults, and computes the challenge and evaluation of each blob.
+// The lengths of the batch must have been checked with checkBlobKZGProofBatch.
+// Duplicates, common with re-broadcasts, are coalesced: a commitment is only decoded once, and a repeated
+// (blob, commitment, proof) opening is dropped altogether, since it is valid if and only if its first occurrence is.
+func (ctx *Context) blobKZGProofBatchOpenings(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence,
+	start, end int, opts *VerifyOpts) (*batchOpenings, error) {
+	n := end - start
+	polynomials, err := ctx.blobRangeToPolynomials(blobs, start, end)
+	if err != nil {
+		return nil, err
+	}
+	opts, deferred := ctx.deferredSubgroupOpts(opts)
+	first := firstOccurrences(n, func(i int) interface{} { return commitments.At(start + i) })
+	o := &batchOpenings{
+		commitments: make([]bls.G1Point, 0, n),
+		proofs:      make([]bls.G1Point, 0, n),
+		zs:          make([]bls.Fr, 0, n),
+		ys:          make([]bls.Fr, 0, n),
+		first:       make([]int, 0, n),
+		indices:     make([]int, 0, n),
+	}
+	// kept[i] is the index of the opening of the batch element start+i
+	kept := make([]int, n)
+	for i := 0; i < n; i++ {
+		commitment := commitments.At(start + i)
+		proof := proofs.At(start + i)
+		k := len(o.commitments)
+		kept[i] = k
+		var c bls.G1Point
+		if j := first[i]; j != i {
+			if proof == proofs.At(start+j) && equalPolynomials(polynomials[i], polynomials[j]) {
+				kept[i] = kept[j]
+				continue
+			}
+			bls.CopyG1(&c, &o.commitments[kept[j]])
+			o.first = append(o.first, kept[j])
+		} else {
+			decoded, err := opts.decodeCommitment(ctx, commitment)
+			if err != nil {
+				return nil, fmt.Errorf("blob %d: %w", start+i, err)
+			}
+			bls.CopyG1(&c, decoded)
+			o.first = append(o.first, k)
+		}
+		p, err := opts.decodeProof(ctx, proof)
+		if err != nil {
+			return nil, fmt.Errorf("blob %d: %w", start+i, err)
+		}
+		o.commitments = append(o.commitments, c)
+		o.proofs = append(o.proofs, *p)
+		o.zs = append(o.zs, *ctx.ComputeChallenge(polynomials[i], commitment))
+		o.ys = append(o.ys, *ctx.EvaluatePolynomialInEvaluationForm(polynomials[i], &o.zs[k]))
+		o.indices = append(o.indices, start+i)
+	}
+	if deferred {
+		if err := o.checkSubgroups(); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// VerifyKZGProofBatchFromPoints verifies many openings at once, from already decoded commitments and proofs,
+// for callers that maintain their own caches of decompressed points. It returns true only if every proofs[i]
+// opens commitments[i] to ys[i] at zs[i], with a constant number of pairings.
+func (ctx *Context) VerifyKZGProofBatchFromPoints(commitments []*bls.G1Point, zs, ys []*bls.Fr, proofs []*bls.G1Point) (bool, error) {
+	n := len(commitments)
+	if len(zs) != n || len(ys) != n || len(proofs) != n {
+		return false, fmt.Errorf("%w of the batch", ErrLengthMismatch)
+	}
+	if err := ctx.checkOpeningLimit(n); err != nil {
+		return false, err
+	}
+	defer ctx.reportUsage("VerifyKZGProofBatchFromPoints", 160*n, 3*n+1)
+	commitmentsG1 := make([]bls.G1Point, n)
+	proofsG1 := make([]bls.G1Point, n)
+	zsFr := make([]bls.Fr, n)
+	ysFr := make([]bls.Fr, n)
+	for i := 0; i < n; i++ {
+		bls.CopyG1(&commitmentsG1[i], commitments[i])
+		bls.CopyG1(&proofsG1[i], proofs[i])
+		bls.CopyFr(&zsFr[i], zs[i])
+		bls.CopyFr(&ysFr[i], ys[i])
+	}
+	// the same commitment is commonly passed as the same pointer
+	first := firstOccurrences(n, func(i int) interface{} { return commitments[i] })
+	return ctx.verifyKZGProofBatch(commitmentsG1, zsFr, ysFr, proofsG1, first)
+}
+
+// VerifyKZGProofBatchFromPoints calls VerifyKZGProofBatchFromPoints on the default context.
+func VerifyKZGProofBatchFromPoints(commitments []*bls.G1Point, zs, ys []*bls.Fr, proofs []*bls.G1Point) (bool, error) {
+	return defaultContext().VerifyKZGProofBatchFromPoints(commitments, zs, ys, proofs)
+}
+
+// verifyKZGProofBatch checks that every proof opens its commitment to ys[i] at zs[i].
+// Each check e(C - [y]_1, [1]_2) == e(proof, [tau - z]_2) is rewritten as
+// e(C - [y]_1 + z * proof, [1]_2) == e(proof, [tau]_2), and all of them are summed with random scalars r_i:
+//
+//	e(sum(r_i * (C_i - [y_i]_1 + z_i * proof_i)), [1]_2) == e(sum(r_i * proof_i), [tau]_2)
+//
+// If first is not nil, first[i] is the index of the first commitment equal to commitments[i]:
+// the scalars of equal commitments are summed, so that every commitment is only once in the MSM.
+func (ctx *Context) verifyKZGProofBatch(commitments []bls.G1Point, zs, ys []bls.Fr, proofs []bls.G1Point, first []int) (bool, error) {
+	return ctx.verifyKZGProofBatchWith(new(batchScratch), nil, commitments, zs, ys, proofs, first)
+}
+
+// batchScratch holds the buffers of a batch verification, grown as needed.
+type batchScratch struct {
+	r       []bls.Fr
+	scalars []bls.Fr
+	points  []bls.G1Point
+	// position of the scalar of every commitment
+	positions []int
+}
+
+// verifyKZGProofBatchWith is verifyKZGProofBatch, using the given buffers, and the prepared setup if not nil.
+func (ctx *Context) verifyKZGProofBatchWith(s *batchScratch, prepared *preparedSetup,
+	commitments []bls.G1Point, zs, ys []bls.Fr, proofs []bls.G1Point, first []int) (bool, error) {
+	n := len(commitments)
+	if len(zs) != n || len(ys) != n || len(proofs) != n || (first != nil && len(first) != n) {
+		return false, fmt.Errorf("%w of the batch", ErrLengthMismatch)
+	}
+	if n == 0 {
+		return true, nil
+	}
+	defer bls.StartTraceOperation("kzg verify batch").End()
+	start := time.Now()
+	if cap(s.r) < n {
+		s.r = make([]bls.Fr, n)
+	}
+	r := s.r[:n]
+	for i := range r {
+		bls.CopyFr(&r[i], bls.RandomFr())
+	}
+	if cap(s.positions) < n {
+		s.positions = make([]int, n)
+	}
+	positions := s.positions[:n]
+	// the left side is a single MSM over the distinct commitments, the proofs, and the generator.
+	// The scalars have storage of their own, and are set with CopyFr: with big.Int field elements, appending
+	// r[i] would share its digits, and the accumulation of duplicates below would change r[i] too.
+	points := s.points[:0]
+	if cap(s.scalars) < 2*n+1 {
+		s.scalars = make([]bls.Fr, 2*n+1)
+	}
+	scalars := s.scalars[:0]
+	var sumRY, tmp bls.Fr
+	bls.CopyFr(&sumRY, &bls.ZERO)
+	for i := 0; i < n; i++ {
+		if first != nil && first[i] != i {
+			pos := positions[first[i]]
+			bls.AddModFr(&scalars[pos], &scalars[pos], &r[i])
+		} else {
+			positions[i] = len(points)
+			points = append(points, commitments[i])
+			scalars = scalars[:len(scalars)+1]
+			bls.CopyFr(&scalars[len(scalars)-1], &r[i])
+		}
+		points = append(points, proofs[i])
+		scalars = scalars[:len(scalars)+1]
+		bls.MulModFr(&scalars[len(scalars)-1], &r[i], &zs[i])
+		bls.MulModFr(&tmp, &r[i], &ys[i])
+		bls.AddModFr(&sumRY, &sumRY, &tmp)
+	}
+	points = append(points, bls.GenG1)
+	scalars = scalars[:len(scalars)+1]
+	bls.SubModFr(&scalars[len(scalars)-1], &bls.ZERO, &sumRY)
+	s.points, s.scalars = points, scalars
+
+	msmStart := time.Now()
+	lhs := bls.LinCombG1(points, scalars)
+	rhs := bls.LinCombG1(proofs, r)
+	ctx.metrics.MSM(len(points)+len(proofs), time.Since(msmStart))
+
+	pairingStart := time.Now()
+	ok := ctx.pairingsVerify(prepared, lhs, rhs)
+	ctx.metrics.Pairing(time.Since(pairingStart))
+	ctx.metrics.ProofVerified(time.Since(start), ok)
+	return ok, nil
+}
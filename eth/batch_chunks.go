@@ -0,0 +1,95 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+// SetMaxBatchSize bounds the number of blobs of the combined check of the blob proof batch verifiers,
+// VerifyBlobKZGProofBatch and its variants, and FindInvalidBlobKZGProof. Larger batches, e.g. the thousands of
+// proofs of a historical sync, are split into chunks of at most n blobs, each checked with its own random scalars,
+// spread over the pool of workers (see SetMaxWorkers); the batch is valid if all of its chunks are.
+// This bounds the memory of a verification to the decoded blobs of a chunk per worker, instead of all of them.
+// Duplicates are only coalesced within a chunk. A value of zero or less, the default, does not split batches.
+// It must not be called concurrently with verifications.
+func (ctx *Context) SetMaxBatchSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	ctx.maxBatchSize = n
+}
+
+// SetMaxBatchSize calls SetMaxBatchSize on a copy of the default context, which then replaces it, see Context.
+func SetMaxBatchSize(n int) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetMaxBatchSize(n)
+		return nil
+	})
+}
+
+// MaxBatchSize returns the maximum number of blobs of a combined batch check, zero if unbounded,
+// see SetMaxBatchSize.
+func (ctx *Context) MaxBatchSize() int {
+	return ctx.maxBatchSize
+}
+
+// batchChunks splits a batch of n blobs into the [start, end) ranges of its chunks, see SetMaxBatchSize.
+// A batch that fits is a single chunk, even if empty.
+func (ctx *Context) batchChunks(n int) [][2]int {
+	return splitChunks(n, ctx.maxBatchSize)
+}
+
+// splitChunks splits n items into the [start, end) ranges of chunks of at most size items, unbounded if size
+// is zero or less. Items that fit are a single chunk, even if empty.
+func splitChunks(n, size int) [][2]int {
+	if size <= 0 || n <= size {
+		return [][2]int{{0, n}}
+	}
+	chunks := make([][2]int, 0, (n+size-1)/size)
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		chunks = append(chunks, [2]int{start, end})
+	}
+	return chunks
+}
+
+// verifyBlobKZGProofBatch is VerifyBlobKZGProofBatch with the inputs validated with the options,
+// nil for the defaults, and the chunks of the batch verified in parallel. The first error, by the order
+// of the blobs, is returned, so that the result does not depend on the scheduling of the chunks.
+func (ctx *Context) verifyBlobKZGProofBatch(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence,
+	opts *VerifyOpts) (bool, error) {
+	n, err := ctx.checkBlobKZGProofBatch(blobs, commitments, proofs, opts)
+	if err != nil {
+		return false, err
+	}
+	defer ctx.reportUsage("VerifyBlobKZGProofBatch", n*(ctx.blobBytes()+96), 3*n+1)
+	return ctx.verifyBlobKZGProofChunks(blobs, commitments, proofs, ctx.batchChunks(n), opts)
+}
+
+// verifyBlobKZGProofChunks verifies the chunks of a blob proof batch, of which the lengths were checked
+// with checkBlobKZGProofBatch, in parallel, each with its own combined check.
+func (ctx *Context) verifyBlobKZGProofChunks(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence,
+	chunks [][2]int, opts *VerifyOpts) (bool, error) {
+	valid := make([]bool, len(chunks))
+	errs := make([]error, len(chunks))
+	parallelFor(len(chunks), func(c int) {
+		o, err := ctx.blobKZGProofBatchOpenings(blobs, commitments, proofs, chunks[c][0], chunks[c][1], opts)
+		if err != nil {
+			errs[c] = err
+			return
+		}
+		valid[c], errs[c] = ctx.verifyKZGProofBatch(o.commitments, o.zs, o.ys, o.proofs, o.first)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return false, err
+		}
+	}
+	for _, ok := range valid {
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
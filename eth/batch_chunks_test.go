@@ -0,0 +1,72 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyBlobKZGProofBatchChunks(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	ctx.SetMaxBatchSize(2)
+	if got := ctx.batchChunks(5); len(got) != 3 || got[2] != [2]int{4, 5} {
+		t.Fatalf("unexpected chunks: %v", got)
+	}
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	var proofs KZGProofSequenceImpl
+	for i := 0; i < 5; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, blob)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	verifier := ctx.NewVerifier()
+	for _, verify := range []func(BlobSequence, KZGCommitmentSequence, KZGProofSequence) (bool, error){
+		ctx.VerifyBlobKZGProofBatch, verifier.VerifyBlobKZGProofBatch,
+	} {
+		if ok, err := verify(blobs, commitments, proofs); err != nil || !ok {
+			t.Fatalf("expected chunked batch to verify: %v", err)
+		}
+	}
+	if i, err := ctx.FindInvalidBlobKZGProof(blobs, commitments, proofs); err != nil || i != -1 {
+		t.Fatalf("expected no invalid proof, got %d: %v", i, err)
+	}
+
+	// an invalid proof in the last chunk fails the whole batch
+	invalid := append(KZGProofSequenceImpl(nil), proofs...)
+	invalid[4] = proofs[3]
+	for _, verify := range []func(BlobSequence, KZGCommitmentSequence, KZGProofSequence) (bool, error){
+		ctx.VerifyBlobKZGProofBatch, verifier.VerifyBlobKZGProofBatch,
+	} {
+		if ok, err := verify(blobs, commitments, invalid); err != nil || ok {
+			t.Fatalf("expected chunked batch with an invalid proof to fail: %v", err)
+		}
+	}
+	if i, err := ctx.FindInvalidBlobKZGProof(blobs, commitments, invalid); err != nil || i != 4 {
+		t.Fatalf("expected invalid proof 4, got %d: %v", i, err)
+	}
+
+	// errors report the index of the blob in the batch, not in its chunk
+	malformed := append(testBlobs(nil), blobs...)
+	malformed[3] = polynomialToBlob(randomPolynomialN(16))
+	malformed[3].(testBlob)[5] = [32]byte{0: 0xff, 31: 0xff}
+	_, err := ctx.VerifyBlobKZGProofBatch(malformed, commitments, invalid)
+	if !errors.Is(err, ErrNonCanonicalScalar) || err.Error() != "blob 3: field element 5: non-canonical field element" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := verifier.VerifyBlobKZGProofBatch(malformed, commitments, invalid); !errors.Is(err, ErrNonCanonicalScalar) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ctx.VerifyBlobKZGProofBatchWithOpts(blobs, commitments, proofs, VerifyOpts{MaxBlobs: 4}); !errors.Is(err, ErrTooManyBlobs) {
+		t.Fatalf("expected too many blobs, got %v", err)
+	}
+}
@@ -0,0 +1,319 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+type testBlobs []Blob
+
+func (b testBlobs) Len() int {
+	return len(b)
+}
+
+func (b testBlobs) At(i int) Blob {
+	return b[i]
+}
+
+func TestVerifyAggregateKZGProofBatch(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	batches := make([]AggregateProofBatch, 4)
+	for i := range batches {
+		var blobs testBlobs
+		var commitments KZGCommitmentSequenceImpl
+		for j := 0; j <= i; j++ {
+			poly := randomPolynomialN(16)
+			blobs = append(blobs, polynomialToBlob(poly))
+			commitments = append(commitments, ctx.PolynomialToKZGCommitment(poly))
+		}
+		proof, err := ctx.ComputeAggregateKZGProof(blobs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		batches[i] = AggregateProofBatch{Blobs: blobs, Commitments: commitments, Proof: proof}
+	}
+	ok, err := ctx.VerifyAggregateKZGProofBatch(batches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected batch to verify")
+	}
+
+	// a proof of another block invalidates the whole batch
+	batches[1].Proof, batches[2].Proof = batches[2].Proof, batches[1].Proof
+	ok, err = ctx.VerifyAggregateKZGProofBatch(batches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected batch with swapped proofs to fail")
+	}
+}
+
+func TestVerifyKZGProofBatchFromPoints(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	n := 3
+	commitments := make([]*bls.G1Point, n)
+	proofs := make([]*bls.G1Point, n)
+	zs := make([]*bls.Fr, n)
+	ys := make([]*bls.Fr, n)
+	for i := 0; i < n; i++ {
+		poly := randomPolynomialN(16)
+		zs[i] = bls.RandomFr()
+		ys[i] = ctx.EvaluatePolynomialInEvaluationForm(poly, zs[i])
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeKZGProof(poly, zs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commitments[i], err = bls.FromCompressedG1(commitment[:]); err != nil {
+			t.Fatal(err)
+		}
+		if proofs[i], err = bls.FromCompressedG1(proof[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ok, err := ctx.VerifyKZGProofBatchFromPoints(commitments, zs, ys, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected batch to verify")
+	}
+	zs[0], zs[1] = zs[1], zs[0]
+	ok, err = ctx.VerifyKZGProofBatchFromPoints(commitments, zs, ys, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected batch with swapped points to fail")
+	}
+	if _, err := ctx.VerifyKZGProofBatchFromPoints(commitments, zs, ys, proofs[:1]); err == nil {
+		t.Fatal("expected length mismatch to be rejected")
+	}
+}
+
+func TestAggregateKZGProofInvalidBlob(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	blob := polynomialToBlob(randomPolynomialN(16))
+	blob[5] = [32]byte{0: 0xff, 31: 0xff}
+	blobs := testBlobs{polynomialToBlob(randomPolynomialN(16)), blob}
+	_, err := ctx.ComputeAggregateKZGProof(blobs)
+	if !errors.Is(err, ErrNonCanonicalScalar) || err.Error() != "blob 1: field element 5: non-canonical field element" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = ctx.VerifyAggregateKZGProof(testBlobs{polynomialToBlob(randomPolynomialN(8))}, KZGCommitmentSequenceImpl{{}}, KZGProof{})
+	if !errors.Is(err, ErrWrongBlobLength) || err.Error() != "wrong blob length: blob 0 has 8 field elements, expected 16" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAggregateKZGProofCounts(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs testBlobs
+	var polys Polynomials
+	var commitments KZGCommitmentSequenceImpl
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		blobs = append(blobs, polynomialToBlob(poly))
+		polys = append(polys, poly)
+		commitments = append(commitments, ctx.PolynomialToKZGCommitment(poly))
+	}
+	proof, err := ctx.ComputeAggregateKZGProof(blobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.VerifyAggregateKZGProof(blobs, commitments[:2], proof); !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("expected length mismatch, got %v", err)
+	}
+	if _, err := ctx.VerifyAggregateKZGProofFromPolynomials(polys[:2], commitments, proof); !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("expected length mismatch, got %v", err)
+	}
+	if _, _, _, err := ctx.ComputeAggregatedPolyAndCommitment(polys, commitments[:1]); !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("expected length mismatch, got %v", err)
+	}
+	_, err = ctx.VerifyAggregateKZGProofBatch([]AggregateProofBatch{
+		{Blobs: blobs, Commitments: commitments, Proof: proof},
+		{Blobs: blobs[:1], Commitments: commitments, Proof: proof},
+	})
+	if !errors.Is(err, ErrLengthMismatch) || err.Error() != "batch 1: mismatched lengths: 1 blobs, 3 commitments" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	many := make(testBlobs, MaxBlobsPerBlock+1)
+	manyCommitments := make(KZGCommitmentSequenceImpl, MaxBlobsPerBlock+1)
+	for i := range many {
+		many[i] = blobs[0]
+		manyCommitments[i] = commitments[0]
+	}
+	if _, err := ctx.VerifyAggregateKZGProof(many, manyCommitments, proof); !errors.Is(err, ErrTooManyBlobs) {
+		t.Fatalf("expected too many blobs, got %v", err)
+	}
+}
+
+func TestVerifyBlobKZGProofBatch(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	var proofs KZGProofSequenceImpl
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, blob)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	ok, err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected batch to verify")
+	}
+	ok, err = ctx.VerifyBlobKZGProofBatch(testBlobs{}, KZGCommitmentSequenceImpl{}, KZGProofSequenceImpl{})
+	if err != nil || !ok {
+		t.Fatalf("expected empty batch to verify: %v", err)
+	}
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	ok, err = ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected batch with swapped proofs to fail")
+	}
+	if _, err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs[:2]); err == nil {
+		t.Fatal("expected length mismatch to be rejected")
+	}
+}
+
+func TestFindInvalidBlobKZGProof(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	var proofs KZGProofSequenceImpl
+	for i := 0; i < 5; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, blob)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	i, err := ctx.FindInvalidBlobKZGProof(blobs, commitments, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != -1 {
+		t.Fatalf("expected all proofs to be valid, got %d", i)
+	}
+	proofs[2], proofs[4] = proofs[4], proofs[2]
+	i, err = ctx.FindInvalidBlobKZGProof(blobs, commitments, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != 2 {
+		t.Fatalf("expected proof 2 to be invalid, got %d", i)
+	}
+}
+
+func TestBatchDuplicateCommitments(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	var proofs KZGProofSequenceImpl
+	for i := 0; i < 2; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, blob)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	// the same blob sidecar, received twice
+	blobs = append(blobs, blobs[0], blobs[1], blobs[0])
+	commitments = append(commitments, commitments[0], commitments[1], commitments[0])
+	proofs = append(proofs, proofs[0], proofs[1], proofs[0])
+	ok, err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected batch with duplicates to verify")
+	}
+
+	// a repeated commitment with another blob is still checked
+	blobs[3] = blobs[0]
+	proofs[3] = proofs[0]
+	ok, err = ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected repeated commitment with a wrong blob to fail")
+	}
+	i, err := ctx.FindInvalidBlobKZGProof(blobs, commitments, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != 3 {
+		t.Fatalf("expected proof 3 to be invalid, got %d", i)
+	}
+
+	// repeated commitment points, as in a multi-proof
+	poly := randomPolynomialN(16)
+	c := ctx.PolynomialToKZGCommitment(poly)
+	commitment, err := bls.FromCompressedG1(c[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var zs, ys []*bls.Fr
+	var ps, cs []*bls.G1Point
+	for i := 0; i < 3; i++ {
+		z := bls.RandomFr()
+		proof, err := ctx.ComputeKZGProof(poly, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := bls.FromCompressedG1(proof[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		y := ctx.EvaluatePolynomialInEvaluationForm(poly, z)
+		zs, ys, ps, cs = append(zs, z), append(ys, y), append(ps, p), append(cs, commitment)
+	}
+	ok, err = ctx.VerifyKZGProofBatchFromPoints(cs, zs, ys, ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected openings of the same commitment to verify")
+	}
+	ys[1] = ys[2]
+	ok, err = ctx.VerifyKZGProofBatchFromPoints(cs, zs, ys, ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected wrong evaluation to fail")
+	}
+}
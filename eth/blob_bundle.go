@@ -0,0 +1,103 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BlobBundle holds the blobs of a payload with everything a blob transaction and its sidecar need.
+// All the slices have one entry per blob.
+type BlobBundle struct {
+	Blobs           []Blob
+	Commitments     []KZGCommitment
+	Proofs          []KZGProof
+	VersionedHashes []VersionedHash
+}
+
+// BuildBlobs splits a payload across as many blobs as needed, each holding up to MaxBlobDataSize bytes
+// encoded with EncodeToBlob, and computes their commitments, blob proofs and versioned hashes.
+// The blobs are committed to and proven in parallel (see SetMaxWorkers).
+func (ctx *Context) BuildBlobs(payload []byte) (*BlobBundle, error) {
+	blobs, err := ctx.splitToBlobs(payload)
+	if err != nil {
+		return nil, err
+	}
+	count := len(blobs)
+	bundle := &BlobBundle{
+		Blobs:           blobs,
+		Commitments:     make([]KZGCommitment, count),
+		Proofs:          make([]KZGProof, count),
+		VersionedHashes: make([]VersionedHash, count),
+	}
+	errs := make([]error, count)
+	parallelFor(count, func(i int) {
+		blob := blobs[i]
+		poly, ok := BlobToPolynomial(blob)
+		if !ok {
+			errs[i] = ctx.blobError(blob)
+			return
+		}
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		bundle.Commitments[i] = commitment
+		bundle.Proofs[i] = proof
+		bundle.VersionedHashes[i] = KZGToVersionedHash(commitment)
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("blob %d: %w", i, err)
+		}
+	}
+	return bundle, nil
+}
+
+// splitToBlobs encodes the payload into as many blobs as needed, with EncodeToBlob.
+func (ctx *Context) splitToBlobs(payload []byte) ([]Blob, error) {
+	if len(payload) == 0 {
+		return nil, errors.New("empty payload")
+	}
+	capacity := ctx.MaxBlobDataSize()
+	blobs := make([]Blob, (len(payload)+capacity-1)/capacity)
+	for i := range blobs {
+		end := (i + 1) * capacity
+		if end > len(payload) {
+			end = len(payload)
+		}
+		blob, err := ctx.EncodeToBlob(payload[i*capacity : end])
+		if err != nil {
+			return nil, err
+		}
+		blobs[i] = blob
+	}
+	return blobs, nil
+}
+
+// BuildBlobs calls BuildBlobs on the default context.
+func BuildBlobs(payload []byte) (*BlobBundle, error) {
+	return defaultContext().BuildBlobs(payload)
+}
+
+// DecodeBlobs decodes the payload of blobs built with BuildBlobs, in order.
+func (ctx *Context) DecodeBlobs(blobs BlobSequence) ([]byte, error) {
+	var payload []byte
+	for i := 0; i < blobs.Len(); i++ {
+		data, err := ctx.DecodeFromBlob(blobs.At(i))
+		if err != nil {
+			return nil, fmt.Errorf("blob %d: %w", i, err)
+		}
+		payload = append(payload, data...)
+	}
+	return payload, nil
+}
+
+// DecodeBlobs calls DecodeBlobs on the default context.
+func DecodeBlobs(blobs BlobSequence) ([]byte, error) {
+	return defaultContext().DecodeBlobs(blobs)
+}
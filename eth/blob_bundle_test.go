@@ -0,0 +1,80 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestBuildBlobs(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	payload := make([]byte, 2*ctx.MaxBlobDataSize()+100)
+	if _, err := rand.Read(payload); err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := ctx.BuildBlobs(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bundle.Blobs) != 3 {
+		t.Fatalf("expected 3 blobs, got %d", len(bundle.Blobs))
+	}
+	blobs := testBlobs(bundle.Blobs)
+	ok, err := ctx.VerifyBlobKZGProofBatch(blobs, KZGCommitmentSequenceImpl(bundle.Commitments), KZGProofSequenceImpl(bundle.Proofs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected the proofs of the bundle to verify")
+	}
+	err = VerifyKZGCommitmentsAgainstVersionedHashes(KZGCommitmentSequenceImpl(bundle.Commitments), VersionedHashSequenceImpl(bundle.VersionedHashes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ctx.DecodeBlobs(blobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, payload) {
+		t.Fatal("decoded payload does not match")
+	}
+	if _, err := ctx.BuildBlobs(nil); err == nil {
+		t.Fatal("expected an empty payload to be rejected")
+	}
+}
+
+func TestVerifyBlobsBundle(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	payload := make([]byte, 2*ctx.MaxBlobDataSize()+100)
+	if _, err := rand.Read(payload); err != nil {
+		t.Fatal(err)
+	}
+	built, err := ctx.BuildBlobs(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := &BlobsBundle{Commitments: built.Commitments, Proofs: built.Proofs, Blobs: built.Blobs}
+	if err := ctx.VerifyBlobsBundle(bundle); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyBlobsBundleForPayload(bundle, VersionedHashSequenceImpl(built.VersionedHashes)); err != nil {
+		t.Fatal(err)
+	}
+	hashes := VersionedHashSequenceImpl{built.VersionedHashes[1], built.VersionedHashes[0], built.VersionedHashes[2]}
+	if err := ctx.VerifyBlobsBundleForPayload(bundle, hashes); err == nil {
+		t.Fatal("expected a bundle in another order than the payload to be rejected")
+	}
+
+	bundle.Proofs = []KZGProof{built.Proofs[0], built.Proofs[2], built.Proofs[1]}
+	if err := ctx.VerifyBlobsBundle(bundle); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected swapped proofs to be invalid, got %v", err)
+	}
+	bundle.Proofs = built.Proofs[:2]
+	if err := ctx.VerifyBlobsBundle(bundle); err == nil || errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected length mismatch to be malformed, got %v", err)
+	}
+}
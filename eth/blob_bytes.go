@@ -0,0 +1,48 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// VerifyBlobBytes is VerifyBlobKZGProof for a blob given as its contiguous encoding
+// (FieldElementsPerBlob little-endian 32-byte field elements), decoded directly without going through
+// the Blob interface. It returns nil if the proof is valid, ErrInvalidProof if it is not,
+// and another error if the inputs are malformed.
+func (ctx *Context) VerifyBlobBytes(blob []byte, commitment [48]byte, proof [48]byte) error {
+	n := ctx.FieldElementsPerBlob()
+	if len(blob) != n*32 {
+		return fmt.Errorf("blob has %d bytes, expected %d", len(blob), n*32)
+	}
+	poly := make(Polynomial, n)
+	var b [32]byte
+	for i := range poly {
+		copy(b[:], blob[i*32:])
+		if !bls.FrFrom32(&poly[i], b) {
+			return fmt.Errorf("blob field element %d: %w", i, ErrNonCanonicalScalar)
+		}
+	}
+	commitmentG1, err := ctx.decodeCommitment(commitment)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	proofG1, err := ctx.decodeProof(proof)
+	if err != nil {
+		return err
+	}
+	z := ctx.ComputeChallenge(poly, commitment)
+	y := ctx.EvaluatePolynomialInEvaluationForm(poly, z)
+	if !ctx.VerifyKZGProofFromPoints(commitmentG1, z, y, proofG1) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyBlobBytes calls VerifyBlobBytes on the default context.
+func VerifyBlobBytes(blob []byte, commitment [48]byte, proof [48]byte) error {
+	return defaultContext().VerifyBlobBytes(blob, commitment, proof)
+}
@@ -0,0 +1,42 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyBlobBytes(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	blob := polynomialToBlob(poly)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := flattenBlob(blob)
+	if err := ctx.VerifyBlobBytes(data, commitment, proof); err != nil {
+		t.Fatal(err)
+	}
+
+	otherProof, err := ctx.ComputeBlobKZGProof(polynomialToBlob(randomPolynomialN(16)), commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyBlobBytes(data, commitment, otherProof); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected ErrInvalidProof, got %v", err)
+	}
+	if err := ctx.VerifyBlobBytes(data[:len(data)-1], commitment, proof); err == nil || errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected truncated blob to be rejected as malformed, got %v", err)
+	}
+	nonCanonical := append([]byte{}, data...)
+	for i := 32; i < 64; i++ {
+		nonCanonical[i] = 0xff
+	}
+	if err := ctx.VerifyBlobBytes(nonCanonical, commitment, proof); err == nil || errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected non-canonical field element to be rejected as malformed, got %v", err)
+	}
+}
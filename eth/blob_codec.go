@@ -0,0 +1,92 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The codec of EncodeToBlob packs data into the blob with the layout of DataBytesPerFieldElement,
+// behind a header of a version byte and the data length as a little-endian uint32:
+//
+//	packed data = version || uint32_le(len(data)) || data || zero padding
+const (
+	// BlobCodecVersion is the version byte of the blob codec.
+	BlobCodecVersion = 0
+	// BlobDataOffset is the offset of the data in the packed data of the blob, after the header,
+	// e.g. to compute byte-range proofs of the data, see ComputeByteRangeProof.
+	BlobDataOffset = 5
+)
+
+// MaxBlobDataSize returns the maximum data size of EncodeToBlob.
+func (ctx *Context) MaxBlobDataSize() int {
+	return ctx.FieldElementsPerBlob()*DataBytesPerFieldElement - BlobDataOffset
+}
+
+// MaxBlobDataSize calls MaxBlobDataSize on the default context.
+func MaxBlobDataSize() int {
+	return defaultContext().MaxBlobDataSize()
+}
+
+// EncodeToBlob encodes data into a blob, 31 bytes per field element behind a length header, see BlobDataOffset.
+// The top byte of every field element is zero, so the blob is always canonical.
+func (ctx *Context) EncodeToBlob(data []byte) (Blob, error) {
+	if len(data) > ctx.MaxBlobDataSize() {
+		return nil, fmt.Errorf("data of %d bytes exceeds the blob capacity of %d bytes", len(data), ctx.MaxBlobDataSize())
+	}
+	n := ctx.FieldElementsPerBlob()
+	packed := make([]byte, n*DataBytesPerFieldElement)
+	packed[0] = BlobCodecVersion
+	binary.LittleEndian.PutUint32(packed[1:BlobDataOffset], uint32(len(data)))
+	copy(packed[BlobDataOffset:], data)
+	blob := make(blobSlice, n*32)
+	for i := 0; i < n; i++ {
+		copy(blob[i*32:i*32+DataBytesPerFieldElement], packed[i*DataBytesPerFieldElement:])
+	}
+	return blob, nil
+}
+
+// EncodeToBlob calls EncodeToBlob on the default context.
+func EncodeToBlob(data []byte) (Blob, error) {
+	return defaultContext().EncodeToBlob(data)
+}
+
+// DecodeFromBlob decodes the data of a blob encoded with EncodeToBlob. The encoding is strict: the version must
+// match, the length must fit in the blob, and the top byte of every field element and the padding after the data
+// must be zero.
+func (ctx *Context) DecodeFromBlob(b Blob) ([]byte, error) {
+	n := b.Len()
+	if n != ctx.FieldElementsPerBlob() {
+		return nil, fmt.Errorf("%w: blob has %d field elements, expected %d", ErrWrongBlobLength, n, ctx.FieldElementsPerBlob())
+	}
+	packed := make([]byte, 0, n*DataBytesPerFieldElement)
+	for i := 0; i < n; i++ {
+		element := b.At(i)
+		if element[DataBytesPerFieldElement] != 0 {
+			return nil, fmt.Errorf("field element %d has a non-zero top byte", i)
+		}
+		packed = append(packed, element[:DataBytesPerFieldElement]...)
+	}
+	if packed[0] != BlobCodecVersion {
+		return nil, fmt.Errorf("unsupported blob codec version %d", packed[0])
+	}
+	size := binary.LittleEndian.Uint32(packed[1:BlobDataOffset])
+	if uint64(size) > uint64(ctx.MaxBlobDataSize()) {
+		return nil, fmt.Errorf("data length %d exceeds the blob capacity of %d bytes", size, ctx.MaxBlobDataSize())
+	}
+	end := BlobDataOffset + int(size)
+	for _, v := range packed[end:] {
+		if v != 0 {
+			return nil, errors.New("non-zero padding after the blob data")
+		}
+	}
+	return packed[BlobDataOffset:end], nil
+}
+
+// DecodeFromBlob calls DecodeFromBlob on the default context.
+func DecodeFromBlob(b Blob) ([]byte, error) {
+	return defaultContext().DecodeFromBlob(b)
+}
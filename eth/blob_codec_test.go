@@ -0,0 +1,75 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestBlobCodec(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	if ctx.MaxBlobDataSize() != 16*31-5 {
+		t.Fatalf("unexpected capacity %d", ctx.MaxBlobDataSize())
+	}
+	for _, size := range []int{0, 1, 26, 27, 100, ctx.MaxBlobDataSize()} {
+		data := make([]byte, size)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatal(err)
+		}
+		blob, err := ctx.EncodeToBlob(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := BlobToPolynomial(blob); !ok {
+			t.Fatalf("encoding of %d bytes is not canonical", size)
+		}
+		decoded, err := ctx.DecodeFromBlob(blob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Fatalf("round trip of %d bytes does not match", size)
+		}
+	}
+	if _, err := ctx.EncodeToBlob(make([]byte, ctx.MaxBlobDataSize()+1)); err == nil {
+		t.Fatal("expected data over the capacity to be rejected")
+	}
+
+	data := []byte("hello blob")
+	blob, err := ctx.EncodeToBlob(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the data is where byte-range proofs expect it
+	proof, err := ctx.ComputeByteRangeProof(blob, BlobDataOffset, len(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	poly, _ := BlobToPolynomial(blob)
+	ok, err := ctx.VerifyByteRangeProof(ctx.PolynomialToKZGCommitment(poly), data, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected byte range proof of the data to verify")
+	}
+
+	raw := blob.(blobSlice)
+	raw[32*3] = 1
+	if _, err := ctx.DecodeFromBlob(raw); err == nil {
+		t.Fatal("expected non-zero padding to be rejected")
+	}
+	raw[32*3] = 0
+	raw[31] = 1
+	if _, err := ctx.DecodeFromBlob(raw); err == nil {
+		t.Fatal("expected a non-zero top byte to be rejected")
+	}
+	raw[31] = 0
+	raw[0] = 1
+	if _, err := ctx.DecodeFromBlob(raw); err == nil {
+		t.Fatal("expected an unknown version to be rejected")
+	}
+}
@@ -0,0 +1,59 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+)
+
+// BlobElementPoint returns the evaluation point of the blob element of the given index: blobs are in evaluation
+// form, over the roots of unity in bit-reversed order, so element i is the evaluation at w**reverse_bits(i),
+// not w**i. This is the z of the openings of ProveBlobElement.
+func (ctx *Context) BlobElementPoint(index uint64) (Bytes32, error) {
+	if index >= uint64(len(ctx.domain)) {
+		return Bytes32{}, fmt.Errorf("element index %d is out of range for %d field elements", index, len(ctx.domain))
+	}
+	return Bytes32FromFr(&ctx.domain[index]), nil
+}
+
+// BlobElementPoint calls BlobElementPoint on the default context.
+func BlobElementPoint(index uint64) (Bytes32, error) {
+	return defaultContext().BlobElementPoint(index)
+}
+
+// ProveBlobElement opens the blob at the point of the element of the given index (see BlobElementPoint),
+// e.g. for a fraud proof about a single element. It returns the element, and the proof that it is the evaluation
+// of the committed polynomial at that point, which VerifyBlobElement checks, or VerifyKZGProof
+// with the point of the element.
+func (ctx *Context) ProveBlobElement(blob Blob, index uint64) (Bytes32, KZGProof, error) {
+	z, err := ctx.BlobElementPoint(index)
+	if err != nil {
+		return Bytes32{}, KZGProof{}, err
+	}
+	proof, y, err := ctx.ComputeKZGProofAt(blob, z)
+	if err != nil {
+		return Bytes32{}, KZGProof{}, err
+	}
+	return y, proof, nil
+}
+
+// ProveBlobElement calls ProveBlobElement on the default context.
+func ProveBlobElement(blob Blob, index uint64) (Bytes32, KZGProof, error) {
+	return defaultContext().ProveBlobElement(blob, index)
+}
+
+// VerifyBlobElement verifies that the element of the given index of the blob committed to has the given value,
+// with a proof of ProveBlobElement. It returns an error if the inputs are malformed.
+func (ctx *Context) VerifyBlobElement(commitment KZGCommitment, index uint64, value Bytes32, proof KZGProof) (bool, error) {
+	z, err := ctx.BlobElementPoint(index)
+	if err != nil {
+		return false, err
+	}
+	return ctx.VerifyKZGProof(commitment, z, value, proof)
+}
+
+// VerifyBlobElement calls VerifyBlobElement on the default context.
+func VerifyBlobElement(commitment KZGCommitment, index uint64, value Bytes32, proof KZGProof) (bool, error) {
+	return defaultContext().VerifyBlobElement(commitment, index, value, proof)
+}
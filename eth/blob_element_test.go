@@ -0,0 +1,56 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestProveBlobElement(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	blob := polynomialToBlob(poly)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	roots := kzg.NewFFTSettings(4).ExpandedRootsOfUnity
+	for i := uint64(0); i < 16; i++ {
+		z, err := ctx.BlobElementPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := Bytes32FromFr(&roots[reverseBits(i, 16)]); z != expected {
+			t.Fatalf("element %d: point is not the bit-reversed root of unity", i)
+		}
+		value, proof, err := ctx.ProveBlobElement(blob, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != Bytes32(blob[i]) {
+			t.Fatalf("element %d: got value %x, expected %x", i, value, blob[i])
+		}
+		if ok, err := ctx.VerifyBlobElement(commitment, i, value, proof); err != nil || !ok {
+			t.Fatalf("element %d: proof does not verify: %v", i, err)
+		}
+		// the proof is a plain opening at the point of the element
+		if ok, err := ctx.VerifyKZGProof(commitment, z, value, proof); err != nil || !ok {
+			t.Fatalf("element %d: opening does not verify: %v", i, err)
+		}
+		if ok, _ := ctx.VerifyBlobElement(commitment, (i+1)%16, value, proof); ok {
+			t.Fatalf("element %d: proof verifies for another index", i)
+		}
+		var wrong bls.Fr
+		bls.AddModFr(&wrong, &poly[i], &bls.ONE)
+		if ok, _ := ctx.VerifyBlobElement(commitment, i, Bytes32FromFr(&wrong), proof); ok {
+			t.Fatalf("element %d: proof verifies for a wrong value", i)
+		}
+	}
+	if _, _, err := ctx.ProveBlobElement(blob, 16); err == nil {
+		t.Fatal("expected out of range error")
+	}
+	if _, err := ctx.VerifyBlobElement(commitment, 16, Bytes32{}, KZGProof{}); err == nil {
+		t.Fatal("expected out of range error")
+	}
+}
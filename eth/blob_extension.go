@@ -0,0 +1,55 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// extendedDomainPoints returns the roots of unity of twice the size of the domain, in bit-reversed order:
+// the first half are the points of the domain, in the same order, and the second half the points in between.
+func (ctx *Context) extendedDomainPoints() []bls.Fr {
+	ctx.extendedDomainOnce.Do(func() {
+		ctx.extendedDomain = computeDomain(2 * len(ctx.domain))
+	})
+	return ctx.extendedDomain
+}
+
+// VerifyBlobExtension checks that the extended polynomial, the evaluations of the polynomial of the blob over
+// twice the domain in bit-reversed order (the blob itself, then the evaluations in between), encodes the same
+// polynomial as the blob, and that the blob matches the commitment. DAS nodes can use it to validate an
+// extension they computed before distributing cells of it.
+// The extension is checked by evaluating both polynomials at a random point: an extension of degree n or more
+// only matches with negligible probability. It returns an error wrapping ErrInvalidExtension if the check fails,
+// and another error if the inputs are malformed.
+func (ctx *Context) VerifyBlobExtension(originalPoly, extendedPoly Polynomial, commitment KZGCommitment) error {
+	n := len(ctx.domain)
+	if len(originalPoly) != n {
+		return fmt.Errorf("%w: polynomial has %d field elements, expected %d", ErrWrongBlobLength, len(originalPoly), n)
+	}
+	if len(extendedPoly) != 2*n {
+		return fmt.Errorf("%w: extended polynomial has %d field elements, expected %d", ErrWrongBlobLength, len(extendedPoly), 2*n)
+	}
+	if _, err := ctx.decodeCommitment(commitment); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	if ctx.PolynomialToKZGCommitment(originalPoly) != commitment {
+		return fmt.Errorf("%w: polynomial does not match the commitment", ErrInvalidExtension)
+	}
+	r := bls.RandomFr()
+	var original, extended bls.Fr
+	bls.EvaluatePolyInEvaluationForm(&original, originalPoly, r, ctx.domain, 0)
+	bls.EvaluatePolyInEvaluationForm(&extended, extendedPoly, r, ctx.extendedDomainPoints(), 0)
+	if !bls.EqualFr(&original, &extended) {
+		return fmt.Errorf("%w: extension does not encode the polynomial of the blob", ErrInvalidExtension)
+	}
+	return nil
+}
+
+// VerifyBlobExtension calls VerifyBlobExtension on the default context.
+func VerifyBlobExtension(originalPoly, extendedPoly Polynomial, commitment KZGCommitment) error {
+	return defaultContext().VerifyBlobExtension(originalPoly, extendedPoly, commitment)
+}
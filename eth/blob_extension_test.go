@@ -0,0 +1,48 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestVerifyBlobExtension(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	points := ctx.extendedDomainPoints()
+	extended := make(Polynomial, 32)
+	for i := range extended {
+		bls.CopyFr(&extended[i], ctx.EvaluatePolynomialInEvaluationForm(poly, &points[i]))
+	}
+	for i := range poly {
+		if !bls.EqualFr(&extended[i], &poly[i]) {
+			t.Fatalf("element %d: expected the extension to start with the blob", i)
+		}
+	}
+	if err := ctx.VerifyBlobExtension(poly, extended, commitment); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := copyPolynomial(extended)
+	bls.AddModFr(&tampered[20], &tampered[20], &bls.ONE)
+	if err := ctx.VerifyBlobExtension(poly, tampered, commitment); !errors.Is(err, ErrInvalidExtension) {
+		t.Fatalf("expected tampered extension to be rejected, got %v", err)
+	}
+	if err := ctx.VerifyBlobExtension(poly, randomPolynomialN(32), commitment); !errors.Is(err, ErrInvalidExtension) {
+		t.Fatalf("expected random extension to be rejected, got %v", err)
+	}
+	if err := ctx.VerifyBlobExtension(randomPolynomialN(16), extended, commitment); !errors.Is(err, ErrInvalidExtension) {
+		t.Fatalf("expected other blob to be rejected, got %v", err)
+	}
+	if err := ctx.VerifyBlobExtension(poly, extended[:16], commitment); !errors.Is(err, ErrWrongBlobLength) {
+		t.Fatalf("expected wrong extension length, got %v", err)
+	}
+	if err := ctx.VerifyBlobExtension(poly, extended, KZGCommitment{0xff}); !errors.Is(err, ErrInvalidCommitment) {
+		t.Fatalf("expected invalid commitment, got %v", err)
+	}
+}
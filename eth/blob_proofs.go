@@ -0,0 +1,148 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// ComputeChallenge implements compute_challenge from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#compute_challenge
+//
+// Unlike the rest of this package, Deneb serializes field elements and the degree as big-endian,
+// and the challenge is hashed that way to stay compatible with other implementations.
+func (ctx *Context) ComputeChallenge(poly Polynomial, commitment KZGCommitment) *bls.Fr {
+	return ctx.computeChallenge(ctx.newTranscript(false), poly, commitment)
+}
+
+// ComputeChallengeDebug is ComputeChallenge, also returning a transcript of all the hashed inputs.
+func (ctx *Context) ComputeChallengeDebug(poly Polynomial, commitment KZGCommitment) (*bls.Fr, *ChallengeTranscript) {
+	h := ctx.newTranscript(true)
+	return ctx.computeChallenge(h, poly, commitment), h.record
+}
+
+// ComputeChallengeDebug calls ComputeChallengeDebug on the default context.
+func ComputeChallengeDebug(poly Polynomial, commitment KZGCommitment) (*bls.Fr, *ChallengeTranscript) {
+	return defaultContext().ComputeChallengeDebug(poly, commitment)
+}
+
+func (ctx *Context) computeChallenge(h *Transcript, poly Polynomial, commitment KZGCommitment) *bls.Fr {
+	defer bls.StartTraceRegion("kzg hash").End()
+	_, domain := ctx.fiatShamirDomains()
+	h.absorb(func() string { return "domain" }, []byte(domain))
+	var degree [16]byte
+	binary.BigEndian.PutUint64(degree[8:], uint64(ctx.FieldElementsPerBlob()))
+	h.absorb(func() string { return "degree" }, degree[:])
+	for i := range poly {
+		b32 := bls.FrTo32BE(&poly[i])
+		h.absorb(func() string { return fmt.Sprintf("blob[%d]", i) }, b32[:])
+	}
+	h.absorb(func() string { return "commitment" }, commitment[:])
+	return h.challenge(true)
+}
+
+// ComputeChallenge calls ComputeChallenge on the default context.
+func ComputeChallenge(poly Polynomial, commitment KZGCommitment) *bls.Fr {
+	return defaultContext().ComputeChallenge(poly, commitment)
+}
+
+// ComputeBlobKZGProof implements compute_blob_kzg_proof from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#compute_blob_kzg_proof
+func (ctx *Context) ComputeBlobKZGProof(blob Blob, commitment KZGCommitment) (KZGProof, error) {
+	poly, ok := BlobToPolynomial(blob)
+	if !ok {
+		return KZGProof{}, ctx.blobError(blob)
+	}
+	if _, err := ctx.decodeCommitment(commitment); err != nil {
+		return KZGProof{}, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	if len(poly) != len(ctx.domain) {
+		return KZGProof{}, fmt.Errorf("%w: polynomial has invalid length", ErrWrongBlobLength)
+	}
+	defer ctx.reportUsage("ComputeBlobKZGProof", ctx.blobBytes()+48, len(poly))
+	proof, _ := ctx.computeKZGProofCached(poly, func() KZGCommitment { return commitment }, ctx.ComputeChallenge(poly, commitment))
+	return proof, nil
+}
+
+// ComputeBlobKZGProof calls ComputeBlobKZGProof on the default context.
+func ComputeBlobKZGProof(blob Blob, commitment KZGCommitment) (KZGProof, error) {
+	return defaultContext().ComputeBlobKZGProof(blob, commitment)
+}
+
+// ComputeKZGProofAt opens the blob at a point chosen by the caller, rather than at its Fiat-Shamir challenge,
+// e.g. for fraud proofs. z may be in the evaluation domain or not. It returns the proof and the evaluation y,
+// with z and y encoded like the inputs of VerifyKZGProof.
+func (ctx *Context) ComputeKZGProofAt(blob Blob, z [32]byte) (KZGProof, [32]byte, error) {
+	var zFr bls.Fr
+	if !bls.FrFrom32(&zFr, z) {
+		return KZGProof{}, [32]byte{}, fmt.Errorf("invalid evaluation point: %w", ErrNonCanonicalScalar)
+	}
+	poly, ok := BlobToPolynomial(blob)
+	if !ok {
+		return KZGProof{}, [32]byte{}, ctx.blobError(blob)
+	}
+	if len(poly) != ctx.FieldElementsPerBlob() {
+		return KZGProof{}, [32]byte{}, fmt.Errorf("%w: blob has %d field elements, expected %d", ErrWrongBlobLength, len(poly), ctx.FieldElementsPerBlob())
+	}
+	proof, y := ctx.computeKZGProofCached(poly, func() KZGCommitment {
+		return ctx.PolynomialToKZGCommitment(poly)
+	}, &zFr)
+	return proof, bls.FrTo32(&y), nil
+}
+
+// ComputeKZGProofAt calls ComputeKZGProofAt on the default context.
+func ComputeKZGProofAt(blob Blob, z [32]byte) (KZGProof, [32]byte, error) {
+	return defaultContext().ComputeKZGProofAt(blob, z)
+}
+
+// VerifyBlobKZGProof implements verify_blob_kzg_proof from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof
+func (ctx *Context) VerifyBlobKZGProof(blob Blob, commitment KZGCommitment, proof KZGProof) (bool, error) {
+	defer ctx.reportUsage("VerifyBlobKZGProof", ctx.blobBytes()+96, 2)
+	commitmentG1, z, y, proofG1, err := ctx.blobKZGProofOpening(blob, commitment, proof, nil)
+	if err != nil {
+		return false, err
+	}
+	return ctx.VerifyKZGProofFromPoints(commitmentG1, z, y, proofG1), nil
+}
+
+// blobKZGProofOpening parses the inputs of VerifyBlobKZGProof, validated with the options, nil for the defaults,
+// and computes the opening they stand for.
+func (ctx *Context) blobKZGProofOpening(blob Blob, commitment KZGCommitment, proof KZGProof, opts *VerifyOpts) (
+	commitmentG1 *bls.G1Point, z, y *bls.Fr, proofG1 *bls.G1Point, err error) {
+	poly, ok := BlobToPolynomial(blob)
+	if !ok {
+		return nil, nil, nil, nil, ctx.blobError(blob)
+	}
+	if len(poly) != ctx.FieldElementsPerBlob() {
+		return nil, nil, nil, nil, fmt.Errorf("%w: blob has %d field elements, expected %d", ErrWrongBlobLength, len(poly), ctx.FieldElementsPerBlob())
+	}
+	commitmentG1, err = opts.decodeCommitment(ctx, commitment)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	proofG1, err = opts.decodeProof(ctx, proof)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	z = ctx.ComputeChallenge(poly, commitment)
+	y = ctx.EvaluatePolynomialInEvaluationForm(poly, z)
+	return commitmentG1, z, y, proofG1, nil
+}
+
+// VerifyBlobKZGProof calls VerifyBlobKZGProof on the default context.
+func VerifyBlobKZGProof(blob Blob, commitment KZGCommitment, proof KZGProof) (bool, error) {
+	return defaultContext().VerifyBlobKZGProof(blob, commitment, proof)
+}
+
+// reverse32 converts a 32 byte value between big-endian and little-endian.
+func reverse32(v [32]byte) (out [32]byte) {
+	for i := range v {
+		out[i] = v[31-i]
+	}
+	return out
+}
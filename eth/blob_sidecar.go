@@ -0,0 +1,115 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxBlobCommitmentsPerBlock is the limit of the blob_kzg_commitments list of the Deneb beacon block body,
+	// of the mainnet preset.
+	MaxBlobCommitmentsPerBlock = 4096
+	// KZGCommitmentInclusionProofDepth is the depth of the Merkle proof of a commitment in the beacon block body:
+	// 4 for the body fields, 1 for the length mix-in of the list, and 12 for the list of commitments.
+	KZGCommitmentInclusionProofDepth = 17
+	// blobKZGCommitmentsFieldIndex is the index of blob_kzg_commitments in the Deneb beacon block body.
+	blobKZGCommitmentsFieldIndex = 11
+)
+
+// ErrInvalidInclusionProof is returned by VerifyBlobSidecar when the commitment is not in the block body.
+var ErrInvalidInclusionProof = errors.New("invalid commitment inclusion proof")
+
+// BeaconBlockHeader is the Deneb beacon block header.
+type BeaconBlockHeader struct {
+	Slot          Slot
+	ProposerIndex uint64
+	ParentRoot    Root
+	StateRoot     Root
+	BodyRoot      Root
+}
+
+// SignedBeaconBlockHeader is a beacon block header with the signature of its proposer.
+type SignedBeaconBlockHeader struct {
+	Message   BeaconBlockHeader
+	Signature [96]byte
+}
+
+// BlobSidecar is the Deneb networking blob sidecar, carrying a blob with its commitment and proof,
+// and the Merkle proof that the commitment is the one at Index in the body of the signed block.
+type BlobSidecar struct {
+	Index                       uint64
+	Blob                        Blob
+	KZGCommitment               KZGCommitment
+	KZGProof                    KZGProof
+	SignedBlockHeader           SignedBeaconBlockHeader
+	KZGCommitmentInclusionProof [KZGCommitmentInclusionProofDepth]Root
+}
+
+// kzgCommitmentSubtreeIndex returns the position in the beacon block body tree of the commitment at the given
+// index, as get_subtree_index(get_generalized_index(BeaconBlockBody, 'blob_kzg_commitments', index)).
+// The bits are, from the top: the field index, 0 for the data of the list rather than its length,
+// and the index in the list.
+func kzgCommitmentSubtreeIndex(index uint64) uint64 {
+	return (blobKZGCommitmentsFieldIndex<<1)<<12 | index
+}
+
+// VerifyBlobSidecarInclusionProof implements verify_blob_sidecar_inclusion_proof from the Deneb p2p spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/p2p-interface.md#verify_blob_sidecar_inclusion_proof
+// It checks that the commitment of the sidecar is at its index in the body of the block,
+// of which the header commits to the root. The signature of the header is not checked.
+func VerifyBlobSidecarInclusionProof(sidecar *BlobSidecar) bool {
+	if sidecar.Index >= MaxBlobCommitmentsPerBlock {
+		return false
+	}
+	// hash_tree_root of the commitment, as a 48-byte vector: two chunks, the second zero-padded
+	var chunks [64]byte
+	copy(chunks[:], sidecar.KZGCommitment[:])
+	value := sha256.Sum256(chunks[:])
+	index := kzgCommitmentSubtreeIndex(sidecar.Index)
+	var pair [64]byte
+	for i := range sidecar.KZGCommitmentInclusionProof {
+		if index>>uint(i)&1 == 1 {
+			copy(pair[:32], sidecar.KZGCommitmentInclusionProof[i][:])
+			copy(pair[32:], value[:])
+		} else {
+			copy(pair[:32], value[:])
+			copy(pair[32:], sidecar.KZGCommitmentInclusionProof[i][:])
+		}
+		value = sha256.Sum256(pair[:])
+	}
+	return Root(value) == sidecar.SignedBlockHeader.Message.BodyRoot
+}
+
+// VerifyBlobSidecar verifies a blob sidecar as gossip validation of the blob_sidecar topics does, apart from
+// the checks that need the chain: the inclusion proof of the commitment (see VerifyBlobSidecarInclusionProof),
+// and the blob proof (see VerifyBlobKZGProof). It returns nil if the sidecar is valid,
+// ErrInvalidInclusionProof or ErrInvalidProof if one of the proofs is invalid,
+// and another error if the sidecar is malformed.
+func (ctx *Context) VerifyBlobSidecar(sidecar *BlobSidecar) error {
+	if sidecar.Index >= MaxBlobCommitmentsPerBlock {
+		return fmt.Errorf("blob index %d is out of range", sidecar.Index)
+	}
+	if sidecar.Blob == nil {
+		return errors.New("missing blob")
+	}
+	if !VerifyBlobSidecarInclusionProof(sidecar) {
+		return ErrInvalidInclusionProof
+	}
+	ok, err := ctx.VerifyBlobKZGProof(sidecar.Blob, sidecar.KZGCommitment, sidecar.KZGProof)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyBlobSidecar calls VerifyBlobSidecar on the default context.
+func VerifyBlobSidecar(sidecar *BlobSidecar) error {
+	return defaultContext().VerifyBlobSidecar(sidecar)
+}
@@ -0,0 +1,105 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// merkleProof merkleizes the leaves, padded with zero leaves to 2^depth, and returns the root
+// and the branch of the leaf at index.
+func merkleProof(leaves []Root, depth int, index int) (Root, []Root) {
+	var zero Root
+	level := append([]Root(nil), leaves...)
+	branch := make([]Root, 0, depth)
+	for d := 0; d < depth; d++ {
+		if len(level)%2 == 1 {
+			level = append(level, zero)
+		}
+		branch = append(branch, level[index^1])
+		next := make([]Root, len(level)/2)
+		for i := range next {
+			next[i] = sha256.Sum256(append(level[2*i][:], level[2*i+1][:]...))
+		}
+		level = next
+		index /= 2
+		zero = sha256.Sum256(append(zero[:], zero[:]...))
+	}
+	return level[0], branch
+}
+
+func TestVerifyBlobSidecar(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var commitments []KZGCommitment
+	var leaves []Root
+	var blobs []Blob
+	var proofs []KZGProof
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var chunks [64]byte
+		copy(chunks[:], commitment[:])
+		blobs = append(blobs, blob)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+		leaves = append(leaves, sha256.Sum256(chunks[:]))
+	}
+	const index = 2
+	// the list of commitments, with its length mixed in
+	dataRoot, dataBranch := merkleProof(leaves, 12, index)
+	var length Root
+	binary.LittleEndian.PutUint64(length[:], uint64(len(leaves)))
+	listRoot := Root(sha256.Sum256(append(dataRoot[:], length[:]...)))
+	// the body, with random roots for the other fields
+	fields := make([]Root, 12)
+	for i := range fields {
+		if _, err := rand.Read(fields[i][:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fields[blobKZGCommitmentsFieldIndex] = listRoot
+	bodyRoot, bodyBranch := merkleProof(fields, 4, blobKZGCommitmentsFieldIndex)
+
+	sidecar := &BlobSidecar{
+		Index:         index,
+		Blob:          blobs[index],
+		KZGCommitment: commitments[index],
+		KZGProof:      proofs[index],
+	}
+	sidecar.SignedBlockHeader.Message.BodyRoot = bodyRoot
+	copy(sidecar.KZGCommitmentInclusionProof[:], dataBranch)
+	sidecar.KZGCommitmentInclusionProof[12] = length
+	copy(sidecar.KZGCommitmentInclusionProof[13:], bodyBranch)
+	if err := ctx.VerifyBlobSidecar(sidecar); err != nil {
+		t.Fatal(err)
+	}
+
+	sidecar.Index = 1
+	if err := ctx.VerifyBlobSidecar(sidecar); !errors.Is(err, ErrInvalidInclusionProof) {
+		t.Fatalf("expected the wrong index to fail the inclusion proof, got %v", err)
+	}
+	sidecar.Index = index
+	sidecar.KZGProof = proofs[0]
+	if err := ctx.VerifyBlobSidecar(sidecar); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected the wrong proof to fail, got %v", err)
+	}
+	sidecar.KZGProof = proofs[index]
+	sidecar.KZGCommitment = commitments[0]
+	if err := ctx.VerifyBlobSidecar(sidecar); !errors.Is(err, ErrInvalidInclusionProof) {
+		t.Fatalf("expected another commitment to fail the inclusion proof, got %v", err)
+	}
+	sidecar.Index = MaxBlobCommitmentsPerBlock
+	if err := ctx.VerifyBlobSidecar(sidecar); err == nil || errors.Is(err, ErrInvalidInclusionProof) {
+		t.Fatalf("expected an out of range index to be malformed, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+)
+
+// BlobsBundle is the blobs bundle of an engine API engine_getPayloadV3 response (BlobsBundleV1):
+// the commitments, proofs and blobs of the payload's blob transactions,
+// in the order of their versioned hashes in the payload.
+type BlobsBundle struct {
+	Commitments []KZGCommitment
+	Proofs      []KZGProof
+	Blobs       []Blob
+}
+
+// VerifyBlobsBundle checks a bundle returned by a builder or execution client:
+// the three lists must have the same length, and each blob proof must open the commitment at the same position
+// to the blob at the same position, which is checked as one batch (see VerifyBlobKZGProofBatch).
+// It returns nil if the bundle is valid, an error wrapping ErrInvalidProof with the index of the first
+// invalid proof if it is not, and another error if the bundle is malformed.
+func (ctx *Context) VerifyBlobsBundle(bundle *BlobsBundle) error {
+	n := len(bundle.Blobs)
+	if len(bundle.Commitments) != n || len(bundle.Proofs) != n {
+		return fmt.Errorf("%w: %d blobs, %d commitments, %d proofs",
+			ErrLengthMismatch, n, len(bundle.Commitments), len(bundle.Proofs))
+	}
+	i, err := ctx.FindInvalidBlobKZGProof(blobList(bundle.Blobs),
+		KZGCommitmentSequenceImpl(bundle.Commitments), KZGProofSequenceImpl(bundle.Proofs))
+	if err != nil {
+		return err
+	}
+	if i >= 0 {
+		return fmt.Errorf("blob %d: %w", i, ErrInvalidProof)
+	}
+	return nil
+}
+
+// VerifyBlobsBundle calls VerifyBlobsBundle on the default context.
+func VerifyBlobsBundle(bundle *BlobsBundle) error {
+	return defaultContext().VerifyBlobsBundle(bundle)
+}
+
+// VerifyBlobsBundleForPayload is VerifyBlobsBundle, also checking that the commitments of the bundle
+// are those of the versioned hashes of the payload's blob transactions, in the same order.
+func (ctx *Context) VerifyBlobsBundleForPayload(bundle *BlobsBundle, versionedHashes VersionedHashSequence) error {
+	if err := VerifyKZGCommitmentsAgainstVersionedHashes(KZGCommitmentSequenceImpl(bundle.Commitments), versionedHashes); err != nil {
+		return err
+	}
+	return ctx.VerifyBlobsBundle(bundle)
+}
+
+// VerifyBlobsBundleForPayload calls VerifyBlobsBundleForPayload on the default context.
+func VerifyBlobsBundleForPayload(bundle *BlobsBundle, versionedHashes VersionedHashSequence) error {
+	return defaultContext().VerifyBlobsBundleForPayload(bundle, versionedHashes)
+}
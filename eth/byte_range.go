@@ -0,0 +1,130 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// DataBytesPerFieldElement is the number of data bytes packed into a field element of a blob:
+// the low 31 bytes of its little-endian encoding, the top byte being zero so that any data is canonical.
+// Byte k of the packed data is byte k % 31 of the field element k / 31 of the blob.
+const DataBytesPerFieldElement = 31
+
+// ByteRangeElements returns the range [first, first+count) of the blob field elements covering the packed data
+// bytes [offset, offset+length).
+func ByteRangeElements(offset, length int) (first, count int) {
+	if length <= 0 {
+		return offset / DataBytesPerFieldElement, 0
+	}
+	first = offset / DataBytesPerFieldElement
+	last := (offset + length - 1) / DataBytesPerFieldElement
+	return first, last - first + 1
+}
+
+// ByteRangeProof proves that a byte range of the data packed into a blob is part of the committed blob.
+type ByteRangeProof struct {
+	// Offset and Length of the range in the packed data of the blob, see DataBytesPerFieldElement.
+	Offset int
+	Length int
+	// Elements are the blob field elements covering the range, which also contain the bytes around it.
+	Elements [][32]byte
+	// Proof is the multi-proof of the opening of the blob at the domain points of the elements.
+	Proof KZGProof
+}
+
+// checkByteRange checks that the byte range fits in a blob, and can be opened with the setup.
+func (ctx *Context) checkByteRange(offset, length int) (first, count int, err error) {
+	if offset < 0 || length <= 0 {
+		return 0, 0, fmt.Errorf("invalid byte range: offset %d, length %d", offset, length)
+	}
+	first, count = ByteRangeElements(offset, length)
+	if first+count > ctx.FieldElementsPerBlob() {
+		return 0, 0, fmt.Errorf("byte range [%d, %d) exceeds the %d data bytes of a blob",
+			offset, offset+length, ctx.FieldElementsPerBlob()*DataBytesPerFieldElement)
+	}
+	if count >= len(ctx.setupG2) {
+		return 0, 0, fmt.Errorf("%w: byte range covers %d field elements, more than the G2 setup of %d powers allows", ErrSetupTooSmall, count, len(ctx.setupG2))
+	}
+	return first, count, nil
+}
+
+// ComputeByteRangeProof proves that the packed data bytes [offset, offset+length) are in the blob,
+// with a multi-proof of the covering field elements. The number of covered field elements must be lower than
+// the number of G2 powers of the setup: 64 with the Ethereum setup, or up to 1984 bytes.
+func (ctx *Context) ComputeByteRangeProof(blob Blob, offset, length int) (*ByteRangeProof, error) {
+	first, count, err := ctx.checkByteRange(offset, length)
+	if err != nil {
+		return nil, err
+	}
+	if blob.Len() != ctx.FieldElementsPerBlob() {
+		return nil, fmt.Errorf("%w: blob has %d field elements, expected %d", ErrWrongBlobLength, blob.Len(), ctx.FieldElementsPerBlob())
+	}
+	poly, ok := BlobToPolynomial(blob)
+	if !ok {
+		return nil, ctx.blobError(blob)
+	}
+	proof, _, err := ctx.ComputeKZGMultiProof(poly, ctx.domain[first:first+count])
+	if err != nil {
+		return nil, err
+	}
+	elements := make([][32]byte, count)
+	for i := range elements {
+		elements[i] = blob.At(first + i)
+	}
+	return &ByteRangeProof{Offset: offset, Length: length, Elements: elements, Proof: proof}, nil
+}
+
+// ComputeByteRangeProof calls ComputeByteRangeProof on the default context.
+func ComputeByteRangeProof(blob Blob, offset, length int) (*ByteRangeProof, error) {
+	return defaultContext().ComputeByteRangeProof(blob, offset, length)
+}
+
+// VerifyByteRangeProof verifies that data is the byte range of the proof, in the blob of the commitment.
+func (ctx *Context) VerifyByteRangeProof(commitment KZGCommitment, data []byte, proof *ByteRangeProof) (bool, error) {
+	first, count, err := ctx.checkByteRange(proof.Offset, proof.Length)
+	if err != nil {
+		return false, err
+	}
+	if len(data) != proof.Length {
+		return false, fmt.Errorf("got %d bytes of data for a range of %d bytes", len(data), proof.Length)
+	}
+	if len(proof.Elements) != count {
+		return false, fmt.Errorf("got %d field elements for a range covering %d", len(proof.Elements), count)
+	}
+	packed := make([]byte, 0, count*DataBytesPerFieldElement)
+	ys := make([]bls.Fr, count)
+	for i := range proof.Elements {
+		if proof.Elements[i][DataBytesPerFieldElement] != 0 {
+			return false, fmt.Errorf("field element %d does not hold packed data", first+i)
+		}
+		if !bls.FrFrom32(&ys[i], proof.Elements[i]) {
+			return false, fmt.Errorf("field element %d: %w", first+i, ErrNonCanonicalScalar)
+		}
+		packed = append(packed, proof.Elements[i][:DataBytesPerFieldElement]...)
+	}
+	start := proof.Offset - first*DataBytesPerFieldElement
+	if !bytes.Equal(packed[start:start+proof.Length], data) {
+		return false, nil
+	}
+	commitmentG1, err := ctx.decodeCommitment(commitment)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	// decodeProof takes a copy of the proof, as cgo backends must not be passed pointers into the byte range
+	// proof, which holds Go pointers
+	proofG1, err := ctx.decodeProof(proof.Proof)
+	if err != nil {
+		return false, err
+	}
+	return ctx.VerifyKZGMultiProofFromPoints(commitmentG1, ctx.domain[first:first+count], ys, proofG1)
+}
+
+// VerifyByteRangeProof calls VerifyByteRangeProof on the default context.
+func VerifyByteRangeProof(commitment KZGCommitment, data []byte, proof *ByteRangeProof) (bool, error) {
+	return defaultContext().VerifyByteRangeProof(commitment, data, proof)
+}
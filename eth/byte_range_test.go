@@ -0,0 +1,77 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestByteRangeElements(t *testing.T) {
+	for _, c := range []struct{ offset, length, first, count int }{
+		{0, 1, 0, 1},
+		{0, 31, 0, 1},
+		{30, 2, 0, 2},
+		{31, 31, 1, 1},
+		{40, 100, 1, 4},
+		{10, 0, 0, 0},
+	} {
+		first, count := ByteRangeElements(c.offset, c.length)
+		if first != c.first || count != c.count {
+			t.Errorf("range [%d, %d): got elements %d+%d, expected %d+%d",
+				c.offset, c.offset+c.length, first, count, c.first, c.count)
+		}
+	}
+}
+
+func TestByteRangeProof(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	data := make([]byte, 16*DataBytesPerFieldElement)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	blob := make(testBlob, 16)
+	for i := range blob {
+		copy(blob[i][:DataBytesPerFieldElement], data[i*DataBytesPerFieldElement:])
+	}
+	poly, ok := BlobToPolynomial(blob)
+	if !ok {
+		t.Fatal("expected packed data to be canonical")
+	}
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+
+	offset, length := 50, 70
+	proof, err := ctx.ComputeByteRangeProof(blob, offset, length)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err = ctx.VerifyByteRangeProof(commitment, data[offset:offset+length], proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected byte range proof to verify")
+	}
+
+	tampered := append([]byte(nil), data[offset:offset+length]...)
+	tampered[3] ^= 1
+	ok, err = ctx.VerifyByteRangeProof(commitment, tampered, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected tampered data to fail")
+	}
+	proof.Elements[1][0] ^= 1
+	ok, err = ctx.VerifyByteRangeProof(commitment, data[offset:offset+length], proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected tampered field elements to fail")
+	}
+	if _, err := ctx.ComputeByteRangeProof(blob, len(data)-10, 11); err == nil {
+		t.Fatal("expected a range past the blob to be rejected")
+	}
+}
@@ -0,0 +1,145 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// BytesPerBlob is the size of the flat encoding of a blob of the default context.
+const BytesPerBlob = FieldElementsPerBlob * 32
+
+// BlobBytes is a blob of the default context as a flat byte array, of the 32-byte little-endian
+// field elements, as most callers store blobs. It is the Blob type of the c-kzg-4844 API, named so as not to
+// clash with the Blob interface, which *BlobBytes implements.
+type BlobBytes [BytesPerBlob]byte
+
+// Bytes48 is a serialized G1 point, a commitment or a proof, as in the c-kzg-4844 API.
+type Bytes48 [48]byte
+
+// Bytes32 is a serialized field element, little-endian, as in the c-kzg-4844 API.
+type Bytes32 [32]byte
+
+// BlobBytesFromSlice copies a flat blob encoding, which must be BytesPerBlob long.
+func BlobBytesFromSlice(b []byte) (*BlobBytes, error) {
+	var out BlobBytes
+	if len(b) != len(out) {
+		return nil, fmt.Errorf("blob has %d bytes, expected %d", len(b), len(out))
+	}
+	copy(out[:], b)
+	return &out, nil
+}
+
+// Len returns the number of field elements of the blob.
+func (b *BlobBytes) Len() int {
+	return FieldElementsPerBlob
+}
+
+// At returns the encoding of the i'th field element.
+func (b *BlobBytes) At(i int) (out [32]byte) {
+	copy(out[:], b[i*32:])
+	return out
+}
+
+// BlobBytesSequence is a BlobSequence of flat blobs.
+type BlobBytesSequence []BlobBytes
+
+func (s BlobBytesSequence) Len() int {
+	return len(s)
+}
+
+func (s BlobBytesSequence) At(i int) Blob {
+	return &s[i]
+}
+
+// BlobSlice is a Blob over a flat encoding of little-endian 32-byte field elements, aliasing the memory
+// of the caller instead of copying it like BlobBytesFromSlice: the bytes must not be modified while in use.
+// See BlobView.
+type BlobSlice []byte
+
+// BlobView returns a Blob over the flat blob encoding, without copying it. The encoding must have
+// the number of field elements of the context.
+func (ctx *Context) BlobView(b []byte) (BlobSlice, error) {
+	if n := ctx.FieldElementsPerBlob() * 32; len(b) != n {
+		return nil, fmt.Errorf("%w: blob has %d bytes, expected %d", ErrWrongBlobLength, len(b), n)
+	}
+	return BlobSlice(b), nil
+}
+
+// BlobView calls BlobView on the default context.
+func BlobView(b []byte) (BlobSlice, error) {
+	return defaultContext().BlobView(b)
+}
+
+// Len returns the number of field elements of the blob.
+func (b BlobSlice) Len() int {
+	return len(b) / 32
+}
+
+// At returns the encoding of the i'th field element.
+func (b BlobSlice) At(i int) [32]byte {
+	return *(*[32]byte)(b[i*32 : i*32+32])
+}
+
+// Element returns the encoding of the i'th field element, as a sub-slice of the blob.
+func (b BlobSlice) Element(i int) []byte {
+	return b[i*32 : i*32+32 : i*32+32]
+}
+
+// BlobSliceSequence is a BlobSequence of blob views.
+type BlobSliceSequence []BlobSlice
+
+func (s BlobSliceSequence) Len() int {
+	return len(s)
+}
+
+func (s BlobSliceSequence) At(i int) Blob {
+	return s[i]
+}
+
+// Bytes48FromSlice copies a serialized G1 point, which must be 48 bytes long.
+func Bytes48FromSlice(b []byte) (Bytes48, error) {
+	var out Bytes48
+	if len(b) != len(out) {
+		return out, fmt.Errorf("expected 48 bytes, got %d", len(b))
+	}
+	copy(out[:], b)
+	return out, nil
+}
+
+// KZGCommitment converts the bytes into a commitment, without validating the point.
+func (b Bytes48) KZGCommitment() KZGCommitment {
+	return KZGCommitment(b)
+}
+
+// KZGProof converts the bytes into a proof, without validating the point.
+func (b Bytes48) KZGProof() KZGProof {
+	return KZGProof(b)
+}
+
+// Bytes32FromSlice copies a serialized field element, which must be 32 bytes long.
+func Bytes32FromSlice(b []byte) (Bytes32, error) {
+	var out Bytes32
+	if len(b) != len(out) {
+		return out, fmt.Errorf("expected 32 bytes, got %d", len(b))
+	}
+	copy(out[:], b)
+	return out, nil
+}
+
+// Bytes32FromFr encodes a field element.
+func Bytes32FromFr(v *bls.Fr) Bytes32 {
+	return Bytes32(bls.FrTo32(v))
+}
+
+// Fr decodes the field element, failing if it is not canonical.
+func (b Bytes32) Fr() (*bls.Fr, error) {
+	out := new(bls.Fr)
+	if !bls.FrFrom32(out, b) {
+		return nil, fmt.Errorf("%w %x", ErrNonCanonicalScalar, b)
+	}
+	return out, nil
+}
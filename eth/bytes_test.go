@@ -0,0 +1,108 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestBytesTypes(t *testing.T) {
+	poly := randomPolynomial()
+	blob, err := BlobBytesFromSlice(flattenBlob(polynomialToBlob(poly)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BlobBytesFromSlice(blob[1:]); err == nil {
+		t.Fatal("expected short blob to be rejected")
+	}
+	commitment, err := BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commitment != PolynomialToKZGCommitment(poly) {
+		t.Fatal("expected the commitment of the polynomial")
+	}
+	proof, err := ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c48, err := Bytes48FromSlice(commitment[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	p48, err := Bytes48FromSlice(proof[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := VerifyBlobKZGProofBatch(BlobBytesSequence{*blob}, KZGCommitmentSequenceImpl{c48.KZGCommitment()},
+		KZGProofSequenceImpl{p48.KZGProof()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected proof to verify")
+	}
+	if _, err := Bytes48FromSlice(commitment[1:]); err == nil {
+		t.Fatal("expected short point to be rejected")
+	}
+
+	v := bls.RandomFr()
+	encoded := Bytes32FromFr(v)
+	b32, err := Bytes32FromSlice(encoded[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := b32.Fr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.EqualFr(decoded, v) {
+		t.Fatal("expected the field element to round-trip")
+	}
+	if _, err := (Bytes32{31: 0xff}).Fr(); err == nil {
+		t.Fatal("expected non-canonical field element to be rejected")
+	}
+}
+
+func TestBlobView(t *testing.T) {
+	poly := randomPolynomial()
+	flat := flattenBlob(polynomialToBlob(poly))
+	view, err := BlobView(flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BlobView(flat[1:]); !errors.Is(err, ErrWrongBlobLength) {
+		t.Fatalf("expected wrong blob length error, got %v", err)
+	}
+	if view.Len() != FieldElementsPerBlob {
+		t.Fatalf("expected %d field elements, got %d", FieldElementsPerBlob, view.Len())
+	}
+	commitment, err := BlobToKZGCommitment(view)
+	if err != nil || commitment != PolynomialToKZGCommitment(poly) {
+		t.Fatalf("expected the commitment of the polynomial: %v", err)
+	}
+	proof, err := ComputeBlobKZGProof(view, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := VerifyBlobKZGProofBatch(BlobSliceSequence{view}, KZGCommitmentSequenceImpl{commitment},
+		KZGProofSequenceImpl{proof})
+	if err != nil || !ok {
+		t.Fatalf("expected proof to verify: %v", err)
+	}
+
+	// the view aliases the memory of the caller
+	flat[32] ^= 1
+	if view.At(1) != *(*[32]byte)(flat[32:64]) {
+		t.Fatal("expected the view to alias the bytes")
+	}
+	element := view.Element(1)
+	element[0] ^= 1
+	if flat[32] != element[0] || len(element) != 32 || cap(element) != 32 {
+		t.Fatal("expected the element to be a sub-slice of the bytes")
+	}
+}
@@ -0,0 +1,204 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// cellBundleVersion is the first byte of the encoding of a CellBundle, for future changes of the format.
+const cellBundleVersion = 1
+
+// Cell is a sample of a blob with its proof, see VerifySample.
+type Cell struct {
+	// Row is the index of the commitment to the blob in the commitments of the bundle.
+	Row uint64
+	// Index is the sample index of the cell in the blob, see SamplePoints.
+	Index uint64
+	// Data is the field elements of the cell.
+	Data []bls.Fr
+	// Proof is the multi-proof of the cell, against the commitment of its row.
+	Proof KZGProof
+}
+
+// CellBundle is a set of cells of the same size, of one or more blobs, with the commitments to the blobs,
+// e.g. the cells a node custodies or gossips for DAS. It has a canonical binary encoding, see MarshalBinary,
+// for the network and for caches on disk.
+type CellBundle struct {
+	Commitments []KZGCommitment
+	Cells       []Cell
+}
+
+// cellEntrySize is the size of the encoding of a cell of the given number of field elements.
+func cellEntrySize(cellSize int) int {
+	return 8 + 8 + cellSize*32 + 48
+}
+
+// appendUvarint appends the uvarint encoding of v to b.
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+// MarshalBinary encodes the bundle as
+//
+//	version (1 byte) || uvarint(cell size) || uvarint(len(commitments)) || commitments (48 bytes each)
+//	|| uvarint(len(cells)) || cells
+//
+// with every cell encoded in a fixed size, as
+//
+//	uint64_le(row) || uint64_le(index) || data (32 bytes per field element) || proof (48 bytes)
+//
+// The cell size is the number of field elements of each cell, zero if there are no cells.
+// It returns an error if the cells are not all of the same, non-zero, size, or if one refers to a missing commitment.
+func (b *CellBundle) MarshalBinary() ([]byte, error) {
+	cellSize := 0
+	if len(b.Cells) > 0 {
+		cellSize = len(b.Cells[0].Data)
+		if cellSize == 0 {
+			return nil, errors.New("empty cell")
+		}
+	}
+	for i := range b.Cells {
+		if len(b.Cells[i].Data) != cellSize {
+			return nil, fmt.Errorf("cell %d has %d field elements, expected %d", i, len(b.Cells[i].Data), cellSize)
+		}
+		if b.Cells[i].Row >= uint64(len(b.Commitments)) {
+			return nil, fmt.Errorf("cell %d refers to row %d of %d commitments", i, b.Cells[i].Row, len(b.Commitments))
+		}
+	}
+	out := make([]byte, 0, 1+3*binary.MaxVarintLen64+len(b.Commitments)*48+len(b.Cells)*cellEntrySize(cellSize))
+	out = append(out, cellBundleVersion)
+	out = appendUvarint(out, uint64(cellSize))
+	out = appendUvarint(out, uint64(len(b.Commitments)))
+	for i := range b.Commitments {
+		out = append(out, b.Commitments[i][:]...)
+	}
+	out = appendUvarint(out, uint64(len(b.Cells)))
+	for i := range b.Cells {
+		c := &b.Cells[i]
+		var position [16]byte
+		binary.LittleEndian.PutUint64(position[0:8], c.Row)
+		binary.LittleEndian.PutUint64(position[8:16], c.Index)
+		out = append(out, position[:]...)
+		for j := range c.Data {
+			v := bls.FrTo32(&c.Data[j])
+			out = append(out, v[:]...)
+		}
+		out = append(out, c.Proof[:]...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary decodes a bundle encoded by MarshalBinary, replacing the contents of b. The encoding is canonical:
+// it rejects non-minimal varints, trailing bytes, cells referring to a missing commitment,
+// and, with an error wrapping ErrNonCanonicalScalar, field elements that are not canonical.
+// The commitments and proofs are not decoded as points, which VerifyCellBundle does.
+func (b *CellBundle) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty cell bundle")
+	}
+	if data[0] != cellBundleVersion {
+		return fmt.Errorf("unknown cell bundle version %d", data[0])
+	}
+	rest := data[1:]
+	readCount := func(name string, entrySize int) (int, error) {
+		v, n := binary.Uvarint(rest)
+		if n <= 0 || n != len(appendUvarint(nil, v)) {
+			return 0, fmt.Errorf("invalid %s", name)
+		}
+		rest = rest[n:]
+		// bound the count by the remaining data, before allocating for it
+		if entrySize > 0 && v > uint64(len(rest)/entrySize) {
+			return 0, fmt.Errorf("%s %d exceeds the remaining %d bytes", name, v, len(rest))
+		}
+		return int(v), nil
+	}
+	cellSize, err := readCount("cell size", 32)
+	if err != nil {
+		return err
+	}
+	commitments, err := readCount("number of commitments", 48)
+	if err != nil {
+		return err
+	}
+	bundle := CellBundle{Commitments: make([]KZGCommitment, commitments)}
+	for i := range bundle.Commitments {
+		copy(bundle.Commitments[i][:], rest[i*48:])
+	}
+	rest = rest[commitments*48:]
+	cells, err := readCount("number of cells", cellEntrySize(cellSize))
+	if err != nil {
+		return err
+	}
+	if cells > 0 && cellSize == 0 {
+		return errors.New("empty cell")
+	}
+	if len(rest) != cells*cellEntrySize(cellSize) {
+		return fmt.Errorf("%d bytes of cells, expected %d", len(rest), cells*cellEntrySize(cellSize))
+	}
+	bundle.Cells = make([]Cell, cells)
+	for i := range bundle.Cells {
+		c := &bundle.Cells[i]
+		c.Row = binary.LittleEndian.Uint64(rest[0:8])
+		c.Index = binary.LittleEndian.Uint64(rest[8:16])
+		if c.Row >= uint64(commitments) {
+			return fmt.Errorf("cell %d refers to row %d of %d commitments", i, c.Row, commitments)
+		}
+		rest = rest[16:]
+		c.Data = make([]bls.Fr, cellSize)
+		for j := range c.Data {
+			var v [32]byte
+			copy(v[:], rest[:32])
+			if !bls.FrFrom32(&c.Data[j], v) {
+				return fmt.Errorf("%w: element %d of cell %d", ErrNonCanonicalScalar, j, i)
+			}
+			rest = rest[32:]
+		}
+		copy(c.Proof[:], rest[:48])
+		rest = rest[48:]
+	}
+	*b = bundle
+	return nil
+}
+
+// VerifyCellBundle verifies the proofs of all the cells of the bundle against the commitments of their rows,
+// in parallel over the pool of workers (see SetMaxWorkers). It returns nil if all of them are valid, and otherwise
+// the error of VerifySample for the first invalid cell, prefixed with its position in the bundle.
+func (ctx *Context) VerifyCellBundle(bundle *CellBundle) error {
+	if err := ctx.checkCellLimit(len(bundle.Cells)); err != nil {
+		return err
+	}
+	// every cell is checked with an interpolation MSM, a G2 multiplication and a pairing check
+	bytes, mults := len(bundle.Commitments)*48, 0
+	for i := range bundle.Cells {
+		bytes += 16 + 32*len(bundle.Cells[i].Data) + 48
+		mults += len(bundle.Cells[i].Data) + 1
+	}
+	defer ctx.reportUsage("VerifyCellBundle", bytes, mults)
+	errs := make([]error, len(bundle.Cells))
+	parallelFor(len(bundle.Cells), func(i int) {
+		c := &bundle.Cells[i]
+		if c.Row >= uint64(len(bundle.Commitments)) {
+			errs[i] = fmt.Errorf("row %d of %d commitments", c.Row, len(bundle.Commitments))
+			return
+		}
+		errs[i] = ctx.VerifySample(bundle.Commitments[c.Row], c.Index, c.Data, c.Proof)
+	})
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("cell %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// VerifyCellBundle calls VerifyCellBundle on the default context.
+func VerifyCellBundle(bundle *CellBundle) error {
+	return defaultContext().VerifyCellBundle(bundle)
+}
@@ -0,0 +1,96 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestCellBundle(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var bundle CellBundle
+	for row := 0; row < 2; row++ {
+		poly := randomPolynomialN(16)
+		bundle.Commitments = append(bundle.Commitments, ctx.PolynomialToKZGCommitment(poly))
+		for _, index := range []uint64{1, 3} {
+			points, err := ctx.SamplePoints(index, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, _, err := ctx.ComputeKZGMultiProof(poly, points)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bundle.Cells = append(bundle.Cells, Cell{Row: uint64(row), Index: index, Data: poly[index*4 : index*4+4], Proof: proof})
+		}
+	}
+	if err := ctx.VerifyCellBundle(&bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := bundle.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1+1+1+2*48+1+4*cellEntrySize(4) {
+		t.Fatalf("unexpected encoding size %d", len(data))
+	}
+	var decoded CellBundle
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyCellBundle(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	again, err := decoded.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, data) {
+		t.Fatal("expected the decoded bundle to encode to the same bytes")
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("expected truncated bundle to be rejected")
+	}
+	if err := decoded.UnmarshalBinary(append(data, 0)); err == nil {
+		t.Fatal("expected trailing bytes to be rejected")
+	}
+	nonCanonical := append([]byte(nil), data...)
+	copy(nonCanonical[1+1+1+2*48+1+16:], bytes.Repeat([]byte{0xff}, 32))
+	if err := decoded.UnmarshalBinary(nonCanonical); !errors.Is(err, ErrNonCanonicalScalar) {
+		t.Fatalf("expected non-canonical field element to be rejected, got %v", err)
+	}
+	nonMinimal := append([]byte{cellBundleVersion, 0x84, 0x00}, data[2:]...)
+	if err := decoded.UnmarshalBinary(nonMinimal); err == nil {
+		t.Fatal("expected non-minimal varint to be rejected")
+	}
+	if err := decoded.UnmarshalBinary([]byte{cellBundleVersion, 0, 0, 0xff, 0xff, 0xff, 0xff, 0x0f}); err == nil {
+		t.Fatal("expected cell count beyond the data to be rejected")
+	}
+
+	tampered := bundle
+	tampered.Cells = append([]Cell(nil), bundle.Cells...)
+	tampered.Cells[3].Row = 0
+	if err := ctx.VerifyCellBundle(&tampered); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected a cell against another row to be rejected, got %v", err)
+	}
+	tampered.Cells[3].Row = 2
+	if _, err := tampered.MarshalBinary(); err == nil {
+		t.Fatal("expected a cell of a missing row to be rejected")
+	}
+	tampered.Cells[3] = Cell{Row: 1, Data: make([]bls.Fr, 2)}
+	if _, err := tampered.MarshalBinary(); err == nil {
+		t.Fatal("expected cells of different sizes to be rejected")
+	}
+
+	var empty CellBundle
+	if data, err := empty.MarshalBinary(); err != nil || !bytes.Equal(data, []byte{cellBundleVersion, 0, 0, 0}) {
+		t.Fatalf("unexpected encoding of the empty bundle: %x, %v", data, err)
+	}
+}
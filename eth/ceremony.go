@@ -0,0 +1,197 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	kzg "github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// CeremonyTranscript is the transcript.json output of the Ethereum KZG ceremony:
+// https://github.com/ethereum/kzg-ceremony-specs/blob/master/docs/sequencer/sequencer.md
+type CeremonyTranscript struct {
+	Transcripts    []CeremonySubTranscript `json:"transcripts"`
+	ParticipantIDs []string                `json:"participantIds"`
+}
+
+// CeremonySubTranscript holds the powers of tau of one of the ceremony sizes,
+// and the witness of the contributions that produced them.
+type CeremonySubTranscript struct {
+	NumG1Powers int `json:"numG1Powers"`
+	NumG2Powers int `json:"numG2Powers"`
+	PowersOfTau struct {
+		G1Powers []string `json:"G1Powers"`
+		G2Powers []string `json:"G2Powers"`
+	} `json:"powersOfTau"`
+	Witness struct {
+		RunningProducts []string `json:"runningProducts"`
+		PotPubkeys      []string `json:"potPubkeys"`
+	} `json:"witness"`
+}
+
+// ParseCeremonyTranscript parses a ceremony transcript in JSON format. The points are not decoded or verified,
+// see CeremonyTranscript.TrustedSetup.
+func ParseCeremonyTranscript(data []byte) (*CeremonyTranscript, error) {
+	var t CeremonyTranscript
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse ceremony transcript: %v", err)
+	}
+	return &t, nil
+}
+
+// TrustedSetup verifies the sub-transcript with numG1Powers G1 powers, and converts it into a trusted setup,
+// with the Lagrange form computed from the G1 powers.
+func (t *CeremonyTranscript) TrustedSetup(numG1Powers int) (*JSONTrustedSetup, error) {
+	for i := range t.Transcripts {
+		if t.Transcripts[i].NumG1Powers == numG1Powers {
+			return t.Transcripts[i].TrustedSetup()
+		}
+	}
+	return nil, fmt.Errorf("no transcript with %d G1 powers", numG1Powers)
+}
+
+// TrustedSetup verifies the sub-transcript, and converts it into a trusted setup,
+// with the Lagrange form computed from the G1 powers.
+func (st *CeremonySubTranscript) TrustedSetup() (*JSONTrustedSetup, error) {
+	g1Powers, g2Powers, err := st.verify()
+	if err != nil {
+		return nil, err
+	}
+	if !isPowerOfTwo(uint64(len(g1Powers))) {
+		return nil, fmt.Errorf("number of G1 powers must be a power of two, got %d", len(g1Powers))
+	}
+	scale := uint8(bits.TrailingZeros64(uint64(len(g1Powers))))
+	lagrange, err := kzg.NewFFTSettings(scale).FFTG1(g1Powers, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute lagrange setup: %v", err)
+	}
+	return &JSONTrustedSetup{
+		Name:          "Ethereum KZG ceremony",
+		SetupG1:       g1Powers,
+		SetupG2:       g2Powers,
+		SetupLagrange: lagrange,
+	}, nil
+}
+
+// Verify checks that the sub-transcript is well-formed: the powers are consecutive powers of the same tau,
+// and tau is the product of the secrets of all the contributions, as attested by the pot pubkeys.
+func (st *CeremonySubTranscript) Verify() error {
+	_, _, err := st.verify()
+	return err
+}
+
+func (st *CeremonySubTranscript) verify() ([]bls.G1Point, []bls.G2Point, error) {
+	if len(st.PowersOfTau.G1Powers) != st.NumG1Powers || len(st.PowersOfTau.G2Powers) != st.NumG2Powers {
+		return nil, nil, fmt.Errorf("expected %d G1 and %d G2 powers, got %d and %d", st.NumG1Powers, st.NumG2Powers,
+			len(st.PowersOfTau.G1Powers), len(st.PowersOfTau.G2Powers))
+	}
+	if st.NumG1Powers < 2 || st.NumG2Powers < 2 {
+		return nil, nil, errors.New("transcript needs at least 2 G1 and 2 G2 powers")
+	}
+	// the G2 powers are checked with the randomizers of the G1 powers
+	if st.NumG2Powers > st.NumG1Powers {
+		return nil, nil, fmt.Errorf("transcript has more G2 powers (%d) than G1 powers (%d)", st.NumG2Powers, st.NumG1Powers)
+	}
+	if len(st.Witness.RunningProducts) != len(st.Witness.PotPubkeys) || len(st.Witness.RunningProducts) == 0 {
+		return nil, nil, fmt.Errorf("witness has %d running products and %d pot pubkeys",
+			len(st.Witness.RunningProducts), len(st.Witness.PotPubkeys))
+	}
+	g1Powers, err := decodeCeremonyG1s(st.PowersOfTau.G1Powers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid G1 power: %v", err)
+	}
+	g2Powers, err := decodeCeremonyG2s(st.PowersOfTau.G2Powers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid G2 power: %v", err)
+	}
+	runningProducts, err := decodeCeremonyG1s(st.Witness.RunningProducts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid running product: %v", err)
+	}
+	potPubkeys, err := decodeCeremonyG2s(st.Witness.PotPubkeys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid pot pubkey: %v", err)
+	}
+
+	if !bls.EqualG1(&g1Powers[0], &bls.GenG1) || !bls.EqualG2(&g2Powers[0], &bls.GenG2) {
+		return nil, nil, errors.New("powers of tau do not start with the generators")
+	}
+
+	// each contribution multiplies the running product by the secret of its pot pubkey
+	if !bls.EqualG1(&runningProducts[0], &bls.GenG1) {
+		return nil, nil, errors.New("running products do not start with the generator")
+	}
+	for i := 1; i < len(runningProducts); i++ {
+		if !bls.PairingsVerify(&runningProducts[i], &bls.GenG2, &runningProducts[i-1], &potPubkeys[i]) {
+			return nil, nil, fmt.Errorf("contribution %d does not match its pot pubkey", i)
+		}
+	}
+	if !bls.EqualG1(&runningProducts[len(runningProducts)-1], &g1Powers[1]) {
+		return nil, nil, errors.New("last running product does not match the first power of tau")
+	}
+
+	// check all consecutive powers at once, with a random linear combination:
+	// e(sum(r_i * [tau^(i+1)]_1), [1]_2) == e(sum(r_i * [tau^i]_1), [tau]_2)
+	n := len(g1Powers) - 1
+	factors := make([]bls.Fr, n)
+	for i := range factors {
+		bls.CopyFr(&factors[i], bls.RandomFr())
+	}
+	lo := bls.LinCombG1(g1Powers[:n], factors)
+	hi := bls.LinCombG1(g1Powers[1:], factors)
+	if !bls.PairingsVerify(hi, &bls.GenG2, lo, &g2Powers[1]) {
+		return nil, nil, errors.New("G1 powers are not consecutive powers of tau")
+	}
+	// e([tau]_1, sum(r_i * [tau^i]_2)) == e([1]_1, sum(r_i * [tau^(i+1)]_2))
+	m := len(g2Powers) - 1
+	lo2 := bls.LinCombG2(g2Powers[:m], factors[:m])
+	hi2 := bls.LinCombG2(g2Powers[1:], factors[:m])
+	if !bls.PairingsVerify(&g1Powers[1], lo2, &bls.GenG1, hi2) {
+		return nil, nil, errors.New("G2 powers are not consecutive powers of tau")
+	}
+	return g1Powers, g2Powers, nil
+}
+
+func decodeCeremonyHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+func decodeCeremonyG1s(values []string) ([]bls.G1Point, error) {
+	out := make([]bls.G1Point, len(values))
+	for i, v := range values {
+		b, err := decodeCeremonyHex(v)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", i, err)
+		}
+		p, err := bls.FromCompressedG1(b)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", i, err)
+		}
+		bls.CopyG1(&out[i], p)
+	}
+	return out, nil
+}
+
+func decodeCeremonyG2s(values []string) ([]bls.G2Point, error) {
+	out := make([]bls.G2Point, len(values))
+	for i, v := range values {
+		b, err := decodeCeremonyHex(v)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", i, err)
+		}
+		p, err := bls.FromCompressedG2(b)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", i, err)
+		}
+		bls.CopyG2(&out[i], p)
+	}
+	return out, nil
+}
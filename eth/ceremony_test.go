@@ -0,0 +1,130 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// testCeremonyTranscript builds a transcript of two contributions with secrets 3 and 5, so tau = 15.
+func testCeremonyTranscript(t *testing.T, numG1, numG2 int) []byte {
+	var tau, s1, s2 bls.Fr
+	bls.AsFr(&s1, 3)
+	bls.AsFr(&s2, 5)
+	bls.MulModFr(&tau, &s1, &s2)
+
+	g1Hex := func(p *bls.G1Point) string { return "0x" + hex.EncodeToString(bls.ToCompressedG1(p)) }
+	g2Hex := func(p *bls.G2Point) string { return "0x" + hex.EncodeToString(bls.ToCompressedG2(p)) }
+
+	var st CeremonySubTranscript
+	st.NumG1Powers = numG1
+	st.NumG2Powers = numG2
+	var pow, tmp bls.Fr
+	bls.CopyFr(&pow, &bls.ONE)
+	for i := 0; i < numG1 || i < numG2; i++ {
+		if i < numG1 {
+			var p1 bls.G1Point
+			bls.MulG1(&p1, &bls.GenG1, &pow)
+			st.PowersOfTau.G1Powers = append(st.PowersOfTau.G1Powers, g1Hex(&p1))
+		}
+		if i < numG2 {
+			var p2 bls.G2Point
+			bls.MulG2(&p2, &bls.GenG2, &pow)
+			st.PowersOfTau.G2Powers = append(st.PowersOfTau.G2Powers, g2Hex(&p2))
+		}
+		bls.CopyFr(&tmp, &pow)
+		bls.MulModFr(&pow, &tmp, &tau)
+	}
+	var rp1, rp2 bls.G1Point
+	bls.MulG1(&rp1, &bls.GenG1, &s1)
+	bls.MulG1(&rp2, &bls.GenG1, &tau)
+	var pk1, pk2 bls.G2Point
+	bls.MulG2(&pk1, &bls.GenG2, &s1)
+	bls.MulG2(&pk2, &bls.GenG2, &s2)
+	st.Witness.RunningProducts = []string{g1Hex(&bls.GenG1), g1Hex(&rp1), g1Hex(&rp2)}
+	st.Witness.PotPubkeys = []string{g2Hex(&bls.GenG2), g2Hex(&pk1), g2Hex(&pk2)}
+
+	data, err := json.Marshal(&CeremonyTranscript{Transcripts: []CeremonySubTranscript{st}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestCeremonyTranscript(t *testing.T) {
+	transcript, err := ParseCeremonyTranscript(testCeremonyTranscript(t, 16, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transcript.TrustedSetup(32); err == nil {
+		t.Fatal("expected missing transcript size to fail")
+	}
+	setup, err := transcript.TrustedSetup(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := NewContext(setup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poly := make(Polynomial, 16)
+	for i := range poly {
+		bls.CopyFr(&poly[i], bls.RandomFr())
+	}
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	z := bls.RandomFr()
+	proof, err := ctx.ComputeKZGProof(poly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y := ctx.EvaluatePolynomialInEvaluationForm(poly, z)
+	ok, err := ctx.VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected proof to verify with the ceremony setup")
+	}
+
+	// swapping the pot pubkeys breaks the contribution chain
+	st := &transcript.Transcripts[0]
+	pks := st.Witness.PotPubkeys
+	pks[1], pks[2] = pks[2], pks[1]
+	if err := st.Verify(); err == nil {
+		t.Fatal("expected invalid contribution chain to fail")
+	}
+	pks[1], pks[2] = pks[2], pks[1]
+
+	// a G1 power of another tau
+	g1 := st.PowersOfTau.G1Powers
+	g1[5], g1[6] = g1[6], g1[5]
+	if err := st.Verify(); err == nil {
+		t.Fatal("expected inconsistent G1 powers to fail")
+	}
+	g1[5], g1[6] = g1[6], g1[5]
+
+	g2 := st.PowersOfTau.G2Powers
+	g2[2], g2[3] = g2[3], g2[2]
+	if err := st.Verify(); err == nil {
+		t.Fatal("expected inconsistent G2 powers to fail")
+	}
+	g2[2], g2[3] = g2[3], g2[2]
+
+	if err := st.Verify(); err != nil {
+		t.Fatalf("restored transcript failed: %v", err)
+	}
+
+	// transcripts are downloaded: more G2 than G1 powers must fail, not panic
+	wide, err := ParseCeremonyTranscript(testCeremonyTranscript(t, 4, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wide.Transcripts[0].Verify(); err == nil {
+		t.Fatal("expected transcript with more G2 than G1 powers to fail")
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// FIAT_SHAMIR_PREHASH_DOMAIN is absorbed right after the domain of the aggregate challenges when they pre-hash
+// the polynomials, see SetAggregateChallengePrehash, so that their challenges never collide with those of the specs.
+const FIAT_SHAMIR_PREHASH_DOMAIN = "FSBLOBPREHASH_V1_"
+
+// SetAggregateChallengePrehash enables pre-hashing in the challenges of the aggregate proofs (HashToBLSField):
+// every polynomial is hashed on its own, with the challenge hash, in parallel over the pool of workers
+// (see SetMaxWorkers), and only the digests are absorbed, after FIAT_SHAMIR_PREHASH_DOMAIN, instead of every field
+// element, which is serial. This keeps the challenge from being the bottleneck of large aggregate batches,
+// but the challenges do not match the consensus specs: proofs only verify against contexts with the same setting.
+// It is disabled by default, and must not be called concurrently with the other methods of the context.
+func (ctx *Context) SetAggregateChallengePrehash(enabled bool) {
+	ctx.aggregatePrehash = enabled
+}
+
+// SetAggregateChallengePrehash calls SetAggregateChallengePrehash on a copy of the default context, which then replaces it, see Context.
+func SetAggregateChallengePrehash(enabled bool) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetAggregateChallengePrehash(enabled)
+		return nil
+	})
+}
+
+// polynomialDigest hashes the 32-byte little-endian encodings of the field elements of the polynomial,
+// with the hash of the transcript.
+func (h *Transcript) polynomialDigest(poly Polynomial) []byte {
+	d := h.newHash()
+	for j := range poly {
+		b32 := bls.FrTo32(&poly[j])
+		d.Write(b32[:])
+	}
+	return d.Sum(nil)
+}
+
+// absorbAggregateDigests hashes the polynomials in parallel, and absorbs their digests in order.
+func absorbAggregateDigests(h *Transcript, polys Polynomials) {
+	digests := make([][]byte, len(polys))
+	parallelFor(len(polys), func(i int) {
+		digests[i] = h.polynomialDigest(polys[i])
+	})
+	for i := range digests {
+		absorbAggregateDigest(h, i, digests[i])
+	}
+}
+
+func absorbAggregateDigest(h *Transcript, i int, digest []byte) {
+	h.absorb(func() string { return fmt.Sprintf("polynomial_digest[%d]", i) }, digest)
+}
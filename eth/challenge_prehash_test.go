@@ -0,0 +1,81 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestAggregateChallengePrehash(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs testBlobs
+	var polys Polynomials
+	var commitments KZGCommitmentSequenceImpl
+	for i := 0; i < 5; i++ {
+		poly := randomPolynomialN(16)
+		polys = append(polys, poly)
+		blobs = append(blobs, polynomialToBlob(poly))
+		commitments = append(commitments, ctx.PolynomialToKZGCommitment(poly))
+	}
+	specChallenge, _ := ctx.HashToBLSField(polys, commitments)
+	specProof, err := ctx.ComputeAggregateKZGProof(blobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx.SetAggregateChallengePrehash(true)
+	challenge, transcript := ctx.HashToBLSFieldDebug(polys, commitments)
+	if bls.EqualFr(challenge, specChallenge) {
+		t.Fatal("expected the pre-hashed challenge to differ from the one of the specs")
+	}
+	// domain, prehash domain, field elements per blob, number of polynomials, digests, commitments
+	if len(transcript.Entries) != 4+2*len(polys) {
+		t.Fatalf("got %d transcript entries", len(transcript.Entries))
+	}
+	if got := transcript.Entries[1].Data; string(got) != FIAT_SHAMIR_PREHASH_DOMAIN {
+		t.Fatalf("expected the prehash domain after the domain, got %q", got)
+	}
+	for i, poly := range polys {
+		h := sha256.New()
+		for j := range poly {
+			b32 := bls.FrTo32(&poly[j])
+			h.Write(b32[:])
+		}
+		if !bytes.Equal(transcript.Entries[4+i].Data, h.Sum(nil)) {
+			t.Fatalf("polynomial %d: expected its sha256 digest in the transcript", i)
+		}
+	}
+
+	proof, err := ctx.ComputeAggregateKZGProof(blobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof == specProof {
+		t.Fatal("expected the pre-hashed aggregate proof to differ from the one of the specs")
+	}
+	if ok, err := ctx.VerifyAggregateKZGProof(blobs, commitments, proof); err != nil || !ok {
+		t.Fatalf("expected the pre-hashed aggregate proof to verify, got %v, %v", ok, err)
+	}
+	b, err := ctx.NewAggregateProofBuilder(len(blobs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range blobs {
+		if err := b.Add(blobs[i], commitments[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if built, err := b.Finalize(); err != nil || built != proof {
+		t.Fatalf("expected the builder to compute the same pre-hashed proof, got %v", err)
+	}
+
+	ctx.SetAggregateChallengePrehash(false)
+	if ok, err := ctx.VerifyAggregateKZGProof(blobs, commitments, proof); err != nil || ok {
+		t.Fatalf("expected the pre-hashed aggregate proof not to verify without pre-hashing, got %v, %v", ok, err)
+	}
+}
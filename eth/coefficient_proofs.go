@@ -0,0 +1,70 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// ComputeKZGProofFromCoefficients is ComputeKZGProof for a polynomial given by its coefficients,
+// lowest degree first, for callers whose polynomials never exist in evaluation form.
+// The quotient (p(X) - p(z)) / (X - z) is computed by synthetic division, and committed to with the monomial
+// G1 setup (see SetupG1), which must have at least len(coeffs) powers. The proof is the same as the one of
+// ComputeKZGProof for the evaluation form of the polynomial, and is verified the same way.
+func (ctx *Context) ComputeKZGProofFromCoefficients(coeffs []bls.Fr, z *bls.Fr) (KZGProof, error) {
+	n := len(coeffs)
+	if n == 0 {
+		return KZGProof{}, errors.New("empty polynomial")
+	}
+	if err := ctx.checkSetupG1(n); err != nil {
+		return KZGProof{}, err
+	}
+	if n == 1 {
+		// a constant polynomial has a zero quotient, committed to by the point at infinity
+		return KZGProof(InfinityKZGCommitment), nil
+	}
+	// q_(i-1) = p_i + z * q_i, from the top coefficient down
+	quotient := make([]bls.Fr, n-1)
+	bls.CopyFr(&quotient[n-2], &coeffs[n-1])
+	var tmp bls.Fr
+	for i := n - 2; i > 0; i-- {
+		bls.MulModFr(&tmp, &quotient[i], z)
+		bls.AddModFr(&quotient[i-1], &coeffs[i], &tmp)
+	}
+	proof := bls.LinCombG1(ctx.setupG1[:n-1], quotient)
+	var out KZGProof
+	copy(out[:], bls.ToCompressedG1(proof))
+	return out, nil
+}
+
+// ComputeKZGProofFromCoefficients calls ComputeKZGProofFromCoefficients on the default context.
+func ComputeKZGProofFromCoefficients(coeffs []bls.Fr, z *bls.Fr) (KZGProof, error) {
+	return defaultContext().ComputeKZGProofFromCoefficients(coeffs, z)
+}
+
+// EvaluateCoefficients evaluates a polynomial given by its coefficients, lowest degree first, at x, with Horner's
+// method. x may be any point, in or outside the domain, and the polynomial may be of any degree; the empty
+// polynomial is zero. For the coefficients of a polynomial in evaluation form, see PolynomialToCoefficients,
+// the value is the one of EvaluatePolynomialInEvaluationForm.
+func EvaluateCoefficients(coeffs []bls.Fr, x *bls.Fr) *bls.Fr {
+	var out bls.Fr
+	if len(coeffs) == 0 {
+		bls.CopyFr(&out, &bls.ZERO)
+		return &out
+	}
+	bls.EvalPolyAt(&out, coeffs, x)
+	return &out
+}
+
+// EvaluateCoefficientsAt is EvaluateCoefficients at many points, evaluated in parallel over the pool of workers
+// (see SetMaxWorkers). The values are in the order of the points.
+func EvaluateCoefficientsAt(coeffs []bls.Fr, xs []bls.Fr) []bls.Fr {
+	out := make([]bls.Fr, len(xs))
+	parallelFor(len(xs), func(i int) {
+		bls.CopyFr(&out[i], EvaluateCoefficients(coeffs, &xs[i]))
+	})
+	return out
+}
@@ -0,0 +1,87 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestComputeKZGProofFromCoefficients(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := polynomialOfDegree(t, ctx, 9)
+	coeffs, err := ctx.PolynomialToCoefficients(poly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := bls.RandomFr()
+	expected, err := ctx.ComputeKZGProof(poly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the top coefficients are zero, so the proof does not depend on them
+	for _, n := range []int{len(coeffs), 10} {
+		proof, err := ctx.ComputeKZGProofFromCoefficients(coeffs[:n], z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof != expected {
+			t.Fatalf("proof from %d coefficients mismatch: got %v, expected %v", n, proof, expected)
+		}
+	}
+	// a point of the domain
+	proof, err := ctx.ComputeKZGProofFromCoefficients(coeffs, &ctx.domain[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	ok, err := ctx.VerifyKZGProof(commitment, bls.FrTo32(&ctx.domain[3]), bls.FrTo32(&poly[3]), proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected proof at a point of the domain to verify")
+	}
+
+	constant, err := ctx.ComputeKZGProofFromCoefficients(coeffs[:1], z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsInfinityProof(constant) {
+		t.Fatalf("expected identity proof for a constant, got %v", constant)
+	}
+	if _, err := ctx.ComputeKZGProofFromCoefficients(nil, z); err == nil {
+		t.Fatal("expected an empty polynomial to be rejected")
+	}
+	if _, err := ctx.ComputeKZGProofFromCoefficients(make([]bls.Fr, len(ctx.setupG1)+1), z); err == nil {
+		t.Fatal("expected a polynomial larger than the setup to be rejected")
+	}
+}
+
+func TestEvaluateCoefficients(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	coeffs, err := ctx.PolynomialToCoefficients(poly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xs := []bls.Fr{*bls.RandomFr(), bls.ZERO, ctx.domain[5]}
+	ys := EvaluateCoefficientsAt(coeffs, xs)
+	for i := range xs {
+		y := EvaluateCoefficients(coeffs, &xs[i])
+		if !bls.EqualFr(y, &ys[i]) {
+			t.Fatalf("point %d: batched evaluation mismatch", i)
+		}
+		if !bls.EqualFr(y, ctx.EvaluatePolynomialInEvaluationForm(poly, &xs[i])) {
+			t.Fatalf("point %d: evaluation mismatch with the evaluation form", i)
+		}
+	}
+	if !bls.EqualFr(&ys[2], &poly[5]) {
+		t.Fatal("expected the evaluation at a domain point to be the blob element")
+	}
+	if !bls.EqualZero(EvaluateCoefficients(nil, &xs[0])) {
+		t.Fatal("expected the empty polynomial to evaluate to zero")
+	}
+}
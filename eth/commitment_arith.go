@@ -0,0 +1,74 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// decodeCommitments decompresses the commitments, which must all be valid points.
+func decodeCommitments(commitments []KZGCommitment) ([]bls.G1Point, error) {
+	points := make([]bls.G1Point, len(commitments))
+	for i := range commitments {
+		p, err := bls.FromCompressedG1(commitments[i][:])
+		if err != nil {
+			return nil, fmt.Errorf("commitment %d: %w: %v", i, ErrInvalidCommitment, err)
+		}
+		bls.CopyG1(&points[i], p)
+	}
+	return points, nil
+}
+
+func compressCommitment(p *bls.G1Point) KZGCommitment {
+	var out KZGCommitment
+	copy(out[:], bls.ToCompressedG1(p))
+	return out
+}
+
+// AddCommitments returns the sum of the commitments, which is the commitment to the sum of the polynomials.
+// The sum of no commitments is the commitment to the zero polynomial, the point at infinity.
+func AddCommitments(commitments ...KZGCommitment) (KZGCommitment, error) {
+	points, err := decodeCommitments(commitments)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	var sum bls.G1Point
+	bls.ClearG1(&sum)
+	for i := range points {
+		bls.AddG1(&sum, &sum, &points[i])
+	}
+	return compressCommitment(&sum), nil
+}
+
+// ScaleCommitment returns the commitment multiplied by the scalar, which is the commitment to the polynomial
+// multiplied by the scalar.
+func ScaleCommitment(commitment KZGCommitment, scalar *bls.Fr) (KZGCommitment, error) {
+	p, err := bls.FromCompressedG1(commitment[:])
+	if err != nil {
+		return KZGCommitment{}, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	var out bls.G1Point
+	bls.MulG1(&out, p, scalar)
+	return compressCommitment(&out), nil
+}
+
+// LinCombCommitments returns sum(scalars[i] * commitments[i]), the commitment to the same linear combination
+// of the polynomials, with a single MSM.
+func LinCombCommitments(commitments []KZGCommitment, scalars []bls.Fr) (KZGCommitment, error) {
+	if len(commitments) != len(scalars) {
+		return KZGCommitment{}, fmt.Errorf("%w: got %d commitments but %d scalars", ErrLengthMismatch, len(commitments), len(scalars))
+	}
+	points, err := decodeCommitments(commitments)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	if len(points) == 0 {
+		var zero bls.G1Point
+		bls.ClearG1(&zero)
+		return compressCommitment(&zero), nil
+	}
+	return compressCommitment(bls.LinCombG1(points, scalars)), nil
+}
@@ -0,0 +1,60 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestCommitmentArithmetic(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	a, b := randomPolynomialN(16), randomPolynomialN(16)
+	ca, cb := ctx.PolynomialToKZGCommitment(a), ctx.PolynomialToKZGCommitment(b)
+	x, y := bls.RandomFr(), bls.RandomFr()
+
+	sum := make(Polynomial, 16)
+	combined := make(Polynomial, 16)
+	scaled := make(Polynomial, 16)
+	for i := range sum {
+		bls.AddModFr(&sum[i], &a[i], &b[i])
+		bls.MulModFr(&scaled[i], &a[i], x)
+		var term bls.Fr
+		bls.MulModFr(&term, &b[i], y)
+		bls.AddModFr(&combined[i], &scaled[i], &term)
+	}
+
+	got, err := AddCommitments(ca, cb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != ctx.PolynomialToKZGCommitment(sum) {
+		t.Fatal("sum of commitments does not match the commitment to the sum")
+	}
+	got, err = ScaleCommitment(ca, x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != ctx.PolynomialToKZGCommitment(scaled) {
+		t.Fatal("scaled commitment does not match the commitment to the scaled polynomial")
+	}
+	got, err = LinCombCommitments([]KZGCommitment{ca, cb}, []bls.Fr{*x, *y})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != ctx.PolynomialToKZGCommitment(combined) {
+		t.Fatal("linear combination of commitments does not match the commitment to the combination")
+	}
+	got, err = AddCommitments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != ctx.PolynomialToKZGCommitment(make(Polynomial, 16)) {
+		t.Fatal("empty sum does not match the commitment to zero")
+	}
+	if _, err := AddCommitments(ca, KZGCommitment{1}); err == nil {
+		t.Fatal("expected an invalid commitment to be rejected")
+	}
+}
@@ -0,0 +1,112 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// builderChunkSize is the number of field elements accumulated into the commitment at once.
+const builderChunkSize = 256
+
+// CommitmentBuilder computes the commitment to a blob incrementally, as its field elements arrive,
+// so that the MSM overlaps with the production of the data. Field elements are added in blob order,
+// and any missing trailing field elements are zero. A builder must not be used concurrently.
+type CommitmentBuilder struct {
+	ctx *Context
+	// sum of the chunks accumulated so far
+	acc bls.G1Point
+	// number of field elements accumulated into acc
+	done int
+	// field elements not accumulated yet
+	pending []bls.Fr
+	// partial 32-byte field element of the last Write
+	partial    [32]byte
+	partialLen int
+}
+
+// NewCommitmentBuilder returns a builder for the commitment to a blob of this context.
+func (ctx *Context) NewCommitmentBuilder() *CommitmentBuilder {
+	b := &CommitmentBuilder{ctx: ctx, pending: make([]bls.Fr, 0, builderChunkSize)}
+	bls.ClearG1(&b.acc)
+	return b
+}
+
+// NewCommitmentBuilder calls NewCommitmentBuilder on the default context.
+func NewCommitmentBuilder() *CommitmentBuilder {
+	return defaultContext().NewCommitmentBuilder()
+}
+
+// Len returns the number of field elements added so far.
+func (b *CommitmentBuilder) Len() int {
+	return b.done + len(b.pending)
+}
+
+// AddFieldElement adds the next field element of the blob.
+func (b *CommitmentBuilder) AddFieldElement(v *bls.Fr) error {
+	if b.Len() >= b.ctx.FieldElementsPerBlob() {
+		return fmt.Errorf("blob is full, it has %d field elements", b.ctx.FieldElementsPerBlob())
+	}
+	b.pending = append(b.pending, *v)
+	if len(b.pending) == builderChunkSize {
+		b.flush()
+	}
+	return nil
+}
+
+// Write adds the little-endian 32-byte field elements in p. The input does not have to be aligned to
+// field elements: a trailing partial field element is completed by the next call.
+// It fails on the first non-canonical field element, or when the blob is full.
+func (b *CommitmentBuilder) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		c := copy(b.partial[b.partialLen:], p)
+		b.partialLen += c
+		p = p[c:]
+		if b.partialLen < 32 {
+			n += c
+			break
+		}
+		var fr bls.Fr
+		if !bls.FrFrom32(&fr, b.partial) {
+			b.partialLen -= c
+			return n, fmt.Errorf("blob field element %d: %w", b.Len(), ErrNonCanonicalScalar)
+		}
+		if err := b.AddFieldElement(&fr); err != nil {
+			b.partialLen -= c
+			return n, err
+		}
+		b.partialLen = 0
+		n += c
+	}
+	return n, nil
+}
+
+// Commitment returns the commitment to the field elements added so far, padded with zeroes to a full blob.
+// More field elements can still be added afterwards.
+func (b *CommitmentBuilder) Commitment() (KZGCommitment, error) {
+	if b.partialLen != 0 {
+		return KZGCommitment{}, fmt.Errorf("incomplete field element, got %d of 32 bytes", b.partialLen)
+	}
+	b.flush()
+	var out KZGCommitment
+	copy(out[:], bls.ToCompressedG1(&b.acc))
+	return out, nil
+}
+
+// flush accumulates the pending field elements into the commitment.
+func (b *CommitmentBuilder) flush() {
+	if len(b.pending) == 0 {
+		return
+	}
+	start := time.Now()
+	bases := b.ctx.setupLagrange[b.done : b.done+len(b.pending)]
+	bls.AddG1(&b.acc, &b.acc, bls.LinCombG1(bases, b.pending))
+	b.ctx.metrics.MSM(len(b.pending), time.Since(start))
+	b.done += len(b.pending)
+	b.pending = b.pending[:0]
+}
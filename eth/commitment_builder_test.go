@@ -0,0 +1,92 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestCommitmentBuilder(t *testing.T) {
+	// enough field elements for multiple chunks
+	ctx := newTestContext(t, 9)
+	poly := randomPolynomialN(512)
+	expected := ctx.PolynomialToKZGCommitment(poly)
+
+	b := ctx.NewCommitmentBuilder()
+	for i := range poly {
+		if err := b.AddFieldElement(&poly[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := b.Commitment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != expected {
+		t.Fatal("commitment mismatch")
+	}
+	if err := b.AddFieldElement(&poly[0]); err == nil {
+		t.Fatal("expected full blob to be rejected")
+	}
+
+	// unaligned writes
+	var data []byte
+	for i := range poly {
+		fe := bls.FrTo32(&poly[i])
+		data = append(data, fe[:]...)
+	}
+	b = ctx.NewCommitmentBuilder()
+	for off := 0; off < len(data); off += 100 {
+		end := off + 100
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := b.Write(data[off:end]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, err = b.Commitment(); err != nil {
+		t.Fatal(err)
+	}
+	if got != expected {
+		t.Fatal("commitment mismatch for unaligned writes")
+	}
+}
+
+func TestCommitmentBuilderPartial(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	for i := 10; i < len(poly); i++ {
+		bls.CopyFr(&poly[i], &bls.ZERO)
+	}
+	b := ctx.NewCommitmentBuilder()
+	for i := 0; i < 10; i++ {
+		fe := bls.FrTo32(&poly[i])
+		if _, err := b.Write(fe[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := b.Commitment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != ctx.PolynomialToKZGCommitment(poly) {
+		t.Fatal("expected missing field elements to be zero")
+	}
+	if _, err := b.Write([]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Commitment(); err == nil {
+		t.Fatal("expected incomplete field element to be rejected")
+	}
+	nonCanonical := make([]byte, 29)
+	for i := range nonCanonical {
+		nonCanonical[i] = 0xff
+	}
+	if _, err := b.Write(nonCanonical); err == nil {
+		t.Fatal("expected non-canonical field element to be rejected")
+	}
+}
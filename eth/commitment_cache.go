@@ -0,0 +1,151 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// lruCache is a bounded least-recently-used cache, safe for concurrent use.
+// Keys must be comparable, like the KZGCommitment and hash arrays it is used with.
+type lruCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[interface{}]*list.Element
+	order   *list.List // front is the most recently used
+}
+
+type lruCacheEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:    size,
+		entries: make(map[interface{}]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+func (c *lruCache) get(key interface{}) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruCacheEntry).value, true
+}
+
+func (c *lruCache) add(key, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruCacheEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&lruCacheEntry{key: key, value: value})
+}
+
+func (c *lruCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// SetCommitmentCacheSize enables a cache of the last size decompressed commitments, consulted by the verification
+// paths, since the same commitments are decompressed many times during the lifetime of a blob (mempool, block
+// verification). A size of zero or less disables the cache, which is the default.
+// This must not be called concurrently with the other methods of the context.
+func (ctx *Context) SetCommitmentCacheSize(size int) {
+	if size <= 0 {
+		ctx.commitmentCache = nil
+		return
+	}
+	ctx.commitmentCache = newLRUCache(size)
+}
+
+// SetCommitmentCacheSize calls SetCommitmentCacheSize on a copy of the default context, which then replaces it, see Context.
+func SetCommitmentCacheSize(size int) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetCommitmentCacheSize(size)
+		return nil
+	})
+}
+
+// decodeCommitment decompresses a commitment, using the cache of the context when enabled.
+func (ctx *Context) decodeCommitment(c KZGCommitment) (*bls.G1Point, error) {
+	cache := ctx.commitmentCache
+	if cache != nil {
+		// only valid points are added, so a hit skips both the square root and the subgroup check
+		if v, ok := cache.get(c); ok {
+			p := v.(bls.G1Point)
+			return &p, nil
+		}
+	}
+	p, err := bls.FromCompressedG1(c[:])
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		cache.add(c, *p)
+	}
+	return p, nil
+}
+
+// decodeCommitmentUnchecked is decodeCommitment without the subgroup check, see VerifyOpts.SkipSubgroupCheck.
+// Cached points are checked ones, but the unchecked ones are not added to the cache.
+func (ctx *Context) decodeCommitmentUnchecked(c KZGCommitment) (*bls.G1Point, error) {
+	if cache := ctx.commitmentCache; cache != nil {
+		if v, ok := cache.get(c); ok {
+			p := v.(bls.G1Point)
+			return &p, nil
+		}
+	}
+	return bls.FromCompressedG1Unchecked(c[:])
+}
+
+// SetBlobCommitmentCacheSize enables a cache of the commitments of the last size committed blobs, keyed by
+// the sha256 hash of the blob, consulted by PolynomialToKZGCommitment and the blob commitment paths,
+// since builders and relays often commit to the same blobs again (mempool, re-orgs, bundle simulations).
+// A size of zero or less disables the cache, which is the default.
+// This must not be called concurrently with the other methods of the context.
+func (ctx *Context) SetBlobCommitmentCacheSize(size int) {
+	if size <= 0 {
+		ctx.blobCommitmentCache = nil
+		return
+	}
+	ctx.blobCommitmentCache = newLRUCache(size)
+}
+
+// SetBlobCommitmentCacheSize calls SetBlobCommitmentCacheSize on a copy of the default context, which then replaces it, see Context.
+func SetBlobCommitmentCacheSize(size int) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetBlobCommitmentCacheSize(size)
+		return nil
+	})
+}
+
+// blobHash is the sha256 hash of the blob of the polynomial, i.e. of its field elements in their 32-byte encoding.
+func blobHash(poly Polynomial) [32]byte {
+	h := sha256.New()
+	for i := range poly {
+		b := bls.FrTo32(&poly[i])
+		h.Write(b[:])
+	}
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
@@ -0,0 +1,108 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	c := newLRUCache(2)
+	c.add("a", 1)
+	c.add("b", 2)
+	// touch the first key, so the second one is evicted
+	if v, ok := c.get("a"); !ok || v.(int) != 1 {
+		t.Fatal("expected cached value")
+	}
+	c.add("c", 3)
+	if c.len() != 2 {
+		t.Fatalf("unexpected cache size %d", c.len())
+	}
+	if _, ok := c.get("b"); ok {
+		t.Fatal("expected least recently used entry to be evicted")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("expected recent entries to be kept")
+	}
+	if _, ok := c.get("c"); !ok {
+		t.Fatal("expected recent entries to be kept")
+	}
+}
+
+func TestCommitmentCache(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	ctx.SetCommitmentCacheSize(4)
+	poly := randomPolynomialN(16)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	blob := polynomialToBlob(poly)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		ok, err := ctx.VerifyBlobKZGProof(blob, commitment, proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected proof to verify")
+		}
+	}
+	if n := ctx.commitmentCache.len(); n != 1 {
+		t.Fatalf("expected one cached commitment, got %d", n)
+	}
+	// invalid commitments are not cached
+	if _, err := ctx.VerifyBlobKZGProof(blob, KZGCommitment{0x80}, proof); err == nil {
+		t.Fatal("expected invalid commitment to be rejected")
+	}
+	if n := ctx.commitmentCache.len(); n != 1 {
+		t.Fatalf("expected one cached commitment, got %d", n)
+	}
+	ctx.SetCommitmentCacheSize(0)
+	if ctx.commitmentCache != nil {
+		t.Fatal("expected cache to be disabled")
+	}
+}
+
+func TestBlobCommitmentCache(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	metrics := &countingMetrics{}
+	ctx.SetMetrics(metrics)
+	ctx.SetBlobCommitmentCacheSize(2)
+	poly := randomPolynomialN(16)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	blobCommitment, err := ctx.BlobToKZGCommitment(polynomialToBlob(poly))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blobCommitment != commitment {
+		t.Fatal("expected the cached commitment")
+	}
+	if metrics.commitments != 1 {
+		t.Fatalf("expected a single commitment computation, got %d", metrics.commitments)
+	}
+	if h := blobHash(poly); h != sha256.Sum256(flattenBlob(polynomialToBlob(poly))) {
+		t.Fatal("expected the cache key to be the hash of the blob")
+	}
+	other := randomPolynomialN(16)
+	if ctx.PolynomialToKZGCommitment(other) == commitment {
+		t.Fatal("expected another blob to get another commitment")
+	}
+	if metrics.commitments != 2 {
+		t.Fatalf("expected the other blob to be committed, got %d computations", metrics.commitments)
+	}
+	ctx.SetBlobCommitmentCacheSize(0)
+	if ctx.blobCommitmentCache != nil {
+		t.Fatal("expected cache to be disabled")
+	}
+}
+
+func flattenBlob(blob testBlob) []byte {
+	out := make([]byte, 0, 32*len(blob))
+	for i := range blob {
+		out = append(out, blob[i][:]...)
+	}
+	return out
+}
@@ -0,0 +1,403 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+
+	kzg "github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+var (
+	//go:embed trusted_setup.json
+	kzgSetupStr string
+
+	// Context used by all the package-level functions, of the embedded trusted setup loaded on first use,
+	// until replaced with SwapTrustedSetup. Holds a *Context.
+	defaultCtx     atomic.Value
+	defaultCtxOnce sync.Once
+	// Guards the loading options of the default context, see UseVerifierDefaultContext, and its replacement
+	defaultCtxMu           sync.Mutex
+	defaultCtxLoaded       bool
+	defaultCtxVerifierOnly bool
+)
+
+type JSONTrustedSetup struct {
+	// Name of the setup, e.g. of the ceremony it comes from, optional, see Context.SetupMetadata.
+	Name          string        `json:"name,omitempty"`
+	SetupG1       []bls.G1Point `json:"setup_G1"`
+	SetupG2       []bls.G2Point `json:"setup_G2"`
+	SetupLagrange []bls.G1Point `json:"setup_G1_lagrange"`
+}
+
+// Context holds a trusted setup, the matching evaluation domain, and optional precomputations.
+// Different contexts can be used side by side, e.g. with different setups or blob sizes.
+// The package-level functions all use the default context, loaded from the embedded trusted setup.
+//
+// The options of a context, set with its Set methods, are not synchronized: a context is meant to be configured
+// once, before it is shared, and must not be configured while it is in use. The package-level setters
+// do not have this restriction: they configure a copy of the default context, which then replaces it.
+type Context struct {
+	// KZG CRS for G2, all the powers of the setup
+	setupG2 []bls.G2Point
+	// KZG CRS for commitment computation, in bit-reversed order
+	setupLagrange []bls.G1Point
+	// KZG CRS for G1 (only used in tests (for proof creation))
+	setupG1 []bls.G1Point
+	// Roots of unity of the evaluation domain, in bit-reversed order
+	domain []bls.Fr
+	// FFT settings over the domain, for conversions to coefficient form, created on first use
+	fft     *kzg.FFTSettings
+	fftOnce sync.Once
+	// FK20 settings over the domain, for ComputeAllKZGProofs, created on first use
+	fk20     *kzg.FK20SingleSettings
+	fk20Once sync.Once
+	// Roots of unity of twice the size of the domain, in bit-reversed order, for VerifyBlobExtension,
+	// created on first use
+	extendedDomain     []bls.Fr
+	extendedDomainOnce sync.Once
+	// Name of the setup, see SetupMetadata, and its digest, computed on first use, see SetupDigest
+	setupName       string
+	setupDigest     [32]byte
+	setupDigestOnce sync.Once
+
+	// Optional fixed-base MSM precomputation over setupLagrange,
+	// used for both commitments and proofs when available. Holds a *bls.G1LinCombTable, so that it can be
+	// built in the background, see PrecomputeAsync. Shared with the copies of the context, see clone.
+	lagrangeTable *atomic.Value
+	// How Fiat-Shamir challenges are derived, see SetChallengeMode.
+	challengeMode  ChallengeMode
+	hashToFieldDST []byte
+	// Hash function of the Fiat-Shamir challenges, sha256 when nil, see SetChallengeHash.
+	challengeHash func() hash.Hash
+	// Fiat-Shamir domain separators, FIAT_SHAMIR_PROTOCOL_DOMAIN when empty, see SetFiatShamirDomains.
+	aggregateChallengeDomain string
+	blobChallengeDomain      string
+	// Whether the aggregate challenges absorb digests of the polynomials, see SetAggregateChallengePrehash.
+	aggregatePrehash bool
+	// Which proofs the version-generic functions use, see SetSpecVersion.
+	specVersion SpecVersion
+	// Maximum number of blobs of a combined batch check, unbounded when zero, see SetMaxBatchSize.
+	maxBatchSize int
+	// Limits of the calls, see SetLimits, and the reporter of their usage, see SetUsageReporter.
+	limits        Limits
+	usageReporter UsageReporter
+	// Optional cache of openings, see SetProofCache.
+	proofCache ProofCache
+	// How the verification functions decode commitments and proofs, see SetDecodePolicy.
+	decodePolicy DecodePolicy
+	// Costs of the backend VerifyAuto chooses its strategy with, see SetVerifyCosts, else measured on first use.
+	verifyCosts         VerifyCosts
+	measuredVerifyCosts VerifyCosts
+	verifyCostsOnce     sync.Once
+	// Optional cache of decompressed commitments, see SetCommitmentCacheSize.
+	commitmentCache *lruCache
+	// Optional cache of commitments by blob hash, see SetBlobCommitmentCacheSize.
+	blobCommitmentCache *lruCache
+
+	metrics Metrics
+}
+
+// NewContext creates a context from a parsed trusted setup. The number of field elements per blob
+// of the context is the size of the Lagrange setup, which must be a power of two.
+func NewContext(setup *JSONTrustedSetup) (*Context, error) {
+	// checked before the bit-reversal, which needs a power of two, and again by newContext
+	if !isPowerOfTwo(uint64(len(setup.SetupLagrange))) {
+		return nil, fmt.Errorf("lagrange setup size must be a power of two, got %d", len(setup.SetupLagrange))
+	}
+	return newContext(setup.Name, setup.SetupG1, bitReversalPermutation(setup.SetupLagrange), setup.SetupG2, nil)
+}
+
+// newContext creates a context from a setup with the Lagrange points in bit-reversed order, and the domain
+// of its width, or computes the domain if nil. All constructors go through it, so that no field is left out.
+func newContext(name string, setupG1 []bls.G1Point, setupLagrange []bls.G1Point, setupG2 []bls.G2Point,
+	domain []bls.Fr) (*Context, error) {
+	width := len(setupLagrange)
+	if !isPowerOfTwo(uint64(width)) {
+		return nil, fmt.Errorf("lagrange setup size must be a power of two, got %d", width)
+	}
+	if len(setupG2) < 2 {
+		return nil, errors.New("setup needs at least 2 G2 points")
+	}
+	if domain == nil {
+		domain = computeDomain(width)
+	}
+	return &Context{
+		setupG2:       setupG2,
+		setupLagrange: setupLagrange,
+		setupG1:       setupG1,
+		domain:        domain,
+		setupName:     name,
+		metrics:       noopMetrics{},
+		lagrangeTable: new(atomic.Value),
+	}, nil
+}
+
+// NewContextFromSettings creates a context from the settings of the core kzg package, reusing its setup and
+// roots of unity instead of loading and computing them again. The number of field elements per blob is the
+// maximum width of the FFT settings, and the Lagrange setup is computed from the G1 secrets.
+func NewContextFromSettings(ks *kzg.KZGSettings) (*Context, error) {
+	width := ks.MaxWidth
+	if uint64(len(ks.SecretG1)) < width {
+		return nil, fmt.Errorf("settings have %d G1 secrets, need %d", len(ks.SecretG1), width)
+	}
+	lagrange, err := ks.FFTG1(ks.SecretG1[:width], true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute lagrange setup: %v", err)
+	}
+	domain := make([]bls.Fr, width)
+	for i := range domain {
+		bls.CopyFr(&domain[i], &ks.ExpandedRootsOfUnity[reverseBits(uint64(i), width)])
+	}
+	ctx, err := newContext("", ks.SecretG1, bitReversalPermutation(lagrange), ks.SecretG2, domain)
+	if err != nil {
+		return nil, err
+	}
+	ctx.fft = ks.FFTSettings
+	return ctx, nil
+}
+
+// jsonTrustedSetupHex is JSONTrustedSetup before decoding the points, so they can be decoded in parallel.
+type jsonTrustedSetupHex struct {
+	Name          string   `json:"name"`
+	SetupG1       []string `json:"setup_G1"`
+	SetupG2       []string `json:"setup_G2"`
+	SetupLagrange []string `json:"setup_G1_lagrange"`
+}
+
+// NewContextFromJSON creates a context from a trusted setup in JSON format, see JSONTrustedSetup.
+// The points are decoded in parallel, over a pool of workers (see SetMaxWorkers).
+func NewContextFromJSON(data []byte) (*Context, error) {
+	return newContextFromJSON(data, false)
+}
+
+// NewVerifierContextFromJSON is NewContextFromJSON, but only decodes the Lagrange setup and the first two G2 points,
+// skipping the monomial G1 setup. This saves memory and loading time for binaries that only verify proofs.
+// Commitments and single-point proofs can still be computed with the Lagrange setup, but multi-point proofs can not.
+func NewVerifierContextFromJSON(data []byte) (*Context, error) {
+	return newContextFromJSON(data, true)
+}
+
+func newContextFromJSON(data []byte, verifierOnly bool) (*Context, error) {
+	var hexSetup jsonTrustedSetupHex
+	if err := json.Unmarshal(data, &hexSetup); err != nil {
+		return nil, fmt.Errorf("failed to parse trusted setup: %v", err)
+	}
+	if verifierOnly {
+		hexSetup.SetupG1 = nil
+		if len(hexSetup.SetupG2) > 2 {
+			hexSetup.SetupG2 = hexSetup.SetupG2[:2]
+		}
+	}
+	parsedSetup := JSONTrustedSetup{
+		Name:          hexSetup.Name,
+		SetupG1:       make([]bls.G1Point, len(hexSetup.SetupG1)),
+		SetupG2:       make([]bls.G2Point, len(hexSetup.SetupG2)),
+		SetupLagrange: make([]bls.G1Point, len(hexSetup.SetupLagrange)),
+	}
+	g1s, g2s := len(hexSetup.SetupG1), len(hexSetup.SetupG2)
+	errs := make([]error, g1s+g2s+len(hexSetup.SetupLagrange))
+	parallelFor(len(errs), func(i int) {
+		switch {
+		case i < g1s:
+			errs[i] = parsedSetup.SetupG1[i].UnmarshalText([]byte(hexSetup.SetupG1[i]))
+		case i < g1s+g2s:
+			errs[i] = parsedSetup.SetupG2[i-g1s].UnmarshalText([]byte(hexSetup.SetupG2[i-g1s]))
+		default:
+			j := i - g1s - g2s
+			errs[i] = parsedSetup.SetupLagrange[j].UnmarshalText([]byte(hexSetup.SetupLagrange[j]))
+		}
+	})
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		switch {
+		case i < g1s:
+			return nil, fmt.Errorf("failed to parse trusted setup: setup_G1[%d]: %v", i, err)
+		case i < g1s+g2s:
+			return nil, fmt.Errorf("failed to parse trusted setup: setup_G2[%d]: %v", i-g1s, err)
+		default:
+			return nil, fmt.Errorf("failed to parse trusted setup: setup_G1_lagrange[%d]: %v", i-g1s-g2s, err)
+		}
+	}
+	return NewContext(&parsedSetup)
+}
+
+// DefaultContext returns the context of the embedded trusted setup, used by the package-level functions.
+// The setup is loaded on first use, see Warmup.
+func DefaultContext() *Context {
+	return defaultContext()
+}
+
+// Warmup loads the embedded trusted setup of the default context and its FFT settings, which otherwise happens
+// on first use. Binaries that do not use the package-level functions do not pay for loading the setup,
+// but latency-sensitive users can call this at startup.
+func Warmup() {
+	defaultContext().fftSettings()
+}
+
+// FieldElementsPerBlob returns the number of field elements in the blobs of this context.
+func (ctx *Context) FieldElementsPerBlob() int {
+	return len(ctx.domain)
+}
+
+// Domain returns the roots of unity of the evaluation domain, in bit-reversed order.
+// The returned slice must not be modified.
+func (ctx *Context) Domain() []bls.Fr {
+	return ctx.domain
+}
+
+// Domain calls Domain on the default context.
+func Domain() []bls.Fr {
+	return defaultContext().Domain()
+}
+
+// fftSettings returns the FFT settings over the domain of the context.
+func (ctx *Context) fftSettings() *kzg.FFTSettings {
+	ctx.fftOnce.Do(func() {
+		if ctx.fft == nil {
+			ctx.fft = kzg.NewFFTSettings(uint8(bits.TrailingZeros64(uint64(len(ctx.domain)))))
+		}
+	})
+	return ctx.fft
+}
+
+// SetupG1 returns the monomial G1 setup. The returned slice must not be modified.
+func (ctx *Context) SetupG1() []bls.G1Point {
+	return ctx.setupG1
+}
+
+// SetupG1 calls SetupG1 on the default context.
+func SetupG1() []bls.G1Point {
+	return defaultContext().SetupG1()
+}
+
+// SetupG2 returns the monomial G2 setup, [tau^i]_2 for i < len. The returned slice must not be modified.
+// The number of G2 powers bounds the number of points of a multi-point opening, see VerifyKZGMultiProofFromPoints.
+func (ctx *Context) SetupG2() []bls.G2Point {
+	return ctx.setupG2
+}
+
+// UseVerifierDefaultContext makes the default context load only what is needed for verification,
+// see NewVerifierContextFromJSON. SetupG1 then returns an empty setup. This must be called before the first use of
+// the default context, and fails if it has been loaded already.
+func UseVerifierDefaultContext() error {
+	defaultCtxMu.Lock()
+	defer defaultCtxMu.Unlock()
+	if defaultCtxLoaded {
+		return errors.New("default context has already been loaded")
+	}
+	defaultCtxVerifierOnly = true
+	return nil
+}
+
+// defaultContext returns the context of the embedded trusted setup, loading it on first use.
+func defaultContext() *Context {
+	defaultCtxOnce.Do(func() {
+		defaultCtxMu.Lock()
+		defaultCtxLoaded = true
+		verifierOnly := defaultCtxVerifierOnly
+		defaultCtxMu.Unlock()
+		ctx, err := newContextFromJSON([]byte(kzgSetupStr), verifierOnly)
+		if err != nil {
+			panic(err)
+		}
+		if ctx.FieldElementsPerBlob() != FieldElementsPerBlob {
+			panic(fmt.Errorf("embedded setup has %d field elements per blob, expected %d",
+				ctx.FieldElementsPerBlob(), FieldElementsPerBlob))
+		}
+		defaultCtx.Store(ctx)
+	})
+	return defaultCtx.Load().(*Context)
+}
+
+// copyOptions copies the options set with the Set methods of another context. The caches are shared,
+// which callers with another setup must undo for the cache of blob commitments.
+func (ctx *Context) copyOptions(from *Context) {
+	ctx.metrics = from.metrics
+	ctx.decodePolicy = from.decodePolicy
+	ctx.verifyCosts = from.verifyCosts
+	ctx.challengeMode = from.challengeMode
+	ctx.hashToFieldDST = from.hashToFieldDST
+	ctx.challengeHash = from.challengeHash
+	ctx.aggregateChallengeDomain = from.aggregateChallengeDomain
+	ctx.blobChallengeDomain = from.blobChallengeDomain
+	ctx.aggregatePrehash = from.aggregatePrehash
+	ctx.specVersion = from.specVersion
+	ctx.maxBatchSize = from.maxBatchSize
+	// decompressed commitments do not depend on the setup, unlike the commitments of blobs
+	ctx.commitmentCache = from.commitmentCache
+	// openings cached with another setup fail the check of cached openings, and are misses
+	ctx.proofCache = from.proofCache
+	ctx.limits = from.limits
+	ctx.usageReporter = from.usageReporter
+	ctx.blobCommitmentCache = from.blobCommitmentCache
+}
+
+// clone returns a copy of the context, with the same setup, options, caches and Lagrange table.
+// What is computed on first use (FFT settings, setup digest, measured verification costs) is recomputed by the copy.
+func (ctx *Context) clone() *Context {
+	out := &Context{
+		setupG2:       ctx.setupG2,
+		setupLagrange: ctx.setupLagrange,
+		setupG1:       ctx.setupG1,
+		domain:        ctx.domain,
+		setupName:     ctx.setupName,
+		lagrangeTable: ctx.lagrangeTable,
+	}
+	out.copyOptions(ctx)
+	return out
+}
+
+// updateDefaultContext calls set on a copy of the default context, and makes the copy the default context
+// unless set fails. Calls in flight complete with the previous one, so that the package-level setters
+// do not race with them.
+func updateDefaultContext(set func(ctx *Context) error) error {
+	defaultContext()
+	defaultCtxMu.Lock()
+	defer defaultCtxMu.Unlock()
+	ctx := defaultCtx.Load().(*Context).clone()
+	if err := set(ctx); err != nil {
+		return err
+	}
+	defaultCtx.Store(ctx)
+	return nil
+}
+
+// SwapTrustedSetup atomically replaces the default context with one of the given setup, e.g. at a fork boundary.
+// In-flight calls of the package-level functions complete with the context they started with,
+// and later calls use the new one. The options of the previous default context (metrics, challenge mode,
+// hash and domains, spec version, limits, batch size, caches) are carried over. What depends on the setup is not:
+// the cache of blob commitments starts empty, and precomputations like the Lagrange table must be redone.
+// The setup must have FieldElementsPerBlob Lagrange points.
+func SwapTrustedSetup(newSetup *JSONTrustedSetup) error {
+	ctx, err := NewContext(newSetup)
+	if err != nil {
+		return err
+	}
+	if ctx.FieldElementsPerBlob() != FieldElementsPerBlob {
+		return fmt.Errorf("setup has %d field elements per blob, expected %d",
+			ctx.FieldElementsPerBlob(), FieldElementsPerBlob)
+	}
+	// make sure the embedded setup is loaded first, so that it cannot overwrite the new one later
+	defaultContext()
+	defaultCtxMu.Lock()
+	defer defaultCtxMu.Unlock()
+	old := defaultCtx.Load().(*Context)
+	ctx.copyOptions(old)
+	if old.blobCommitmentCache != nil {
+		ctx.blobCommitmentCache = newLRUCache(old.blobCommitmentCache.size)
+	}
+	defaultCtx.Store(ctx)
+	return nil
+}
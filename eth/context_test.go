@@ -0,0 +1,247 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	kzg "github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// newTestContext creates a context with an insecure setup of the given scale (blob size 2**scale).
+func newTestContext(t testing.TB, scale uint8) *Context {
+	width := uint64(1) << scale
+	s1, s2 := kzg.GenerateTestingSetup("1927409816240961209460912649124", width)
+	lagrange, err := kzg.NewFFTSettings(scale).FFTG1(s1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := NewContext(&JSONTrustedSetup{SetupG1: s1, SetupG2: s2, SetupLagrange: lagrange})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func TestNewContext(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	if ctx.FieldElementsPerBlob() != 16 {
+		t.Fatalf("unexpected blob size %d", ctx.FieldElementsPerBlob())
+	}
+	poly := make(Polynomial, 16)
+	for i := range poly {
+		bls.CopyFr(&poly[i], bls.RandomFr())
+	}
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	z := bls.RandomFr()
+	proof, err := ctx.ComputeKZGProof(poly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y := ctx.EvaluatePolynomialInEvaluationForm(poly, z)
+	ok, err := ctx.VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected proof to verify against its own context")
+	}
+	// the default context has a different setup and blob size
+	if _, err := ComputeKZGProof(poly, z); err == nil {
+		t.Fatal("expected default context to reject the small polynomial")
+	}
+	ok, err = VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected proof not to verify against the default context")
+	}
+
+	if _, err := NewContext(&JSONTrustedSetup{SetupG2: make([]bls.G2Point, 2), SetupLagrange: make([]bls.G1Point, 3)}); err == nil {
+		t.Fatal("expected error for non power of two setup")
+	}
+}
+
+func TestNewContextFromSettings(t *testing.T) {
+	s1, s2 := kzg.GenerateTestingSetup("1927409816240961209460912649124", 16)
+	ks := kzg.NewKZGSettings(kzg.NewFFTSettings(4), s1, s2)
+	ctx, err := NewContextFromSettings(ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := newTestContext(t, 4)
+	for i := range expected.domain {
+		if !bls.EqualFr(&ctx.domain[i], &expected.domain[i]) {
+			t.Fatalf("domain differs at %d", i)
+		}
+	}
+	poly := randomPolynomialN(16)
+	if ctx.PolynomialToKZGCommitment(poly) != expected.PolynomialToKZGCommitment(poly) {
+		t.Fatal("commitment differs from the context created from the same setup")
+	}
+}
+
+func TestDefaultContextLazy(t *testing.T) {
+	Warmup()
+	ctx := DefaultContext()
+	if ctx.FieldElementsPerBlob() != FieldElementsPerBlob || len(Domain()) != FieldElementsPerBlob || len(SetupG1()) == 0 {
+		t.Fatal("expected default context to be loaded")
+	}
+	if DefaultContext() != ctx {
+		t.Fatal("expected the default context to be loaded once")
+	}
+	// the domain computed with field multiplications matches the big.Int definition
+	width := 16
+	domain := computeDomain(width)
+	exp := new(big.Int).Div(new(big.Int).Sub(BLSModulus, big.NewInt(1)), big.NewInt(int64(width)))
+	root := new(big.Int).Exp(big.NewInt(7), exp, BLSModulus)
+	for i := range domain {
+		var expected bls.Fr
+		bigToFr(&expected, new(big.Int).Exp(root, big.NewInt(int64(reverseBits(uint64(i), uint64(width)))), BLSModulus))
+		if !bls.EqualFr(&expected, &domain[i]) {
+			t.Fatalf("domain mismatch at %d", i)
+		}
+	}
+}
+
+func TestNewVerifierContextFromJSON(t *testing.T) {
+	ctx, err := NewVerifierContextFromJSON([]byte(kzgSetupStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ctx.SetupG1()) != 0 || len(ctx.SetupG2()) != 2 {
+		t.Fatalf("unexpected setup sizes: %d G1, %d G2", len(ctx.SetupG1()), len(ctx.SetupG2()))
+	}
+	poly := randomPolynomial()
+	commitment := PolynomialToKZGCommitment(poly)
+	z := bls.RandomFr()
+	proof, err := ComputeKZGProof(poly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y := EvaluatePolynomialInEvaluationForm(poly, z)
+	ok, err := ctx.VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected proof to verify with the verifier context")
+	}
+	if _, _, err := ctx.ComputeKZGMultiProof(poly, []bls.Fr{*z}); err == nil {
+		t.Fatal("expected multi-point proof to require the G1 setup")
+	}
+	DefaultContext()
+	if err := UseVerifierDefaultContext(); err == nil {
+		t.Fatal("expected loaded default context to be rejected")
+	}
+}
+
+func TestSwapTrustedSetup(t *testing.T) {
+	old := DefaultContext()
+	old.SetSpecVersion(SpecEIP4844Aggregate)
+	t.Cleanup(func() {
+		old.SetSpecVersion(SpecDeneb)
+		defaultCtx.Store(old)
+	})
+	// A setup of the secret omega * tau, for a root of unity omega, is cheap to derive from the default one:
+	// L_i(omega * tau) = L_(i-1)(tau), so the Lagrange points are rotated by one.
+	n := uint64(FieldElementsPerBlob)
+	natural := bitReversalPermutation(old.setupLagrange)
+	lagrange := make([]bls.G1Point, n)
+	for i := range lagrange {
+		lagrange[i] = natural[(uint64(i)+n-1)%n]
+	}
+	omega := &old.domain[reverseBits(1, n)]
+	s2 := make([]bls.G2Point, 2)
+	bls.CopyG2(&s2[0], &old.setupG2[0])
+	bls.MulG2(&s2[1], &old.setupG2[1], omega)
+	if err := SwapTrustedSetup(&JSONTrustedSetup{SetupG2: s2, SetupLagrange: lagrange[:16]}); err == nil {
+		t.Fatal("expected setup of another blob size to be rejected")
+	}
+	if DefaultContext() != old {
+		t.Fatal("expected failed swap to keep the default context")
+	}
+	if err := SwapTrustedSetup(&JSONTrustedSetup{SetupG2: s2, SetupLagrange: lagrange}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := DefaultContext()
+	if ctx == old || &Domain()[0] != &ctx.domain[0] || len(SetupG1()) != 0 {
+		t.Fatal("expected the default context to be replaced")
+	}
+	if ctx.SpecVersion() != SpecEIP4844Aggregate {
+		t.Fatal("expected the options to be carried over")
+	}
+
+	poly := randomPolynomial()
+	z := bls.RandomFr()
+	y := EvaluatePolynomialInEvaluationForm(poly, z)
+	proof, err := ComputeKZGProof(poly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := PolynomialToKZGCommitment(poly)
+	for _, c := range []struct {
+		ctx      *Context
+		expected bool
+	}{{ctx, true}, {old, false}} {
+		ok, err := c.ctx.VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != c.expected {
+			t.Fatalf("expected verification result %v", c.expected)
+		}
+	}
+}
+
+func TestDefaultContextSetters(t *testing.T) {
+	old := DefaultContext()
+	t.Cleanup(func() { defaultCtx.Store(old) })
+
+	poly := randomPolynomial()
+	z := bls.RandomFr()
+	y := EvaluatePolynomialInEvaluationForm(poly, z)
+	proof, err := ComputeKZGProof(poly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := PolynomialToKZGCommitment(poly)
+
+	// the setters replace the default context, so they do not race with the verifications in flight
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			if ok, err := VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof); err != nil || !ok {
+				t.Errorf("expected proof to verify: %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		SetLimits(Limits{MaxBlobs: i + 1})
+		SetDecodePolicy(DecodePolicy{DeferSubgroupChecks: i%2 == 0})
+	}
+	<-done
+
+	ctx := DefaultContext()
+	if ctx == old {
+		t.Fatal("expected the default context to be replaced")
+	}
+	if ctx.Limits() != (Limits{MaxBlobs: 10}) || old.Limits() != (Limits{}) {
+		t.Fatalf("expected the limits to be set on the new context only, got %+v and %+v", ctx.Limits(), old.Limits())
+	}
+	if ctx.loadLagrangeTable() != old.loadLagrangeTable() || &ctx.setupLagrange[0] != &old.setupLagrange[0] {
+		t.Fatal("expected the setup to be shared")
+	}
+	if err := SetSpecVersion(SpecVersion(255)); err == nil {
+		t.Fatal("expected invalid spec version to be rejected")
+	}
+	if DefaultContext() != ctx {
+		t.Fatal("expected a failed setter to keep the default context")
+	}
+}
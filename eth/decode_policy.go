@@ -0,0 +1,81 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// DecodePolicy controls how the verification functions of a context decode commitments and proofs,
+// for the call sites that need a policy other than the one of the spec, e.g. a node that rejects points
+// at infinity from gossip. The zero value is the policy of the spec, and the default.
+// The options of VerifyOpts, per call, apply on top of the policy of the context.
+type DecodePolicy struct {
+	// RejectInfinityCommitments rejects commitments at infinity, the commitment to the zero blob,
+	// with an error wrapping ErrInvalidCommitment.
+	RejectInfinityCommitments bool
+	// RejectInfinityProofs rejects proofs at infinity, the proof of a constant polynomial,
+	// with an error wrapping ErrMalformedProof.
+	RejectInfinityProofs bool
+	// DeferSubgroupChecks defers the subgroup checks of the commitments and proofs of a blob proof batch,
+	// see VerifyBlobKZGProofBatch, until all of them are decoded and on the curve: the checks then run
+	// in parallel over the pool of workers (see SetMaxWorkers), once per distinct point, instead of one
+	// at a time while decoding. The points are still all checked. Single proofs are always checked eagerly,
+	// and the Herumi backend always checks the subgroup when decoding.
+	DeferSubgroupChecks bool
+}
+
+// SetDecodePolicy sets the policy the verification functions of the context decode commitments and proofs with.
+// It must not be called concurrently with verifications.
+func (ctx *Context) SetDecodePolicy(policy DecodePolicy) {
+	ctx.decodePolicy = policy
+}
+
+// SetDecodePolicy calls SetDecodePolicy on a copy of the default context, which then replaces it, see Context.
+func SetDecodePolicy(policy DecodePolicy) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetDecodePolicy(policy)
+		return nil
+	})
+}
+
+// DecodePolicy returns the policy the context decodes commitments and proofs with, see SetDecodePolicy.
+func (ctx *Context) DecodePolicy() DecodePolicy {
+	return ctx.decodePolicy
+}
+
+// deferredSubgroupOpts returns the options to decode the points of a batch with, with the subgroup checks deferred
+// if the policy of the context defers them and the options do not skip them, and whether they are deferred.
+func (ctx *Context) deferredSubgroupOpts(opts *VerifyOpts) (*VerifyOpts, bool) {
+	if !ctx.decodePolicy.DeferSubgroupChecks || (opts != nil && opts.SkipSubgroupCheck) {
+		return opts, false
+	}
+	deferred := VerifyOpts{SkipSubgroupCheck: true}
+	if opts != nil {
+		deferred = *opts
+		deferred.SkipSubgroupCheck = true
+	}
+	return &deferred, true
+}
+
+// checkSubgroups runs the deferred subgroup checks of the distinct commitments and of the proofs of the openings
+// of a batch, in parallel. The first error, by the order of the blobs, is returned.
+func (o *batchOpenings) checkSubgroups() error {
+	errs := make([]error, len(o.commitments))
+	parallelFor(len(o.commitments), func(k int) {
+		if o.first[k] == k && !bls.InSubgroupG1(&o.commitments[k]) {
+			errs[k] = fmt.Errorf("blob %d: %w: point not in the G1 subgroup", o.indices[k], ErrInvalidCommitment)
+		} else if !bls.InSubgroupG1(&o.proofs[k]) {
+			errs[k] = fmt.Errorf("blob %d: %w: point not in the G1 subgroup", o.indices[k], ErrMalformedProof)
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
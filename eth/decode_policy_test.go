@@ -0,0 +1,113 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// offSubgroupPoint returns an encoding of a point on the curve, off the G1 subgroup, near the given one,
+// or false if the backend does not decode such points.
+func offSubgroupPoint(near [48]byte) ([48]byte, bool) {
+	p := near
+	for {
+		p[47]++
+		_, errChecked := bls.FromCompressedG1(p[:])
+		_, errUnchecked := bls.FromCompressedG1Unchecked(p[:])
+		if errChecked != nil && errUnchecked == nil {
+			return p, true
+		}
+		if p[47] == near[47]-1 {
+			return p, false
+		}
+	}
+}
+
+func TestDecodePolicy(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	blobs := make(testBlobs, 3)
+	commitments := make(KZGCommitmentSequenceImpl, 3)
+	proofs := make(KZGProofSequenceImpl, 3)
+	for i := range blobs {
+		poly := randomPolynomialN(16)
+		if i == 0 {
+			// the zero blob commits to the point at infinity, with a proof at infinity
+			poly = make(Polynomial, 16)
+		}
+		blobs[i] = polynomialToBlob(poly)
+		commitments[i] = ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blobs[i], commitments[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofs[i] = proof
+	}
+	for _, policy := range []DecodePolicy{{}, {DeferSubgroupChecks: true}} {
+		ctx.SetDecodePolicy(policy)
+		if ok, err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != nil || !ok {
+			t.Fatalf("%+v: expected batch to verify: %v", policy, err)
+		}
+	}
+
+	var infinity KZGProof
+	copy(infinity[:], bls.ToCompressedG1(&bls.ZeroG1))
+	var z, y [32]byte
+	// the zero blob opens to zero anywhere, with a proof at infinity
+	versionedHash := KZGToVersionedHash(commitments[0])
+	input := append(append(append(append(versionedHash[:], z[:]...), y[:]...), commitments[0][:]...), infinity[:]...)
+	if _, err := ctx.PointEvaluationPrecompile(input); err != nil {
+		t.Fatalf("expected points at infinity in the precompile to be accepted: %v", err)
+	}
+
+	ctx.SetDecodePolicy(DecodePolicy{RejectInfinityCommitments: true})
+	if _, err := ctx.VerifyBlobKZGProof(blobs[0], commitments[0], proofs[0]); !errors.Is(err, ErrInvalidCommitment) {
+		t.Fatalf("expected commitment at infinity to be rejected, got %v", err)
+	}
+	if _, err := ctx.VerifyKZGProof(commitments[1], z, y, infinity); err != nil {
+		t.Fatalf("expected proof at infinity to be accepted: %v", err)
+	}
+	if _, err := ctx.PointEvaluationPrecompile(input); !errors.Is(err, ErrInvalidCommitment) {
+		t.Fatalf("expected commitment at infinity in the precompile to be rejected, got %v", err)
+	}
+	ctx.SetDecodePolicy(DecodePolicy{RejectInfinityProofs: true})
+	if _, err := ctx.VerifyKZGProof(commitments[1], z, y, infinity); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("expected proof at infinity to be rejected, got %v", err)
+	}
+	if _, err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("expected proof at infinity in a batch to be rejected, got %v", err)
+	}
+	if _, err := ctx.PointEvaluationPrecompile(input); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("expected proof at infinity in the precompile to be rejected, got %v", err)
+	}
+	if ctx.DecodePolicy() != (DecodePolicy{RejectInfinityProofs: true}) {
+		t.Fatalf("unexpected policy %+v", ctx.DecodePolicy())
+	}
+
+	offSubgroup, ok := offSubgroupPoint(proofs[2])
+	if !ok {
+		t.Skip("the backend always checks the subgroup")
+	}
+	invalid := append(KZGProofSequenceImpl(nil), proofs...)
+	invalid[2] = offSubgroup
+	for _, policy := range []DecodePolicy{{}, {DeferSubgroupChecks: true}} {
+		ctx.SetDecodePolicy(policy)
+		_, err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, invalid)
+		if !errors.Is(err, ErrMalformedProof) || !strings.HasPrefix(err.Error(), "blob 2: ") {
+			t.Fatalf("%+v: expected proof off the subgroup to be rejected, got %v", policy, err)
+		}
+		if _, err := ctx.VerifyBlobKZGProofBatchWithOpts(blobs, commitments, invalid, VerifyOpts{SkipSubgroupCheck: true}); err != nil {
+			t.Fatalf("%+v: expected the skipped subgroup check not to be deferred: %v", policy, err)
+		}
+	}
+	offSubgroupCommitment, _ := offSubgroupPoint(commitments[1])
+	invalidCommitments := append(KZGCommitmentSequenceImpl(nil), commitments...)
+	invalidCommitments[1] = offSubgroupCommitment
+	if _, err := ctx.VerifyBlobKZGProofBatch(blobs, invalidCommitments, proofs); !errors.Is(err, ErrInvalidCommitment) {
+		t.Fatalf("expected deferred check of a commitment off the subgroup to reject it, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// checkDegreeBound checks that a degree bound d can be proven with the setup, and returns the shift n - d,
+// where n is the number of field elements per blob.
+func (ctx *Context) checkDegreeBound(d int) (int, error) {
+	n := ctx.FieldElementsPerBlob()
+	if d < 1 || d > n {
+		return 0, fmt.Errorf("degree bound %d is out of range [1, %d]", d, n)
+	}
+	shift := n - d
+	if shift >= len(ctx.setupG2) {
+		return 0, fmt.Errorf("%w: degree bound %d needs G2 power %d, the setup has %d powers", ErrSetupTooSmall, d, shift, len(ctx.setupG2))
+	}
+	return shift, nil
+}
+
+// ProveDegreeBound proves that the polynomial has degree lower than d. The proof is the commitment to the
+// polynomial shifted to the top of the setup, [tau^(n-d) * p(tau)]_1, where n is the number of field elements
+// per blob, which can only be computed from the powers of tau below n if p has degree lower than d.
+// It needs the monomial G1 setup, and the G2 power n - d, see VerifyDegreeBound.
+func (ctx *Context) ProveDegreeBound(poly Polynomial, d int) (KZGProof, error) {
+	shift, err := ctx.checkDegreeBound(d)
+	if err != nil {
+		return KZGProof{}, err
+	}
+	n := ctx.FieldElementsPerBlob()
+	if err := ctx.checkSetupG1(n); err != nil {
+		return KZGProof{}, err
+	}
+	coeffs, err := ctx.PolynomialToCoefficients(poly)
+	if err != nil {
+		return KZGProof{}, err
+	}
+	for i := d; i < n; i++ {
+		if !bls.EqualZero(&coeffs[i]) {
+			return KZGProof{}, fmt.Errorf("polynomial has degree %d or more, not lower than %d", i, d)
+		}
+	}
+	proof := bls.LinCombG1(ctx.setupG1[shift:n], coeffs[:d])
+	var out KZGProof
+	copy(out[:], bls.ToCompressedG1(proof))
+	return out, nil
+}
+
+// ProveDegreeBound calls ProveDegreeBound on the default context.
+func ProveDegreeBound(poly Polynomial, d int) (KZGProof, error) {
+	return defaultContext().ProveDegreeBound(poly, d)
+}
+
+// VerifyDegreeBound verifies a proof of ProveDegreeBound, that the committed polynomial has degree lower than d:
+//
+//	e(commitment, [tau^(n-d)]_2) == e(proof, [1]_2)
+//
+// The shift n - d must be lower than the number of G2 powers of the setup, so only bounds close to the blob size
+// can be verified with the Ethereum setup. The check is only sound if the setup has no G1 powers beyond n - 1,
+// as is the case of the Ethereum setup.
+func (ctx *Context) VerifyDegreeBound(commitment KZGCommitment, proof KZGProof, d int) (bool, error) {
+	shift, err := ctx.checkDegreeBound(d)
+	if err != nil {
+		return false, err
+	}
+	commitmentG1, err := ctx.decodeCommitment(commitment)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	proofG1, err := ctx.decodeProof(proof)
+	if err != nil {
+		return false, err
+	}
+	return bls.PairingsVerify(commitmentG1, &ctx.setupG2[shift], proofG1, &bls.GenG2), nil
+}
+
+// VerifyDegreeBound calls VerifyDegreeBound on the default context.
+func VerifyDegreeBound(commitment KZGCommitment, proof KZGProof, d int) (bool, error) {
+	return defaultContext().VerifyDegreeBound(commitment, proof, d)
+}
@@ -0,0 +1,63 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// polynomialOfDegree returns a random polynomial of the given degree, in evaluation form over the context domain.
+func polynomialOfDegree(t *testing.T, ctx *Context, degree int) Polynomial {
+	width := ctx.FieldElementsPerBlob()
+	coeffs := make([]bls.Fr, width)
+	for i := 0; i <= degree; i++ {
+		bls.CopyFr(&coeffs[i], bls.RandomFr())
+	}
+	evals, err := ctx.fftSettings().FFT(coeffs, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poly := make(Polynomial, width)
+	for i := range poly {
+		bls.CopyFr(&poly[i], &evals[reverseBits(uint64(i), uint64(width))])
+	}
+	return poly
+}
+
+func TestDegreeBound(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := polynomialOfDegree(t, ctx, 9)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	for _, d := range []int{10, 12, 16} {
+		proof, err := ctx.ProveDegreeBound(poly, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := ctx.VerifyDegreeBound(commitment, proof, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("expected degree bound %d to verify", d)
+		}
+		if d > 10 {
+			// the proof of a looser bound does not prove a tighter one
+			ok, err = ctx.VerifyDegreeBound(commitment, proof, d-1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok {
+				t.Fatalf("expected proof of bound %d to fail for bound %d", d, d-1)
+			}
+		}
+	}
+	if _, err := ctx.ProveDegreeBound(poly, 9); err == nil {
+		t.Fatal("expected a bound below the degree to be rejected")
+	}
+	if _, err := ctx.ProveDegreeBound(poly, 17); err == nil {
+		t.Fatal("expected a bound above the blob size to be rejected")
+	}
+}
@@ -1,5 +1,5 @@
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package eth
 
@@ -10,25 +10,24 @@ import (
 )
 
 var (
-	BLSModulus *big.Int
-	DomainFr   []bls.Fr
+	BLSModulus, _ = new(big.Int).SetString(bls.ModulusStr, 10)
 )
 
-func initDomain() {
-	BLSModulus = new(big.Int)
-	BLSModulus.SetString(bls.ModulusStr, 10)
-
+// computeDomain returns the roots of unity for a domain of the given width (a power of two), in bit-reversed order.
+func computeDomain(width int) []bls.Fr {
 	// ROOT_OF_UNITY = pow(PRIMITIVE_ROOT, (MODULUS - 1) // WIDTH, MODULUS)
 	primitiveRoot := big.NewInt(7)
-	width := big.NewInt(int64(FieldElementsPerBlob))
-	exp := new(big.Int).Div(new(big.Int).Sub(BLSModulus, big.NewInt(1)), width)
+	exp := new(big.Int).Div(new(big.Int).Sub(BLSModulus, big.NewInt(1)), big.NewInt(int64(width)))
 	rootOfUnity := new(big.Int).Exp(primitiveRoot, exp, BLSModulus)
-	DomainFr = make([]bls.Fr, FieldElementsPerBlob)
-	for i := 0; i < FieldElementsPerBlob; i++ {
+	var root, power bls.Fr
+	_ = bigToFr(&root, rootOfUnity)
+	bls.CopyFr(&power, &bls.ONE)
+	domain := make([]bls.Fr, width)
+	for i := 0; i < width; i++ {
 		// We reverse the bits of the index as specified in https://github.com/ethereum/consensus-specs/pull/3011
 		// This effectively permutes the order of the elements in Domain
-		reversedIndex := reverseBits(uint64(i), FieldElementsPerBlob)
-		domain := new(big.Int).Exp(rootOfUnity, big.NewInt(int64(reversedIndex)), BLSModulus)
-		_ = bigToFr(&DomainFr[i], domain)
+		bls.CopyFr(&domain[reverseBits(uint64(i), uint64(width))], &power)
+		bls.MulModFr(&power, &power, &root)
 	}
+	return domain
 }
@@ -0,0 +1,99 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// EquivalenceProof shows that a blob commitment commits to the same data as a hash, e.g. the calldata hash of an
+// optimistic rollup batch: the blob is opened at a challenge derived from both the commitment and the hash,
+// and the opening can then be checked against the data itself at that point.
+// Z and Y are big-endian, like in the input of the point evaluation precompile.
+type EquivalenceProof struct {
+	Z     [32]byte
+	Y     [32]byte
+	Proof KZGProof
+}
+
+// EquivalenceChallenge returns the evaluation point of an equivalence proof:
+// sha256(commitment || dataHash) interpreted as a big-endian integer, modulo BLS_MODULUS.
+func EquivalenceChallenge(commitment KZGCommitment, dataHash [32]byte) *bls.Fr {
+	h := sha256.New()
+	h.Write(commitment[:])
+	h.Write(dataHash[:])
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	out := new(bls.Fr)
+	bls.FrFrom32Mod(out, reverse32(sum))
+	return out
+}
+
+// ComputeEquivalenceProof opens the blob at the equivalence challenge of its commitment and the data hash.
+func (ctx *Context) ComputeEquivalenceProof(blob Blob, commitment KZGCommitment, dataHash [32]byte) (EquivalenceProof, error) {
+	poly, ok := BlobToPolynomial(blob)
+	if !ok {
+		return EquivalenceProof{}, ctx.blobError(blob)
+	}
+	if _, err := ctx.decodeCommitment(commitment); err != nil {
+		return EquivalenceProof{}, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	z := EquivalenceChallenge(commitment, dataHash)
+	proof, err := ctx.ComputeKZGProof(poly, z)
+	if err != nil {
+		return EquivalenceProof{}, err
+	}
+	return EquivalenceProof{
+		Z:     bls.FrTo32BE(z),
+		Y:     bls.FrTo32BE(ctx.EvaluatePolynomialInEvaluationForm(poly, z)),
+		Proof: proof,
+	}, nil
+}
+
+// ComputeEquivalenceProof calls ComputeEquivalenceProof on the default context.
+func ComputeEquivalenceProof(blob Blob, commitment KZGCommitment, dataHash [32]byte) (EquivalenceProof, error) {
+	return defaultContext().ComputeEquivalenceProof(blob, commitment, dataHash)
+}
+
+// VerifyEquivalenceProof checks that the proof is for the equivalence challenge of the commitment and data hash,
+// and that it opens the commitment to Y. The caller still has to check that the data evaluates to Y at Z.
+func (ctx *Context) VerifyEquivalenceProof(commitment KZGCommitment, dataHash [32]byte, proof EquivalenceProof) (bool, error) {
+	z := EquivalenceChallenge(commitment, dataHash)
+	if bls.FrTo32BE(z) != proof.Z {
+		return false, nil
+	}
+	var y bls.Fr
+	if !bls.FrFrom32BE(&y, proof.Y) {
+		return false, fmt.Errorf("invalid expected output: %w", ErrNonCanonicalScalar)
+	}
+	commitmentG1, err := ctx.decodeCommitment(commitment)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	proofG1, err := ctx.decodeProof(proof.Proof)
+	if err != nil {
+		return false, err
+	}
+	return ctx.VerifyKZGProofFromPoints(commitmentG1, z, &y, proofG1), nil
+}
+
+// VerifyEquivalenceProof calls VerifyEquivalenceProof on the default context.
+func VerifyEquivalenceProof(commitment KZGCommitment, dataHash [32]byte, proof EquivalenceProof) (bool, error) {
+	return defaultContext().VerifyEquivalenceProof(commitment, dataHash, proof)
+}
+
+// PrecompileInput returns the input of the point evaluation precompile for the proof, to verify it on L1.
+func (p *EquivalenceProof) PrecompileInput(commitment KZGCommitment) []byte {
+	versionedHash := KZGToVersionedHash(commitment)
+	out := make([]byte, 0, PrecompileInputLength)
+	out = append(out, versionedHash[:]...)
+	out = append(out, p.Z[:]...)
+	out = append(out, p.Y[:]...)
+	out = append(out, commitment[:]...)
+	out = append(out, p.Proof[:]...)
+	return out
+}
@@ -0,0 +1,55 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestEquivalenceProof(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	blob := polynomialToBlob(poly)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	var data []byte
+	for i := range blob {
+		data = append(data, blob[i][:]...)
+	}
+	dataHash := sha256.Sum256(data)
+
+	proof, err := ctx.ComputeEquivalenceProof(blob, commitment, dataHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := ctx.VerifyEquivalenceProof(commitment, dataHash, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected equivalence proof to verify")
+	}
+	// the data side: evaluating the data at z gives the same y
+	var z bls.Fr
+	bls.FrFrom32BE(&z, proof.Z)
+	if bls.FrTo32BE(ctx.EvaluatePolynomialInEvaluationForm(poly, &z)) != proof.Y {
+		t.Fatal("expected data to evaluate to y")
+	}
+	if _, err := ctx.PointEvaluationPrecompile(proof.PrecompileInput(commitment)); err != nil {
+		t.Fatal(err)
+	}
+
+	// a proof for another data hash is rejected
+	otherHash := dataHash
+	otherHash[0] ^= 1
+	ok, err = ctx.VerifyEquivalenceProof(commitment, otherHash, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected proof for another data hash to fail")
+	}
+}
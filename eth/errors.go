@@ -0,0 +1,61 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The classes of the errors of this package. Errors are returned wrapped, with the details of the failure,
+// so that callers can branch on the class with errors.Is, e.g. to tell a malformed input from an invalid proof.
+var (
+	// ErrInvalidProof is returned by the verification functions that only return an error,
+	// when the inputs are well-formed but the proof does not verify.
+	ErrInvalidProof = errors.New("invalid proof")
+	// ErrMalformedProof is returned when a proof does not decode to a point of the G1 subgroup.
+	ErrMalformedProof = errors.New("malformed proof")
+	// ErrInvalidCommitment is returned when a commitment does not decode to a point of the G1 subgroup.
+	ErrInvalidCommitment = errors.New("invalid commitment")
+	// ErrNonCanonicalScalar is returned when a field element, of a blob or an evaluation point or value,
+	// is not lower than the modulus.
+	ErrNonCanonicalScalar = errors.New("non-canonical field element")
+	// ErrWrongBlobLength is returned when a blob or a polynomial does not have the number of field elements
+	// of the context.
+	ErrWrongBlobLength = errors.New("wrong blob length")
+	// ErrLengthMismatch is returned when the inputs of a batch, e.g. blobs, commitments and proofs,
+	// have different lengths.
+	ErrLengthMismatch = errors.New("mismatched lengths")
+	// ErrSetupNotLoaded is returned when the part of the trusted setup an operation needs is not loaded,
+	// e.g. the monomial G1 setup of a verifier context, or a precomputed table.
+	ErrSetupNotLoaded = errors.New("trusted setup not loaded")
+	// ErrSetupTooSmall is returned when the trusted setup has too few powers for an operation,
+	// e.g. the G2 powers bounding the number of points of a multi-point opening.
+	ErrSetupTooSmall = errors.New("trusted setup too small")
+	// ErrTooManyBlobs is returned when a batch has more blobs than allowed, see VerifyOpts.MaxBlobs,
+	// or an aggregate proof covers more than MaxBlobsPerBlock.
+	ErrTooManyBlobs = errors.New("too many blobs")
+	// ErrInvalidExtension is returned by VerifyBlobExtension when the extension of a blob does not encode
+	// the polynomial of the blob, or the blob does not match its commitment.
+	ErrInvalidExtension = errors.New("invalid blob extension")
+)
+
+// checkSetupG1 returns an error if the monomial G1 setup has fewer than n powers.
+func (ctx *Context) checkSetupG1(n int) error {
+	if len(ctx.setupG1) == 0 {
+		return fmt.Errorf("%w: no monomial G1 setup", ErrSetupNotLoaded)
+	}
+	if len(ctx.setupG1) < n {
+		return fmt.Errorf("%w: G1 setup has %d powers, need %d", ErrSetupTooSmall, len(ctx.setupG1), n)
+	}
+	return nil
+}
+
+// blobError returns the error of a blob that BlobToPolynomial rejects, classified by ValidateBlob.
+func (ctx *Context) blobError(blob Blob) error {
+	if err := ctx.ValidateBlob(blob); err != nil {
+		return err
+	}
+	return errors.New("could not convert blob to polynomial")
+}
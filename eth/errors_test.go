@@ -0,0 +1,79 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestErrorClasses(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	blob := polynomialToBlob(poly)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	badPoint := KZGCommitment{0xff}
+	nonCanonical := [32]byte{0: 0xff, 31: 0xff}
+	nonCanonicalBlob := polynomialToBlob(poly)
+	nonCanonicalBlob[3] = nonCanonical
+	verifier, err := NewVerifierContextFromJSON([]byte(kzgSetupStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		err   error
+		class error
+	}{
+		{"commitment", func() error {
+			_, err := ctx.VerifyBlobKZGProof(blob, badPoint, proof)
+			return err
+		}(), ErrInvalidCommitment},
+		{"proof", func() error {
+			_, err := ctx.VerifyBlobKZGProof(blob, commitment, KZGProof(badPoint))
+			return err
+		}(), ErrMalformedProof},
+		{"blob element", func() error {
+			_, err := ctx.VerifyBlobKZGProof(nonCanonicalBlob, commitment, proof)
+			return err
+		}(), ErrNonCanonicalScalar},
+		{"evaluation point", func() error {
+			_, err := ctx.VerifyKZGProof(commitment, nonCanonical, [32]byte{}, proof)
+			return err
+		}(), ErrNonCanonicalScalar},
+		{"blob length", func() error {
+			_, err := ctx.VerifyBlobKZGProof(polynomialToBlob(poly[:8]), commitment, proof)
+			return err
+		}(), ErrWrongBlobLength},
+		{"batch lengths", func() error {
+			_, err := ctx.VerifyBlobKZGProofBatch(testBlobs{blob}, KZGCommitmentSequenceImpl{commitment}, KZGProofSequenceImpl{})
+			return err
+		}(), ErrLengthMismatch},
+		{"verifier setup", func() error {
+			_, err := verifier.ComputeKZGProofFromCoefficients(make([]bls.Fr, 2), &bls.ONE)
+			return err
+		}(), ErrSetupNotLoaded},
+		{"setup size", func() error {
+			_, err := ctx.ComputeKZGProofFromCoefficients(make([]bls.Fr, 17), &bls.ONE)
+			return err
+		}(), ErrSetupTooSmall},
+		{"invalid proof", ctx.VerifyBlobsBundle(&BlobsBundle{
+			Commitments: []KZGCommitment{commitment},
+			Proofs:      []KZGProof{KZGProof(commitment)},
+			Blobs:       []Blob{blob},
+		}), ErrInvalidProof},
+	}
+	for _, test := range tests {
+		if !errors.Is(test.err, test.class) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.class, test.err)
+		}
+	}
+}
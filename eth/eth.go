@@ -1,5 +1,5 @@
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 // Package eth implements the various EIP-4844 function specifications as defined
 // in the EIP-4844 proposal and the EIP-4844 consensus specs:
@@ -9,6 +9,7 @@
 package eth
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/binary"
 	"errors"
@@ -20,6 +21,9 @@ import (
 const (
 	BlobCommitmentVersionKZG uint8 = 0x01
 	FieldElementsPerBlob           = 4096
+	// MaxBlobsPerBlock is MAX_BLOBS_PER_BLOCK of the mainnet preset, the most blobs
+	// an aggregate proof of a block may cover.
+	MaxBlobsPerBlock = 16
 )
 
 // The custom types from EIP-4844 consensus spec:
@@ -30,6 +34,65 @@ type VersionedHash [32]byte
 type Root [32]byte
 type Slot uint64
 
+// Equal reports whether the commitments have the same encoding.
+// Commitments are arrays, so they can also be compared with == and used as map keys directly.
+func (c KZGCommitment) Equal(other KZGCommitment) bool {
+	return c == other
+}
+
+// Compare orders commitments by their encoding, returning -1, 0 or +1, e.g. to sort them.
+func (c KZGCommitment) Compare(other KZGCommitment) int {
+	return bytes.Compare(c[:], other[:])
+}
+
+// Hash64 returns the 64-bit FNV-1a hash of the encoding, e.g. to shard sets of commitments.
+// It is not collision resistant.
+func (c KZGCommitment) Hash64() uint64 {
+	return fnv64a(c[:])
+}
+
+// Equal reports whether the proofs have the same encoding.
+// Proofs are arrays, so they can also be compared with == and used as map keys directly.
+func (p KZGProof) Equal(other KZGProof) bool {
+	return p == other
+}
+
+// Compare orders proofs by their encoding, returning -1, 0 or +1, e.g. to sort them.
+func (p KZGProof) Compare(other KZGProof) int {
+	return bytes.Compare(p[:], other[:])
+}
+
+// Hash64 returns the 64-bit FNV-1a hash of the encoding, e.g. to shard sets of proofs.
+// It is not collision resistant.
+func (p KZGProof) Hash64() uint64 {
+	return fnv64a(p[:])
+}
+
+// fnv64a is the 64-bit FNV-1a hash, without the allocation of hash/fnv.
+func fnv64a(data []byte) uint64 {
+	h := uint64(14695981039346656037)
+	for _, b := range data {
+		h ^= uint64(b)
+		h *= 1099511628211
+	}
+	return h
+}
+
+// InfinityKZGCommitment is the canonical compressed encoding of the point at infinity,
+// which is the commitment to the zero polynomial.
+var InfinityKZGCommitment = KZGCommitment{0xc0}
+
+// IsInfinity returns true if the commitment is the canonical encoding of the point at infinity.
+func IsInfinity(c KZGCommitment) bool {
+	return c == InfinityKZGCommitment
+}
+
+// IsInfinityProof returns true if the proof is the canonical encoding of the point at infinity,
+// which is the proof for any opening of a constant polynomial.
+func IsInfinityProof(p KZGProof) bool {
+	return KZGCommitment(p) == InfinityKZGCommitment
+}
+
 type BlobsSidecar struct {
 	BeaconBlockRoot    Root
 	BeaconBlockSlot    Slot
@@ -62,6 +125,36 @@ func (s KZGCommitmentSequenceImpl) Len() int {
 	return len(s)
 }
 
+type KZGProofSequence interface {
+	Len() int
+	At(int) KZGProof
+}
+
+type KZGProofSequenceImpl []KZGProof
+
+func (s KZGProofSequenceImpl) At(i int) KZGProof {
+	return s[i]
+}
+
+func (s KZGProofSequenceImpl) Len() int {
+	return len(s)
+}
+
+type VersionedHashSequence interface {
+	Len() int
+	At(int) VersionedHash
+}
+
+type VersionedHashSequenceImpl []VersionedHash
+
+func (s VersionedHashSequenceImpl) At(i int) VersionedHash {
+	return s[i]
+}
+
+func (s VersionedHashSequenceImpl) Len() int {
+	return len(s)
+}
+
 const (
 	BlobTxType                = 5
 	PrecompileInputLength     = 192
@@ -69,11 +162,15 @@ const (
 )
 
 var (
-	invalidKZGProofError = errors.New("invalid kzg proof")
+	invalidKZGProofError = fmt.Errorf("invalid kzg proof: %w", ErrInvalidProof)
 )
 
-// PointEvaluationPrecompile implements point_evaluation_precompile from EIP-4844
-func PointEvaluationPrecompile(input []byte) ([]byte, error) {
+// PointEvaluationPrecompile implements point_evaluation_precompile from EIP-4844:
+// https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+//
+// The input is versioned_hash (32) | z (32) | y (32) | commitment (48) | proof (48), with z and y big-endian.
+// On success it returns FIELD_ELEMENTS_PER_BLOB and BLS_MODULUS, each as a 32-byte big-endian integer.
+func (ctx *Context) PointEvaluationPrecompile(input []byte) ([]byte, error) {
 	if len(input) != PrecompileInputLength {
 		return nil, errors.New("invalid input length")
 	}
@@ -88,9 +185,9 @@ func PointEvaluationPrecompile(input []byte) ([]byte, error) {
 	copy(y[:], input[64:96])
 
 	// input kzg point: next 48 bytes
-	var dataKZG [48]byte
+	var dataKZG KZGCommitment
 	copy(dataKZG[:], input[96:144])
-	if KZGToVersionedHash(KZGCommitment(dataKZG)) != VersionedHash(versionedHash) {
+	if KZGToVersionedHash(dataKZG) != VersionedHash(versionedHash) {
 		return nil, errors.New("mismatched versioned hash")
 	}
 
@@ -98,38 +195,71 @@ func PointEvaluationPrecompile(input []byte) ([]byte, error) {
 	var quotientKZG [48]byte
 	copy(quotientKZG[:], input[144:PrecompileInputLength])
 
-	ok, err := VerifyKZGProof(KZGCommitment(dataKZG), x, y, KZGProof(quotientKZG))
+	var xFr, yFr bls.Fr
+	if !bls.FrFrom32BE(&xFr, x) {
+		return nil, fmt.Errorf("verify_kzg_proof error: invalid evaluation point: %w", ErrNonCanonicalScalar)
+	}
+	if !bls.FrFrom32BE(&yFr, y) {
+		return nil, fmt.Errorf("verify_kzg_proof error: invalid expected output: %w", ErrNonCanonicalScalar)
+	}
+	dataKZGG1, err := (*VerifyOpts)(nil).decodeCommitment(ctx, dataKZG)
 	if err != nil {
-		return nil, fmt.Errorf("verify_kzg_proof error: %v", err)
+		return nil, fmt.Errorf("verify_kzg_proof error: %w", err)
 	}
-	if !ok {
+	quotientKZGG1, err := ctx.decodeProof(quotientKZG)
+	if err != nil {
+		return nil, fmt.Errorf("verify_kzg_proof error: %w", err)
+	}
+	if !ctx.VerifyKZGProofFromPoints(dataKZGG1, &xFr, &yFr, quotientKZGG1) {
 		return nil, invalidKZGProofError
 	}
-	return []byte{}, nil
+	out := make([]byte, 64)
+	binary.BigEndian.PutUint64(out[24:32], uint64(ctx.FieldElementsPerBlob()))
+	BLSModulus.FillBytes(out[32:])
+	return out, nil
+}
+
+// PointEvaluationPrecompile calls PointEvaluationPrecompile on the default context.
+func PointEvaluationPrecompile(input []byte) ([]byte, error) {
+	return defaultContext().PointEvaluationPrecompile(input)
 }
 
 // VerifyKZGProof implements verify_kzg_proof from the EIP-4844 consensus spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#verify_kzg_proof
-func VerifyKZGProof(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof) (bool, error) {
+func (ctx *Context) VerifyKZGProof(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof) (bool, error) {
+	defer ctx.reportUsage("VerifyKZGProof", 160, 2)
+	polynomialKZGG1, zFr, yFr, kzgProofG1, err := ctx.decodeKZGOpening(polynomialKZG, z, y, kzgProof, nil)
+	if err != nil {
+		return false, err
+	}
+	return ctx.VerifyKZGProofFromPoints(polynomialKZGG1, zFr, yFr, kzgProofG1), nil
+}
+
+// decodeKZGOpening parses the inputs of VerifyKZGProof, validated with the options, nil for the defaults.
+func (ctx *Context) decodeKZGOpening(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof, opts *VerifyOpts) (
+	polynomialKZGG1 *bls.G1Point, zFr, yFr *bls.Fr, kzgProofG1 *bls.G1Point, err error) {
 	// successfully converting z and y to bls.Fr confirms they are < MODULUS per the spec
-	var zFr, yFr bls.Fr
-	ok := bls.FrFrom32(&zFr, z)
-	if !ok {
-		return false, errors.New("invalid evaluation point")
+	zFr, yFr = new(bls.Fr), new(bls.Fr)
+	if !bls.FrFrom32(zFr, z) {
+		return nil, nil, nil, nil, fmt.Errorf("invalid evaluation point: %w", ErrNonCanonicalScalar)
 	}
-	ok = bls.FrFrom32(&yFr, y)
-	if !ok {
-		return false, errors.New("invalid expected output")
+	if !bls.FrFrom32(yFr, y) {
+		return nil, nil, nil, nil, fmt.Errorf("invalid expected output: %w", ErrNonCanonicalScalar)
 	}
-	polynomialKZGG1, err := bls.FromCompressedG1(polynomialKZG[:])
+	polynomialKZGG1, err = opts.decodeCommitment(ctx, polynomialKZG)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode polynomialKZG: %v", err)
+		return nil, nil, nil, nil, err
 	}
-	kzgProofG1, err := bls.FromCompressedG1(kzgProof[:])
+	kzgProofG1, err = opts.decodeProof(ctx, kzgProof)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode kzgProof: %v", err)
+		return nil, nil, nil, nil, err
 	}
-	return VerifyKZGProofFromPoints(polynomialKZGG1, &zFr, &yFr, kzgProofG1), nil
+	return polynomialKZGG1, zFr, yFr, kzgProofG1, nil
+}
+
+// VerifyKZGProof calls VerifyKZGProof on the default context.
+func VerifyKZGProof(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof) (bool, error) {
+	return defaultContext().VerifyKZGProof(polynomialKZG, z, y, kzgProof)
 }
 
 // KZGToVersionedHash implements kzg_to_versioned_hash from EIP-4844
@@ -141,47 +271,124 @@ func KZGToVersionedHash(kzg KZGCommitment) VersionedHash {
 
 // BlobToKZGCommitment implements blob_to_kzg_commitment from the EIP-4844 consensus spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#blob_to_kzg_commitment
-func BlobToKZGCommitment(blob Blob) (KZGCommitment, bool) {
-	poly, ok := BlobToPolynomial(blob)
-	if !ok {
-		return KZGCommitment{}, false
+// The field elements are checked and decoded in one pass, into a pooled buffer rather than a new Polynomial,
+// and committed to. An invalid blob is reported like ValidateBlob does.
+func (ctx *Context) BlobToKZGCommitment(blob Blob) (KZGCommitment, error) {
+	n := ctx.FieldElementsPerBlob()
+	if l := blob.Len(); l != n {
+		return KZGCommitment{}, fmt.Errorf("%w: blob has %d field elements, expected %d", ErrWrongBlobLength, l, n)
+	}
+	defer ctx.reportUsage("BlobToKZGCommitment", ctx.blobBytes(), n)
+	scalars := getScalars(n)
+	defer scalarsPool.Put(scalars)
+	for i := 0; i < n; i++ {
+		if !bls.FrFrom32(&(*scalars)[i], blob.At(i)) {
+			return KZGCommitment{}, fmt.Errorf("blob field element %d: %w", i, ErrNonCanonicalScalar)
+		}
+	}
+	return ctx.PolynomialToKZGCommitment(*scalars), nil
+}
+
+// BlobToKZGCommitment calls BlobToKZGCommitment on the default context.
+func BlobToKZGCommitment(blob Blob) (KZGCommitment, error) {
+	return defaultContext().BlobToKZGCommitment(blob)
+}
+
+// ValidateBlob checks that the blob has the expected number of field elements, and that each of them
+// is canonical (less than the BLS modulus), without converting the blob into a polynomial.
+// The returned error identifies the index of the first invalid field element.
+func (ctx *Context) ValidateBlob(blob Blob) error {
+	l := blob.Len()
+	if l != ctx.FieldElementsPerBlob() {
+		return fmt.Errorf("%w: blob has %d field elements, expected %d", ErrWrongBlobLength, l, ctx.FieldElementsPerBlob())
 	}
-	return PolynomialToKZGCommitment(poly), true
+	for i := 0; i < l; i++ {
+		if !bls.ValidFr(blob.At(i)) {
+			return fmt.Errorf("blob field element %d: %w", i, ErrNonCanonicalScalar)
+		}
+	}
+	return nil
+}
+
+// ValidateBlob calls ValidateBlob on the default context.
+func ValidateBlob(blob Blob) error {
+	return defaultContext().ValidateBlob(blob)
 }
 
 // VerifyAggregateKZGProof implements verify_aggregate_kzg_proof from the EIP-4844 consensus spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#verify_aggregate_kzg_proof
-func VerifyAggregateKZGProof(blobs BlobSequence, expectedKZGCommitments KZGCommitmentSequence, kzgAggregatedProof KZGProof) (bool, error) {
-	polynomials, ok := BlobsToPolynomials(blobs)
-	if !ok {
-		return false, errors.New("could not convert blobs to polynomials")
+// The blobs and commitments must be as many, and at most MaxBlobsPerBlock: otherwise an error wrapping
+// ErrLengthMismatch or ErrTooManyBlobs is returned.
+func (ctx *Context) VerifyAggregateKZGProof(blobs BlobSequence, expectedKZGCommitments KZGCommitmentSequence, kzgAggregatedProof KZGProof) (bool, error) {
+	if err := checkBlockBlobCounts(blobs.Len(), expectedKZGCommitments.Len()); err != nil {
+		return false, err
+	}
+	n := blobs.Len()
+	if err := ctx.checkBlobLimit(n); err != nil {
+		return false, err
+	}
+	defer ctx.reportUsage("VerifyAggregateKZGProof", n*(ctx.blobBytes()+48)+48, n+2)
+	return ctx.verifyAggregateKZGProof(blobs, expectedKZGCommitments, kzgAggregatedProof)
+}
+
+// verifyAggregateKZGProof is VerifyAggregateKZGProof without the limit of the blobs of a block,
+// for objects spanning more blobs than a block, see VerifyMultiBlobObject.
+func (ctx *Context) verifyAggregateKZGProof(blobs BlobSequence, expectedKZGCommitments KZGCommitmentSequence, kzgAggregatedProof KZGProof) (bool, error) {
+	polynomials, err := ctx.blobsToPolynomials(blobs)
+	if err != nil {
+		return false, err
 	}
 	aggregatedPoly, aggregatedPolyCommitment, evaluationChallenge, err :=
-		ComputeAggregatedPolyAndCommitment(polynomials, expectedKZGCommitments)
+		ctx.ComputeAggregatedPolyAndCommitment(polynomials, expectedKZGCommitments)
 	if err != nil {
 		return false, err
 	}
-	y := EvaluatePolynomialInEvaluationForm(aggregatedPoly, evaluationChallenge)
-	kzgProofG1, err := bls.FromCompressedG1(kzgAggregatedProof[:])
+	y := ctx.EvaluatePolynomialInEvaluationForm(aggregatedPoly, evaluationChallenge)
+	kzgProofG1, err := ctx.decodeProof(kzgAggregatedProof)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode kzgProof: %v", err)
+		return false, err
 	}
-	return VerifyKZGProofFromPoints(aggregatedPolyCommitment, evaluationChallenge, y, kzgProofG1), nil
+	return ctx.VerifyKZGProofFromPoints(aggregatedPolyCommitment, evaluationChallenge, y, kzgProofG1), nil
+}
+
+// checkBlockBlobCounts returns an error if the blobs and commitments of a block are not as many,
+// or more than MaxBlobsPerBlock.
+func checkBlockBlobCounts(blobs, commitments int) error {
+	if blobs != commitments {
+		return fmt.Errorf("%w: %d blobs, %d commitments", ErrLengthMismatch, blobs, commitments)
+	}
+	if blobs > MaxBlobsPerBlock {
+		return fmt.Errorf("%w: %d blobs, at most %d", ErrTooManyBlobs, blobs, MaxBlobsPerBlock)
+	}
+	return nil
+}
+
+// VerifyAggregateKZGProof calls VerifyAggregateKZGProof on the default context.
+func VerifyAggregateKZGProof(blobs BlobSequence, expectedKZGCommitments KZGCommitmentSequence, kzgAggregatedProof KZGProof) (bool, error) {
+	return defaultContext().VerifyAggregateKZGProof(blobs, expectedKZGCommitments, kzgAggregatedProof)
 }
 
 // ComputeAggregateKZGProof implements compute_aggregate_kzg_proof from the EIP-4844 consensus spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#compute_aggregate_kzg_proof
-func ComputeAggregateKZGProof(blobs BlobSequence) (KZGProof, error) {
-	polynomials, ok := BlobsToPolynomials(blobs)
-	if !ok {
-		return KZGProof{}, errors.New("could not convert blobs to polynomials")
+func (ctx *Context) ComputeAggregateKZGProof(blobs BlobSequence) (KZGProof, error) {
+	polynomials, err := ctx.blobsToPolynomials(blobs)
+	if err != nil {
+		return KZGProof{}, err
 	}
-	return ComputeAggregateKZGProofFromPolynomials(polynomials)
+	// the commitments, their aggregate, and the proof
+	n := len(polynomials)
+	defer ctx.reportUsage("ComputeAggregateKZGProof", n*ctx.blobBytes(), (n+1)*ctx.FieldElementsPerBlob()+n)
+	return ctx.ComputeAggregateKZGProofFromPolynomials(polynomials)
+}
+
+// ComputeAggregateKZGProof calls ComputeAggregateKZGProof on the default context.
+func ComputeAggregateKZGProof(blobs BlobSequence) (KZGProof, error) {
+	return defaultContext().ComputeAggregateKZGProof(blobs)
 }
 
 // ValidateBlobsSidecar implements validate_blobs_sidecar from the EIP-4844 consensus spec:
 // https://github.com/roberto-bayardo/consensus-specs/blob/dev/specs/eip4844/beacon-chain.md#validate_blobs_sidecar
-func ValidateBlobsSidecar(slot Slot, beaconBlockRoot Root, expectedKZGCommitments KZGCommitmentSequence, blobsSidecar BlobsSidecar) error {
+func (ctx *Context) ValidateBlobsSidecar(slot Slot, beaconBlockRoot Root, expectedKZGCommitments KZGCommitmentSequence, blobsSidecar BlobsSidecar) error {
 	if slot != blobsSidecar.BeaconBlockSlot {
 		return fmt.Errorf(
 			"slot doesn't match sidecar's beacon block slot (%v != %v)",
@@ -196,9 +403,9 @@ func ValidateBlobsSidecar(slot Slot, beaconBlockRoot Root, expectedKZGCommitment
 			"blob len doesn't match expected kzg commitments len (%v != %v)",
 			blobs.Len(), expectedKZGCommitments.Len())
 	}
-	ok, err := VerifyAggregateKZGProof(blobs, expectedKZGCommitments, blobsSidecar.KZGAggregatedProof)
+	ok, err := ctx.VerifyAggregateKZGProof(blobs, expectedKZGCommitments, blobsSidecar.KZGAggregatedProof)
 	if err != nil {
-		return fmt.Errorf("verify_aggregate_kzg_proof error: %v", err)
+		return fmt.Errorf("verify_aggregate_kzg_proof error: %w", err)
 	}
 	if !ok {
 		return invalidKZGProofError
@@ -206,6 +413,11 @@ func ValidateBlobsSidecar(slot Slot, beaconBlockRoot Root, expectedKZGCommitment
 	return nil
 }
 
+// ValidateBlobsSidecar calls ValidateBlobsSidecar on the default context.
+func ValidateBlobsSidecar(slot Slot, beaconBlockRoot Root, expectedKZGCommitments KZGCommitmentSequence, blobsSidecar BlobsSidecar) error {
+	return defaultContext().ValidateBlobsSidecar(slot, beaconBlockRoot, expectedKZGCommitments, blobsSidecar)
+}
+
 // TxPeekBlobVersionedHashes implements tx_peek_blob_versioned_hashes from EIP-4844 consensus spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/beacon-chain.md#tx_peek_blob_versioned_hashes
 //
@@ -268,12 +480,17 @@ func VerifyKZGCommitmentsAgainstTransactions(transactions [][]byte, kzgCommitmen
 			versionedHashes = append(versionedHashes, v...)
 		}
 	}
-	if kzgCommitments.Len() != len(versionedHashes) {
-		return fmt.Errorf("invalid number of blob versioned hashes: %v vs %v", kzgCommitments.Len(), len(versionedHashes))
+	return VerifyKZGCommitmentsAgainstVersionedHashes(kzgCommitments, VersionedHashSequenceImpl(versionedHashes))
+}
+
+// VerifyKZGCommitmentsAgainstVersionedHashes checks that the versioned hashes are those of the commitments, in order.
+func VerifyKZGCommitmentsAgainstVersionedHashes(kzgCommitments KZGCommitmentSequence, versionedHashes VersionedHashSequence) error {
+	if kzgCommitments.Len() != versionedHashes.Len() {
+		return fmt.Errorf("%w: invalid number of blob versioned hashes: %v vs %v", ErrLengthMismatch, kzgCommitments.Len(), versionedHashes.Len())
 	}
 	for i := 0; i < kzgCommitments.Len(); i++ {
 		h := KZGToVersionedHash(kzgCommitments.At(i))
-		if h != versionedHashes[i] {
+		if h != versionedHashes.At(i) {
 			return errors.New("invalid version hashes vs kzg")
 		}
 	}
@@ -0,0 +1,203 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestValidateBlob(t *testing.T) {
+	blob := polynomialToBlob(randomPolynomial())
+	if err := ValidateBlob(blob); err != nil {
+		t.Fatal(err)
+	}
+	blob[42] = [32]byte{31: 0xff}
+	if err := ValidateBlob(blob); err == nil || !strings.Contains(err.Error(), "42") {
+		t.Fatalf("expected error for field element 42, got: %v", err)
+	}
+	if err := ValidateBlob(blob[:100]); err == nil {
+		t.Fatal("expected error for short blob")
+	}
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	blob := polynomialToBlob(poly)
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commitment != ctx.PolynomialToKZGCommitment(poly) {
+		t.Fatal("expected the commitment of the polynomial")
+	}
+	if _, err := ctx.BlobToKZGCommitment(blob[:15]); !errors.Is(err, ErrWrongBlobLength) {
+		t.Fatalf("expected wrong blob length error, got %v", err)
+	}
+	blob[3] = [32]byte{31: 0xff}
+	if _, err := ctx.BlobToKZGCommitment(blob); !errors.Is(err, ErrNonCanonicalScalar) || !strings.Contains(err.Error(), "element 3") {
+		t.Fatalf("expected non-canonical field element 3, got %v", err)
+	}
+	// the pooled buffer of the failed call must not leak into the next one
+	blob[3] = bls.FrTo32(&poly[3])
+	if again, err := ctx.BlobToKZGCommitment(blob); err != nil || again != commitment {
+		t.Fatalf("expected the same commitment again: %v", err)
+	}
+}
+
+func TestPointAtInfinity(t *testing.T) {
+	zeroPoly := make(Polynomial, FieldElementsPerBlob)
+	commitment := PolynomialToKZGCommitment(zeroPoly)
+	if commitment != InfinityKZGCommitment {
+		t.Fatalf("expected canonical identity encoding, got %v", commitment)
+	}
+	if !IsInfinity(commitment) {
+		t.Fatal("expected commitment to be the point at infinity")
+	}
+	p, err := bls.FromCompressedG1(commitment[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.EqualG1(p, &bls.ZeroG1) {
+		t.Fatal("expected decoded commitment to be the point at infinity")
+	}
+
+	// the zero polynomial opens to zero everywhere, with the identity as proof
+	z := bls.RandomFr()
+	proof, err := ComputeKZGProof(zeroPoly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsInfinityProof(proof) {
+		t.Fatalf("expected identity proof, got %v", proof)
+	}
+	ok, err := VerifyKZGProof(commitment, bls.FrTo32(z), [32]byte{}, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected identity proof for the zero polynomial to verify")
+	}
+	var one [32]byte
+	one[0] = 1
+	ok, err = VerifyKZGProof(commitment, bls.FrTo32(z), one, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected wrong evaluation of the zero polynomial to fail")
+	}
+
+	// a constant polynomial has a non-identity commitment, but an identity proof
+	constPoly := make(Polynomial, FieldElementsPerBlob)
+	for i := range constPoly {
+		bls.AsFr(&constPoly[i], 7)
+	}
+	commitment = PolynomialToKZGCommitment(constPoly)
+	if IsInfinity(commitment) {
+		t.Fatal("expected non-identity commitment for a constant polynomial")
+	}
+	proof, err = ComputeKZGProof(constPoly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsInfinityProof(proof) {
+		t.Fatalf("expected identity proof, got %v", proof)
+	}
+	var seven [32]byte
+	seven[0] = 7
+	ok, err = VerifyKZGProof(commitment, bls.FrTo32(z), seven, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected identity proof for a constant polynomial to verify")
+	}
+}
+
+func TestPointEvaluationPrecompile(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	z := bls.RandomFr()
+	proof, err := ctx.ComputeKZGProof(poly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versionedHash := KZGToVersionedHash(commitment)
+	zBytes := bls.FrTo32BE(z)
+	yBytes := bls.FrTo32BE(ctx.EvaluatePolynomialInEvaluationForm(poly, z))
+	var input []byte
+	input = append(input, versionedHash[:]...)
+	input = append(input, zBytes[:]...)
+	input = append(input, yBytes[:]...)
+	input = append(input, commitment[:]...)
+	input = append(input, proof[:]...)
+
+	out, err := ctx.PointEvaluationPrecompile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 64 || new(big.Int).SetBytes(out[:32]).Uint64() != 16 || new(big.Int).SetBytes(out[32:]).Cmp(BLSModulus) != 0 {
+		t.Fatalf("unexpected output %x", out)
+	}
+
+	// wrong output
+	input[95] ^= 1
+	if _, err := ctx.PointEvaluationPrecompile(input); err == nil {
+		t.Fatal("expected invalid proof to be rejected")
+	}
+	input[95] ^= 1
+	// wrong versioned hash
+	input[0] ^= 1
+	if _, err := ctx.PointEvaluationPrecompile(input); err == nil {
+		t.Fatal("expected mismatched versioned hash to be rejected")
+	}
+	if _, err := ctx.PointEvaluationPrecompile(input[:191]); err == nil {
+		t.Fatal("expected invalid input length to be rejected")
+	}
+}
+
+func TestVerifyKZGCommitmentsAgainstVersionedHashes(t *testing.T) {
+	commitments := KZGCommitmentSequenceImpl{{1}, {2}}
+	hashes := VersionedHashSequenceImpl{KZGToVersionedHash(commitments[0]), KZGToVersionedHash(commitments[1])}
+	if err := VerifyKZGCommitmentsAgainstVersionedHashes(commitments, hashes); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyKZGCommitmentsAgainstVersionedHashes(commitments, hashes[:1]); err == nil {
+		t.Fatal("expected count mismatch to be rejected")
+	}
+	hashes[0], hashes[1] = hashes[1], hashes[0]
+	if err := VerifyKZGCommitmentsAgainstVersionedHashes(commitments, hashes); err == nil {
+		t.Fatal("expected hash mismatch to be rejected")
+	}
+}
+
+func TestCommitmentKeys(t *testing.T) {
+	a, b := KZGCommitment{0xc0}, KZGCommitment{0xc0, 1}
+	if !a.Equal(InfinityKZGCommitment) || a.Equal(b) {
+		t.Fatal("unexpected equality")
+	}
+	if a.Compare(b) != -1 || b.Compare(a) != 1 || a.Compare(a) != 0 {
+		t.Fatal("unexpected order")
+	}
+	h := fnv.New64a()
+	h.Write(b[:])
+	if b.Hash64() != h.Sum64() {
+		t.Fatal("expected the FNV-1a hash of the encoding")
+	}
+	if KZGProof(b).Hash64() != b.Hash64() || KZGProof(a).Compare(KZGProof(b)) != -1 || !KZGProof(a).Equal(KZGProof(a)) {
+		t.Fatal("expected proofs to behave like commitments")
+	}
+	set := map[KZGCommitment]struct{}{a: {}, b: {}, InfinityKZGCommitment: {}}
+	if len(set) != 2 {
+		t.Fatalf("expected 2 distinct keys, got %d", len(set))
+	}
+}
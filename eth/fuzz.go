@@ -0,0 +1,194 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// The round-trip functions are fuzzing targets, for go-fuzz or testing.F, over the whole pipeline of the package:
+// they take arbitrary bytes, and return an error only if the package is inconsistent with itself, e.g. a proof
+// it computed does not verify. Inputs that the package rejects are not errors, as long as they are rejected
+// consistently. A fuzzer should treat any returned error as a crash:
+//
+//	func FuzzCommitment(f *testing.F) {
+//		f.Fuzz(func(t *testing.T, b []byte) {
+//			if err := eth.RoundTripCommitment(b); err != nil {
+//				t.Fatal(err)
+//			}
+//		})
+//	}
+
+// RoundTripCommitment parses the bytes as a blob, padded or truncated to the size of the context,
+// and checks that it is accepted or rejected consistently by validation and commitment, that its commitment
+// matches the one computed from the monomial setup, when loaded, and that it decodes and re-encodes to itself.
+// The bytes are also round-tripped through the blob codec and, for their first 48 bytes, the point encoding.
+func (ctx *Context) RoundTripCommitment(b []byte) error {
+	size := ctx.FieldElementsPerBlob() * 32
+	if _, err := ctx.BlobView(b); (err == nil) != (len(b) == size) || (err != nil && !errors.Is(err, ErrWrongBlobLength)) {
+		return fmt.Errorf("blob view of %d bytes: unexpected error: %v", len(b), err)
+	}
+	raw := make([]byte, size)
+	copy(raw, b)
+	blob := BlobSlice(raw)
+
+	validateErr := ctx.ValidateBlob(blob)
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if (validateErr == nil) != (err == nil) {
+		return fmt.Errorf("validation error %v, but commitment error %v", validateErr, err)
+	}
+	if err == nil {
+		if err := ctx.checkCommitmentRoundTrip(blob, commitment); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, ErrNonCanonicalScalar) {
+		return fmt.Errorf("unexpected class of commitment error: %v", err)
+	}
+
+	// the codec is strict, so a blob that decodes is the encoding of its data
+	if data, err := ctx.DecodeFromBlob(blob); err == nil {
+		encoded, err := ctx.EncodeToBlob(data)
+		if err != nil {
+			return fmt.Errorf("decoded data of %d bytes does not encode: %v", len(data), err)
+		}
+		if !bytes.Equal(encoded.(blobSlice), raw) {
+			return errors.New("decoded blob data does not encode to the same blob")
+		}
+	}
+	data := b
+	if len(data) > ctx.MaxBlobDataSize() {
+		data = data[:ctx.MaxBlobDataSize()]
+	}
+	encoded, err := ctx.EncodeToBlob(data)
+	if err != nil {
+		return fmt.Errorf("data of %d bytes does not encode: %v", len(data), err)
+	}
+	decoded, err := ctx.DecodeFromBlob(encoded)
+	if err != nil || !bytes.Equal(decoded, data) {
+		return fmt.Errorf("encoded data does not decode to itself: %v", err)
+	}
+
+	var point KZGCommitment
+	copy(point[:], b)
+	if p, err := bls.FromCompressedG1(point[:]); err == nil {
+		if !bytes.Equal(bls.ToCompressedG1(p), point[:]) {
+			return fmt.Errorf("point %x does not re-encode to itself", point)
+		}
+	}
+	return nil
+}
+
+// RoundTripCommitment calls RoundTripCommitment on the default context.
+func RoundTripCommitment(b []byte) error {
+	return defaultContext().RoundTripCommitment(b)
+}
+
+// checkCommitmentRoundTrip checks the commitment to a valid blob against its encoding,
+// and against the commitment to the coefficients of the blob with the monomial setup, when loaded.
+func (ctx *Context) checkCommitmentRoundTrip(blob Blob, commitment KZGCommitment) error {
+	p, err := bls.FromCompressedG1(commitment[:])
+	if err != nil {
+		return fmt.Errorf("commitment %x does not decode: %v", commitment, err)
+	}
+	if !bytes.Equal(bls.ToCompressedG1(p), commitment[:]) {
+		return fmt.Errorf("commitment %x does not re-encode to itself", commitment)
+	}
+	if ctx.checkSetupG1(len(ctx.domain)) != nil {
+		return nil
+	}
+	poly, ok := BlobToPolynomial(blob)
+	if !ok {
+		return errors.New("valid blob does not convert to a polynomial")
+	}
+	coeffs, err := ctx.PolynomialToCoefficients(poly)
+	if err != nil {
+		return fmt.Errorf("blob polynomial does not convert to coefficients: %v", err)
+	}
+	if !bls.EqualG1(p, bls.LinCombG1(ctx.setupG1[:len(coeffs)], coeffs)) {
+		return fmt.Errorf("commitment %x does not match the monomial commitment", commitment)
+	}
+	return nil
+}
+
+// fuzzScalar derives the field element of the given index from the seed.
+func fuzzScalar(seed []byte, index uint32) [32]byte {
+	h := sha256.New()
+	h.Write(seed)
+	var i [4]byte
+	binary.LittleEndian.PutUint32(i[:], index)
+	h.Write(i[:])
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	var v bls.Fr
+	bls.FrFrom32Mod(&v, sum)
+	return bls.FrTo32(&v)
+}
+
+// RoundTripProofVerify derives a valid blob and an evaluation point from the seed, and checks that the proofs
+// the context computes for them verify, alone and in a batch, and that an opening to another value does not.
+// The first byte of the seed bounds the number of non-zero field elements of the blob, so that degenerate blobs,
+// e.g. constant or zero, are reached too.
+func (ctx *Context) RoundTripProofVerify(seed []byte) error {
+	n := ctx.FieldElementsPerBlob()
+	nonZero := n
+	if len(seed) > 0 {
+		nonZero = int(seed[0]) % (n + 1)
+	}
+	raw := make([]byte, n*32)
+	for i := 0; i < nonZero; i++ {
+		v := fuzzScalar(seed, uint32(i))
+		copy(raw[i*32:], v[:])
+	}
+	blob := BlobSlice(raw)
+	z := fuzzScalar(seed, uint32(n))
+
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		return fmt.Errorf("valid blob does not commit: %v", err)
+	}
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		return fmt.Errorf("valid blob does not prove: %v", err)
+	}
+	if ok, err := ctx.VerifyBlobKZGProof(blob, commitment, proof); err != nil || !ok {
+		return fmt.Errorf("blob proof does not verify: %v", err)
+	}
+	ok, err := ctx.VerifyBlobKZGProofBatch(blobList{blob, blob}, KZGCommitmentSequenceImpl{commitment, commitment},
+		KZGProofSequenceImpl{proof, proof})
+	if err != nil || !ok {
+		return fmt.Errorf("blob proof batch does not verify: %v", err)
+	}
+
+	proofAt, y, err := ctx.ComputeKZGProofAt(blob, z)
+	if err != nil {
+		return fmt.Errorf("valid blob does not open at %x: %v", z, err)
+	}
+	poly, _ := BlobToPolynomial(blob)
+	var zFr bls.Fr
+	bls.FrFrom32(&zFr, z)
+	if bls.FrTo32(ctx.EvaluatePolynomialInEvaluationForm(poly, &zFr)) != y {
+		return fmt.Errorf("opening at %x is not the evaluation of the blob", z)
+	}
+	if ok, err := ctx.VerifyKZGProof(commitment, z, y, proofAt); err != nil || !ok {
+		return fmt.Errorf("opening at %x does not verify: %v", z, err)
+	}
+	var yFr, other bls.Fr
+	bls.FrFrom32(&yFr, y)
+	bls.AddModFr(&other, &yFr, &bls.ONE)
+	if ok, err := ctx.VerifyKZGProof(commitment, z, bls.FrTo32(&other), proofAt); err != nil || ok {
+		return fmt.Errorf("opening at %x to another value verifies: %v", z, err)
+	}
+	return nil
+}
+
+// RoundTripProofVerify calls RoundTripProofVerify on the default context.
+func RoundTripProofVerify(seed []byte) error {
+	return defaultContext().RoundTripProofVerify(seed)
+}
@@ -0,0 +1,36 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzRoundTripCommitment(f *testing.F) {
+	ctx := newTestContext(f, 4)
+	f.Add([]byte{})
+	f.Add([]byte("some blob data"))
+	f.Add(bytes.Repeat([]byte{0xff}, 16*32))
+	f.Add(bytes.Repeat([]byte{0x01}, 16*32))
+	f.Add(append([]byte{0xc0}, make([]byte, 47)...))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		if err := ctx.RoundTripCommitment(b); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzRoundTripProofVerify(f *testing.F) {
+	ctx := newTestContext(f, 4)
+	f.Add([]byte{})
+	f.Add([]byte{0})
+	f.Add([]byte{1, 2, 3})
+	f.Add([]byte("seed"))
+	f.Fuzz(func(t *testing.T, seed []byte) {
+		if err := ctx.RoundTripProofVerify(seed); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
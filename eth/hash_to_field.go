@@ -0,0 +1,136 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// ChallengeMode selects how Fiat-Shamir challenges are derived from their inputs.
+type ChallengeMode uint8
+
+const (
+	// ChallengeModeReduce reduces the digest of the inputs modulo the BLS modulus, as in the consensus specs.
+	ChallengeModeReduce ChallengeMode = iota
+	// ChallengeModeHashToField uses hash_to_field from RFC 9380, with expand_message_xmd and the challenge hash,
+	// which samples the field uniformly. Challenges do not match the consensus specs in this mode.
+	ChallengeModeHashToField
+)
+
+// hashToFieldL is L from RFC 9380: ceil((ceil(log2(p)) + k) / 8), with k = 128 bits of security.
+const hashToFieldL = 48
+
+// two256 is 2**256 mod the BLS modulus.
+var two256 bls.Fr
+
+func init() {
+	// 2**256 mod p = (2**256 - 1) mod p + 1
+	var allOnes [32]byte
+	for i := range allOnes {
+		allOnes[i] = 0xff
+	}
+	bls.FrFrom32Mod(&two256, allOnes)
+	bls.AddModFr(&two256, &two256, &bls.ONE)
+}
+
+// SetChallengeMode selects how the Fiat-Shamir challenges of the context are derived. The domain separation tag
+// is used by ChallengeModeHashToField, and must be non-empty and at most 255 bytes long.
+func (ctx *Context) SetChallengeMode(mode ChallengeMode, dst []byte) error {
+	switch mode {
+	case ChallengeModeReduce:
+		ctx.challengeMode = mode
+		ctx.hashToFieldDST = nil
+		return nil
+	case ChallengeModeHashToField:
+		if len(dst) == 0 || len(dst) > 255 {
+			return errors.New("domain separation tag must be 1 to 255 bytes long")
+		}
+		ctx.challengeMode = mode
+		ctx.hashToFieldDST = append([]byte(nil), dst...)
+		return nil
+	default:
+		return errors.New("unknown challenge mode")
+	}
+}
+
+// SetChallengeMode calls SetChallengeMode on a copy of the default context, which then replaces it, see Context.
+func SetChallengeMode(mode ChallengeMode, dst []byte) error {
+	return updateDefaultContext(func(ctx *Context) error {
+		return ctx.SetChallengeMode(mode, dst)
+	})
+}
+
+// SetChallengeHash sets the hash function of the Fiat-Shamir challenges of the context, e.g. keccak256 or BLAKE2s
+// for deployments outside of Ethereum. The hash must have a 32-byte digest. A nil function restores the default,
+// SHA-256, which the consensus specs use. Proofs only verify against contexts with the same challenge hash.
+func (ctx *Context) SetChallengeHash(newHash func() hash.Hash) error {
+	if newHash != nil && newHash().Size() != 32 {
+		return fmt.Errorf("challenge hash must have a 32-byte digest, got %d bytes", newHash().Size())
+	}
+	ctx.challengeHash = newHash
+	return nil
+}
+
+// SetChallengeHash calls SetChallengeHash on a copy of the default context, which then replaces it, see Context.
+func SetChallengeHash(newHash func() hash.Hash) error {
+	return updateDefaultContext(func(ctx *Context) error {
+		return ctx.SetChallengeHash(newHash)
+	})
+}
+
+// expandMessageXMD finishes expand_message_xmd from RFC 9380 section 5.3.1, given a hash that already absorbed
+// Z_pad || msg. lenInBytes must be at most 255 * 32.
+func expandMessageXMD(sha hash.Hash, dst []byte, lenInBytes int) (b0 []byte, uniform []byte) {
+	dstPrime := append(append([]byte(nil), dst...), byte(len(dst)))
+	// msg_prime = Z_pad || msg || I2OSP(len_in_bytes, 2) || I2OSP(0, 1) || DST_prime
+	sha.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	sha.Write(dstPrime)
+	b0 = sha.Sum(nil)
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
+	ell := (lenInBytes + sha.Size() - 1) / sha.Size()
+	prev := b0
+	xored := make([]byte, len(b0))
+	for i := 1; i <= ell; i++ {
+		sha.Reset()
+		if i == 1 {
+			sha.Write(b0)
+		} else {
+			for j := range xored {
+				xored[j] = b0[j] ^ prev[j]
+			}
+			sha.Write(xored)
+		}
+		sha.Write([]byte{byte(i)})
+		sha.Write(dstPrime)
+		prev = sha.Sum(nil)
+		uniform = append(uniform, prev...)
+	}
+	return b0, uniform[:lenInBytes]
+}
+
+// hashToField derives a single field element from the absorbed message with hash_to_field from RFC 9380 section 5.2:
+// OS2IP(expand_message_xmd(msg, DST, L)) mod p.
+func (h *Transcript) hashToField() *bls.Fr {
+	b0, uniform := expandMessageXMD(h.hasher, h.dst, hashToFieldL)
+	if h.record != nil {
+		copy(h.record.Hash[:], b0)
+	}
+	// uniform = hi || lo, 16 and 32 bytes big-endian: hi * 2**256 + lo
+	var hiBytes, loBytes [32]byte
+	copy(hiBytes[16:], uniform[:16])
+	copy(loBytes[:], uniform[16:])
+	var hi, lo bls.Fr
+	bls.FrFrom32BE(&hi, hiBytes)
+	bls.FrFrom32Mod(&lo, reverse32(loBytes))
+	out := new(bls.Fr)
+	bls.MulModFr(out, &hi, &two256)
+	bls.AddModFr(out, out, &lo)
+	return out
+}
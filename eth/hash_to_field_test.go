@@ -0,0 +1,134 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Test vectors of RFC 9380 appendix K.1
+func TestExpandMessageXMD(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	vectors := []struct {
+		msg      string
+		length   int
+		expected string
+	}{
+		{"", 0x20, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", 0x20, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+		{"", 0x80, "af84c27ccfd45d41914fdff5df25293e221afc53d8ad2ac06d5e3e29485dadbe" +
+			"e0d121587713a3e0dd4d5e69e93eb7cd4f5df4cd103e188cf60cb02edc3edf18" +
+			"eda8576c412b18ffb658e3dd6ec849469b979d444cf7b26911a08e63cf31f9dc" +
+			"c541708d3491184472c2c29bb749d4286b004ceb5ee6b9a7fa5b646c993f0ced"},
+	}
+	for _, v := range vectors {
+		sha := sha256.New()
+		sha.Write(make([]byte, sha.BlockSize()))
+		sha.Write([]byte(v.msg))
+		_, uniform := expandMessageXMD(sha, dst, v.length)
+		if got := hex.EncodeToString(uniform); got != v.expected {
+			t.Errorf("msg %q, length %d: expected %s, got %s", v.msg, v.length, v.expected, got)
+		}
+	}
+}
+
+func TestChallengeModeHashToField(t *testing.T) {
+	ctx := newTestContext(t, 2)
+	poly := randomPolynomialN(4)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	reduced := ctx.ComputeChallenge(poly, commitment)
+
+	if err := ctx.SetChallengeMode(ChallengeModeHashToField, nil); err == nil {
+		t.Fatal("expected empty domain separation tag to be rejected")
+	}
+	dst := []byte("TEST-KZG-CHALLENGE")
+	if err := ctx.SetChallengeMode(ChallengeModeHashToField, dst); err != nil {
+		t.Fatal(err)
+	}
+	challenge, transcript := ctx.ComputeChallengeDebug(poly, commitment)
+	if bls.EqualFr(challenge, reduced) {
+		t.Fatal("expected hash_to_field challenge to differ")
+	}
+
+	// recompute hash_to_field over the transcript with big.Int
+	sha := sha256.New()
+	sha.Write(make([]byte, sha.BlockSize()))
+	for _, e := range transcript.Entries {
+		sha.Write(e.Data)
+	}
+	_, uniform := expandMessageXMD(sha, dst, hashToFieldL)
+	modulus, _ := new(big.Int).SetString(bls.ModulusStr, 10)
+	expected := new(big.Int).Mod(new(big.Int).SetBytes(uniform), modulus)
+	got := bls.FrTo32BE(challenge)
+	if new(big.Int).SetBytes(got[:]).Cmp(expected) != 0 {
+		t.Fatalf("expected challenge %x, got %x", expected, got)
+	}
+
+	if err := ctx.SetChallengeMode(ChallengeModeReduce, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bls.EqualFr(ctx.ComputeChallenge(poly, commitment), reduced) {
+		t.Fatal("expected reduce mode to be restored")
+	}
+}
+
+func TestSetChallengeHash(t *testing.T) {
+	ctx := newTestContext(t, 2)
+	poly := randomPolynomialN(4)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	challenge, transcript := ctx.ComputeChallengeDebug(poly, commitment)
+
+	if err := ctx.SetChallengeHash(sha512.New); err == nil {
+		t.Fatal("expected a 64-byte hash to be rejected")
+	}
+	if err := ctx.SetChallengeHash(sha512.New512_256); err != nil {
+		t.Fatal(err)
+	}
+	other, otherTranscript := ctx.ComputeChallengeDebug(poly, commitment)
+	if bls.EqualFr(challenge, other) {
+		t.Fatal("expected another hash to derive another challenge")
+	}
+	if otherTranscript.FirstMismatch(transcript) != -1 {
+		t.Fatal("expected the same inputs to be absorbed")
+	}
+	expected := sha512.New512_256()
+	for _, e := range otherTranscript.Entries {
+		expected.Write(e.Data)
+	}
+	if string(expected.Sum(nil)) != string(otherTranscript.Hash[:]) {
+		t.Fatal("expected the challenge to be derived from the configured hash")
+	}
+
+	blob := polynomialToBlob(poly)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := ctx.VerifyBlobKZGProof(blob, commitment, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected proof to verify with the same challenge hash")
+	}
+	if err := ctx.SetChallengeHash(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bls.EqualFr(ctx.ComputeChallenge(poly, commitment), challenge) {
+		t.Fatal("expected sha256 to be restored")
+	}
+	ok, err = ctx.VerifyBlobKZGProof(blob, commitment, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected proof to fail with another challenge hash")
+	}
+}
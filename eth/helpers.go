@@ -1,17 +1,15 @@
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package eth
 
 import (
-	"crypto/sha256"
-	_ "embed"
 	"encoding/binary"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"math/bits"
+	"time"
 
 	"github.com/protolambda/go-kzg/bls"
 )
@@ -23,41 +21,6 @@ const (
 type Polynomial []bls.Fr
 type Polynomials [][]bls.Fr
 
-var (
-	// KZG CRS for G2
-	kzgSetupG2 []bls.G2Point
-
-	// KZG CRS for commitment computation
-	kzgSetupLagrange []bls.G1Point
-
-	// KZG CRS for G1 (only used in tests (for proof creation))
-	KzgSetupG1 []bls.G1Point
-
-	//go:embed trusted_setup.json
-	kzgSetupStr string
-)
-
-type JSONTrustedSetup struct {
-	SetupG1       []bls.G1Point `json:"setup_G1"`
-	SetupG2       []bls.G2Point `json:"setup_G2"`
-	SetupLagrange []bls.G1Point `json:"setup_G1_lagrange"`
-}
-
-// Initialize KZG subsystem (load the trusted setup data)
-func init() {
-	var parsedSetup = JSONTrustedSetup{}
-
-	err := json.Unmarshal([]byte(kzgSetupStr), &parsedSetup)
-	if err != nil {
-		panic(err)
-	}
-	kzgSetupG2 = parsedSetup.SetupG2
-	kzgSetupLagrange = bitReversalPermutation(parsedSetup.SetupLagrange)
-	KzgSetupG1 = parsedSetup.SetupG1
-
-	initDomain()
-}
-
 // Bit-reversal permutation helper functions
 
 // Check if `value` is a power of two integer.
@@ -89,34 +52,56 @@ func bitReversalPermutation(l []bls.G1Point) []bls.G1Point {
 
 // VerifyKZGProof implements verify_kzg_proof from the EIP-4844 consensus spec,
 // only with the byte inputs already parsed into points & field elements.
-func VerifyKZGProofFromPoints(polynomialKZG *bls.G1Point, z *bls.Fr, y *bls.Fr, kzgProof *bls.G1Point) bool {
+// The commitment and proof may be the point at infinity: e.g. the zero polynomial commits to the identity,
+// and any opening of a constant polynomial has the identity as proof.
+func (ctx *Context) VerifyKZGProofFromPoints(polynomialKZG *bls.G1Point, z *bls.Fr, y *bls.Fr, kzgProof *bls.G1Point) bool {
+	defer bls.StartTraceOperation("kzg verify").End()
+	start := time.Now()
 	var zG2 bls.G2Point
-	bls.MulG2(&zG2, &bls.GenG2, z)
+	bls.MulGenG2(&zG2, z)
 	var yG1 bls.G1Point
 	bls.MulG1(&yG1, &bls.GenG1, y)
 
 	var xMinusZ bls.G2Point
-	bls.SubG2(&xMinusZ, &kzgSetupG2[1], &zG2)
+	bls.SubG2(&xMinusZ, &ctx.setupG2[1], &zG2)
 	var pMinusY bls.G1Point
 	bls.SubG1(&pMinusY, polynomialKZG, &yG1)
 
-	return bls.PairingsVerify(&pMinusY, &bls.GenG2, kzgProof, &xMinusZ)
+	pairingStart := time.Now()
+	ok := bls.PairingsVerify(&pMinusY, &bls.GenG2, kzgProof, &xMinusZ)
+	ctx.metrics.Pairing(time.Since(pairingStart))
+	ctx.metrics.ProofVerified(time.Since(start), ok)
+	return ok
+}
+
+// VerifyKZGProofFromPoints calls VerifyKZGProofFromPoints on the default context.
+func VerifyKZGProofFromPoints(polynomialKZG *bls.G1Point, z *bls.Fr, y *bls.Fr, kzgProof *bls.G1Point) bool {
+	return defaultContext().VerifyKZGProofFromPoints(polynomialKZG, z, y, kzgProof)
 }
 
 // VerifyAggregateKZGProof implements verify_aggregate_kzg_proof from the EIP-4844 consensus spec,
 // only operating on blobs that have already been converted into polynomials.
-func VerifyAggregateKZGProofFromPolynomials(blobs Polynomials, expectedKZGCommitments KZGCommitmentSequence, kzgAggregatedProof KZGProof) (bool, error) {
+// The counts are checked as by VerifyAggregateKZGProof.
+func (ctx *Context) VerifyAggregateKZGProofFromPolynomials(blobs Polynomials, expectedKZGCommitments KZGCommitmentSequence, kzgAggregatedProof KZGProof) (bool, error) {
+	if err := checkBlockBlobCounts(len(blobs), expectedKZGCommitments.Len()); err != nil {
+		return false, err
+	}
 	aggregatedPoly, aggregatedPolyCommitment, evaluationChallenge, err :=
-		ComputeAggregatedPolyAndCommitment(blobs, expectedKZGCommitments)
+		ctx.ComputeAggregatedPolyAndCommitment(blobs, expectedKZGCommitments)
 	if err != nil {
 		return false, err
 	}
-	y := EvaluatePolynomialInEvaluationForm(aggregatedPoly, evaluationChallenge)
-	kzgProofG1, err := bls.FromCompressedG1(kzgAggregatedProof[:])
+	y := ctx.EvaluatePolynomialInEvaluationForm(aggregatedPoly, evaluationChallenge)
+	kzgProofG1, err := ctx.decodeProof(kzgAggregatedProof)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode kzgProof: %v", err)
+		return false, err
 	}
-	return VerifyKZGProofFromPoints(aggregatedPolyCommitment, evaluationChallenge, y, kzgProofG1), nil
+	return ctx.VerifyKZGProofFromPoints(aggregatedPolyCommitment, evaluationChallenge, y, kzgProofG1), nil
+}
+
+// VerifyAggregateKZGProofFromPolynomials calls VerifyAggregateKZGProofFromPolynomials on the default context.
+func VerifyAggregateKZGProofFromPolynomials(blobs Polynomials, expectedKZGCommitments KZGCommitmentSequence, kzgAggregatedProof KZGProof) (bool, error) {
+	return defaultContext().VerifyAggregateKZGProofFromPolynomials(blobs, expectedKZGCommitments, kzgAggregatedProof)
 }
 
 // ComputePowers implements compute_powers from the EIP-4844 consensus spec:
@@ -132,157 +117,323 @@ func ComputePowers(r *bls.Fr, n int) []bls.Fr {
 	return powers
 }
 
-func PolynomialToKZGCommitment(eval Polynomial) KZGCommitment {
-	g1 := bls.LinCombG1(kzgSetupLagrange, []bls.Fr(eval))
+// PolynomialToKZGCommitment computes the commitment to a polynomial in evaluation form.
+func (ctx *Context) PolynomialToKZGCommitment(eval Polynomial) KZGCommitment {
+	cache := ctx.blobCommitmentCache
+	var key [32]byte
+	if cache != nil {
+		key = blobHash(eval)
+		if v, ok := cache.get(key); ok {
+			return v.(KZGCommitment)
+		}
+	}
+	defer bls.StartTraceOperation("kzg commit").End()
+	start := time.Now()
+	g1 := ctx.lagrangeLinComb([]bls.Fr(eval))
 	var out KZGCommitment
 	copy(out[:], bls.ToCompressedG1(g1))
+	ctx.metrics.CommitmentComputed(time.Since(start))
+	if cache != nil {
+		cache.add(key, out)
+	}
+	return out
+}
+
+// PolynomialToKZGCommitment calls PolynomialToKZGCommitment on the default context.
+func PolynomialToKZGCommitment(eval Polynomial) KZGCommitment {
+	return defaultContext().PolynomialToKZGCommitment(eval)
+}
+
+// PolynomialsToKZGCommitments computes the commitments of all the given polynomials, spreading the
+// work over a pool of workers (see SetMaxWorkers). The output is in the same order as the input.
+func (ctx *Context) PolynomialsToKZGCommitments(blobs Polynomials) []KZGCommitment {
+	out := make([]KZGCommitment, len(blobs))
+	parallelFor(len(blobs), func(i int) {
+		out[i] = ctx.PolynomialToKZGCommitment(Polynomial(blobs[i]))
+	})
 	return out
 }
 
+// PolynomialsToKZGCommitments calls PolynomialsToKZGCommitments on the default context.
+func PolynomialsToKZGCommitments(blobs Polynomials) []KZGCommitment {
+	return defaultContext().PolynomialsToKZGCommitments(blobs)
+}
+
 // BytesToBLSField implements bytes_to_bls_field from the EIP-4844 consensus spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#bytes_to_bls_field
 func BytesToBLSField(h [32]byte) *bls.Fr {
-	// re-interpret as little-endian
-	var b [32]byte = h
-	for i := 0; i < 16; i++ {
-		b[31-i], b[i] = b[i], b[31-i]
-	}
-	zB := new(big.Int).Mod(new(big.Int).SetBytes(b[:]), BLSModulus)
 	out := new(bls.Fr)
-	bigToFr(out, zB)
+	bls.FrFrom32Mod(out, h)
 	return out
 }
 
+// bytesToBLSFieldChunk is the number of inputs BytesToBLSFieldBatch converts per parallel job.
+const bytesToBLSFieldChunk = 1024
+
+// BytesToBLSFieldBatch is BytesToBLSField over many inputs, setting out[i] to in[i] reduced modulo BLS_MODULUS.
+// Nothing is allocated per element, and large inputs are converted in parallel (see SetMaxWorkers).
+// out must have the length of in.
+func BytesToBLSFieldBatch(in [][32]byte, out []bls.Fr) {
+	if len(in) != len(out) {
+		panic("BytesToBLSFieldBatch: in and out length mismatch")
+	}
+	chunks := (len(in) + bytesToBLSFieldChunk - 1) / bytesToBLSFieldChunk
+	parallelFor(chunks, func(c int) {
+		end := (c + 1) * bytesToBLSFieldChunk
+		if end > len(in) {
+			end = len(in)
+		}
+		for i := c * bytesToBLSFieldChunk; i < end; i++ {
+			bls.FrFrom32Mod(&out[i], in[i])
+		}
+	})
+}
+
 // ComputeAggregatedPolyAndcommitment implements compute_aggregated_poly_and_commitment from the EIP-4844 consensus spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#compute_aggregated_poly_and_commitment
-func ComputeAggregatedPolyAndCommitment(blobs Polynomials, commitments KZGCommitmentSequence) ([]bls.Fr, *bls.G1Point, *bls.Fr, error) {
+// The polynomials are combined in parallel (see SetMaxWorkers).
+func (ctx *Context) ComputeAggregatedPolyAndCommitment(blobs Polynomials, commitments KZGCommitmentSequence) ([]bls.Fr, *bls.G1Point, *bls.Fr, error) {
+	// the challenge hashes all the polynomials and commitments: of different counts, it would not bind them pairwise
+	if len(blobs) != commitments.Len() {
+		return nil, nil, nil, fmt.Errorf("%w: %d polynomials, %d commitments", ErrLengthMismatch, len(blobs), commitments.Len())
+	}
 	// create challenges
-	r, err := HashToBLSField(blobs, commitments)
+	r, err := ctx.HashToBLSField(blobs, commitments)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	powers := ComputePowers(r, len(blobs))
-	if len(powers) == 0 {
+	if len(blobs) == 0 {
 		return nil, nil, nil, errors.New("powers can't be 0 length")
 	}
+	aggregatedCommitmentG1, powers, err := ctx.AggregateCommitments(commitments, r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	var evaluationChallenge bls.Fr
 	bls.MulModFr(&evaluationChallenge, r, &powers[len(powers)-1])
 
-	aggregatedPoly, err := bls.PolyLinComb(blobs, powers)
+	aggregatedPoly, err := bls.PolyLinCombParallel(blobs, powers, MaxWorkers())
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	return aggregatedPoly, aggregatedCommitmentG1, &evaluationChallenge, nil
+}
+
+// ComputeAggregatedPolyAndCommitment calls ComputeAggregatedPolyAndCommitment on the default context.
+func ComputeAggregatedPolyAndCommitment(blobs Polynomials, commitments KZGCommitmentSequence) ([]bls.Fr, *bls.G1Point, *bls.Fr, error) {
+	return defaultContext().ComputeAggregatedPolyAndCommitment(blobs, commitments)
+}
 
+// AggregateCommitments is the commitment side of ComputeAggregatedPolyAndCommitment, for verifiers that obtain
+// the aggregated polynomial elsewhere, or only have the commitments. It returns the aggregated commitment
+// sum(r**i * commitments[i]), which commits to the aggregated polynomial, and the powers of r the commitments
+// are combined with, [1, r, r**2, ...]. No commitments aggregate to the point at infinity. A commitment that
+// is not a valid point is rejected with an error wrapping ErrInvalidCommitment.
+func (ctx *Context) AggregateCommitments(commitments KZGCommitmentSequence, r *bls.Fr) (*bls.G1Point, []bls.Fr, error) {
 	l := commitments.Len()
+	powers := ComputePowers(r, l)
 	commitmentsG1 := make([]bls.G1Point, l)
 	for i := 0; i < l; i++ {
-		c := commitments.At(i)
-		p, err := bls.FromCompressedG1(c[:])
+		p, err := ctx.decodeCommitment(commitments.At(i))
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, fmt.Errorf("commitment %d: %w: %v", i, ErrInvalidCommitment, err)
 		}
 		bls.CopyG1(&commitmentsG1[i], p)
 	}
+	if l == 0 {
+		// ClearG1 rather than a copy of ZeroG1, which is not the identity of the Herumi pairing
+		var zero bls.G1Point
+		bls.ClearG1(&zero)
+		return &zero, powers, nil
+	}
+	msmStart := time.Now()
 	aggregatedCommitmentG1 := bls.LinCombG1(commitmentsG1, powers)
-	return aggregatedPoly, aggregatedCommitmentG1, &evaluationChallenge, nil
+	ctx.metrics.MSM(l, time.Since(msmStart))
+	return aggregatedCommitmentG1, powers, nil
+}
+
+// AggregateCommitments calls AggregateCommitments on the default context.
+func AggregateCommitments(commitments KZGCommitmentSequence, r *bls.Fr) (*bls.G1Point, []bls.Fr, error) {
+	return defaultContext().AggregateCommitments(commitments, r)
 }
 
 // ComputeAggregateKZGProofFromPolynomials implements compute_aggregate_kzg_proof from the EIP-4844
 // consensus spec, only operating over blobs that are already parsed into a polynomial.
-func ComputeAggregateKZGProofFromPolynomials(blobs Polynomials) (KZGProof, error) {
-	commitments := make(KZGCommitmentSequenceImpl, len(blobs))
-	for i, b := range blobs {
-		commitments[i] = PolynomialToKZGCommitment(Polynomial(b))
-	}
-	aggregatedPoly, _, evaluationChallenge, err := ComputeAggregatedPolyAndCommitment(blobs, commitments)
+func (ctx *Context) ComputeAggregateKZGProofFromPolynomials(blobs Polynomials) (KZGProof, error) {
+	commitments := KZGCommitmentSequenceImpl(ctx.PolynomialsToKZGCommitments(blobs))
+	aggregatedPoly, _, evaluationChallenge, err := ctx.ComputeAggregatedPolyAndCommitment(blobs, commitments)
 	if err != nil {
 		return KZGProof{}, err
 	}
-	return ComputeKZGProof(aggregatedPoly, evaluationChallenge)
+	return ctx.ComputeKZGProof(aggregatedPoly, evaluationChallenge)
+}
+
+// ComputeAggregateKZGProofFromPolynomials calls ComputeAggregateKZGProofFromPolynomials on the default context.
+func ComputeAggregateKZGProofFromPolynomials(blobs Polynomials) (KZGProof, error) {
+	return defaultContext().ComputeAggregateKZGProofFromPolynomials(blobs)
 }
 
 // ComputeAggregateKZGProof implements compute_kzg_proof from the EIP-4844 consensus spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#compute_kzg_proof
-func ComputeKZGProof(polynomial []bls.Fr, z *bls.Fr) (KZGProof, error) {
-	y := EvaluatePolynomialInEvaluationForm(polynomial, z)
-	polynomialShifted := make([]bls.Fr, len(polynomial))
-	for i := range polynomial {
-		bls.SubModFr(&polynomialShifted[i], &polynomial[i], y)
+func (ctx *Context) ComputeKZGProof(polynomial []bls.Fr, z *bls.Fr) (KZGProof, error) {
+	if len(polynomial) != len(ctx.domain) {
+		return KZGProof{}, fmt.Errorf("%w: polynomial has invalid length", ErrWrongBlobLength)
 	}
-	denominatorPoly := make([]bls.Fr, len(polynomial))
-	if len(polynomial) != len(DomainFr) {
-		return KZGProof{}, errors.New("polynomial has invalid length")
+	proof, _ := ctx.computeKZGProofCached(polynomial, func() KZGCommitment {
+		return ctx.PolynomialToKZGCommitment(polynomial)
+	}, z)
+	return proof, nil
+}
+
+// computeQuotientEvalWithinDomain implements compute_quotient_eval_within_domain from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#compute_quotient_eval_within_domain
+// It sets out to the quotient (f(X) - y) / (X - z) at z = domain[m], given the shifted polynomial f(X) - y:
+//
+//	q(z) = sum_(i != m) (f(DOMAIN[i]) - y) * DOMAIN[i] / (z * (z - DOMAIN[i]))
+func (ctx *Context) computeQuotientEvalWithinDomain(out *bls.Fr, polynomialShifted []bls.Fr, m int) {
+	z := &ctx.domain[m]
+	denominators := make([]bls.Fr, 0, len(polynomialShifted)-1)
+	for i := range polynomialShifted {
+		if i == m {
+			continue
+		}
+		var d bls.Fr
+		bls.SubModFr(&d, z, &ctx.domain[i])
+		bls.MulModFr(&d, &d, z)
+		denominators = append(denominators, d)
 	}
-	for i := range polynomial {
-		if bls.EqualFr(&DomainFr[i], z) {
-			return KZGProof{}, errors.New("invalid z challenge")
+	bls.BatchInvModFr(denominators, denominators)
+	bls.CopyFr(out, &bls.ZERO)
+	j := 0
+	for i := range polynomialShifted {
+		if i == m {
+			continue
 		}
-		bls.SubModFr(&denominatorPoly[i], &DomainFr[i], z)
+		var term bls.Fr
+		bls.MulModFr(&term, &polynomialShifted[i], &ctx.domain[i])
+		bls.MulModFr(&term, &term, &denominators[j])
+		bls.AddModFr(out, out, &term)
+		j++
 	}
-	quotientPolynomial := make([]bls.Fr, len(polynomial))
-	for i := range polynomial {
-		bls.DivModFr(&quotientPolynomial[i], &polynomialShifted[i], &denominatorPoly[i])
+}
+
+// ComputeKZGProof calls ComputeKZGProof on the default context.
+func ComputeKZGProof(polynomial []bls.Fr, z *bls.Fr) (KZGProof, error) {
+	return defaultContext().ComputeKZGProof(polynomial, z)
+}
+
+// KZGProofTrace holds the intermediate values of a proof computation, see ComputeKZGProofDebug.
+// When a proof differs from the one of another implementation, a different Y points at the evaluation,
+// a different Quotient at its construction, and a different Proof with the same Quotient at the MSM.
+type KZGProofTrace struct {
+	// evaluation of the polynomial at z
+	Y bls.Fr
+	// quotient (f(X) - y) / (X - z), in evaluation form, in the bit-reversed order of the domain
+	Quotient Polynomial
+	// proof point, the commitment to the quotient
+	Proof bls.G1Point
+}
+
+// ComputeKZGProofDebug is ComputeKZGProof, also returning the intermediate values of the computation.
+func (ctx *Context) ComputeKZGProofDebug(polynomial []bls.Fr, z *bls.Fr) (KZGProof, *KZGProofTrace, error) {
+	if len(polynomial) != len(ctx.domain) {
+		return KZGProof{}, nil, fmt.Errorf("%w: polynomial has invalid length", ErrWrongBlobLength)
 	}
-	rG1 := bls.LinCombG1(kzgSetupLagrange, quotientPolynomial)
-	var proof KZGProof
-	copy(proof[:], bls.ToCompressedG1(rG1))
-	return proof, nil
+	s := newProverScratch(len(polynomial))
+	proof, y := ctx.computeKZGProof(s, polynomial, z)
+	return proof, &KZGProofTrace{Y: y, Quotient: s.quotient, Proof: s.proof}, nil
+}
+
+// ComputeKZGProofDebug calls ComputeKZGProofDebug on the default context.
+func ComputeKZGProofDebug(polynomial []bls.Fr, z *bls.Fr) (KZGProof, *KZGProofTrace, error) {
+	return defaultContext().ComputeKZGProofDebug(polynomial, z)
 }
 
 // EvaluatePolynomialInEvaluationForm implements evaluate_polynomial_in_evaluation_form from the EIP-4844 consensus spec:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#evaluate_polynomial_in_evaluation_form
-func EvaluatePolynomialInEvaluationForm(poly []bls.Fr, x *bls.Fr) *bls.Fr {
+func (ctx *Context) EvaluatePolynomialInEvaluationForm(poly []bls.Fr, x *bls.Fr) *bls.Fr {
 	var result bls.Fr
-	bls.EvaluatePolyInEvaluationForm(&result, poly, x, DomainFr, 0)
+	bls.EvaluatePolyInEvaluationForm(&result, poly, x, ctx.domain, 0)
 	return &result
 }
 
+// EvaluatePolynomialInEvaluationForm calls EvaluatePolynomialInEvaluationForm on the default context.
+func EvaluatePolynomialInEvaluationForm(poly []bls.Fr, x *bls.Fr) *bls.Fr {
+	return defaultContext().EvaluatePolynomialInEvaluationForm(poly, x)
+}
+
 // HashToBLSField implements hash_to_bls_field from the EIP-4844 consensus specs:
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#hash_to_bls_field
-func HashToBLSField(polys Polynomials, comms KZGCommitmentSequence) (*bls.Fr, error) {
-	sha := sha256.New()
+func (ctx *Context) HashToBLSField(polys Polynomials, comms KZGCommitmentSequence) (*bls.Fr, error) {
+	return ctx.hashToBLSField(ctx.newTranscript(false), polys, comms), nil
+}
 
-	_, err := sha.Write([]byte(FIAT_SHAMIR_PROTOCOL_DOMAIN))
-	if err != nil {
-		return nil, err
+// HashToBLSFieldDebug is HashToBLSField, also returning a transcript of all the hashed inputs.
+func (ctx *Context) HashToBLSFieldDebug(polys Polynomials, comms KZGCommitmentSequence) (*bls.Fr, *ChallengeTranscript) {
+	h := ctx.newTranscript(true)
+	return ctx.hashToBLSField(h, polys, comms), h.record
+}
+
+// HashToBLSFieldDebug calls HashToBLSFieldDebug on the default context.
+func HashToBLSFieldDebug(polys Polynomials, comms KZGCommitmentSequence) (*bls.Fr, *ChallengeTranscript) {
+	return defaultContext().HashToBLSFieldDebug(polys, comms)
+}
+
+func (ctx *Context) hashToBLSField(h *Transcript, polys Polynomials, comms KZGCommitmentSequence) *bls.Fr {
+	ctx.absorbAggregateHeader(h, len(polys))
+	if ctx.aggregatePrehash {
+		absorbAggregateDigests(h, polys)
+	} else {
+		for i, poly := range polys {
+			ctx.absorbAggregatePolynomial(h, i, poly)
+		}
+	}
+	l := comms.Len()
+	for i := 0; i < l; i++ {
+		absorbAggregateCommitment(h, i, comms.At(i))
 	}
+	return h.challenge(false)
+}
 
-	bytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(bytes, uint64(FieldElementsPerBlob))
-	_, err = sha.Write(bytes)
-	if err != nil {
-		return nil, err
+// The inputs of hash_to_bls_field are absorbed in three steps, so that AggregateProofBuilder can absorb them
+// incrementally: the header, then every polynomial, then every commitment.
+
+func (ctx *Context) absorbAggregateHeader(h *Transcript, numPolynomials int) {
+	domain, _ := ctx.fiatShamirDomains()
+	h.absorb(func() string { return "domain" }, []byte(domain))
+	if ctx.aggregatePrehash {
+		h.absorb(func() string { return "prehash_domain" }, []byte(FIAT_SHAMIR_PREHASH_DOMAIN))
 	}
 
+	bytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bytes, uint64(ctx.FieldElementsPerBlob()))
+	h.absorb(func() string { return "field_elements_per_blob" }, bytes)
+
 	bytes = make([]byte, 8)
-	binary.LittleEndian.PutUint64(bytes, uint64(len(polys)))
-	_, err = sha.Write(bytes)
-	if err != nil {
-		return nil, err
-	}
+	binary.LittleEndian.PutUint64(bytes, uint64(numPolynomials))
+	h.absorb(func() string { return "num_polynomials" }, bytes)
+}
 
-	for _, poly := range polys {
-		for _, fe := range poly {
-			b32 := bls.FrTo32(&fe)
-			_, err := sha.Write(b32[:])
-			if err != nil {
-				return nil, err
-			}
-		}
+func (ctx *Context) absorbAggregatePolynomial(h *Transcript, i int, poly Polynomial) {
+	if ctx.aggregatePrehash {
+		absorbAggregateDigest(h, i, h.polynomialDigest(poly))
+		return
 	}
-	l := comms.Len()
-	for i := 0; i < l; i++ {
-		c := comms.At(i)
-		_, err := sha.Write(c[:])
-		if err != nil {
-			return nil, err
-		}
+	for j := range poly {
+		b32 := bls.FrTo32(&poly[j])
+		h.absorb(func() string { return fmt.Sprintf("polynomial[%d][%d]", i, j) }, b32[:])
 	}
-	var hash [32]byte
-	copy(hash[:], sha.Sum(nil))
-	return BytesToBLSField(hash), nil
+}
+
+func absorbAggregateCommitment(h *Transcript, i int, c KZGCommitment) {
+	h.absorb(func() string { return fmt.Sprintf("commitment[%d]", i) }, c[:])
+}
+
+// HashToBLSField calls HashToBLSField on the default context.
+func HashToBLSField(polys Polynomials, comms KZGCommitmentSequence) (*bls.Fr, error) {
+	return defaultContext().HashToBLSField(polys, comms)
 }
 
 func BlobToPolynomial(b Blob) (Polynomial, bool) {
@@ -309,23 +460,43 @@ func BlobsToPolynomials(blobs BlobSequence) ([][]bls.Fr, bool) {
 	return out, true
 }
 
-func frToBig(b *big.Int, val *bls.Fr) {
-	//b.SetBytes((*kilicbls.Fr)(val).RedToBytes())
-	// silly double conversion
-	v := bls.FrTo32(val)
-	for i := 0; i < 16; i++ {
-		v[31-i], v[i] = v[i], v[31-i]
+// blobsToPolynomials is BlobsToPolynomials, returning an error that identifies the first invalid blob
+// and field element, and also checking the size of the blobs against the context, and their number against its limits.
+func (ctx *Context) blobsToPolynomials(blobs BlobSequence) (Polynomials, error) {
+	if err := ctx.checkBlobLimit(blobs.Len()); err != nil {
+		return nil, err
 	}
+	return ctx.blobRangeToPolynomials(blobs, 0, blobs.Len())
+}
+
+// blobRangeToPolynomials is blobsToPolynomials over the blobs [start, end) of the sequence,
+// with the errors reporting the indices of the blobs in the sequence.
+func (ctx *Context) blobRangeToPolynomials(blobs BlobSequence, start, end int) (Polynomials, error) {
+	out := make(Polynomials, end-start)
+	for i := start; i < end; i++ {
+		blob := blobs.At(i)
+		n := blob.Len()
+		if n != ctx.FieldElementsPerBlob() {
+			return nil, fmt.Errorf("%w: blob %d has %d field elements, expected %d", ErrWrongBlobLength, i, n, ctx.FieldElementsPerBlob())
+		}
+		poly := make(Polynomial, n)
+		for j := 0; j < n; j++ {
+			if !bls.FrFrom32(&poly[j], blob.At(j)) {
+				return nil, fmt.Errorf("blob %d: field element %d: %w", i, j, ErrNonCanonicalScalar)
+			}
+		}
+		out[i-start] = poly
+	}
+	return out, nil
+}
+
+func frToBig(b *big.Int, val *bls.Fr) {
+	v := bls.FrTo32BE(val)
 	b.SetBytes(v[:])
 }
 
 func bigToFr(out *bls.Fr, in *big.Int) bool {
 	var b [32]byte
-	inb := in.Bytes()
-	copy(b[32-len(inb):], inb)
-	// again, we have to double convert as go-kzg only accepts little-endian
-	for i := 0; i < 16; i++ {
-		b[31-i], b[i] = b[i], b[31-i]
-	}
-	return bls.FrFrom32(out, b)
+	in.FillBytes(b[:])
+	return bls.FrFrom32BE(out, b)
 }
@@ -0,0 +1,224 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func randomPolynomial() Polynomial {
+	return randomPolynomialN(FieldElementsPerBlob)
+}
+
+func randomPolynomialN(n int) Polynomial {
+	poly := make(Polynomial, n)
+	for i := range poly {
+		bls.CopyFr(&poly[i], bls.RandomFr())
+	}
+	return poly
+}
+
+func TestPolynomialsToKZGCommitments(t *testing.T) {
+	blobs := make(Polynomials, 5)
+	for i := range blobs {
+		blobs[i] = randomPolynomial()
+	}
+	commitments := PolynomialsToKZGCommitments(blobs)
+	if len(commitments) != len(blobs) {
+		t.Fatalf("expected %d commitments, got %d", len(blobs), len(commitments))
+	}
+	for i, b := range blobs {
+		if expected := PolynomialToKZGCommitment(Polynomial(b)); commitments[i] != expected {
+			t.Fatalf("commitment %d mismatch: got %v, expected %v", i, commitments[i], expected)
+		}
+	}
+	if len(PolynomialsToKZGCommitments(nil)) != 0 {
+		t.Fatal("expected no commitments for no polynomials")
+	}
+}
+
+// copyPolynomial returns a deep copy of the polynomial: with big.Int field elements, a copy of the slice
+// would share their digits.
+func copyPolynomial(poly Polynomial) Polynomial {
+	out := make(Polynomial, len(poly))
+	for i := range poly {
+		bls.CopyFr(&out[i], &poly[i])
+	}
+	return out
+}
+
+type testBlob [][32]byte
+
+func (b testBlob) Len() int {
+	return len(b)
+}
+
+func (b testBlob) At(i int) [32]byte {
+	return b[i]
+}
+
+func polynomialToBlob(poly Polynomial) testBlob {
+	blob := make(testBlob, len(poly))
+	for i := range poly {
+		blob[i] = bls.FrTo32(&poly[i])
+	}
+	return blob
+}
+
+func TestComputeKZGProofInDomain(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	for _, i := range []int{0, 3, 15} {
+		z := &ctx.Domain()[i]
+		y := ctx.EvaluatePolynomialInEvaluationForm(poly, z)
+		if !bls.EqualFr(y, &poly[i]) {
+			t.Fatalf("expected evaluation at domain point %d to be the polynomial element", i)
+		}
+		proof, err := ctx.ComputeKZGProof(poly, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := ctx.VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("expected proof at domain point %d to verify", i)
+		}
+	}
+}
+
+func TestComputeKZGProofAt(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	blob := polynomialToBlob(poly)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	for _, z := range []*bls.Fr{bls.RandomFr(), &ctx.Domain()[5]} {
+		proof, y, err := ctx.ComputeKZGProofAt(blob, bls.FrTo32(z))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := ctx.EvaluatePolynomialInEvaluationForm(poly, z); y != bls.FrTo32(expected) {
+			t.Fatal("unexpected evaluation")
+		}
+		ok, err := ctx.VerifyKZGProof(commitment, bls.FrTo32(z), y, proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected proof to verify")
+		}
+	}
+	var nonCanonical [32]byte
+	for i := range nonCanonical {
+		nonCanonical[i] = 0xff
+	}
+	if _, _, err := ctx.ComputeKZGProofAt(blob, nonCanonical); err == nil {
+		t.Fatal("expected non-canonical evaluation point to be rejected")
+	}
+	if _, _, err := ctx.ComputeKZGProofAt(polynomialToBlob(randomPolynomialN(8)), bls.FrTo32(bls.RandomFr())); err == nil {
+		t.Fatal("expected blob of the wrong size to be rejected")
+	}
+}
+
+func TestComputeKZGProofDebug(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	for _, z := range []*bls.Fr{bls.RandomFr(), &ctx.Domain()[2]} {
+		proof, trace, err := ctx.ComputeKZGProofDebug(poly, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := ctx.ComputeKZGProof(poly, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof != expected {
+			t.Fatal("expected the same proof as ComputeKZGProof")
+		}
+		if !bls.EqualFr(&trace.Y, ctx.EvaluatePolynomialInEvaluationForm(poly, z)) {
+			t.Fatal("unexpected evaluation in trace")
+		}
+		if !bls.EqualG1(&trace.Proof, ctx.lagrangeLinComb(trace.Quotient)) {
+			t.Fatal("expected the trace proof to commit to the trace quotient")
+		}
+		if compressed := bls.ToCompressedG1(&trace.Proof); string(compressed) != string(proof[:]) {
+			t.Fatal("expected the trace proof to match the proof")
+		}
+		// q(X) * (X - z) = f(X) - y at any domain point
+		for i := range poly {
+			var lhs, rhs bls.Fr
+			bls.SubModFr(&lhs, &ctx.Domain()[i], z)
+			bls.MulModFr(&lhs, &lhs, &trace.Quotient[i])
+			bls.SubModFr(&rhs, &poly[i], &trace.Y)
+			if !bls.EqualFr(&lhs, &rhs) {
+				t.Fatalf("quotient mismatch at %d", i)
+			}
+		}
+	}
+}
+
+func TestBytesToBLSFieldBatch(t *testing.T) {
+	in := make([][32]byte, 2*bytesToBLSFieldChunk+3)
+	for i := range in {
+		if _, err := rand.Read(in[i][:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// values above the modulus are reduced
+	for i := range in[0] {
+		in[0][i] = 0xff
+	}
+	out := make([]bls.Fr, len(in))
+	BytesToBLSFieldBatch(in, out)
+	for i := range in {
+		if !bls.EqualFr(&out[i], BytesToBLSField(in[i])) {
+			t.Fatalf("element %d does not match BytesToBLSField", i)
+		}
+	}
+}
+
+func TestAggregateCommitments(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	polys := Polynomials{randomPolynomialN(16), randomPolynomialN(16), randomPolynomialN(16)}
+	commitments := make(KZGCommitmentSequenceImpl, len(polys))
+	for i := range polys {
+		commitments[i] = ctx.PolynomialToKZGCommitment(polys[i])
+	}
+	aggregatedPoly, aggregatedCommitment, _, err := ctx.ComputeAggregatedPolyAndCommitment(polys, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ctx.HashToBLSField(polys, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment, powers, err := ctx.AggregateCommitments(commitments, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.EqualG1(commitment, aggregatedCommitment) {
+		t.Fatal("aggregated commitment mismatch")
+	}
+	if len(powers) != 3 || !bls.EqualOne(&powers[0]) || !bls.EqualFr(&powers[1], r) {
+		t.Fatal("unexpected powers")
+	}
+	if ctx.PolynomialToKZGCommitment(aggregatedPoly) != compressCommitment(commitment) {
+		t.Fatal("expected the aggregated commitment to commit to the aggregated polynomial")
+	}
+
+	if commitment, powers, err := ctx.AggregateCommitments(KZGCommitmentSequenceImpl{}, r); err != nil || !bls.IsZeroG1(commitment) || len(powers) != 0 {
+		t.Fatalf("expected no commitments to aggregate to the point at infinity: %v", err)
+	}
+	// without the compression flag
+	commitments[1][0] &^= 0x80
+	if _, _, err := ctx.AggregateCommitments(commitments, r); !errors.Is(err, ErrInvalidCommitment) {
+		t.Fatalf("expected invalid commitment to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// The 0x-prefixed hex encoding of the JSON-RPC APIs is implemented by the text marshaling
+// of the commitments, proofs, versioned hashes and blobs, so that they can be used as is in JSON-RPC structs.
+// Decoding is strict: the prefix is required, and the length must be exact.
+
+func encodeHex(b []byte) []byte {
+	out := make([]byte, 2+2*len(b))
+	copy(out, "0x")
+	hex.Encode(out[2:], b)
+	return out
+}
+
+func decodeHex(name string, text []byte, out []byte) error {
+	if len(text) < 2 || text[0] != '0' || (text[1] != 'x' && text[1] != 'X') {
+		return fmt.Errorf("invalid %s: missing 0x prefix", name)
+	}
+	text = text[2:]
+	if len(text) != 2*len(out) {
+		return fmt.Errorf("invalid %s: expected %d hex characters, got %d", name, 2*len(out), len(text))
+	}
+	if _, err := hex.Decode(out, text); err != nil {
+		return fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return nil
+}
+
+// String returns the 0x-prefixed hex encoding of the commitment.
+func (c KZGCommitment) String() string {
+	return string(encodeHex(c[:]))
+}
+
+// MarshalText implements encoding.TextMarshaler, with the 0x-prefixed hex encoding.
+func (c KZGCommitment) MarshalText() ([]byte, error) {
+	return encodeHex(c[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, with the 0x-prefixed hex encoding of exactly 48 bytes.
+// The point itself is not checked, which is left to the functions using the commitment.
+func (c *KZGCommitment) UnmarshalText(text []byte) error {
+	return decodeHex("commitment", text, c[:])
+}
+
+// String returns the 0x-prefixed hex encoding of the proof.
+func (p KZGProof) String() string {
+	return string(encodeHex(p[:]))
+}
+
+// MarshalText implements encoding.TextMarshaler, with the 0x-prefixed hex encoding.
+func (p KZGProof) MarshalText() ([]byte, error) {
+	return encodeHex(p[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, with the 0x-prefixed hex encoding of exactly 48 bytes.
+// The point itself is not checked, which is left to the functions using the proof.
+func (p *KZGProof) UnmarshalText(text []byte) error {
+	return decodeHex("proof", text, p[:])
+}
+
+// String returns the 0x-prefixed hex encoding of the versioned hash.
+func (h VersionedHash) String() string {
+	return string(encodeHex(h[:]))
+}
+
+// MarshalText implements encoding.TextMarshaler, with the 0x-prefixed hex encoding.
+func (h VersionedHash) MarshalText() ([]byte, error) {
+	return encodeHex(h[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, with the 0x-prefixed hex encoding of exactly 32 bytes.
+func (h *VersionedHash) UnmarshalText(text []byte) error {
+	return decodeHex("versioned hash", text, h[:])
+}
+
+// String returns the 0x-prefixed hex encoding of the blob.
+// Like the other methods of BlobBytes, it takes a pointer, so as not to copy the blob.
+func (b *BlobBytes) String() string {
+	return string(encodeHex(b[:]))
+}
+
+// MarshalText implements encoding.TextMarshaler, with the 0x-prefixed hex encoding.
+func (b *BlobBytes) MarshalText() ([]byte, error) {
+	return encodeHex(b[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, with the 0x-prefixed hex encoding of exactly BytesPerBlob bytes.
+// The field elements are not checked, which is left to the functions using the blob.
+func (b *BlobBytes) UnmarshalText(text []byte) error {
+	return decodeHex("blob", text, b[:])
+}
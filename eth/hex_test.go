@@ -0,0 +1,57 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHexEncoding(t *testing.T) {
+	type rpcBundle struct {
+		Commitments []KZGCommitment `json:"commitments"`
+		Proofs      []KZGProof      `json:"proofs"`
+		Hashes      []VersionedHash `json:"hashes"`
+		Blobs       []*BlobBytes    `json:"blobs"`
+	}
+	var blob BlobBytes
+	blob[0], blob[len(blob)-1] = 1, 2
+	commitment := KZGCommitment{0xc0}
+	in := rpcBundle{
+		Commitments: []KZGCommitment{commitment},
+		Proofs:      []KZGProof{{0xab}},
+		Hashes:      []VersionedHash{KZGToVersionedHash(commitment)},
+		Blobs:       []*BlobBytes{&blob},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"commitments":["0xc000`) {
+		t.Fatalf("unexpected encoding %s", data[:64])
+	}
+	var out rpcBundle
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Commitments[0] != commitment || out.Proofs[0] != in.Proofs[0] || out.Hashes[0] != in.Hashes[0] || *out.Blobs[0] != blob {
+		t.Fatal("round trip mismatch")
+	}
+	if s := commitment.String(); len(s) != 2+96 || !strings.HasPrefix(s, "0xc000") {
+		t.Fatalf("unexpected string %s", s)
+	}
+
+	for _, text := range []string{
+		"c0" + strings.Repeat("00", 47),
+		"0x" + strings.Repeat("00", 47),
+		"0x" + strings.Repeat("00", 49),
+		"0x" + strings.Repeat("zz", 48),
+	} {
+		var c KZGCommitment
+		if err := c.UnmarshalText([]byte(text)); err == nil {
+			t.Fatalf("expected %q to be rejected", text)
+		}
+	}
+}
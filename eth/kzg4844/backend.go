@@ -0,0 +1,162 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg4844
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Backend implements the KZG functions of this package. The package functions use the active backend,
+// selected with UseBackend or SelectFastestBackend.
+//
+// The curve library of the native backend is fixed at build time (see bls.CurveBackend),
+// but other implementations, e.g. bindings of c-kzg, can be compiled in and registered with RegisterBackend,
+// so that a binary running on heterogeneous hardware can pick the fastest one at startup.
+type Backend interface {
+	BlobToCommitment(blob *Blob) (Commitment, error)
+	ComputeProof(blob *Blob, point Point) (Proof, Claim, error)
+	VerifyProof(commitment Commitment, point Point, claim Claim, proof Proof) error
+	ComputeBlobProof(blob *Blob, commitment Commitment) (Proof, error)
+	VerifyBlobProof(blob *Blob, commitment Commitment, proof Proof) error
+}
+
+const (
+	// NativeBackend is the name of the backend of this package, on top of the eth package.
+	NativeBackend = "go-kzg"
+	// CKZGBackend is the name under which c-kzg bindings are expected to register, see UseCKZG.
+	CKZGBackend = "ckzg"
+)
+
+// nativeBackend implements Backend with the eth package, with the trusted setup embedded in it.
+type nativeBackend struct{}
+
+var (
+	backendsMu sync.RWMutex
+	backends           = map[string]Backend{NativeBackend: nativeBackend{}}
+	active     Backend = nativeBackend{}
+	activeName         = NativeBackend
+)
+
+func activeBackend() Backend {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	return active
+}
+
+// RegisterBackend makes a backend available under the given name, typically from the init function of its package.
+// It does not make it active.
+func RegisterBackend(name string, backend Backend) error {
+	if backend == nil {
+		return errors.New("nil backend")
+	}
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	if _, ok := backends[name]; ok {
+		return fmt.Errorf("backend %q is already registered", name)
+	}
+	backends[name] = backend
+	return nil
+}
+
+// Backends returns the names of the registered backends, sorted.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UseBackend makes the registered backend with the given name active.
+func UseBackend(name string) error {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backend, ok := backends[name]
+	if !ok {
+		return fmt.Errorf("unknown backend %q", name)
+	}
+	active, activeName = backend, name
+	return nil
+}
+
+// ActiveBackend returns the name of the active backend.
+func ActiveBackend() string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	return activeName
+}
+
+// backendBenchmarkRounds is the number of times each operation is timed per backend, keeping the best time.
+const backendBenchmarkRounds = 3
+
+// SelectFastestBackend runs a short micro-benchmark of every registered backend, and makes the fastest one active.
+// A backend is timed computing a commitment, which is dominated by an MSM, and verifying a point proof,
+// which is dominated by the pairings; both are checked to agree with the native backend,
+// and backends that fail or disagree are skipped. It returns the name of the selected backend.
+// This takes in the order of a few hundred milliseconds per backend, and is meant to be called once at startup.
+func SelectFastestBackend() (string, error) {
+	var blob Blob
+	for i := 0; i < len(blob); i += 32 {
+		// big-endian field elements, small enough to be canonical
+		blob[i+31] = byte(i / 32)
+		blob[i+30] = byte(i / 32 >> 8)
+	}
+	var point Point
+	point[31] = 0x2a
+	commitment, err := nativeBackend{}.BlobToCommitment(&blob)
+	if err != nil {
+		return "", err
+	}
+	proof, claim, err := nativeBackend{}.ComputeProof(&blob, point)
+	if err != nil {
+		return "", err
+	}
+
+	best, bestTime := "", time.Duration(0)
+	for _, name := range Backends() {
+		backendsMu.RLock()
+		backend := backends[name]
+		backendsMu.RUnlock()
+		elapsed, err := benchmarkBackend(backend, &blob, commitment, point, claim, proof)
+		if err != nil {
+			continue
+		}
+		if best == "" || elapsed < bestTime {
+			best, bestTime = name, elapsed
+		}
+	}
+	if best == "" {
+		return "", errors.New("no working backend")
+	}
+	return best, UseBackend(best)
+}
+
+// benchmarkBackend returns the best time of the backend computing the commitment and verifying the proof.
+func benchmarkBackend(backend Backend, blob *Blob, commitment Commitment, point Point, claim Claim, proof Proof) (time.Duration, error) {
+	var best time.Duration
+	for i := 0; i < backendBenchmarkRounds; i++ {
+		start := time.Now()
+		c, err := backend.BlobToCommitment(blob)
+		if err != nil {
+			return 0, err
+		}
+		if c != commitment {
+			return 0, errors.New("commitment mismatch")
+		}
+		if err := backend.VerifyProof(commitment, point, claim, proof); err != nil {
+			return 0, err
+		}
+		if elapsed := time.Since(start); i == 0 || elapsed < best {
+			best = elapsed
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,70 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg4844
+
+import (
+	"testing"
+	"time"
+)
+
+// slowBackend is the native backend, slowed down.
+type slowBackend struct {
+	nativeBackend
+}
+
+func (slowBackend) BlobToCommitment(blob *Blob) (Commitment, error) {
+	time.Sleep(50 * time.Millisecond)
+	return nativeBackend{}.BlobToCommitment(blob)
+}
+
+// brokenBackend computes wrong commitments.
+type brokenBackend struct {
+	nativeBackend
+}
+
+func (brokenBackend) BlobToCommitment(blob *Blob) (Commitment, error) {
+	return Commitment{0xc0}, nil
+}
+
+func TestSelectFastestBackend(t *testing.T) {
+	if err := RegisterBackend("slow", slowBackend{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterBackend("broken", brokenBackend{}); err != nil {
+		t.Fatal(err)
+	}
+	defer UseBackend(NativeBackend)
+	if err := RegisterBackend("slow", slowBackend{}); err == nil {
+		t.Fatal("expected a duplicate name to be rejected")
+	}
+	if got := Backends(); len(got) != 3 || got[0] != "broken" || got[1] != NativeBackend || got[2] != "slow" {
+		t.Fatalf("unexpected backends %v", got)
+	}
+	if err := UseBackend("slow"); err != nil {
+		t.Fatal(err)
+	}
+	if ActiveBackend() != "slow" {
+		t.Fatalf("expected slow backend to be active, got %s", ActiveBackend())
+	}
+	name, err := SelectFastestBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != NativeBackend || ActiveBackend() != NativeBackend {
+		t.Fatalf("expected the native backend to be selected, got %s", name)
+	}
+	if err := UseBackend("missing"); err == nil {
+		t.Fatal("expected an unknown backend to be rejected")
+	}
+	if err := UseCKZG(true); err == nil {
+		t.Fatal("expected c-kzg to be unavailable")
+	}
+	if err := UseCKZG(false); err != nil {
+		t.Fatal(err)
+	}
+	var b Blob
+	if _, err := BlobToCommitment(&b); err != nil {
+		t.Fatalf("expected the native backend to work: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg4844
+
+import (
+	"fmt"
+)
+
+// Divergence describes a call on which the two backends of a cross-check disagree.
+type Divergence struct {
+	// Op is the name of the Backend method.
+	Op string
+	// Primary and Reference are the results of the backends, formatted.
+	Primary   string
+	Reference string
+}
+
+func (d *Divergence) Error() string {
+	return fmt.Sprintf("kzg4844: backends diverge on %s: %s != %s", d.Op, d.Primary, d.Reference)
+}
+
+// crossCheckBackend runs every call through two backends, and reports divergences.
+type crossCheckBackend struct {
+	primary   Backend
+	reference string
+	onDiverge func(*Divergence)
+}
+
+// NewCrossCheckBackend returns a backend that runs every call through the primary backend and through
+// the reference backend registered under the given name, looked up on every call so that it may be registered
+// later, and returns the results of the primary backend. Results that differ, or an error from only one of
+// the backends, are passed to onDivergence, or cause a panic if it is nil; error messages are not compared.
+// A missing reference backend is reported as a divergence too.
+//
+// This doubles the cost of every call, and is meant for canary nodes watching for drift between implementations.
+func NewCrossCheckBackend(primary Backend, reference string, onDivergence func(*Divergence)) Backend {
+	return &crossCheckBackend{primary: primary, reference: reference, onDiverge: onDivergence}
+}
+
+// EnableCrossCheck wraps the active backend in a cross-check against the reference backend registered under
+// the given name, see NewCrossCheckBackend. UseBackend disables it again.
+func EnableCrossCheck(reference string, onDivergence func(*Divergence)) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	active = NewCrossCheckBackend(active, reference, onDivergence)
+	activeName = fmt.Sprintf("%s+%s", activeName, reference)
+}
+
+// referenceBackend returns the reference backend, or nil after reporting that it is missing.
+func (c *crossCheckBackend) referenceBackend(op string) Backend {
+	backendsMu.RLock()
+	backend, ok := backends[c.reference]
+	backendsMu.RUnlock()
+	if !ok {
+		c.report(op, "registered", fmt.Sprintf("backend %q is not registered", c.reference))
+		return nil
+	}
+	return backend
+}
+
+func (c *crossCheckBackend) report(op string, primary, reference string) {
+	d := &Divergence{Op: op, Primary: primary, Reference: reference}
+	if c.onDiverge == nil {
+		panic(d)
+	}
+	c.onDiverge(d)
+}
+
+func (c *crossCheckBackend) compare(op string, primary, reference string) {
+	if primary != reference {
+		c.report(op, primary, reference)
+	}
+}
+
+// result formats the result of a call, an error only by whether there is one.
+func result(err error, values ...interface{}) string {
+	if err != nil {
+		return "error"
+	}
+	return fmt.Sprintf("%x", values)
+}
+
+func (c *crossCheckBackend) BlobToCommitment(blob *Blob) (Commitment, error) {
+	commitment, err := c.primary.BlobToCommitment(blob)
+	if ref := c.referenceBackend("BlobToCommitment"); ref != nil {
+		refCommitment, refErr := ref.BlobToCommitment(blob)
+		c.compare("BlobToCommitment", result(err, commitment), result(refErr, refCommitment))
+	}
+	return commitment, err
+}
+
+func (c *crossCheckBackend) ComputeProof(blob *Blob, point Point) (Proof, Claim, error) {
+	proof, claim, err := c.primary.ComputeProof(blob, point)
+	if ref := c.referenceBackend("ComputeProof"); ref != nil {
+		refProof, refClaim, refErr := ref.ComputeProof(blob, point)
+		c.compare("ComputeProof", result(err, proof, claim), result(refErr, refProof, refClaim))
+	}
+	return proof, claim, err
+}
+
+func (c *crossCheckBackend) VerifyProof(commitment Commitment, point Point, claim Claim, proof Proof) error {
+	err := c.primary.VerifyProof(commitment, point, claim, proof)
+	if ref := c.referenceBackend("VerifyProof"); ref != nil {
+		refErr := ref.VerifyProof(commitment, point, claim, proof)
+		c.compare("VerifyProof", result(err), result(refErr))
+	}
+	return err
+}
+
+func (c *crossCheckBackend) ComputeBlobProof(blob *Blob, commitment Commitment) (Proof, error) {
+	proof, err := c.primary.ComputeBlobProof(blob, commitment)
+	if ref := c.referenceBackend("ComputeBlobProof"); ref != nil {
+		refProof, refErr := ref.ComputeBlobProof(blob, commitment)
+		c.compare("ComputeBlobProof", result(err, proof), result(refErr, refProof))
+	}
+	return proof, err
+}
+
+func (c *crossCheckBackend) VerifyBlobProof(blob *Blob, commitment Commitment, proof Proof) error {
+	err := c.primary.VerifyBlobProof(blob, commitment, proof)
+	if ref := c.referenceBackend("VerifyBlobProof"); ref != nil {
+		refErr := ref.VerifyBlobProof(blob, commitment, proof)
+		c.compare("VerifyBlobProof", result(err), result(refErr))
+	}
+	return err
+}
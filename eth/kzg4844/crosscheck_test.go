@@ -0,0 +1,95 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg4844
+
+import (
+	"errors"
+	"testing"
+)
+
+// registerTestBackend registers a backend for the duration of the test.
+func registerTestBackend(t *testing.T, name string, backend Backend) {
+	if err := RegisterBackend(name, backend); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		backendsMu.Lock()
+		defer backendsMu.Unlock()
+		delete(backends, name)
+	})
+}
+
+// rejectingBackend is the native backend, rejecting all blob proofs.
+type rejectingBackend struct {
+	nativeBackend
+}
+
+func (rejectingBackend) VerifyBlobProof(blob *Blob, commitment Commitment, proof Proof) error {
+	return errors.New("invalid proof")
+}
+
+func TestCrossCheck(t *testing.T) {
+	registerTestBackend(t, "mirror", nativeBackend{})
+	registerTestBackend(t, "wrong", brokenBackend{})
+	registerTestBackend(t, "rejecting", rejectingBackend{})
+	defer UseBackend(NativeBackend)
+
+	var divergences []*Divergence
+	onDivergence := func(d *Divergence) {
+		divergences = append(divergences, d)
+	}
+	var blob Blob
+	blob[31] = 1
+	EnableCrossCheck("mirror", onDivergence)
+	if ActiveBackend() != NativeBackend+"+mirror" {
+		t.Fatalf("unexpected active backend %s", ActiveBackend())
+	}
+	commitment, err := BlobToCommitment(&blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ComputeBlobProof(&blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBlobProof(&blob, commitment, proof); err != nil {
+		t.Fatal(err)
+	}
+	if len(divergences) != 0 {
+		t.Fatalf("unexpected divergences: %v", divergences)
+	}
+
+	if err := UseBackend(NativeBackend); err != nil {
+		t.Fatal(err)
+	}
+	EnableCrossCheck("wrong", onDivergence)
+	if got, err := BlobToCommitment(&blob); err != nil || got != commitment {
+		t.Fatal("expected the result of the primary backend")
+	}
+	if len(divergences) != 1 || divergences[0].Op != "BlobToCommitment" {
+		t.Fatalf("expected a commitment divergence, got %v", divergences)
+	}
+
+	if err := UseBackend(NativeBackend); err != nil {
+		t.Fatal(err)
+	}
+	EnableCrossCheck("rejecting", onDivergence)
+	if err := VerifyBlobProof(&blob, commitment, proof); err != nil {
+		t.Fatal(err)
+	}
+	if len(divergences) != 2 || divergences[1].Op != "VerifyBlobProof" {
+		t.Fatalf("expected a verification divergence, got %v", divergences)
+	}
+
+	if err := UseBackend(NativeBackend); err != nil {
+		t.Fatal(err)
+	}
+	EnableCrossCheck("missing", nil)
+	defer func() {
+		if _, ok := recover().(*Divergence); !ok {
+			t.Fatal("expected a missing reference backend to panic")
+		}
+	}()
+	_, _ = BlobToCommitment(&blob)
+}
@@ -0,0 +1,166 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+// Package kzg4844 mirrors the API of go-ethereum's crypto/kzg4844 package, backed by the eth package,
+// so that go-ethereum forks can switch implementations by changing the import path.
+//
+// As in go-ethereum, blobs, points and claims are encoded with big-endian field elements.
+// Commitments and proofs are computed with the trusted setup embedded in the eth package.
+package kzg4844
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/protolambda/go-kzg/bls"
+	"github.com/protolambda/go-kzg/eth"
+)
+
+// Blob represents a 4844 data blob.
+type Blob [eth.FieldElementsPerBlob * 32]byte
+
+// Commitment is a serialized commitment to a polynomial.
+type Commitment [48]byte
+
+// Proof is a serialized commitment to the quotient polynomial.
+type Proof [48]byte
+
+// Point is a BLS field element.
+type Point [32]byte
+
+// Claim is a claimed evaluation value in a specific point.
+type Claim [32]byte
+
+// blobView presents a big-endian Blob as an eth.Blob, which uses little-endian field elements.
+type blobView Blob
+
+func (b *blobView) Len() int {
+	return eth.FieldElementsPerBlob
+}
+
+func (b *blobView) At(i int) [32]byte {
+	var out [32]byte
+	for j := 0; j < 32; j++ {
+		out[j] = b[i*32+31-j]
+	}
+	return out
+}
+
+// swapEndianness converts between the big-endian encoding of geth and the little-endian one of the eth package.
+func swapEndianness(v [32]byte) (out [32]byte) {
+	for i := range v {
+		out[i] = v[31-i]
+	}
+	return out
+}
+
+// UseCKZG switches between the c-kzg backend, which must have been registered by its bindings
+// (see RegisterBackend), and the native backend of this package.
+func UseCKZG(use bool) error {
+	if use {
+		if err := UseBackend(CKZGBackend); err != nil {
+			return errors.New("c-kzg is not available in this implementation")
+		}
+		return nil
+	}
+	return UseBackend(NativeBackend)
+}
+
+// BlobToCommitment creates a small commitment out of a data blob.
+func BlobToCommitment(blob *Blob) (Commitment, error) {
+	return activeBackend().BlobToCommitment(blob)
+}
+
+func (nativeBackend) BlobToCommitment(blob *Blob) (Commitment, error) {
+	commitment, err := eth.BlobToKZGCommitment((*blobView)(blob))
+	if err != nil {
+		return Commitment{}, err
+	}
+	return Commitment(commitment), nil
+}
+
+// ComputeProof computes the KZG proof at the given point for the polynomial
+// represented by the blob.
+func ComputeProof(blob *Blob, point Point) (Proof, Claim, error) {
+	return activeBackend().ComputeProof(blob, point)
+}
+
+func (nativeBackend) ComputeProof(blob *Blob, point Point) (Proof, Claim, error) {
+	poly, ok := eth.BlobToPolynomial((*blobView)(blob))
+	if !ok {
+		return Proof{}, Claim{}, errors.New("invalid blob")
+	}
+	var z bls.Fr
+	if !bls.FrFrom32BE(&z, point) {
+		return Proof{}, Claim{}, errors.New("invalid evaluation point")
+	}
+	proof, err := eth.ComputeKZGProof(poly, &z)
+	if err != nil {
+		return Proof{}, Claim{}, err
+	}
+	y := eth.EvaluatePolynomialInEvaluationForm(poly, &z)
+	return Proof(proof), Claim(bls.FrTo32BE(y)), nil
+}
+
+// VerifyProof checks that the commitment, evaluated at the given point, has the claimed value.
+func VerifyProof(commitment Commitment, point Point, claim Claim, proof Proof) error {
+	return activeBackend().VerifyProof(commitment, point, claim, proof)
+}
+
+func (nativeBackend) VerifyProof(commitment Commitment, point Point, claim Claim, proof Proof) error {
+	ok, err := eth.VerifyKZGProof(eth.KZGCommitment(commitment), swapEndianness(point), swapEndianness(claim), eth.KZGProof(proof))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("invalid proof")
+	}
+	return nil
+}
+
+// ComputeBlobProof returns the KZG proof that is used to verify the blob against
+// the commitment.
+//
+// This method does not verify that the commitment is correct with respect to blob.
+func ComputeBlobProof(blob *Blob, commitment Commitment) (Proof, error) {
+	return activeBackend().ComputeBlobProof(blob, commitment)
+}
+
+func (nativeBackend) ComputeBlobProof(blob *Blob, commitment Commitment) (Proof, error) {
+	proof, err := eth.ComputeBlobKZGProof((*blobView)(blob), eth.KZGCommitment(commitment))
+	return Proof(proof), err
+}
+
+// VerifyBlobProof verifies that the blob data corresponds to the provided commitment.
+func VerifyBlobProof(blob *Blob, commitment Commitment, proof Proof) error {
+	return activeBackend().VerifyBlobProof(blob, commitment, proof)
+}
+
+func (nativeBackend) VerifyBlobProof(blob *Blob, commitment Commitment, proof Proof) error {
+	ok, err := eth.VerifyBlobKZGProof((*blobView)(blob), eth.KZGCommitment(commitment), eth.KZGProof(proof))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("invalid proof")
+	}
+	return nil
+}
+
+// CalcBlobHashV1 calculates the 'versioned blob hash' of a commitment.
+// The given hasher must be a sha256 hash instance, otherwise the result will be invalid!
+func CalcBlobHashV1(hasher hash.Hash, commit *Commitment) (vh [32]byte) {
+	if hasher.Size() != 32 {
+		panic("wrong hash size")
+	}
+	hasher.Reset()
+	hasher.Write(commit[:])
+	hasher.Sum(vh[:0])
+	vh[0] = eth.BlobCommitmentVersionKZG
+	return vh
+}
+
+// IsValidVersionedHash checks that h is a structurally-valid versioned blob hash.
+func IsValidVersionedHash(h []byte) bool {
+	return len(h) == 32 && h[0] == eth.BlobCommitmentVersionKZG
+}
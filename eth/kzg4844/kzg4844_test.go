@@ -0,0 +1,69 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg4844
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+)
+
+func randomBlob(t *testing.T) *Blob {
+	var blob Blob
+	if _, err := rand.Read(blob[:]); err != nil {
+		t.Fatal(err)
+	}
+	// keep every big-endian field element below the modulus
+	for i := 0; i < len(blob); i += 32 {
+		blob[i] = 0
+	}
+	return &blob
+}
+
+func TestBlobProofRoundTrip(t *testing.T) {
+	blob := randomBlob(t)
+	commitment, err := BlobToCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ComputeBlobProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBlobProof(blob, commitment, proof); err != nil {
+		t.Fatalf("valid blob proof rejected: %v", err)
+	}
+	blob[31] ^= 1
+	if err := VerifyBlobProof(blob, commitment, proof); err == nil {
+		t.Fatal("blob proof accepted for a modified blob")
+	}
+}
+
+func TestPointProofRoundTrip(t *testing.T) {
+	blob := randomBlob(t)
+	commitment, err := BlobToCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	point := Point{31: 0x2a, 30: 0x01}
+	proof, claim, err := ComputeProof(blob, point)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyProof(commitment, point, claim, proof); err != nil {
+		t.Fatalf("valid proof rejected: %v", err)
+	}
+	claim[31] ^= 1
+	if err := VerifyProof(commitment, point, claim, proof); err == nil {
+		t.Fatal("proof accepted for a wrong claim")
+	}
+}
+
+func TestCalcBlobHashV1(t *testing.T) {
+	var commitment Commitment
+	vh := CalcBlobHashV1(sha256.New(), &commitment)
+	if !IsValidVersionedHash(vh[:]) {
+		t.Fatalf("invalid versioned hash %x", vh)
+	}
+}
@@ -0,0 +1,105 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+// Package kzgtest provides deterministic fixtures for testing code built on the eth package:
+// seeded random blobs and scalars, and valid and invalid commitments and proofs.
+//
+// Field elements are little-endian, like in the eth package.
+package kzgtest
+
+import (
+	"math/rand"
+
+	"github.com/protolambda/go-kzg/bls"
+	"github.com/protolambda/go-kzg/eth"
+)
+
+// Blob is a blob of field elements, implementing eth.Blob.
+type Blob [][32]byte
+
+func (b Blob) Len() int {
+	return len(b)
+}
+
+func (b Blob) At(i int) [32]byte {
+	return b[i]
+}
+
+var (
+	// NonCanonicalScalar is BLS_MODULUS, the smallest field element encoding that is not canonical.
+	NonCanonicalScalar [32]byte
+
+	// InfinityCommitment is the commitment to the zero polynomial, the identity of G1.
+	InfinityCommitment = eth.InfinityKZGCommitment
+	// InfinityProof is the proof of any opening of a constant polynomial, the identity of G1.
+	InfinityProof = eth.KZGProof(eth.InfinityKZGCommitment)
+
+	// NotOnCurveCommitment has x = 1, and there is no point on the curve with this x coordinate.
+	NotOnCurveCommitment = eth.KZGCommitment{0x80, 47: 0x01}
+	// NotOnCurveProof is NotOnCurveCommitment as a proof.
+	NotOnCurveProof = eth.KZGProof(NotOnCurveCommitment)
+
+	// WrongSubgroupCommitment is the point (0, 2), which is on the curve but not in the G1 subgroup.
+	WrongSubgroupCommitment = eth.KZGCommitment{0x80}
+	// WrongSubgroupProof is WrongSubgroupCommitment as a proof.
+	WrongSubgroupProof = eth.KZGProof(WrongSubgroupCommitment)
+
+	// UncompressedFlagCommitment does not have the compression flag set, which is required for 48-byte points.
+	UncompressedFlagCommitment = eth.KZGCommitment{}
+	// UncompressedFlagProof is UncompressedFlagCommitment as a proof.
+	UncompressedFlagProof = eth.KZGProof(UncompressedFlagCommitment)
+)
+
+func init() {
+	var be [32]byte
+	eth.BLSModulus.FillBytes(be[:])
+	for i := range be {
+		NonCanonicalScalar[i] = be[31-i]
+	}
+}
+
+// RandomCanonicalScalar returns a random canonical field element, determined by the seed.
+func RandomCanonicalScalar(seed int64) [32]byte {
+	return randomScalar(rand.New(rand.NewSource(seed)))
+}
+
+func randomScalar(rng *rand.Rand) [32]byte {
+	var v [32]byte
+	rng.Read(v[:])
+	var x bls.Fr
+	bls.FrFrom32Mod(&x, v)
+	return bls.FrTo32(&x)
+}
+
+// RandomBlob returns a blob of eth.FieldElementsPerBlob random canonical field elements, determined by the seed.
+func RandomBlob(seed int64) Blob {
+	return RandomBlobN(seed, eth.FieldElementsPerBlob)
+}
+
+// RandomBlobN is RandomBlob with n field elements, e.g. for contexts with a smaller setup.
+func RandomBlobN(seed int64, n int) Blob {
+	rng := rand.New(rand.NewSource(seed))
+	blob := make(Blob, n)
+	for i := range blob {
+		blob[i] = randomScalar(rng)
+	}
+	return blob
+}
+
+// RandomBlobProof returns a random blob sized for the context, determined by the seed,
+// with its commitment and blob proof. The default context is used if ctx is nil.
+func RandomBlobProof(ctx *eth.Context, seed int64) (Blob, eth.KZGCommitment, eth.KZGProof, error) {
+	if ctx == nil {
+		ctx = eth.DefaultContext()
+	}
+	blob := RandomBlobN(seed, ctx.FieldElementsPerBlob())
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		return nil, eth.KZGCommitment{}, eth.KZGProof{}, err
+	}
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		return nil, eth.KZGCommitment{}, eth.KZGProof{}, err
+	}
+	return blob, commitment, proof, nil
+}
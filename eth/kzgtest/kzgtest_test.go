@@ -0,0 +1,70 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzgtest
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+	"github.com/protolambda/go-kzg/eth"
+)
+
+func TestDeterministic(t *testing.T) {
+	if RandomCanonicalScalar(1) != RandomCanonicalScalar(1) || RandomCanonicalScalar(1) == RandomCanonicalScalar(2) {
+		t.Fatal("expected scalars to be determined by the seed")
+	}
+	a, b := RandomBlobN(7, 16), RandomBlobN(7, 16)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatal("expected blobs to be determined by the seed")
+		}
+	}
+	if err := eth.ValidateBlob(RandomBlob(3)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScalars(t *testing.T) {
+	if bls.ValidFr(NonCanonicalScalar) {
+		t.Fatal("expected non-canonical scalar")
+	}
+	// BLS_MODULUS - 1 is canonical
+	v := NonCanonicalScalar
+	v[0]--
+	if !bls.ValidFr(v) {
+		t.Fatal("expected modulus minus one to be canonical")
+	}
+}
+
+func TestPoints(t *testing.T) {
+	for _, c := range []eth.KZGCommitment{NotOnCurveCommitment, WrongSubgroupCommitment, UncompressedFlagCommitment} {
+		if _, err := bls.FromCompressedG1(c[:]); err == nil {
+			t.Fatalf("expected %v to be rejected", c)
+		}
+	}
+	p, err := bls.FromCompressedG1(InfinityCommitment[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.IsZeroG1(p) {
+		t.Fatal("expected the identity")
+	}
+}
+
+func TestRandomBlobProof(t *testing.T) {
+	blob, commitment, proof, err := RandomBlobProof(nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := eth.VerifyBlobKZGProof(blob, commitment, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected proof to verify")
+	}
+	if _, err := eth.VerifyBlobKZGProof(blob, WrongSubgroupCommitment, proof); err == nil {
+		t.Fatal("expected wrong subgroup commitment to be rejected")
+	}
+}
@@ -0,0 +1,130 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded is returned, wrapped in a *LimitError, when a call exceeds a limit of the context, see SetLimits.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// LimitError is the error of a call exceeding a limit of the context, see SetLimits. It matches ErrLimitExceeded
+// with errors.Is, and ErrTooManyBlobs too for the limit of blobs.
+type LimitError struct {
+	// Limit names what is limited: "blobs", "cells" or "openings".
+	Limit string
+	// Count is the number of items of the call, and Max the limit.
+	Count int
+	Max   int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v: %d %s, at most %d", ErrLimitExceeded, e.Count, e.Limit, e.Max)
+}
+
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded || (target == ErrTooManyBlobs && e.Limit == "blobs")
+}
+
+// Limits bounds the work a single call of a context can be made to do, for services passing untrusted requests
+// to the library. Every limit is checked before any of the inputs is decoded, and disabled when zero or less,
+// which is the default. The limits of VerifyOpts apply on top of them.
+type Limits struct {
+	// MaxBlobs limits the blobs of a call over a sequence of blobs: the batch verifications of blob proofs,
+	// the aggregate proofs (per batch of VerifyAggregateKZGProofBatch) and ComputeBlobProofs.
+	MaxBlobs int
+	// MaxCells limits the cells of a bundle verified with VerifyCellBundle, as received before a recovery.
+	MaxCells int
+	// MaxOpenings limits the size of the batch verifications of single openings: the openings of
+	// VerifyKZGProofBatchFromPoints and VerifyKZGProofBatchUncompressed, and the aggregate proofs of
+	// VerifyAggregateKZGProofBatch.
+	MaxOpenings int
+}
+
+// SetLimits sets the limits of the calls of the context. It must not be called concurrently with them.
+func (ctx *Context) SetLimits(limits Limits) {
+	ctx.limits = limits
+}
+
+// SetLimits calls SetLimits on a copy of the default context, which then replaces it, see Context.
+func SetLimits(limits Limits) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetLimits(limits)
+		return nil
+	})
+}
+
+// Limits returns the limits of the calls of the context, see SetLimits.
+func (ctx *Context) Limits() Limits {
+	return ctx.limits
+}
+
+// checkLimit returns a *LimitError if count exceeds the limit max, when it is enabled.
+func checkLimit(limit string, count, max int) error {
+	if max > 0 && count > max {
+		return &LimitError{Limit: limit, Count: count, Max: max}
+	}
+	return nil
+}
+
+func (ctx *Context) checkBlobLimit(n int) error {
+	return checkLimit("blobs", n, ctx.limits.MaxBlobs)
+}
+
+func (ctx *Context) checkCellLimit(n int) error {
+	return checkLimit("cells", n, ctx.limits.MaxCells)
+}
+
+func (ctx *Context) checkOpeningLimit(n int) error {
+	return checkLimit("openings", n, ctx.limits.MaxOpenings)
+}
+
+// Usage is the work of a call of a context, reported to its UsageReporter.
+type Usage struct {
+	// Call is the name of the method, e.g. "VerifyBlobKZGProofBatch".
+	Call string
+	// Bytes is the size of the inputs of the call, serialized: 32 bytes per field element, 48 per point.
+	Bytes int
+	// ScalarMults is the number of scalar multiplications of the call, counting every point of a multi-scalar
+	// multiplication, the bulk of the cost of the call.
+	ScalarMults int
+}
+
+// UsageReporter receives the usage of the calls of a context, e.g. to charge the requests of a service
+// for the work they trigger. Implementations must be safe for concurrent use, and should return quickly.
+type UsageReporter interface {
+	ReportUsage(u Usage)
+}
+
+// SetUsageReporter registers the reporter of the usage of the calls of the context, nil to disable it, which is
+// the default. The usage is reported by the commitment and proof functions of blobs, the verification functions,
+// single and batched, of openings, blob proofs, aggregate proofs and cell bundles, once their inputs pass the
+// limits of the context, whether the call succeeds or not: it is the work of a call with inputs of these sizes.
+// Functions built on them report the usage of the calls they make, e.g. ComputeBlobProofs the proof of every blob.
+// It must not be called concurrently with the other methods of the context.
+func (ctx *Context) SetUsageReporter(r UsageReporter) {
+	ctx.usageReporter = r
+}
+
+// SetUsageReporter calls SetUsageReporter on a copy of the default context, which then replaces it, see Context.
+func SetUsageReporter(r UsageReporter) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetUsageReporter(r)
+		return nil
+	})
+}
+
+// reportUsage reports the usage of a call, if a reporter is registered.
+func (ctx *Context) reportUsage(call string, bytes, scalarMults int) {
+	if r := ctx.usageReporter; r != nil {
+		r.ReportUsage(Usage{Call: call, Bytes: bytes, ScalarMults: scalarMults})
+	}
+}
+
+// blobBytes is the serialized size of a blob of the context.
+func (ctx *Context) blobBytes() int {
+	return ctx.FieldElementsPerBlob() * 32
+}
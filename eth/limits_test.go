@@ -0,0 +1,121 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type testUsageReporter struct {
+	mu    sync.Mutex
+	usage []Usage
+}
+
+func (r *testUsageReporter) ReportUsage(u Usage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usage = append(r.usage, u)
+}
+
+func TestLimits(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	var proofs KZGProofSequenceImpl
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, blob)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+
+	ctx.SetLimits(Limits{MaxBlobs: 2, MaxCells: 1, MaxOpenings: 1})
+	_, err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != "blobs" || limitErr.Count != 3 || limitErr.Max != 2 {
+		t.Fatalf("expected a limit error for the blobs, got %v", err)
+	}
+	if !errors.Is(err, ErrLimitExceeded) || !errors.Is(err, ErrTooManyBlobs) {
+		t.Fatalf("expected the limit error to match ErrLimitExceeded and ErrTooManyBlobs, got %v", err)
+	}
+	if ok, err := ctx.VerifyBlobKZGProofBatch(blobs[:2], commitments[:2], proofs[:2]); err != nil || !ok {
+		t.Fatalf("expected a batch within the limit to verify, got %v, %v", ok, err)
+	}
+	if _, err := ctx.ComputeAggregateKZGProof(blobs); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected the aggregate proof to exceed the limit, got %v", err)
+	}
+	if _, err := ctx.ComputeBlobProofs(blobs, commitments); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected the blob proofs to exceed the limit, got %v", err)
+	}
+	bundle := &CellBundle{Cells: make([]Cell, 2)}
+	if err := ctx.VerifyCellBundle(bundle); !errors.As(err, &limitErr) || limitErr.Limit != "cells" {
+		t.Fatalf("expected a limit error for the cells, got %v", err)
+	}
+	_, err = ctx.VerifyKZGProofBatchUncompressed(make([]UncompressedKZGCommitment, 2), make([][32]byte, 2),
+		make([][32]byte, 2), make([]UncompressedKZGProof, 2))
+	if !errors.As(err, &limitErr) || limitErr.Limit != "openings" {
+		t.Fatalf("expected a limit error for the openings, got %v", err)
+	}
+	if errors.Is(err, ErrTooManyBlobs) {
+		t.Fatal("expected the limit of openings not to match ErrTooManyBlobs")
+	}
+
+	ctx.SetLimits(Limits{})
+	if ok, err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != nil || !ok {
+		t.Fatalf("expected the batch to verify without limits, got %v, %v", ok, err)
+	}
+}
+
+func TestUsageReporter(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	r := new(testUsageReporter)
+	ctx.SetUsageReporter(r)
+	poly := randomPolynomialN(16)
+	blob := polynomialToBlob(poly)
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.VerifyBlobKZGProof(blob, commitment, proof); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.VerifyBlobKZGProofBatch(testBlobs{blob, blob}, KZGCommitmentSequenceImpl{commitment, commitment},
+		KZGProofSequenceImpl{proof, proof}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []Usage{
+		{Call: "BlobToKZGCommitment", Bytes: 16 * 32, ScalarMults: 16},
+		{Call: "ComputeBlobKZGProof", Bytes: 16*32 + 48, ScalarMults: 16},
+		{Call: "VerifyBlobKZGProof", Bytes: 16*32 + 96, ScalarMults: 2},
+		{Call: "VerifyBlobKZGProofBatch", Bytes: 2 * (16*32 + 96), ScalarMults: 7},
+	}
+	if len(r.usage) != len(expected) {
+		t.Fatalf("got usage %v, expected %v", r.usage, expected)
+	}
+	for i := range expected {
+		if r.usage[i] != expected[i] {
+			t.Errorf("call %d: got usage %+v, expected %+v", i, r.usage[i], expected[i])
+		}
+	}
+
+	ctx.SetUsageReporter(nil)
+	if _, err := ctx.BlobToKZGCommitment(blob); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.usage) != len(expected) {
+		t.Fatal("expected no usage to be reported after the reporter is removed")
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import "time"
+
+// Metrics receives counts and durations of the expensive operations of a context,
+// e.g. to expose them to a monitoring system. Implementations must be safe for concurrent use,
+// and should return quickly, as they are called on the hot paths.
+type Metrics interface {
+	// CommitmentComputed is called after computing a commitment to a polynomial.
+	CommitmentComputed(d time.Duration)
+	// ProofComputed is called after computing an opening proof.
+	ProofComputed(d time.Duration)
+	// ProofVerified is called after verifying an opening proof, with the verification result.
+	ProofVerified(d time.Duration, ok bool)
+	// MSM is called after every multi-scalar multiplication, with the number of points.
+	MSM(points int, d time.Duration)
+	// Pairing is called after every pairing check.
+	Pairing(d time.Duration)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) CommitmentComputed(time.Duration)  {}
+func (noopMetrics) ProofComputed(time.Duration)       {}
+func (noopMetrics) ProofVerified(time.Duration, bool) {}
+func (noopMetrics) MSM(int, time.Duration)            {}
+func (noopMetrics) Pairing(time.Duration)             {}
+
+// SetMetrics registers the metrics hook of the context. A nil value disables the reporting.
+func (ctx *Context) SetMetrics(m Metrics) {
+	if m == nil {
+		m = noopMetrics{}
+	}
+	ctx.metrics = m
+}
+
+// SetMetrics calls SetMetrics on a copy of the default context, which then replaces it, see Context.
+func SetMetrics(m Metrics) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetMetrics(m)
+		return nil
+	})
+}
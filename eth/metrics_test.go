@@ -0,0 +1,78 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+type countingMetrics struct {
+	sync.Mutex
+	commitments, proofs, verified, valid, msms, pairings int
+}
+
+func (m *countingMetrics) CommitmentComputed(time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.commitments++
+}
+
+func (m *countingMetrics) ProofComputed(time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.proofs++
+}
+
+func (m *countingMetrics) ProofVerified(_ time.Duration, ok bool) {
+	m.Lock()
+	defer m.Unlock()
+	m.verified++
+	if ok {
+		m.valid++
+	}
+}
+
+func (m *countingMetrics) MSM(int, time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.msms++
+}
+
+func (m *countingMetrics) Pairing(time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.pairings++
+}
+
+func TestMetrics(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var m countingMetrics
+	ctx.SetMetrics(&m)
+	poly := make(Polynomial, ctx.FieldElementsPerBlob())
+	for i := range poly {
+		bls.CopyFr(&poly[i], bls.RandomFr())
+	}
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	z := bls.RandomFr()
+	proof, err := ctx.ComputeKZGProof(poly, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y := ctx.EvaluatePolynomialInEvaluationForm(poly, z)
+	if ok, err := ctx.VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof); err != nil || !ok {
+		t.Fatalf("expected valid proof: %v", err)
+	}
+	if m.commitments != 1 || m.proofs != 1 || m.verified != 1 || m.valid != 1 || m.msms != 2 || m.pairings != 1 {
+		t.Fatalf("unexpected metrics: %+v", &m)
+	}
+	ctx.SetMetrics(nil)
+	ctx.PolynomialToKZGCommitment(poly)
+	if m.commitments != 1 {
+		t.Fatal("expected metrics to be disabled")
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !bignum_hol256 && !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+// +build !bignum_hol256,!linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package eth
+
+import "os"
+
+// mappedFile holds the contents of a file, read into memory on platforms without mmap support.
+type mappedFile struct {
+	data []byte
+}
+
+func mapFile(path string) (*mappedFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &mappedFile{data: data}, nil
+}
+
+func (m *mappedFile) Close() error {
+	m.data = nil
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build !bignum_hol256 && (linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+// +build !bignum_hol256
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mappedFile is a private, copy-on-write memory mapping of a file. The mapping has to be writable, since some
+// backends normalize the bases of an MSM in place. Pages are shared through the page cache until they are written,
+// and writes are never carried through to the file.
+type mappedFile struct {
+	data []byte
+}
+
+func mapFile(path string) (*mappedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return nil, errors.New("cannot map an empty file")
+	}
+	if int64(int(size)) != size {
+		return nil, fmt.Errorf("file of %d bytes is too large to map", size)
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map %s: %v", path, err)
+	}
+	return &mappedFile{data: data}, nil
+}
+
+func (m *mappedFile) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	err := syscall.Munmap(m.data)
+	m.data = nil
+	return err
+}
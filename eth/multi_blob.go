@@ -0,0 +1,64 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MultiBlobObject is an object larger than a blob, split across several blobs that are committed to
+// separately, with a single proof binding all of them: the aggregate proof of compute_aggregate_kzg_proof,
+// opening the random linear combination of the blobs at a challenge derived from all the blobs and commitments.
+type MultiBlobObject struct {
+	Blobs       []Blob
+	Commitments []KZGCommitment
+	Proof       KZGProof
+}
+
+// CommitMultiBlobObject splits the data across as many blobs as needed, as BuildBlobs does, commits to them
+// in parallel (see SetMaxWorkers), and computes the aggregate proof of all of them.
+// The data can be recovered from the blobs with DecodeBlobs.
+func (ctx *Context) CommitMultiBlobObject(data []byte) (*MultiBlobObject, error) {
+	blobs, err := ctx.splitToBlobs(data)
+	if err != nil {
+		return nil, err
+	}
+	polys, err := ctx.blobsToPolynomials(blobList(blobs))
+	if err != nil {
+		return nil, err
+	}
+	commitments := ctx.PolynomialsToKZGCommitments(polys)
+	aggregatedPoly, _, evaluationChallenge, err :=
+		ctx.ComputeAggregatedPolyAndCommitment(polys, KZGCommitmentSequenceImpl(commitments))
+	if err != nil {
+		return nil, err
+	}
+	proof, err := ctx.ComputeKZGProof(aggregatedPoly, evaluationChallenge)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiBlobObject{Blobs: blobs, Commitments: commitments, Proof: proof}, nil
+}
+
+// CommitMultiBlobObject calls CommitMultiBlobObject on the default context.
+func CommitMultiBlobObject(data []byte) (*MultiBlobObject, error) {
+	return defaultContext().CommitMultiBlobObject(data)
+}
+
+// VerifyMultiBlobObject verifies that the blobs of the object match its commitments, with its single proof.
+func (ctx *Context) VerifyMultiBlobObject(obj *MultiBlobObject) (bool, error) {
+	if len(obj.Blobs) == 0 {
+		return false, errors.New("object has no blobs")
+	}
+	if len(obj.Blobs) != len(obj.Commitments) {
+		return false, fmt.Errorf("object has %d blobs but %d commitments", len(obj.Blobs), len(obj.Commitments))
+	}
+	return ctx.verifyAggregateKZGProof(blobList(obj.Blobs), KZGCommitmentSequenceImpl(obj.Commitments), obj.Proof)
+}
+
+// VerifyMultiBlobObject calls VerifyMultiBlobObject on the default context.
+func VerifyMultiBlobObject(obj *MultiBlobObject) (bool, error) {
+	return defaultContext().VerifyMultiBlobObject(obj)
+}
@@ -0,0 +1,54 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestMultiBlobObject(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	data := make([]byte, 3*ctx.MaxBlobDataSize()-7)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := ctx.CommitMultiBlobObject(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(obj.Blobs) != 3 || len(obj.Commitments) != 3 {
+		t.Fatalf("expected 3 blobs, got %d", len(obj.Blobs))
+	}
+	ok, err := ctx.VerifyMultiBlobObject(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected the object to verify")
+	}
+	decoded, err := ctx.DecodeBlobs(blobList(obj.Blobs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Fatal("decoded data does not match")
+	}
+
+	// the proof binds the order of the blobs
+	obj.Blobs[0], obj.Blobs[1] = obj.Blobs[1], obj.Blobs[0]
+	obj.Commitments[0], obj.Commitments[1] = obj.Commitments[1], obj.Commitments[0]
+	ok, err = ctx.VerifyMultiBlobObject(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected reordered blobs to fail")
+	}
+	obj.Commitments = obj.Commitments[:2]
+	if _, err := ctx.VerifyMultiBlobObject(obj); err == nil {
+		t.Fatal("expected missing commitments to be rejected")
+	}
+}
@@ -0,0 +1,111 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+
+	kzg "github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// PolynomialToCoefficients converts a polynomial in evaluation form over the (bit-reversed) domain
+// of the context into coefficient form.
+func (ctx *Context) PolynomialToCoefficients(poly Polynomial) ([]bls.Fr, error) {
+	width := ctx.FieldElementsPerBlob()
+	if len(poly) != width {
+		return nil, fmt.Errorf("%w: polynomial has %d field elements, expected %d", ErrWrongBlobLength, len(poly), width)
+	}
+	evals := make([]bls.Fr, width)
+	for i := range poly {
+		bls.CopyFr(&evals[reverseBits(uint64(i), uint64(width))], &poly[i])
+	}
+	return ctx.fftSettings().FFT(evals, true)
+}
+
+// ComputeKZGMultiProof computes a single proof for the evaluations of the polynomial at all of the points zs,
+// and returns it with the evaluations. The number of points is bounded by the G2 setup, see VerifyKZGMultiProofFromPoints.
+func (ctx *Context) ComputeKZGMultiProof(poly Polynomial, zs []bls.Fr) (KZGProof, []bls.Fr, error) {
+	if len(zs) == 0 {
+		return KZGProof{}, nil, errors.New("no evaluation points")
+	}
+	if len(zs) >= len(ctx.setupG2) {
+		return KZGProof{}, nil, fmt.Errorf("%w: %d evaluation points exceed the G2 setup of %d powers", ErrSetupTooSmall, len(zs), len(ctx.setupG2))
+	}
+	coeffs, err := ctx.PolynomialToCoefficients(poly)
+	if err != nil {
+		return KZGProof{}, nil, err
+	}
+	if err := ctx.checkSetupG1(len(coeffs)); err != nil {
+		return KZGProof{}, nil, err
+	}
+	ys := EvaluateCoefficientsAt(coeffs, zs)
+	interpolation, err := kzg.InterpolatePolynomial(zs, ys)
+	if err != nil {
+		return KZGProof{}, nil, err
+	}
+	// quotient = (p - I) / prod(X - z_i), dividing by one linear factor at a time
+	quotient := make([]bls.Fr, len(coeffs))
+	for i := range coeffs {
+		if i < len(interpolation) {
+			bls.SubModFr(&quotient[i], &coeffs[i], &interpolation[i])
+		} else {
+			bls.CopyFr(&quotient[i], &coeffs[i])
+		}
+	}
+	var tmp bls.Fr
+	for i := range zs {
+		n := len(quotient)
+		next := make([]bls.Fr, n-1)
+		bls.CopyFr(&next[n-2], &quotient[n-1])
+		for j := n - 2; j > 0; j-- {
+			bls.MulModFr(&tmp, &next[j], &zs[i])
+			bls.AddModFr(&next[j-1], &quotient[j], &tmp)
+		}
+		quotient = next
+	}
+	proof := bls.LinCombG1(ctx.setupG1[:len(quotient)], quotient)
+	var out KZGProof
+	copy(out[:], bls.ToCompressedG1(proof))
+	return out, ys, nil
+}
+
+// ComputeKZGMultiProof calls ComputeKZGMultiProof on the default context.
+func ComputeKZGMultiProof(poly Polynomial, zs []bls.Fr) (KZGProof, []bls.Fr, error) {
+	return defaultContext().ComputeKZGMultiProof(poly, zs)
+}
+
+// VerifyKZGMultiProofFromPoints verifies a proof that the committed polynomial evaluates to ys[i] at each zs[i]:
+//
+//	e(commitment - [I(tau)]_1, [1]_2) == e(proof, [Z(tau)]_2)
+//
+// where I interpolates the evaluations and Z vanishes on the points. Z has degree len(zs),
+// so the number of points must be lower than the number of G2 powers of the setup.
+func (ctx *Context) VerifyKZGMultiProofFromPoints(commitment *bls.G1Point, zs, ys []bls.Fr, proof *bls.G1Point) (bool, error) {
+	if len(zs) == 0 || len(zs) != len(ys) {
+		return false, fmt.Errorf("%w: %d points and %d values", ErrLengthMismatch, len(zs), len(ys))
+	}
+	if len(zs) >= len(ctx.setupG2) {
+		return false, fmt.Errorf("%w: %d evaluation points exceed the G2 setup of %d powers", ErrSetupTooSmall, len(zs), len(ctx.setupG2))
+	}
+	if err := ctx.checkSetupG1(len(zs)); err != nil {
+		return false, err
+	}
+	interpolation, err := kzg.InterpolatePolynomial(zs, ys)
+	if err != nil {
+		return false, err
+	}
+	var commitmentMinusI bls.G1Point
+	bls.SubG1(&commitmentMinusI, commitment, bls.LinCombG1(ctx.setupG1[:len(interpolation)], interpolation))
+
+	vanishing := kzg.VanishingPolynomial(zs)
+	zG2 := bls.LinCombG2(ctx.setupG2[:len(vanishing)], vanishing)
+	return bls.PairingsVerify(&commitmentMinusI, &bls.GenG2, proof, zG2), nil
+}
+
+// VerifyKZGMultiProofFromPoints calls VerifyKZGMultiProofFromPoints on the default context.
+func VerifyKZGMultiProofFromPoints(commitment *bls.G1Point, zs, ys []bls.Fr, proof *bls.G1Point) (bool, error) {
+	return defaultContext().VerifyKZGMultiProofFromPoints(commitment, zs, ys, proof)
+}
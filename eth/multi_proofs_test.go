@@ -0,0 +1,71 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestKZGMultiProof(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := make(Polynomial, 16)
+	for i := range poly {
+		bls.CopyFr(&poly[i], bls.RandomFr())
+	}
+	// the coefficient form evaluates like the evaluation form
+	coeffs, err := ctx.PolynomialToCoefficients(poly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := bls.RandomFr()
+	var y bls.Fr
+	bls.EvalPolyAt(&y, coeffs, z)
+	if !bls.EqualFr(&y, ctx.EvaluatePolynomialInEvaluationForm(poly, z)) {
+		t.Fatal("coefficient form does not match evaluation form")
+	}
+
+	commitmentBytes := ctx.PolynomialToKZGCommitment(poly)
+	commitment, err := bls.FromCompressedG1(commitmentBytes[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	zs := make([]bls.Fr, 5)
+	for i := range zs {
+		bls.CopyFr(&zs[i], bls.RandomFr())
+	}
+	proofBytes, ys, err := ctx.ComputeKZGMultiProof(poly, zs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := bls.FromCompressedG1(proofBytes[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := ctx.VerifyKZGMultiProofFromPoints(commitment, zs, ys, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected multi proof to verify")
+	}
+	bls.AddModFr(&ys[2], &ys[2], &bls.ONE)
+	ok, err = ctx.VerifyKZGMultiProofFromPoints(commitment, zs, ys, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected multi proof with a wrong evaluation to fail")
+	}
+
+	// the test setup has 16 G2 powers, so at most 15 points can be opened at once
+	tooMany := make([]bls.Fr, len(ctx.SetupG2()))
+	for i := range tooMany {
+		bls.AsFr(&tooMany[i], uint64(i+100))
+	}
+	if _, _, err := ctx.ComputeKZGMultiProof(poly, tooMany); err == nil {
+		t.Fatal("expected too many points to exceed the G2 setup")
+	}
+}
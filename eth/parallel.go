@@ -0,0 +1,54 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"sync"
+
+	kzg "github.com/protolambda/go-kzg"
+)
+
+// SetMaxWorkers caps the number of goroutines used by all the parallel paths of the package, of all contexts:
+// commitments to many polynomials, decoding of setups, the per-proof checks of FindInvalidBlobKZGProof,
+// and the FFTs of the kzg package they run on, see kzg.SetMaxWorkers, which this sets too.
+// This is meant for operators running the verifier next to other latency-sensitive work.
+// A value of zero or less restores the default, GOMAXPROCS.
+func SetMaxWorkers(n int) {
+	kzg.SetMaxWorkers(n)
+}
+
+// MaxWorkers returns the number of goroutines the parallel paths use, see SetMaxWorkers.
+func MaxWorkers() int {
+	return kzg.MaxWorkers()
+}
+
+// parallelFor calls fn for every index in [0, n), spreading the calls over a pool of workers, see MaxWorkers.
+func parallelFor(n int, fn func(i int)) {
+	workers := MaxWorkers()
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	jobs := make(chan int, n)
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,53 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	kzg "github.com/protolambda/go-kzg"
+)
+
+func TestSetMaxWorkers(t *testing.T) {
+	defer SetMaxWorkers(0)
+	if MaxWorkers() != runtime.GOMAXPROCS(0) {
+		t.Fatalf("expected GOMAXPROCS workers by default, got %d", MaxWorkers())
+	}
+	for _, workers := range []int{1, 2} {
+		SetMaxWorkers(workers)
+		if kzg.MaxWorkers() != workers {
+			t.Fatalf("expected the FFTs of the kzg package to be capped to %d workers too", workers)
+		}
+		var mu sync.Mutex
+		active, peak := 0, 0
+		done := make([]bool, 20)
+		parallelFor(len(done), func(i int) {
+			mu.Lock()
+			active++
+			if active > peak {
+				peak = active
+			}
+			mu.Unlock()
+			runtime.Gosched()
+			mu.Lock()
+			active--
+			done[i] = true
+			mu.Unlock()
+		})
+		if peak > workers {
+			t.Fatalf("expected at most %d concurrent calls, got %d", workers, peak)
+		}
+		for i, ok := range done {
+			if !ok {
+				t.Fatalf("index %d was not processed", i)
+			}
+		}
+	}
+	SetMaxWorkers(-1)
+	if MaxWorkers() != runtime.GOMAXPROCS(0) {
+		t.Fatal("expected a negative value to restore the default")
+	}
+}
@@ -0,0 +1,166 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrPipelineClosed is returned when submitting to a closed VerificationPipeline.
+var ErrPipelineClosed = errors.New("verification pipeline is closed")
+
+// pipelineBatchSize is the maximum number of queued items a worker of a pipeline verifies at once.
+const pipelineBatchSize = 16
+
+// PipelineItem is a blob proof to verify in a VerificationPipeline.
+type PipelineItem struct {
+	Blob       Blob
+	Commitment KZGCommitment
+	Proof      KZGProof
+	// Tag is returned with the result of the item, to match them, e.g. the ID of the message of the blob.
+	Tag interface{}
+}
+
+// PipelineResult is the result of the verification of an item of a VerificationPipeline:
+// Valid and Err are the results of VerifyBlobKZGProof for the item.
+type PipelineResult struct {
+	Tag   interface{}
+	Valid bool
+	Err   error
+}
+
+// VerificationPipeline verifies blob proofs submitted as they arrive, e.g. from the network, over a pool of workers,
+// and delivers the result of every item on a channel, see Results. Memory is bounded: the queue of submitted items
+// and the channel of results hold at most queueDepth items each, and Submit blocks while the queue is full, as do
+// the workers while the results are not received, so that a slow consumer slows down the producers.
+// Each worker takes the items that are queued, up to 16 at a time, and verifies them as a batch, with a Verifier
+// of its own, falling back to verifying them one at a time if the batch does not verify, to find the invalid ones.
+// The results of a worker are in the order of its items, but the results of different workers interleave.
+type VerificationPipeline struct {
+	queue   chan PipelineItem
+	results chan PipelineResult
+	workers sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewVerificationPipeline starts a pipeline of the given number of workers, MaxWorkers if zero or less,
+// with queues of queueDepth items. Close stops it.
+func (ctx *Context) NewVerificationPipeline(workers, queueDepth int) *VerificationPipeline {
+	if workers <= 0 {
+		workers = MaxWorkers()
+	}
+	if queueDepth < 0 {
+		queueDepth = 0
+	}
+	p := &VerificationPipeline{
+		queue:   make(chan PipelineItem, queueDepth),
+		results: make(chan PipelineResult, queueDepth),
+	}
+	p.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work(ctx.NewVerifier())
+	}
+	go func() {
+		p.workers.Wait()
+		close(p.results)
+	}()
+	return p
+}
+
+// NewVerificationPipeline calls NewVerificationPipeline on the default context.
+func NewVerificationPipeline(workers, queueDepth int) *VerificationPipeline {
+	return defaultContext().NewVerificationPipeline(workers, queueDepth)
+}
+
+// Submit queues an item, blocking while the queue is full. It returns ErrPipelineClosed after Close.
+func (p *VerificationPipeline) Submit(item PipelineItem) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPipelineClosed
+	}
+	p.queue <- item
+	return nil
+}
+
+// TrySubmit queues an item if the queue is not full, and returns whether it did, e.g. for producers that drop
+// or defer items under load rather than block. It returns ErrPipelineClosed after Close.
+func (p *VerificationPipeline) TrySubmit(item PipelineItem) (bool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return false, ErrPipelineClosed
+	}
+	select {
+	case p.queue <- item:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// Results returns the channel of the results of the items, one per submitted item. It is closed after Close,
+// once the results of all the submitted items are delivered.
+func (p *VerificationPipeline) Results() <-chan PipelineResult {
+	return p.results
+}
+
+// Close stops accepting items. The items already submitted are still verified, and their results delivered,
+// before the channel of results is closed. Close does not wait for them, and may be called more than once.
+// It waits for the calls to Submit that are blocked on a full queue, so the results must still be received.
+func (p *VerificationPipeline) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+}
+
+// work verifies the queued items with the verifier, until the queue is closed and drained.
+func (p *VerificationPipeline) work(v *Verifier) {
+	defer p.workers.Done()
+	batch := make([]PipelineItem, 0, pipelineBatchSize)
+	for item := range p.queue {
+		batch = append(batch[:0], item)
+	fill:
+		for len(batch) < pipelineBatchSize {
+			select {
+			case item, ok := <-p.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, item)
+			default:
+				break fill
+			}
+		}
+		p.verify(v, batch)
+	}
+}
+
+// verify verifies a batch of items, and delivers their results.
+func (p *VerificationPipeline) verify(v *Verifier, batch []PipelineItem) {
+	if len(batch) > 1 {
+		blobs := make(blobList, len(batch))
+		commitments := make(KZGCommitmentSequenceImpl, len(batch))
+		proofs := make(KZGProofSequenceImpl, len(batch))
+		for i := range batch {
+			blobs[i], commitments[i], proofs[i] = batch[i].Blob, batch[i].Commitment, batch[i].Proof
+		}
+		if ok, err := v.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err == nil && ok {
+			for i := range batch {
+				p.results <- PipelineResult{Tag: batch[i].Tag, Valid: true}
+			}
+			return
+		}
+	}
+	for i := range batch {
+		ok, err := v.VerifyBlobKZGProof(batch[i].Blob, batch[i].Commitment, batch[i].Proof)
+		p.results <- PipelineResult{Tag: batch[i].Tag, Valid: ok, Err: err}
+	}
+}
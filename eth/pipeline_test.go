@@ -0,0 +1,87 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerificationPipeline(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var items []PipelineItem
+	for i := 0; i < 40; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, PipelineItem{Blob: blob, Commitment: commitment, Proof: proof, Tag: i})
+	}
+	// an invalid proof, and a malformed blob
+	items[7].Proof = items[8].Proof
+	malformed := polynomialToBlob(randomPolynomialN(16))
+	malformed[3] = [32]byte{0: 0xff, 31: 0xff}
+	items[21].Blob = malformed
+
+	p := ctx.NewVerificationPipeline(3, 4)
+	go func() {
+		for _, item := range items {
+			if err := p.Submit(item); err != nil {
+				t.Error(err)
+			}
+		}
+		p.Close()
+	}()
+	results := make(map[int]PipelineResult)
+	for r := range p.Results() {
+		if _, ok := results[r.Tag.(int)]; ok {
+			t.Fatalf("duplicate result for item %d", r.Tag)
+		}
+		results[r.Tag.(int)] = r
+	}
+	if len(results) != len(items) {
+		t.Fatalf("expected %d results, got %d", len(items), len(results))
+	}
+	for i, r := range results {
+		switch i {
+		case 7:
+			if r.Valid || r.Err != nil {
+				t.Fatalf("expected item 7 to be invalid: %+v", r)
+			}
+		case 21:
+			if !errors.Is(r.Err, ErrNonCanonicalScalar) {
+				t.Fatalf("expected item 21 to be malformed: %+v", r)
+			}
+		default:
+			if !r.Valid || r.Err != nil {
+				t.Fatalf("expected item %d to be valid: %+v", i, r)
+			}
+		}
+	}
+	if err := p.Submit(items[0]); !errors.Is(err, ErrPipelineClosed) {
+		t.Fatalf("expected closed pipeline, got %v", err)
+	}
+	if _, err := p.TrySubmit(items[0]); !errors.Is(err, ErrPipelineClosed) {
+		t.Fatalf("expected closed pipeline, got %v", err)
+	}
+	p.Close()
+
+	// without a consumer, the queue fills up
+	p = ctx.NewVerificationPipeline(1, 1)
+	defer p.Close()
+	full := false
+	for i := 0; i < 10 && !full; i++ {
+		ok, err := p.TrySubmit(items[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		full = !ok
+	}
+	if !full {
+		t.Fatal("expected the queue to fill up without a consumer")
+	}
+}
@@ -0,0 +1,152 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// PrecomputeLagrangeTable builds the fixed-base MSM table over the Lagrange setup.
+// This takes a while and ~20MB of memory, but speeds up every subsequent commitment and proof computation.
+func (ctx *Context) PrecomputeLagrangeTable() {
+	ctx.storeLagrangeTable(bls.NewG1LinCombTable(ctx.setupLagrange))
+}
+
+// loadLagrangeTable returns the precomputed Lagrange table, or nil if there is none.
+func (ctx *Context) loadLagrangeTable() *bls.G1LinCombTable {
+	table, _ := ctx.lagrangeTable.Load().(*bls.G1LinCombTable)
+	return table
+}
+
+// storeLagrangeTable sets the precomputed Lagrange table, nil to drop it.
+func (ctx *Context) storeLagrangeTable(table *bls.G1LinCombTable) {
+	ctx.lagrangeTable.Store(table)
+}
+
+// PrecomputeLagrangeTable calls PrecomputeLagrangeTable on the default context.
+func PrecomputeLagrangeTable() {
+	defaultContext().PrecomputeLagrangeTable()
+}
+
+// PrecomputeOptions selects the precomputations of PrecomputeAsync.
+type PrecomputeOptions struct {
+	// FFTSettings of the domain, used by the conversions to coefficient form, otherwise created on first use.
+	FFTSettings bool
+	// LagrangeTable is the fixed-base MSM table of PrecomputeLagrangeTable.
+	LagrangeTable bool
+}
+
+// Steps of PrecomputeAsync, as reported in Progress.
+const (
+	PrecomputeStepFFTSettings   = "fft_settings"
+	PrecomputeStepLagrangeTable = "lagrange_table"
+)
+
+// Progress reports a finished step of PrecomputeAsync.
+type Progress struct {
+	// Step is the finished step, one of the PrecomputeStep constants.
+	Step string
+	// Done is the number of finished steps, out of Total.
+	Done  int
+	Total int
+	// Elapsed is the duration of the step.
+	Elapsed time.Duration
+}
+
+// PrecomputeAsync runs the selected precomputations in the background, one after the other, and reports every
+// finished step on the returned channel, which is closed once all of them are done. The context can be used
+// in the meantime: every precomputation is used as soon as it is finished.
+// The channel is buffered for all the steps, so it does not have to be read.
+func (ctx *Context) PrecomputeAsync(opts PrecomputeOptions) (<-chan Progress, error) {
+	var steps []string
+	if opts.FFTSettings {
+		steps = append(steps, PrecomputeStepFFTSettings)
+	}
+	if opts.LagrangeTable {
+		steps = append(steps, PrecomputeStepLagrangeTable)
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("no precomputation selected")
+	}
+	progress := make(chan Progress, len(steps))
+	go func() {
+		defer close(progress)
+		for i, step := range steps {
+			start := time.Now()
+			switch step {
+			case PrecomputeStepFFTSettings:
+				ctx.fftSettings()
+			case PrecomputeStepLagrangeTable:
+				ctx.PrecomputeLagrangeTable()
+			}
+			progress <- Progress{Step: step, Done: i + 1, Total: len(steps), Elapsed: time.Since(start)}
+		}
+	}()
+	return progress, nil
+}
+
+// PrecomputeAsync calls PrecomputeAsync on the default context.
+func PrecomputeAsync(opts PrecomputeOptions) (<-chan Progress, error) {
+	return defaultContext().PrecomputeAsync(opts)
+}
+
+// SaveLagrangeTable writes the precomputed Lagrange table, so it can be loaded with LoadLagrangeTable
+// instead of being recomputed. The table must have been built or loaded first.
+func (ctx *Context) SaveLagrangeTable(w io.Writer) error {
+	table := ctx.loadLagrangeTable()
+	if table == nil {
+		return fmt.Errorf("%w: lagrange table has not been precomputed", ErrSetupNotLoaded)
+	}
+	_, err := table.WriteTo(w)
+	return err
+}
+
+// SaveLagrangeTable calls SaveLagrangeTable on the default context.
+func SaveLagrangeTable(w io.Writer) error {
+	return defaultContext().SaveLagrangeTable(w)
+}
+
+// LoadLagrangeTable loads a table written by SaveLagrangeTable, and checks that every precomputed point of it
+// matches the loaded setup, which costs about as much as precomputing the table.
+func (ctx *Context) LoadLagrangeTable(r io.Reader) error {
+	table, err := bls.ReadG1LinCombTable(r, len(ctx.setupLagrange))
+	if err != nil {
+		return err
+	}
+	if err := table.CheckBases(ctx.setupLagrange); err != nil {
+		return fmt.Errorf("lagrange table does not match the trusted setup: %v", err)
+	}
+	ctx.storeLagrangeTable(table)
+	return nil
+}
+
+// LoadLagrangeTable calls LoadLagrangeTable on the default context.
+func LoadLagrangeTable(r io.Reader) error {
+	return defaultContext().LoadLagrangeTable(r)
+}
+
+// lagrangeLinComb computes the linear combination of the Lagrange setup with the given scalars,
+// using the precomputed table if there is one.
+func (ctx *Context) lagrangeLinComb(scalars []bls.Fr) *bls.G1Point {
+	var out bls.G1Point
+	ctx.lagrangeLinCombScratch(&out, scalars, nil)
+	return &out
+}
+
+// lagrangeLinCombScratch is lagrangeLinComb, reusing (and returning) the given scratch space for the table digits.
+func (ctx *Context) lagrangeLinCombScratch(out *bls.G1Point, scalars []bls.Fr, scratch []byte) []byte {
+	start := time.Now()
+	defer func() { ctx.metrics.MSM(len(scalars), time.Since(start)) }()
+	if table := ctx.loadLagrangeTable(); table != nil {
+		scratch = table.LinCombScratch(out, scalars, scratch)
+	} else {
+		bls.CopyG1(out, bls.LinCombG1(ctx.setupLagrange, scalars))
+	}
+	return scratch
+}
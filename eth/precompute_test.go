@@ -0,0 +1,81 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLagrangeTable(t *testing.T) {
+	poly := randomPolynomial()
+	expected := PolynomialToKZGCommitment(poly)
+
+	PrecomputeLagrangeTable()
+	defer func() { defaultContext().storeLagrangeTable(nil) }()
+	if got := PolynomialToKZGCommitment(poly); got != expected {
+		t.Fatalf("commitment mismatch with table: %v <> %v", got, expected)
+	}
+
+	var buf bytes.Buffer
+	if err := SaveLagrangeTable(&buf); err != nil {
+		t.Fatal(err)
+	}
+	defaultContext().storeLagrangeTable(nil)
+	if err := LoadLagrangeTable(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := PolynomialToKZGCommitment(poly); got != expected {
+		t.Fatalf("commitment mismatch with loaded table: %v <> %v", got, expected)
+	}
+}
+
+func BenchmarkPolynomialToKZGCommitment(b *testing.B) {
+	poly := randomPolynomial()
+	b.Run("generic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PolynomialToKZGCommitment(poly)
+		}
+	})
+	PrecomputeLagrangeTable()
+	defer func() { defaultContext().storeLagrangeTable(nil) }()
+	b.Run("table", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PolynomialToKZGCommitment(poly)
+		}
+	})
+}
+
+func TestPrecomputeAsync(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	if _, err := ctx.PrecomputeAsync(PrecomputeOptions{}); err == nil {
+		t.Fatal("expected an empty selection to be rejected")
+	}
+	poly := randomPolynomialN(16)
+	expected := ctx.PolynomialToKZGCommitment(poly)
+	progress, err := ctx.PrecomputeAsync(PrecomputeOptions{FFTSettings: true, LagrangeTable: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the context keeps working during the precomputation
+	if got := ctx.PolynomialToKZGCommitment(poly); got != expected {
+		t.Fatal("commitment mismatch during the precomputation")
+	}
+	var steps []string
+	for p := range progress {
+		steps = append(steps, p.Step)
+		if p.Done != len(steps) || p.Total != 2 {
+			t.Fatalf("unexpected progress %d/%d at step %s", p.Done, p.Total, p.Step)
+		}
+	}
+	if len(steps) != 2 || steps[0] != PrecomputeStepFFTSettings || steps[1] != PrecomputeStepLagrangeTable {
+		t.Fatalf("unexpected steps %v", steps)
+	}
+	if ctx.loadLagrangeTable() == nil {
+		t.Fatal("expected the Lagrange table to be built")
+	}
+	if got := ctx.PolynomialToKZGCommitment(poly); got != expected {
+		t.Fatal("commitment mismatch with the table")
+	}
+}
@@ -0,0 +1,164 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// ProofCacheKey identifies an opening: the commitment to the polynomial, and the evaluation point.
+type ProofCacheKey struct {
+	Commitment KZGCommitment
+	Z          [32]byte
+}
+
+// ProofCacheEntry is an opening: the evaluation at the point of the key, and its proof.
+type ProofCacheEntry struct {
+	Y     [32]byte
+	Proof KZGProof
+}
+
+// ProofCache stores computed openings, for services that serve the same openings many times, e.g. of popular
+// historical blobs, see SetProofCache. A cache is best effort: it may drop entries, and a failure to read or
+// write an entry is a miss. Implementations must be safe for concurrent use.
+type ProofCache interface {
+	GetProof(key ProofCacheKey) (ProofCacheEntry, bool)
+	PutProof(key ProofCacheKey, entry ProofCacheEntry)
+}
+
+// SetProofCache sets the cache of openings consulted by ComputeKZGProof, ComputeBlobKZGProof and
+// ComputeKZGProofAt, nil to disable it, which is the default. Cached openings are verified before they are returned,
+// with a pairing check, far cheaper than computing the proof, so that a corrupted cache is a miss.
+// ComputeKZGProof and ComputeKZGProofAt are not given the commitment of the cache key, so they compute it,
+// which costs as much as the proof unless the blob commitment cache (see SetBlobCommitmentCacheSize) has it:
+// enable both for these.
+// It must not be called concurrently with the other methods of the context.
+func (ctx *Context) SetProofCache(cache ProofCache) {
+	ctx.proofCache = cache
+}
+
+// SetProofCache calls SetProofCache on a copy of the default context, which then replaces it, see Context.
+func SetProofCache(cache ProofCache) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetProofCache(cache)
+		return nil
+	})
+}
+
+// computeKZGProofCached is computeKZGProof, with the opening taken from the proof cache of the context when enabled,
+// and added to it otherwise. The commitment to the polynomial is only computed when the cache is enabled.
+func (ctx *Context) computeKZGProofCached(polynomial []bls.Fr, commitment func() KZGCommitment, z *bls.Fr) (KZGProof, bls.Fr) {
+	cache := ctx.proofCache
+	if cache == nil {
+		return ctx.computeKZGProof(newProverScratch(len(polynomial)), polynomial, z)
+	}
+	key := ProofCacheKey{Commitment: commitment(), Z: bls.FrTo32(z)}
+	if entry, ok := cache.GetProof(key); ok {
+		if y, ok := ctx.checkCachedOpening(key, entry); ok {
+			return entry.Proof, y
+		}
+	}
+	proof, y := ctx.computeKZGProof(newProverScratch(len(polynomial)), polynomial, z)
+	cache.PutProof(key, ProofCacheEntry{Y: bls.FrTo32(&y), Proof: proof})
+	return proof, y
+}
+
+// checkCachedOpening verifies a cached opening, and returns its evaluation if it is valid.
+func (ctx *Context) checkCachedOpening(key ProofCacheKey, entry ProofCacheEntry) (bls.Fr, bool) {
+	var z, y bls.Fr
+	if !bls.FrFrom32(&z, key.Z) || !bls.FrFrom32(&y, entry.Y) {
+		return y, false
+	}
+	commitment, err := ctx.decodeCommitment(key.Commitment)
+	if err != nil {
+		return y, false
+	}
+	proof, err := ctx.decodeProof(entry.Proof)
+	if err != nil {
+		return y, false
+	}
+	return y, ctx.VerifyKZGProofFromPoints(commitment, &z, &y, proof)
+}
+
+// MemoryProofCache is a ProofCache of the last size openings, in memory.
+type MemoryProofCache struct {
+	lru *lruCache
+}
+
+// NewMemoryProofCache creates a cache of the last size openings, at least one.
+func NewMemoryProofCache(size int) *MemoryProofCache {
+	if size < 1 {
+		size = 1
+	}
+	return &MemoryProofCache{lru: newLRUCache(size)}
+}
+
+func (c *MemoryProofCache) GetProof(key ProofCacheKey) (ProofCacheEntry, bool) {
+	v, ok := c.lru.get(key)
+	if !ok {
+		return ProofCacheEntry{}, false
+	}
+	return v.(ProofCacheEntry), true
+}
+
+func (c *MemoryProofCache) PutProof(key ProofCacheKey, entry ProofCacheEntry) {
+	c.lru.add(key, entry)
+}
+
+// FileProofCache is a ProofCache on disk, one file per opening in a directory, named by the sha256 hash
+// of its key, and holding the evaluation and the proof, 80 bytes. Files are written to a temporary file first
+// and renamed, so that concurrent readers, of this process or others sharing the directory, never see a partial
+// entry. The directory is not bounded in size: entries are only removed by the operator.
+type FileProofCache struct {
+	dir string
+}
+
+// NewFileProofCache creates a cache in the directory, created if it does not exist.
+func NewFileProofCache(dir string) (*FileProofCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create proof cache directory: %v", err)
+	}
+	return &FileProofCache{dir: dir}, nil
+}
+
+func (c *FileProofCache) path(key ProofCacheKey) string {
+	h := sha256.New()
+	h.Write(key.Commitment[:])
+	h.Write(key.Z[:])
+	return filepath.Join(c.dir, hex.EncodeToString(h.Sum(nil)))
+}
+
+func (c *FileProofCache) GetProof(key ProofCacheKey) (ProofCacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	var entry ProofCacheEntry
+	if err != nil || len(data) != len(entry.Y)+len(entry.Proof) {
+		return entry, false
+	}
+	copy(entry.Y[:], data)
+	copy(entry.Proof[:], data[len(entry.Y):])
+	return entry, true
+}
+
+func (c *FileProofCache) PutProof(key ProofCacheKey, entry ProofCacheEntry) {
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(append(entry.Y[:], entry.Proof[:]...))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
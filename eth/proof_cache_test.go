@@ -0,0 +1,95 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"os"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// countingProofCache counts the hits and writes of a proof cache.
+type countingProofCache struct {
+	ProofCache
+	hits, puts int
+}
+
+func (c *countingProofCache) GetProof(key ProofCacheKey) (ProofCacheEntry, bool) {
+	entry, ok := c.ProofCache.GetProof(key)
+	if ok {
+		c.hits++
+	}
+	return entry, ok
+}
+
+func (c *countingProofCache) PutProof(key ProofCacheKey, entry ProofCacheEntry) {
+	c.puts++
+	c.ProofCache.PutProof(key, entry)
+}
+
+func TestProofCache(t *testing.T) {
+	fileCache, err := NewFileProofCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, backend := range []ProofCache{NewMemoryProofCache(4), fileCache} {
+		ctx := newTestContext(t, 4)
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		expected, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache := &countingProofCache{ProofCache: backend}
+		ctx.SetProofCache(cache)
+		for i := 0; i < 2; i++ {
+			proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if proof != expected {
+				t.Fatalf("%T: proof %d mismatch", backend, i)
+			}
+		}
+		if cache.hits != 1 || cache.puts != 1 {
+			t.Fatalf("%T: expected a miss and a hit, got %d hits, %d writes", backend, cache.hits, cache.puts)
+		}
+
+		// the other entry points share the cache, by commitment and point
+		z := bls.RandomFr()
+		proof, y, err := ctx.ComputeKZGProofAt(blob, bls.FrTo32(z))
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := ctx.ComputeKZGProof(poly, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again != proof || cache.hits != 2 || cache.puts != 2 {
+			t.Fatalf("%T: expected the opening to be cached, got %d hits, %d writes", backend, cache.hits, cache.puts)
+		}
+
+		// a corrupted entry is a miss, and is replaced
+		key := ProofCacheKey{Commitment: commitment, Z: bls.FrTo32(z)}
+		backend.PutProof(key, ProofCacheEntry{Y: y, Proof: expected})
+		if proof, _, err := ctx.ComputeKZGProofAt(blob, bls.FrTo32(z)); err != nil || proof == expected {
+			t.Fatalf("%T: expected the corrupted entry to be recomputed: %v", backend, err)
+		}
+		if entry, ok := backend.GetProof(key); !ok || entry.Proof != proof {
+			t.Fatalf("%T: expected the corrupted entry to be replaced", backend)
+		}
+	}
+
+	// a truncated file is a miss
+	key := ProofCacheKey{Z: [32]byte{1}}
+	fileCache.PutProof(key, ProofCacheEntry{})
+	if err := os.WriteFile(fileCache.path(key), []byte{1, 2, 3}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fileCache.GetProof(key); ok {
+		t.Fatal("expected truncated entry to be a miss")
+	}
+}
@@ -0,0 +1,50 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ConvertAggregateToBlobProofs migrates the blobs of a pre-Deneb sidecar, with an aggregate proof, to the
+// per-blob proofs of Deneb sidecars, e.g. for archival services converting their stored sidecars.
+// The aggregate proof is checked first, as VerifyAggregateKZGProof does, so that only blobs that were valid under
+// the old format are converted; the proofs of the blobs are then computed in parallel over the pool of workers
+// (see SetMaxWorkers) and checked as a batch, as VerifyBlobKZGProofBatch does, before they are returned,
+// in the order of the blobs. It returns an error wrapping ErrInvalidProof if the aggregate proof is invalid.
+func (ctx *Context) ConvertAggregateToBlobProofs(blobs BlobSequence, commitments KZGCommitmentSequence, aggregateProof KZGProof) ([]KZGProof, error) {
+	ok, err := ctx.VerifyAggregateKZGProof(blobs, commitments, aggregateProof)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: aggregate proof", ErrInvalidProof)
+	}
+	n := blobs.Len()
+	proofs := make(KZGProofSequenceImpl, n)
+	errs := make([]error, n)
+	parallelFor(n, func(i int) {
+		proofs[i], errs[i] = ctx.ComputeBlobKZGProof(blobs.At(i), commitments.At(i))
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("blob %d: %w", i, err)
+		}
+	}
+	ok, err = ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// the aggregate proof binds the blobs to their commitments, so this is a bug, not bad input
+		return nil, errors.New("blob proofs of the blobs of a valid aggregate proof do not verify")
+	}
+	return proofs, nil
+}
+
+// ConvertAggregateToBlobProofs calls ConvertAggregateToBlobProofs on the default context.
+func ConvertAggregateToBlobProofs(blobs BlobSequence, commitments KZGCommitmentSequence, aggregateProof KZGProof) ([]KZGProof, error) {
+	return defaultContext().ConvertAggregateToBlobProofs(blobs, commitments, aggregateProof)
+}
@@ -0,0 +1,51 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvertAggregateToBlobProofs(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		blobs = append(blobs, polynomialToBlob(poly))
+		commitments = append(commitments, ctx.PolynomialToKZGCommitment(poly))
+	}
+	aggregateProof, err := ctx.ComputeAggregateKZGProof(blobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofs, err := ctx.ConvertAggregateToBlobProofs(blobs, commitments, aggregateProof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proofs) != len(blobs) {
+		t.Fatalf("expected %d proofs, got %d", len(blobs), len(proofs))
+	}
+	for i := range proofs {
+		expected, err := ctx.ComputeBlobKZGProof(blobs[i], commitments[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proofs[i] != expected {
+			t.Fatalf("proof %d mismatch", i)
+		}
+	}
+
+	otherProof, err := ctx.ComputeAggregateKZGProof(blobs[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.ConvertAggregateToBlobProofs(blobs, commitments, otherProof); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected invalid aggregate proof to be rejected, got %v", err)
+	}
+	if _, err := ctx.ConvertAggregateToBlobProofs(blobs, commitments[:2], aggregateProof); !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("expected length mismatch, got %v", err)
+	}
+}
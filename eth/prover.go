@@ -0,0 +1,136 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// proverScratch holds the buffers of a single proof computation, all of the blob size.
+type proverScratch struct {
+	poly         Polynomial
+	denominators []bls.Fr
+	inverses     []bls.Fr
+	quotient     []bls.Fr
+	// the last proof, before compression
+	proof bls.G1Point
+	// digits of the fixed-base MSM, if the context has a Lagrange table
+	digits []byte
+}
+
+func newProverScratch(n int) *proverScratch {
+	return &proverScratch{
+		poly:         make(Polynomial, n),
+		denominators: make([]bls.Fr, n),
+		inverses:     make([]bls.Fr, n),
+		quotient:     make([]bls.Fr, n),
+	}
+}
+
+// computeKZGProof computes the proof for the polynomial at z, and the evaluation y, using only the scratch buffers.
+// The polynomial must have the size of the domain.
+func (ctx *Context) computeKZGProof(s *proverScratch, polynomial []bls.Fr, z *bls.Fr) (KZGProof, bls.Fr) {
+	defer bls.StartTraceOperation("kzg prove").End()
+	start := time.Now()
+	// the index of z in the domain, if it is one of the roots of unity
+	m := -1
+	for i := range polynomial {
+		if bls.EqualFr(&ctx.domain[i], z) {
+			m = i
+			// avoid the division by zero, this element of the quotient is computed separately below
+			bls.CopyFr(&s.denominators[i], &bls.ONE)
+			continue
+		}
+		bls.SubModFr(&s.denominators[i], &ctx.domain[i], z)
+	}
+	bls.BatchInvModFr(s.inverses, s.denominators)
+
+	// The inverses 1 / (DOMAIN[i] - z) also give the evaluation, with the barycentric formula:
+	//
+	//	y = (1 - z^n) / n * sum_i f(DOMAIN[i]) * DOMAIN[i] / (DOMAIN[i] - z)
+	var y bls.Fr
+	if m >= 0 {
+		bls.CopyFr(&y, &polynomial[m])
+	} else {
+		var term bls.Fr
+		for i := range polynomial {
+			bls.MulModFr(&term, &polynomial[i], &ctx.domain[i])
+			bls.MulModFr(&term, &term, &s.inverses[i])
+			bls.AddModFr(&y, &y, &term)
+		}
+		var zn, factor bls.Fr
+		bls.CopyFr(&zn, z)
+		for k := 1; k < len(polynomial); k <<= 1 {
+			bls.MulModFr(&zn, &zn, &zn)
+		}
+		bls.SubModFr(&factor, &bls.ONE, &zn)
+		var width bls.Fr
+		bls.AsFr(&width, uint64(len(polynomial)))
+		bls.DivModFr(&factor, &factor, &width)
+		bls.MulModFr(&y, &y, &factor)
+	}
+
+	// the denominators are not needed anymore, reuse them for the shifted polynomial f(X) - y
+	polynomialShifted := s.denominators
+	for i := range polynomial {
+		bls.SubModFr(&polynomialShifted[i], &polynomial[i], &y)
+		bls.MulModFr(&s.quotient[i], &polynomialShifted[i], &s.inverses[i])
+	}
+	if m >= 0 {
+		ctx.computeQuotientEvalWithinDomain(&s.quotient[m], polynomialShifted, m)
+	}
+	s.digits = ctx.lagrangeLinCombScratch(&s.proof, s.quotient, s.digits)
+	var proof KZGProof
+	copy(proof[:], bls.ToCompressedG1(&s.proof))
+	ctx.metrics.ProofComputed(time.Since(start))
+	return proof, y
+}
+
+// Prover computes proofs like the context does, but reuses its internal buffers across calls,
+// instead of allocating a few hundred KB per proof.
+// A Prover is not safe for concurrent use: sustained proving workloads should use one per worker.
+type Prover struct {
+	ctx     *Context
+	scratch *proverScratch
+}
+
+// NewProver creates a Prover for the context, with buffers of the blob size.
+func (ctx *Context) NewProver() *Prover {
+	return &Prover{ctx: ctx, scratch: newProverScratch(ctx.FieldElementsPerBlob())}
+}
+
+// NewProver calls NewProver on the default context.
+func NewProver() *Prover {
+	return defaultContext().NewProver()
+}
+
+// ComputeKZGProof is Context.ComputeKZGProof, reusing the prover buffers.
+func (p *Prover) ComputeKZGProof(polynomial []bls.Fr, z *bls.Fr) (KZGProof, error) {
+	if len(polynomial) != p.ctx.FieldElementsPerBlob() {
+		return KZGProof{}, fmt.Errorf("%w: polynomial has invalid length", ErrWrongBlobLength)
+	}
+	proof, _ := p.ctx.computeKZGProof(p.scratch, polynomial, z)
+	return proof, nil
+}
+
+// ComputeBlobKZGProof is Context.ComputeBlobKZGProof, reusing the prover buffers.
+func (p *Prover) ComputeBlobKZGProof(blob Blob, commitment KZGCommitment) (KZGProof, error) {
+	poly := p.scratch.poly
+	if blob.Len() != len(poly) {
+		return KZGProof{}, fmt.Errorf("%w: blob has %d field elements, expected %d", ErrWrongBlobLength, blob.Len(), len(poly))
+	}
+	for i := range poly {
+		if !bls.FrFrom32(&poly[i], blob.At(i)) {
+			return KZGProof{}, fmt.Errorf("blob field element %d: %w", i, ErrNonCanonicalScalar)
+		}
+	}
+	if _, err := p.ctx.decodeCommitment(commitment); err != nil {
+		return KZGProof{}, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	proof, _ := p.ctx.computeKZGProof(p.scratch, poly, p.ctx.ComputeChallenge(poly, commitment))
+	return proof, nil
+}
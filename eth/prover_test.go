@@ -0,0 +1,77 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestProver(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	for _, precompute := range []bool{false, true} {
+		if precompute {
+			ctx.PrecomputeLagrangeTable()
+		}
+		prover := ctx.NewProver()
+		for i := 0; i < 3; i++ {
+			poly := randomPolynomialN(16)
+			commitment := ctx.PolynomialToKZGCommitment(poly)
+			for _, z := range []*bls.Fr{bls.RandomFr(), &ctx.Domain()[i]} {
+				proof, err := prover.ComputeKZGProof(poly, z)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected, err := ctx.ComputeKZGProof(poly, z)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if proof != expected {
+					t.Fatalf("prover proof mismatch: got %v, expected %v", proof, expected)
+				}
+				y := ctx.EvaluatePolynomialInEvaluationForm(poly, z)
+				ok, err := ctx.VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !ok {
+					t.Fatal("expected prover proof to verify")
+				}
+			}
+			blob := polynomialToBlob(poly)
+			proof, err := prover.ComputeBlobKZGProof(blob, commitment)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ok, err := ctx.VerifyBlobKZGProof(blob, commitment, proof)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				t.Fatal("expected prover blob proof to verify")
+			}
+		}
+	}
+}
+
+func TestProverInvalidInput(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	prover := ctx.NewProver()
+	if _, err := prover.ComputeKZGProof(randomPolynomialN(8), bls.RandomFr()); err == nil {
+		t.Fatal("expected error for polynomial of the wrong size")
+	}
+	poly := randomPolynomialN(16)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	if _, err := prover.ComputeBlobKZGProof(polynomialToBlob(randomPolynomialN(8)), commitment); err == nil {
+		t.Fatal("expected error for blob of the wrong size")
+	}
+	blob := polynomialToBlob(poly)
+	for i := range blob[3] {
+		blob[3][i] = 0xff
+	}
+	if _, err := prover.ComputeBlobKZGProof(blob, commitment); err == nil {
+		t.Fatal("expected error for non-canonical field element")
+	}
+}
@@ -0,0 +1,89 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// readChunkSize is the number of bytes read from the underlying reader at once (128 field elements).
+const readChunkSize = 128 * 32
+
+// flatBlob is a blob stored as contiguous 32-byte field elements.
+type flatBlob []byte
+
+func (b flatBlob) Len() int {
+	return len(b) / 32
+}
+
+func (b flatBlob) At(i int) (out [32]byte) {
+	copy(out[:], b[i*32:(i+1)*32])
+	return out
+}
+
+// readBlob reads exactly one blob from r, in chunks, calling fn with every canonical field element.
+// It fails on the first non-canonical field element, without consuming the rest of the blob.
+func (ctx *Context) readBlob(r io.Reader, fn func(i int, b []byte, fr *bls.Fr)) error {
+	width := ctx.FieldElementsPerBlob()
+	buf := make([]byte, readChunkSize)
+	var fe [32]byte
+	var fr bls.Fr
+	for i := 0; i < width; {
+		n := (width - i) * 32
+		if n > len(buf) {
+			n = len(buf)
+		}
+		chunk := buf[:n]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return fmt.Errorf("failed to read blob field element %d: %w", i, err)
+		}
+		for off := 0; off < n; off, i = off+32, i+1 {
+			copy(fe[:], chunk[off:off+32])
+			if !bls.FrFrom32(&fr, fe) {
+				return fmt.Errorf("blob field element %d: %w", i, ErrNonCanonicalScalar)
+			}
+			fn(i, chunk[off:off+32], &fr)
+		}
+	}
+	return nil
+}
+
+// BlobFromReader reads a blob of FieldElementsPerBlob little-endian field elements from r, validating each
+// field element as it is read. Exactly the bytes of one blob are consumed, so multiple blobs can be read from one stream.
+func (ctx *Context) BlobFromReader(r io.Reader) (Blob, error) {
+	blob := make(flatBlob, ctx.FieldElementsPerBlob()*32)
+	err := ctx.readBlob(r, func(i int, b []byte, _ *bls.Fr) {
+		copy(blob[i*32:], b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// BlobFromReader calls BlobFromReader on the default context.
+func BlobFromReader(r io.Reader) (Blob, error) {
+	return defaultContext().BlobFromReader(r)
+}
+
+// CommitmentFromReader reads a blob from r like BlobFromReader, and returns its commitment.
+// The field elements are decoded as they are read, without keeping a copy of the blob bytes.
+func (ctx *Context) CommitmentFromReader(r io.Reader) (KZGCommitment, error) {
+	poly := make(Polynomial, ctx.FieldElementsPerBlob())
+	err := ctx.readBlob(r, func(i int, _ []byte, fr *bls.Fr) {
+		bls.CopyFr(&poly[i], fr)
+	})
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	return ctx.PolynomialToKZGCommitment(poly), nil
+}
+
+// CommitmentFromReader calls CommitmentFromReader on the default context.
+func CommitmentFromReader(r io.Reader) (KZGCommitment, error) {
+	return defaultContext().CommitmentFromReader(r)
+}
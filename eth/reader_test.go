@@ -0,0 +1,58 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestBlobFromReader(t *testing.T) {
+	ctx := newTestContext(t, 8)
+	poly := randomPolynomialN(256)
+	var stream bytes.Buffer
+	for i := range poly {
+		b := bls.FrTo32(&poly[i])
+		stream.Write(b[:])
+	}
+	data := stream.Bytes()
+	// two blobs back to back
+	stream.Write(data)
+
+	for j := 0; j < 2; j++ {
+		blob, err := ctx.BlobFromReader(&stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range poly {
+			if blob.At(i) != bls.FrTo32(&poly[i]) {
+				t.Fatalf("blob %d: field element %d differs", j, i)
+			}
+		}
+	}
+	if _, err := ctx.BlobFromReader(&stream); err == nil {
+		t.Fatal("expected empty stream to fail")
+	}
+
+	commitment, err := ctx.CommitmentFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commitment != ctx.PolynomialToKZGCommitment(poly) {
+		t.Fatal("streamed commitment differs")
+	}
+
+	if _, err := ctx.CommitmentFromReader(bytes.NewReader(data[:len(data)-1])); err == nil {
+		t.Fatal("expected truncated blob to fail")
+	}
+	bad := append([]byte(nil), data...)
+	for i := 200 * 32; i < 201*32; i++ {
+		bad[i] = 0xff
+	}
+	if _, err := ctx.BlobFromReader(bytes.NewReader(bad)); err == nil {
+		t.Fatal("expected non-canonical field element to fail")
+	}
+}
@@ -0,0 +1,115 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// sampleCoset checks the size of a sample and returns the shift h of its coset: the blob elements
+// [sampleIndex*size, (sampleIndex+1)*size) are, in the bit-reversed order of the blob, the evaluations at
+// h * w**reverse_bits(j) for the primitive size-th root of unity w, where h is the domain point of the first one.
+func (ctx *Context) sampleCoset(sampleIndex uint64, size int) (*bls.Fr, error) {
+	n := ctx.FieldElementsPerBlob()
+	if size == 0 || size&(size-1) != 0 || size > n {
+		return nil, fmt.Errorf("sample size %d is not a power of two dividing the blob size %d", size, n)
+	}
+	if size >= len(ctx.setupG2) {
+		return nil, fmt.Errorf("%w: sample size %d exceeds the G2 setup of %d powers", ErrSetupTooSmall, size, len(ctx.setupG2))
+	}
+	if err := ctx.checkSetupG1(size); err != nil {
+		return nil, err
+	}
+	if sampleIndex >= uint64(n/size) {
+		return nil, fmt.Errorf("sample index %d is out of range for %d samples", sampleIndex, n/size)
+	}
+	return &ctx.domain[sampleIndex*uint64(size)], nil
+}
+
+// SamplePoints returns the evaluation points of the sample of the given index, in the order of the sample data:
+// the blob is split into samples of size consecutive elements, size a power of two, and the points of a sample
+// are the domain points of its elements. See VerifySample.
+func (ctx *Context) SamplePoints(sampleIndex uint64, size int) ([]bls.Fr, error) {
+	if _, err := ctx.sampleCoset(sampleIndex, size); err != nil {
+		return nil, err
+	}
+	points := make([]bls.Fr, size)
+	for j := range points {
+		bls.CopyFr(&points[j], &ctx.domain[sampleIndex*uint64(size)+uint64(j)])
+	}
+	return points, nil
+}
+
+// SamplePoints calls SamplePoints on the default context.
+func SamplePoints(sampleIndex uint64, size int) ([]bls.Fr, error) {
+	return defaultContext().SamplePoints(sampleIndex, size)
+}
+
+// VerifySample verifies a proof of a sample of a blob, of len(sampleData) consecutive elements at sampleIndex
+// in the blob (see SamplePoints), against the commitment to the blob. The proof is the multi-proof of the
+// evaluations at the points of the sample, as ComputeKZGMultiProof computes. The points of a sample are a coset
+// h*H of a subgroup H of the roots of unity, so the check is
+//
+//	e(commitment - [I(tau)]_1, [1]_2) == e(proof, [tau**size - h**size]_2)
+//
+// with the interpolation I of the sample computed by an inverse FFT over H. The sample size must be lower than
+// the number of G2 powers of the setup. It returns nil if the proof is valid, ErrInvalidProof if it is not,
+// and another error if the inputs are malformed.
+func (ctx *Context) VerifySample(rowCommitment KZGCommitment, sampleIndex uint64, sampleData []bls.Fr, proof KZGProof) error {
+	size := len(sampleData)
+	h, err := ctx.sampleCoset(sampleIndex, size)
+	if err != nil {
+		return err
+	}
+	commitment, err := ctx.decodeCommitment(rowCommitment)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	proofG1, err := ctx.decodeProof(proof)
+	if err != nil {
+		return err
+	}
+
+	// sampleData[j] is the evaluation at h*w**reverse_bits(j), so the inverse FFT of the data in natural order
+	// gives the coefficients of I(h*X), which are scaled by h**-k back to the coefficients of I
+	evals := make([]bls.Fr, size)
+	for j := range sampleData {
+		bls.CopyFr(&evals[reverseBits(uint64(j), uint64(size))], &sampleData[j])
+	}
+	interpolation, err := ctx.fftSettings().FFT(evals, true)
+	if err != nil {
+		return err
+	}
+	var hInv, scale bls.Fr
+	bls.InvModFr(&hInv, h)
+	bls.CopyFr(&scale, &bls.ONE)
+	for k := range interpolation {
+		bls.MulModFr(&interpolation[k], &interpolation[k], &scale)
+		bls.MulModFr(&scale, &scale, &hInv)
+	}
+	var commitmentMinusI bls.G1Point
+	bls.SubG1(&commitmentMinusI, commitment, bls.LinCombG1(ctx.setupG1[:size], interpolation))
+
+	// [tau**size - h**size]_2
+	var hPow bls.Fr
+	bls.CopyFr(&hPow, h)
+	for s := 1; s < size; s <<= 1 {
+		bls.MulModFr(&hPow, &hPow, &hPow)
+	}
+	var zG2, hPowG2 bls.G2Point
+	bls.MulG2(&hPowG2, &bls.GenG2, &hPow)
+	bls.SubG2(&zG2, &ctx.setupG2[size], &hPowG2)
+
+	if !bls.PairingsVerify(&commitmentMinusI, &bls.GenG2, proofG1, &zG2) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifySample calls VerifySample on the default context.
+func VerifySample(rowCommitment KZGCommitment, sampleIndex uint64, sampleData []bls.Fr, proof KZGProof) error {
+	return defaultContext().VerifySample(rowCommitment, sampleIndex, sampleData, proof)
+}
@@ -0,0 +1,60 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestVerifySample(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	for _, size := range []int{1, 2, 4, 8} {
+		for index := uint64(0); index < uint64(16/size); index++ {
+			points, err := ctx.SamplePoints(index, size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, ys, err := ctx.ComputeKZGMultiProof(poly, points)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the sample data is a range of the blob
+			data := poly[index*uint64(size) : (index+1)*uint64(size)]
+			for j := range data {
+				if !bls.EqualFr(&data[j], &ys[j]) {
+					t.Fatalf("size %d, sample %d: point %d does not evaluate to the blob element", size, index, j)
+				}
+			}
+			if err := ctx.VerifySample(commitment, index, data, proof); err != nil {
+				t.Fatalf("size %d, sample %d: %v", size, index, err)
+			}
+			if err := ctx.VerifySample(commitment, (index+1)%uint64(16/size), data, proof); !errors.Is(err, ErrInvalidProof) {
+				t.Fatalf("size %d, sample %d: expected the data at another index to be rejected, got %v", size, index, err)
+			}
+			tampered := make([]bls.Fr, len(data))
+			for j := range data {
+				bls.CopyFr(&tampered[j], &data[j])
+			}
+			bls.AddModFr(&tampered[size-1], &tampered[size-1], &bls.ONE)
+			if err := ctx.VerifySample(commitment, index, tampered, proof); !errors.Is(err, ErrInvalidProof) {
+				t.Fatalf("size %d, sample %d: expected tampered data to be rejected, got %v", size, index, err)
+			}
+		}
+	}
+
+	if err := ctx.VerifySample(commitment, 0, poly[:3], KZGProof{}); err == nil || errors.Is(err, ErrInvalidProof) {
+		t.Fatal("expected a sample size that is not a power of two to be rejected")
+	}
+	if err := ctx.VerifySample(commitment, 4, poly[:4], KZGProof{}); err == nil || errors.Is(err, ErrInvalidProof) {
+		t.Fatal("expected an out of range sample index to be rejected")
+	}
+	if err := ctx.VerifySample(commitment, 0, poly, KZGProof{}); err == nil || errors.Is(err, ErrInvalidProof) {
+		t.Fatal("expected a sample size beyond the G2 setup to be rejected")
+	}
+}
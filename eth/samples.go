@@ -0,0 +1,67 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Domain separator of the sample derivation, see DeriveSamples.
+const sampleDerivationDomain = "GO_KZG_SAMPLES_V1"
+
+// Sample is a point of a blob to sample.
+type Sample struct {
+	// Index of the field element in the blob, which is in bit-reversed order.
+	Index int
+	// Exponent of the point: Point = omega**Exponent for the primitive root of unity omega of the domain,
+	// and Index is the bit-reversal of Exponent.
+	Exponent int
+	// The evaluation domain point the blob element at Index is the evaluation at.
+	Point bls.Fr
+}
+
+// DeriveSamples deterministically derives count distinct points of a blob to sample, from a node ID and a slot,
+// as in proof-of-custody schemes. The exponents are uniform in [0, FieldElementsPerBlob), taken from
+//
+//	sha256(domain || nodeID || uint64_be(slot) || uint64_be(counter))
+//
+// for counter = 0, 1, ... skipping duplicates, and the blob indices follow from the bit-reversed order of the blob.
+func (ctx *Context) DeriveSamples(nodeID [32]byte, slot uint64, count int) ([]Sample, error) {
+	n := ctx.FieldElementsPerBlob()
+	if count < 0 || count > n {
+		return nil, fmt.Errorf("cannot derive %d distinct samples of a blob of %d field elements", count, n)
+	}
+	var buf [len(sampleDerivationDomain) + 32 + 8 + 8]byte
+	copy(buf[:], sampleDerivationDomain)
+	copy(buf[len(sampleDerivationDomain):], nodeID[:])
+	binary.BigEndian.PutUint64(buf[len(sampleDerivationDomain)+32:], slot)
+	counterOffset := len(sampleDerivationDomain) + 32 + 8
+
+	samples := make([]Sample, 0, count)
+	seen := make(map[int]struct{}, count)
+	for counter := uint64(0); len(samples) < count; counter++ {
+		binary.BigEndian.PutUint64(buf[counterOffset:], counter)
+		h := sha256.Sum256(buf[:])
+		// n is a power of two, so the reduction is unbiased
+		exponent := int(binary.BigEndian.Uint64(h[:8]) % uint64(n))
+		if _, ok := seen[exponent]; ok {
+			continue
+		}
+		seen[exponent] = struct{}{}
+		index := int(reverseBits(uint64(exponent), uint64(n)))
+		sample := Sample{Index: index, Exponent: exponent}
+		bls.CopyFr(&sample.Point, &ctx.domain[index])
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// DeriveSamples calls DeriveSamples on the default context.
+func DeriveSamples(nodeID [32]byte, slot uint64, count int) ([]Sample, error) {
+	return defaultContext().DeriveSamples(nodeID, slot, count)
+}
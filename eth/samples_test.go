@@ -0,0 +1,59 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestDeriveSamples(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	nodeID := [32]byte{1, 2, 3}
+	samples, err := ctx.DeriveSamples(nodeID, 42, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := ctx.DeriveSamples(nodeID, 42, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(samples, again) {
+		t.Fatal("expected the derivation to be deterministic")
+	}
+	other, err := ctx.DeriveSamples(nodeID, 43, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(samples, other) {
+		t.Fatal("expected another slot to derive other samples")
+	}
+
+	// all the points of the blob, matching the powers of the primitive root of unity
+	samples, err = ctx.DeriveSamples(nodeID, 42, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	omega := &ctx.Domain()[reverseBits(1, 16)]
+	seen := make(map[int]bool)
+	for _, s := range samples {
+		if seen[s.Index] {
+			t.Fatalf("duplicate sample index %d", s.Index)
+		}
+		seen[s.Index] = true
+		var expected bls.Fr
+		bls.CopyFr(&expected, &bls.ONE)
+		for i := 0; i < s.Exponent; i++ {
+			bls.MulModFr(&expected, &expected, omega)
+		}
+		if !bls.EqualFr(&expected, &s.Point) || !bls.EqualFr(&s.Point, &ctx.Domain()[s.Index]) {
+			t.Fatalf("sample at index %d does not match omega**%d", s.Index, s.Exponent)
+		}
+	}
+	if _, err := ctx.DeriveSamples(nodeID, 42, 17); err == nil {
+		t.Fatal("expected more samples than blob elements to be rejected")
+	}
+}
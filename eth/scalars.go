@@ -0,0 +1,69 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// IsCanonicalScalar checks that the little-endian 32 bytes encode a field element, i.e. are less than BLSModulus.
+// This is the encoding of the field elements of blobs, and of the z and y inputs of VerifyKZGProof.
+func IsCanonicalScalar(b [32]byte) bool {
+	return bls.ValidFr(b)
+}
+
+// IsCanonicalScalarBE is IsCanonicalScalar for the big-endian encoding, e.g. of the point evaluation precompile.
+func IsCanonicalScalarBE(b [32]byte) bool {
+	return bls.ValidFrBE(b)
+}
+
+// ReduceBytes32ToFr interprets the 32 bytes as a little-endian integer, reduced modulo BLSModulus.
+func ReduceBytes32ToFr(b [32]byte) *bls.Fr {
+	out := new(bls.Fr)
+	bls.FrFrom32Mod(out, b)
+	return out
+}
+
+// ReduceBytes32ToFrBE interprets the 32 bytes as a big-endian integer, reduced modulo BLSModulus.
+func ReduceBytes32ToFrBE(b [32]byte) *bls.Fr {
+	out := new(bls.Fr)
+	bls.FrFrom32ModBE(out, b)
+	return out
+}
+
+// MustFrFrom32 decodes a little-endian canonical scalar, and panics if it is not canonical.
+// This is meant for constants and inputs that have already been validated.
+func MustFrFrom32(b [32]byte) *bls.Fr {
+	out := new(bls.Fr)
+	if !bls.FrFrom32(out, b) {
+		panic(fmt.Errorf("non-canonical scalar %x", b))
+	}
+	return out
+}
+
+// MustFrFrom32BE is MustFrFrom32 for the big-endian encoding.
+func MustFrFrom32BE(b [32]byte) *bls.Fr {
+	out := new(bls.Fr)
+	if !bls.FrFrom32BE(out, b) {
+		panic(fmt.Errorf("non-canonical scalar %x", b))
+	}
+	return out
+}
+
+// scalarsPool holds *[]bls.Fr buffers of decoded field elements, see getScalars.
+var scalarsPool sync.Pool
+
+// getScalars returns a buffer of n field elements from the pool, to be put back when no longer used.
+// The contents are not cleared.
+func getScalars(n int) *[]bls.Fr {
+	if v, ok := scalarsPool.Get().(*[]bls.Fr); ok && cap(*v) >= n {
+		*v = (*v)[:n]
+		return v
+	}
+	v := make([]bls.Fr, n)
+	return &v
+}
@@ -0,0 +1,56 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestScalarHelpers(t *testing.T) {
+	one := big.NewInt(1)
+	inputs := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(42),
+		new(big.Int).Sub(BLSModulus, one),
+		BLSModulus,
+		new(big.Int).Add(BLSModulus, one),
+		new(big.Int).Sub(new(big.Int).Lsh(one, 256), one),
+	}
+	for _, v := range inputs {
+		var be [32]byte
+		v.FillBytes(be[:])
+		le := reverse32(be)
+		canonical := v.Cmp(BLSModulus) < 0
+		if IsCanonicalScalar(le) != canonical || IsCanonicalScalarBE(be) != canonical {
+			t.Fatalf("unexpected canonical check of %x", be)
+		}
+		var expected bls.Fr
+		bigToFr(&expected, new(big.Int).Mod(v, BLSModulus))
+		if !bls.EqualFr(ReduceBytes32ToFr(le), &expected) || !bls.EqualFr(ReduceBytes32ToFrBE(be), &expected) {
+			t.Fatalf("unexpected reduction of %x", be)
+		}
+		if canonical {
+			if !bls.EqualFr(MustFrFrom32(le), &expected) || !bls.EqualFr(MustFrFrom32BE(be), &expected) {
+				t.Fatalf("unexpected decoding of %x", be)
+			}
+			continue
+		}
+		for _, decode := range []func(){
+			func() { MustFrFrom32(le) },
+			func() { MustFrFrom32BE(be) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Fatalf("expected non-canonical %x to panic", be)
+					}
+				}()
+				decode()
+			}()
+		}
+	}
+}
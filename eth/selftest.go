@@ -0,0 +1,97 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Known answers of the embedded trusted setup for the self-test blob, opened at selfTestPoint:
+// the commitment, the proof, and the little-endian evaluation.
+const (
+	selfTestCommitment = "9294e3da3408b6bd48b325a07608570f252d7daa00f49517e9da619716be165b694a32e923b09c9a0487705edb8f47de"
+	selfTestProof      = "a7c7b41cf9cd1d0aa7c44f796e01e8891df4523373cc5e7610b4fe184d20d4ddaf5b01b02c488bc256d2250a78d00e35"
+	selfTestY          = "5f6d415e6ae61b30a42e1e19d687e52af27909de68267073386308cf64412f6b"
+)
+
+// selfTestPoint is the evaluation point of the self-test, outside of the domain.
+const selfTestPoint = 0x5eed
+
+// selfTestOpening commits to the self-test blob, with field element i set to i + 1 so that every Lagrange point
+// of the setup contributes to the commitment, and opens it at selfTestPoint.
+func (ctx *Context) selfTestOpening() (Polynomial, KZGCommitment, KZGProof, bls.Fr) {
+	poly := make(Polynomial, ctx.FieldElementsPerBlob())
+	for i := range poly {
+		bls.AsFr(&poly[i], uint64(i)+1)
+	}
+	var z bls.Fr
+	bls.AsFr(&z, selfTestPoint)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	proof, y := ctx.computeKZGProof(newProverScratch(len(poly)), poly, &z)
+	return poly, commitment, proof, y
+}
+
+// RunSelfTest checks that the setup of the context is consistent: the G1 and G2 powers of tau pair up,
+// the Lagrange setup commits like the monomial one, and a proof of a fixed blob verifies.
+// This is meant to run at startup, to detect a corrupted setup before it produces invalid proofs.
+func (ctx *Context) RunSelfTest() error {
+	if len(ctx.setupG1) >= 2 {
+		if !bls.EqualG1(&ctx.setupG1[0], &bls.GenG1) || !bls.EqualG2(&ctx.setupG2[0], &bls.GenG2) {
+			return errors.New("self-test: the setups do not start with the generators")
+		}
+		// e([tau]_1, [1]_2) == e([1]_1, [tau]_2)
+		if !bls.PairingsVerify(&ctx.setupG1[1], &bls.GenG2, &bls.GenG1, &ctx.setupG2[1]) {
+			return errors.New("self-test: the G1 and G2 setups are not powers of the same secret")
+		}
+	}
+	poly, commitment, proof, y := ctx.selfTestOpening()
+	commitmentG1, err := bls.FromCompressedG1(commitment[:])
+	if err != nil {
+		return fmt.Errorf("self-test: invalid commitment: %v", err)
+	}
+	if n := len(poly); len(ctx.setupG1) >= n {
+		coeffs, err := ctx.PolynomialToCoefficients(poly)
+		if err != nil {
+			return fmt.Errorf("self-test: %w", err)
+		}
+		if !bls.EqualG1(commitmentG1, bls.LinCombG1(ctx.setupG1[:n], coeffs)) {
+			return errors.New("self-test: the Lagrange setup does not match the monomial setup")
+		}
+	}
+	proofG1, err := bls.FromCompressedG1(proof[:])
+	if err != nil {
+		return fmt.Errorf("self-test: invalid proof: %v", err)
+	}
+	var z bls.Fr
+	bls.AsFr(&z, selfTestPoint)
+	if !ctx.VerifyKZGProofFromPoints(commitmentG1, &z, &y, proofG1) {
+		return errors.New("self-test: the proof of the self-test blob does not verify")
+	}
+	return nil
+}
+
+// RunSelfTest runs the self-test of the default context, and checks the commitment and proof of the self-test blob
+// against the known answers of the embedded trusted setup. It fails if the default context has another setup,
+// e.g. after SwapTrustedSetup.
+func RunSelfTest() error {
+	ctx := defaultContext()
+	if err := ctx.RunSelfTest(); err != nil {
+		return err
+	}
+	_, commitment, proof, y := ctx.selfTestOpening()
+	yBytes := bls.FrTo32(&y)
+	switch {
+	case hex.EncodeToString(commitment[:]) != selfTestCommitment:
+		return fmt.Errorf("self-test: unexpected commitment %v", commitment)
+	case hex.EncodeToString(proof[:]) != selfTestProof:
+		return fmt.Errorf("self-test: unexpected proof %v", proof)
+	case hex.EncodeToString(yBytes[:]) != selfTestY:
+		return fmt.Errorf("self-test: unexpected evaluation %x", yBytes)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	if err := RunSelfTest(); err != nil {
+		t.Fatal(err)
+	}
+	// the known evaluation matches an independent evaluation of the self-test blob
+	poly, _, _, y := defaultContext().selfTestOpening()
+	var z bls.Fr
+	bls.AsFr(&z, selfTestPoint)
+	if !bls.EqualFr(&y, defaultContext().EvaluatePolynomialInEvaluationForm(poly, &z)) {
+		t.Fatal("unexpected evaluation of the self-test blob")
+	}
+}
+
+func TestRunSelfTestCorruptSetup(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	if err := ctx.RunSelfTest(); err != nil {
+		t.Fatal(err)
+	}
+	setupLagrange := append([]bls.G1Point(nil), ctx.setupLagrange...)
+	bls.AddG1(&ctx.setupLagrange[5], &ctx.setupLagrange[5], &bls.GenG1)
+	if err := ctx.RunSelfTest(); err == nil {
+		t.Fatal("expected a corrupted Lagrange setup to fail the self-test")
+	}
+	ctx.setupLagrange = setupLagrange
+	bls.AddG2(&ctx.setupG2[1], &ctx.setupG2[1], &bls.GenG2)
+	if err := ctx.RunSelfTest(); err == nil {
+		t.Fatal("expected a corrupted G2 setup to fail the self-test")
+	}
+}
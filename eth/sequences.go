@@ -0,0 +1,124 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import "fmt"
+
+// blobSlice is a Blob over a flat encoding, whose length is a multiple of 32.
+type blobSlice []byte
+
+func (b blobSlice) Len() int {
+	return len(b) / 32
+}
+
+func (b blobSlice) At(i int) (out [32]byte) {
+	copy(out[:], b[i*32:])
+	return out
+}
+
+type blobSliceSequence []blobSlice
+
+func (s blobSliceSequence) Len() int {
+	return len(s)
+}
+
+func (s blobSliceSequence) At(i int) Blob {
+	return s[i]
+}
+
+// blobList is a BlobSequence over a slice of blobs.
+type blobList []Blob
+
+func (s blobList) Len() int {
+	return len(s)
+}
+
+func (s blobList) At(i int) Blob {
+	return s[i]
+}
+
+// BlobSequenceFromSlices presents flat blob encodings as a BlobSequence, without copying them.
+// Every blob must be a whole number of 32-byte field elements; the number of field elements is checked
+// against the context when the blobs are used.
+func BlobSequenceFromSlices(blobs [][]byte) (BlobSequence, error) {
+	out := make(blobSliceSequence, len(blobs))
+	for i, b := range blobs {
+		if len(b)%32 != 0 {
+			return nil, fmt.Errorf("blob %d has %d bytes, not a multiple of 32", i, len(b))
+		}
+		out[i] = b
+	}
+	return out, nil
+}
+
+type blobArraySequence [][BytesPerBlob]byte
+
+func (s blobArraySequence) Len() int {
+	return len(s)
+}
+
+func (s blobArraySequence) At(i int) Blob {
+	return (*BlobBytes)(&s[i])
+}
+
+// BlobSequenceFromArrays presents flat blobs of the default context as a BlobSequence, without copying them.
+func BlobSequenceFromArrays(blobs [][BytesPerBlob]byte) BlobSequence {
+	return blobArraySequence(blobs)
+}
+
+type commitmentArraySequence [][48]byte
+
+func (s commitmentArraySequence) Len() int {
+	return len(s)
+}
+
+func (s commitmentArraySequence) At(i int) KZGCommitment {
+	return s[i]
+}
+
+// KZGCommitmentSequenceFromArrays presents serialized commitments as a KZGCommitmentSequence, without copying them.
+func KZGCommitmentSequenceFromArrays(commitments [][48]byte) KZGCommitmentSequence {
+	return commitmentArraySequence(commitments)
+}
+
+// KZGCommitmentSequenceFromSlices presents serialized commitments as a KZGCommitmentSequence.
+// Every commitment must be 48 bytes long.
+func KZGCommitmentSequenceFromSlices(commitments [][]byte) (KZGCommitmentSequence, error) {
+	out := make(KZGCommitmentSequenceImpl, len(commitments))
+	for i, c := range commitments {
+		if len(c) != len(out[i]) {
+			return nil, fmt.Errorf("commitment %d has %d bytes, expected %d", i, len(c), len(out[i]))
+		}
+		copy(out[i][:], c)
+	}
+	return out, nil
+}
+
+type proofArraySequence [][48]byte
+
+func (s proofArraySequence) Len() int {
+	return len(s)
+}
+
+func (s proofArraySequence) At(i int) KZGProof {
+	return s[i]
+}
+
+// KZGProofSequenceFromArrays presents serialized proofs as a KZGProofSequence, without copying them.
+func KZGProofSequenceFromArrays(proofs [][48]byte) KZGProofSequence {
+	return proofArraySequence(proofs)
+}
+
+// KZGProofSequenceFromSlices presents serialized proofs as a KZGProofSequence.
+// Every proof must be 48 bytes long.
+func KZGProofSequenceFromSlices(proofs [][]byte) (KZGProofSequence, error) {
+	out := make(KZGProofSequenceImpl, len(proofs))
+	for i, p := range proofs {
+		if len(p) != len(out[i]) {
+			return nil, fmt.Errorf("proof %d has %d bytes, expected %d", i, len(p), len(out[i]))
+		}
+		copy(out[i][:], p)
+	}
+	return out, nil
+}
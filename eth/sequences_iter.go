@@ -0,0 +1,49 @@
+//go:build go1.23 && !bignum_hol256
+// +build go1.23,!bignum_hol256
+
+package eth
+
+import "iter"
+
+// The batch APIs need the length of their inputs upfront, and random access to them,
+// so iterators are collected: only the blobs themselves (the Blob values) are kept, not copied.
+
+// BlobSequenceFromSeq collects the blobs of an iterator into a BlobSequence.
+func BlobSequenceFromSeq(seq iter.Seq[Blob]) BlobSequence {
+	var out blobSequence
+	seq(func(b Blob) bool {
+		out = append(out, b)
+		return true
+	})
+	return out
+}
+
+type blobSequence []Blob
+
+func (s blobSequence) Len() int {
+	return len(s)
+}
+
+func (s blobSequence) At(i int) Blob {
+	return s[i]
+}
+
+// KZGCommitmentSequenceFromSeq collects the commitments of an iterator into a KZGCommitmentSequence.
+func KZGCommitmentSequenceFromSeq(seq iter.Seq[KZGCommitment]) KZGCommitmentSequence {
+	var out KZGCommitmentSequenceImpl
+	seq(func(c KZGCommitment) bool {
+		out = append(out, c)
+		return true
+	})
+	return out
+}
+
+// KZGProofSequenceFromSeq collects the proofs of an iterator into a KZGProofSequence.
+func KZGProofSequenceFromSeq(seq iter.Seq[KZGProof]) KZGProofSequence {
+	var out KZGProofSequenceImpl
+	seq(func(p KZGProof) bool {
+		out = append(out, p)
+		return true
+	})
+	return out
+}
@@ -0,0 +1,40 @@
+//go:build go1.23 && !bignum_hol256
+// +build go1.23,!bignum_hol256
+
+package eth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSequencesFromSeq(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs []Blob
+	var commitments []KZGCommitment
+	var proofs []KZGProof
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, blob)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	blobSeq := BlobSequenceFromSeq(slices.Values(blobs))
+	if blobSeq.Len() != len(blobs) {
+		t.Fatalf("expected %d blobs, got %d", len(blobs), blobSeq.Len())
+	}
+	ok, err := ctx.VerifyBlobKZGProofBatch(blobSeq, KZGCommitmentSequenceFromSeq(slices.Values(commitments)),
+		KZGProofSequenceFromSeq(slices.Values(proofs)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected batch to verify")
+	}
+}
@@ -0,0 +1,73 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+)
+
+func TestSequencesFromSlices(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs, commitments, proofs [][]byte
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, flattenBlob(blob))
+		commitments = append(commitments, commitment[:])
+		proofs = append(proofs, proof[:])
+	}
+	blobSeq, err := BlobSequenceFromSlices(blobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitmentSeq, err := KZGCommitmentSequenceFromSlices(commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofSeq, err := KZGProofSequenceFromSlices(proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := ctx.VerifyBlobKZGProofBatch(blobSeq, commitmentSeq, proofSeq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected batch to verify")
+	}
+
+	if _, err := BlobSequenceFromSlices([][]byte{blobs[0][1:]}); err == nil {
+		t.Fatal("expected partial field element to be rejected")
+	}
+	if _, err := KZGCommitmentSequenceFromSlices([][]byte{commitments[0][1:]}); err == nil {
+		t.Fatal("expected short commitment to be rejected")
+	}
+	if _, err := KZGProofSequenceFromSlices([][]byte{proofs[0][1:]}); err == nil {
+		t.Fatal("expected short proof to be rejected")
+	}
+}
+
+func TestSequencesFromArrays(t *testing.T) {
+	poly := randomPolynomial()
+	var blob [BytesPerBlob]byte
+	copy(blob[:], flattenBlob(polynomialToBlob(poly)))
+	commitment := PolynomialToKZGCommitment(poly)
+	proof, err := ComputeBlobKZGProof((*BlobBytes)(&blob), commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := VerifyBlobKZGProofBatch(BlobSequenceFromArrays([][BytesPerBlob]byte{blob}),
+		KZGCommitmentSequenceFromArrays([][48]byte{commitment}), KZGProofSequenceFromArrays([][48]byte{proof}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected batch to verify")
+	}
+}
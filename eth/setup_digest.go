@@ -0,0 +1,88 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// setupDigestDomain separates the setup digest from other hashes of the same points.
+const setupDigestDomain = "KZG_SETUP_DIGEST_V1"
+
+// SetupInfo describes the trusted setup a context was loaded with, see Context.SetupMetadata.
+type SetupInfo struct {
+	// Name of the setup, e.g. "Ethereum KZG ceremony", empty if unknown, see SetSetupName.
+	Name string
+	// Digest identifies the setup, see Context.SetupDigest.
+	Digest [32]byte
+	// FieldElementsPerBlob is the size of the Lagrange setup, and of the blobs of the context.
+	FieldElementsPerBlob int
+	// NumG1Powers is the size of the monomial G1 setup, zero for verifier-only contexts.
+	NumG1Powers int
+	// NumG2Powers is the number of G2 powers of the setup that were loaded.
+	NumG2Powers int
+}
+
+// SetupDigest returns the sha256 digest of the setup of the context, so that operators can check at runtime that
+// all the nodes of a fleet loaded the identical setup. It is computed on first use, over the number of points
+// and the compressed Lagrange points, in the order of the setup file, followed by the first two compressed G2 points:
+// the points that commitments and their verification depend on. The monomial G1 setup and the other G2 points
+// are not covered, so that a verifier-only context and a full context of the same setup have the same digest.
+func (ctx *Context) SetupDigest() [32]byte {
+	ctx.setupDigestOnce.Do(func() {
+		lagrange := bls.ToCompressedG1Batch(bitReversalPermutation(ctx.setupLagrange), MaxWorkers())
+		h := sha256.New()
+		h.Write([]byte(setupDigestDomain))
+		var count [8]byte
+		binary.BigEndian.PutUint64(count[:], uint64(len(lagrange)))
+		h.Write(count[:])
+		for i := range lagrange {
+			h.Write(lagrange[i][:])
+		}
+		for i := 0; i < 2 && i < len(ctx.setupG2); i++ {
+			h.Write(bls.ToCompressedG2(&ctx.setupG2[i]))
+		}
+		copy(ctx.setupDigest[:], h.Sum(nil))
+	})
+	return ctx.setupDigest
+}
+
+// SetupDigest calls SetupDigest on the default context.
+func SetupDigest() [32]byte {
+	return defaultContext().SetupDigest()
+}
+
+// SetSetupName sets the name of the setup of the context, reported by SetupMetadata, for setups loaded
+// without one, e.g. from a setup image or from JSON without a "name" field. It does not change the digest.
+// It must not be called concurrently with SetupMetadata.
+func (ctx *Context) SetSetupName(name string) {
+	ctx.setupName = name
+}
+
+// SetSetupName calls SetSetupName on a copy of the default context, which then replaces it, see Context.
+func SetSetupName(name string) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetSetupName(name)
+		return nil
+	})
+}
+
+// SetupMetadata returns the name, digest and size of the setup of the context.
+func (ctx *Context) SetupMetadata() SetupInfo {
+	return SetupInfo{
+		Name:                 ctx.setupName,
+		Digest:               ctx.SetupDigest(),
+		FieldElementsPerBlob: ctx.FieldElementsPerBlob(),
+		NumG1Powers:          len(ctx.setupG1),
+		NumG2Powers:          len(ctx.setupG2),
+	}
+}
+
+// SetupMetadata calls SetupMetadata on the default context.
+func SetupMetadata() SetupInfo {
+	return defaultContext().SetupMetadata()
+}
@@ -0,0 +1,50 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	kzg "github.com/protolambda/go-kzg"
+)
+
+func TestSetupDigest(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	s1, s2 := kzg.GenerateTestingSetup("1927409816240961209460912649124", 16)
+	fromSettings, err := NewContextFromSettings(kzg.NewKZGSettings(kzg.NewFFTSettings(4), s1, s2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.SetupDigest() != fromSettings.SetupDigest() {
+		t.Fatal("expected contexts of the same setup to have the same digest")
+	}
+	s1, s2 = kzg.GenerateTestingSetup("1927409816240961209460912649125", 16)
+	other, err := NewContextFromSettings(kzg.NewKZGSettings(kzg.NewFFTSettings(4), s1, s2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.SetupDigest() == other.SetupDigest() {
+		t.Fatal("expected contexts of different setups to have different digests")
+	}
+
+	verifier, err := NewVerifierContextFromJSON([]byte(kzgSetupStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, full := verifier.SetupMetadata(), SetupMetadata()
+	if info.Digest != full.Digest || full.Digest != SetupDigest() {
+		t.Fatal("expected the verifier context to have the digest of the full context")
+	}
+	if info.FieldElementsPerBlob != FieldElementsPerBlob || info.NumG1Powers != 0 || info.NumG2Powers != 2 {
+		t.Fatalf("unexpected verifier setup sizes: %+v", info)
+	}
+	if full.NumG1Powers != FieldElementsPerBlob {
+		t.Fatalf("unexpected setup sizes: %+v", full)
+	}
+
+	verifier.SetSetupName("test setup")
+	if info := verifier.SetupMetadata(); info.Name != "test setup" || info.Digest != full.Digest {
+		t.Fatalf("unexpected metadata after naming the setup: %+v", info)
+	}
+}
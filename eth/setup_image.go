@@ -0,0 +1,169 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Header of a setup memory image: magic (8 bytes), bls.MemoryLayout (48 bytes, zero padded),
+// the number of Lagrange, G1 and G2 points and the length of the setup name (8 bytes each).
+// The points follow, then the name.
+const (
+	setupImageMagic      = "KZGSETUP"
+	setupImageHeaderSize = 88
+)
+
+// WriteSetupImage writes the setup of the context as a raw memory image, see NewContextFromSetupImage.
+// The image can only be read back on a machine with the same bls.MemoryLayout.
+func (ctx *Context) WriteSetupImage(w io.Writer) error {
+	layout := bls.MemoryLayout()
+	if len(layout) > 48 {
+		return fmt.Errorf("memory layout %q is too long", layout)
+	}
+	var header [setupImageHeaderSize]byte
+	copy(header[:8], setupImageMagic)
+	copy(header[8:56], layout)
+	binary.LittleEndian.PutUint64(header[56:64], uint64(len(ctx.setupLagrange)))
+	binary.LittleEndian.PutUint64(header[64:72], uint64(len(ctx.setupG1)))
+	binary.LittleEndian.PutUint64(header[72:80], uint64(len(ctx.setupG2)))
+	binary.LittleEndian.PutUint64(header[80:88], uint64(len(ctx.setupName)))
+	for _, b := range [][]byte{header[:], bls.G1PointsMemory(ctx.setupLagrange),
+		bls.G1PointsMemory(ctx.setupG1), bls.G2PointsMemory(ctx.setupG2), []byte(ctx.setupName)} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSetupImage calls WriteSetupImage on the default context.
+func WriteSetupImage(w io.Writer) error {
+	return defaultContext().WriteSetupImage(w)
+}
+
+// NewContextFromSetupImage creates a context that uses the setup points of an image written by WriteSetupImage
+// in place, without copying, decoding or validating them. This is meant for memory-mapped files, see MapSetupImage.
+// The data must come from trusted storage, must be writable (some backends normalize the bases of an MSM in place),
+// and must not be modified by others or unmapped while the context is in use.
+func NewContextFromSetupImage(data []byte) (*Context, error) {
+	if len(data) < setupImageHeaderSize || string(data[:8]) != setupImageMagic {
+		return nil, errors.New("not a setup memory image")
+	}
+	if layout := string(bytes.TrimRight(data[8:56], "\x00")); layout != bls.MemoryLayout() {
+		return nil, fmt.Errorf("setup memory layout %q does not match %q", layout, bls.MemoryLayout())
+	}
+	g1Size := uint64(len(bls.G1PointsMemory(make([]bls.G1Point, 1))))
+	g2Size := uint64(len(bls.G2PointsMemory(make([]bls.G2Point, 1))))
+	rest := data[setupImageHeaderSize:]
+	var sizes [4]uint64
+	var total uint64
+	for i, size := range [4]uint64{g1Size, g1Size, g2Size, 1} {
+		count := binary.LittleEndian.Uint64(data[56+8*i : 64+8*i])
+		// bounded before multiplying, so that a corrupt count can not overflow into a matching length
+		if count > uint64(len(rest))/size {
+			return nil, fmt.Errorf("setup image count %d does not fit in %d bytes", count, len(rest))
+		}
+		sizes[i] = count * size
+		total += sizes[i]
+	}
+	if uint64(len(rest)) != total {
+		return nil, fmt.Errorf("setup image has %d bytes after the header, expected %d", len(rest), total)
+	}
+	lagrange, err := bls.G1PointsFromMemory(rest[:sizes[0]])
+	if err != nil {
+		return nil, err
+	}
+	rest = rest[sizes[0]:]
+	setupG1, err := bls.G1PointsFromMemory(rest[:sizes[1]])
+	if err != nil {
+		return nil, err
+	}
+	rest = rest[sizes[1]:]
+	setupG2, err := bls.G2PointsFromMemory(rest[:sizes[2]])
+	if err != nil {
+		return nil, err
+	}
+	// the Lagrange points are already in bit-reversed order
+	return newContext(string(rest[sizes[2]:]), setupG1, lagrange, setupG2, nil)
+}
+
+// SaveLagrangeTableImage writes the precomputed Lagrange table as a raw memory image,
+// see bls.G1LinCombTable.WriteMemoryImage. The table must have been built or loaded first.
+func (ctx *Context) SaveLagrangeTableImage(w io.Writer) error {
+	table := ctx.loadLagrangeTable()
+	if table == nil {
+		return fmt.Errorf("%w: lagrange table has not been precomputed", ErrSetupNotLoaded)
+	}
+	_, err := table.WriteMemoryImage(w)
+	return err
+}
+
+// SaveLagrangeTableImage calls SaveLagrangeTableImage on the default context.
+func SaveLagrangeTableImage(w io.Writer) error {
+	return defaultContext().SaveLagrangeTableImage(w)
+}
+
+// UseLagrangeTableImage uses a table image written by SaveLagrangeTableImage in place.
+// Unlike LoadLagrangeTable, the bases are not compared against the setup, since that would read most of the table:
+// the data must come from trusted storage, and must not be modified or unmapped while the context is in use.
+func (ctx *Context) UseLagrangeTableImage(data []byte) error {
+	table, err := bls.G1LinCombTableFromMemoryImage(data)
+	if err != nil {
+		return err
+	}
+	if table.Len() != len(ctx.setupLagrange) {
+		return fmt.Errorf("lagrange table has %d bases, expected %d", table.Len(), len(ctx.setupLagrange))
+	}
+	ctx.storeLagrangeTable(table)
+	return nil
+}
+
+// UseLagrangeTableImage calls UseLagrangeTableImage on the default context.
+func UseLagrangeTableImage(data []byte) error {
+	return defaultContext().UseLagrangeTableImage(data)
+}
+
+// MapSetupImage memory-maps a setup image file written by WriteSetupImage, and creates a context from it,
+// see NewContextFromSetupImage. Pages are read on first use and shared between processes through the page cache.
+// The mapping is private: pages written by the process are copied, and the file is never modified.
+// The returned closer unmaps the file, after which the context must no longer be used.
+// On platforms without mmap support, the file is read into memory instead.
+func MapSetupImage(path string) (*Context, io.Closer, error) {
+	m, err := mapFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, err := NewContextFromSetupImage(m.data)
+	if err != nil {
+		_ = m.Close()
+		return nil, nil, err
+	}
+	return ctx, m, nil
+}
+
+// MapLagrangeTableImage memory-maps a table image file written by SaveLagrangeTableImage, and uses it,
+// see UseLagrangeTableImage. The returned closer unmaps the file, after which the context must no longer be used.
+func (ctx *Context) MapLagrangeTableImage(path string) (io.Closer, error) {
+	m, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.UseLagrangeTableImage(m.data); err != nil {
+		_ = m.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// MapLagrangeTableImage calls MapLagrangeTableImage on the default context.
+func MapLagrangeTableImage(path string) (io.Closer, error) {
+	return defaultContext().MapLagrangeTableImage(path)
+}
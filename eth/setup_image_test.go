@@ -0,0 +1,79 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestMapSetupImage(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	ctx.SetSetupName("test-setup")
+	ctx.PrecomputeLagrangeTable()
+	dir := t.TempDir()
+	setupPath := filepath.Join(dir, "setup.img")
+	tablePath := filepath.Join(dir, "table.img")
+	var setup, table bytes.Buffer
+	if err := ctx.WriteSetupImage(&setup); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.SaveLagrangeTableImage(&table); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(setupPath, setup.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tablePath, table.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mapped, closer, err := MapSetupImage(setupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+	if len(mapped.SetupG1()) != len(ctx.SetupG1()) || len(mapped.SetupG2()) != len(ctx.SetupG2()) {
+		t.Fatal("setup size mismatch")
+	}
+	if name := mapped.SetupMetadata().Name; name != "test-setup" {
+		t.Fatalf("setup name %q was not kept", name)
+	}
+	poly := randomPolynomialN(16)
+	if mapped.PolynomialToKZGCommitment(poly) != ctx.PolynomialToKZGCommitment(poly) {
+		t.Fatal("commitment mismatch with the mapped setup")
+	}
+	tableCloser, err := mapped.MapLagrangeTableImage(tablePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tableCloser.Close()
+	if mapped.PolynomialToKZGCommitment(poly) != ctx.PolynomialToKZGCommitment(poly) {
+		t.Fatal("commitment mismatch with the mapped table")
+	}
+
+	if _, err := NewContextFromSetupImage(setup.Bytes()[:setup.Len()-1]); err == nil {
+		t.Fatal("expected truncated image to fail")
+	}
+	// a count that wraps around to the length of the data when multiplied by the point size
+	overflow := append([]byte(nil), setup.Bytes()...)
+	g1Size := uint64(len(bls.G1PointsMemory(make([]bls.G1Point, 1))))
+	lagrange := binary.LittleEndian.Uint64(overflow[56:64])
+	binary.LittleEndian.PutUint64(overflow[56:64], lagrange+1<<(64-bits.TrailingZeros64(g1Size)))
+	if _, err := NewContextFromSetupImage(overflow); err == nil {
+		t.Fatal("expected overflowing point count to be rejected")
+	}
+	if _, err := NewContextFromSetupImage(table.Bytes()); err == nil {
+		t.Fatal("expected table image to be rejected as setup")
+	}
+	if err := newTestContext(t, 3).UseLagrangeTableImage(table.Bytes()); err == nil {
+		t.Fatal("expected table of another size to be rejected")
+	}
+}
@@ -0,0 +1,106 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+)
+
+// SpecVersion selects which proofs the version-generic functions of a context use: ComputeBlobProofs and
+// VerifyBlobProofs. The version-specific functions are always available, so that objects of different forks
+// can be verified in one process, e.g. with one context per fork.
+type SpecVersion uint8
+
+const (
+	// SpecDeneb uses one proof per blob, from the Deneb consensus specs. This is the default.
+	SpecDeneb SpecVersion = iota
+	// SpecEIP4844Aggregate uses a single aggregate proof for all the blobs, from the earlier EIP-4844 consensus specs.
+	SpecEIP4844Aggregate
+)
+
+func (v SpecVersion) String() string {
+	switch v {
+	case SpecDeneb:
+		return "deneb"
+	case SpecEIP4844Aggregate:
+		return "eip4844_aggregate"
+	default:
+		return fmt.Sprintf("SpecVersion(%d)", uint8(v))
+	}
+}
+
+// SetSpecVersion selects the spec version of the version-generic functions of the context.
+func (ctx *Context) SetSpecVersion(v SpecVersion) error {
+	switch v {
+	case SpecDeneb, SpecEIP4844Aggregate:
+		ctx.specVersion = v
+		return nil
+	default:
+		return fmt.Errorf("unknown spec version %v", v)
+	}
+}
+
+// SetSpecVersion calls SetSpecVersion on a copy of the default context, which then replaces it, see Context.
+func SetSpecVersion(v SpecVersion) error {
+	return updateDefaultContext(func(ctx *Context) error {
+		return ctx.SetSpecVersion(v)
+	})
+}
+
+// SpecVersion returns the spec version of the context, see SetSpecVersion.
+func (ctx *Context) SpecVersion() SpecVersion {
+	return ctx.specVersion
+}
+
+// ComputeBlobProofs computes the proofs for the blobs, as specified by the spec version of the context:
+// one proof per blob with SpecDeneb, or a single aggregate proof with SpecEIP4844Aggregate.
+func (ctx *Context) ComputeBlobProofs(blobs BlobSequence, commitments KZGCommitmentSequence) (KZGProofSequenceImpl, error) {
+	if blobs.Len() != commitments.Len() {
+		return nil, fmt.Errorf("%w: %d blobs, %d commitments", ErrLengthMismatch, blobs.Len(), commitments.Len())
+	}
+	if err := ctx.checkBlobLimit(blobs.Len()); err != nil {
+		return nil, err
+	}
+	switch ctx.specVersion {
+	case SpecEIP4844Aggregate:
+		proof, err := ctx.ComputeAggregateKZGProof(blobs)
+		if err != nil {
+			return nil, err
+		}
+		return KZGProofSequenceImpl{proof}, nil
+	default:
+		proofs := make(KZGProofSequenceImpl, blobs.Len())
+		for i := range proofs {
+			proof, err := ctx.ComputeBlobKZGProof(blobs.At(i), commitments.At(i))
+			if err != nil {
+				return nil, fmt.Errorf("blob %d: %w", i, err)
+			}
+			proofs[i] = proof
+		}
+		return proofs, nil
+	}
+}
+
+// ComputeBlobProofs calls ComputeBlobProofs on the default context.
+func ComputeBlobProofs(blobs BlobSequence, commitments KZGCommitmentSequence) (KZGProofSequenceImpl, error) {
+	return defaultContext().ComputeBlobProofs(blobs, commitments)
+}
+
+// VerifyBlobProofs verifies proofs computed by ComputeBlobProofs with the same spec version.
+func (ctx *Context) VerifyBlobProofs(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (bool, error) {
+	switch ctx.specVersion {
+	case SpecEIP4844Aggregate:
+		if proofs.Len() != 1 {
+			return false, fmt.Errorf("expected a single aggregate proof, got %d proofs", proofs.Len())
+		}
+		return ctx.VerifyAggregateKZGProof(blobs, commitments, proofs.At(0))
+	default:
+		return ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	}
+}
+
+// VerifyBlobProofs calls VerifyBlobProofs on the default context.
+func VerifyBlobProofs(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (bool, error) {
+	return defaultContext().VerifyBlobProofs(blobs, commitments, proofs)
+}
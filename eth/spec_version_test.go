@@ -0,0 +1,56 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+)
+
+func TestSpecVersions(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		blobs = append(blobs, polynomialToBlob(poly))
+		commitments = append(commitments, ctx.PolynomialToKZGCommitment(poly))
+	}
+	for _, v := range []SpecVersion{SpecDeneb, SpecEIP4844Aggregate} {
+		if err := ctx.SetSpecVersion(v); err != nil {
+			t.Fatal(err)
+		}
+		proofs, err := ctx.ComputeBlobProofs(blobs, commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedProofs := len(blobs)
+		if v == SpecEIP4844Aggregate {
+			expectedProofs = 1
+		}
+		if len(proofs) != expectedProofs {
+			t.Fatalf("%v: expected %d proofs, got %d", v, expectedProofs, len(proofs))
+		}
+		ok, err := ctx.VerifyBlobProofs(blobs, commitments, proofs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("%v: expected proofs to verify", v)
+		}
+		// the proofs of one version are not valid in the other
+		other := SpecDeneb
+		if v == SpecDeneb {
+			other = SpecEIP4844Aggregate
+		}
+		if err := ctx.SetSpecVersion(other); err != nil {
+			t.Fatal(err)
+		}
+		if ok, _ := ctx.VerifyBlobProofs(blobs, commitments, proofs); ok {
+			t.Fatalf("%v: expected proofs not to verify as %v", v, other)
+		}
+	}
+	if err := ctx.SetSpecVersion(SpecVersion(42)); err == nil {
+		t.Fatal("expected unknown spec version to be rejected")
+	}
+}
@@ -0,0 +1,224 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	kzg "github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Domain separator of the challenge of sub-blob proofs, see ComputeSubBlobCommitment.
+const subBlobChallengeDomain = "GO_KZG_SUB_BLOB_V1"
+
+// SubBlobProof links the commitment to the window [Start, End) of the field elements of a blob,
+// see SubBlob, to the commitment to the blob.
+type SubBlobProof struct {
+	Start int
+	End   int
+	// Quotient is the commitment to (P - S) / Z, for the polynomials P of the blob and S of the window,
+	// and the vanishing polynomial Z of the domain points of the window.
+	Quotient KZGCommitment
+	// Proof is the opening of P - S - Z(r) * Quotient to zero at the challenge r.
+	Proof KZGProof
+}
+
+// checkSubBlobWindow checks that the window [start, end) is a non-empty range of the field elements of a blob.
+func (ctx *Context) checkSubBlobWindow(start, end int) error {
+	if start < 0 || end <= start || end > ctx.FieldElementsPerBlob() {
+		return fmt.Errorf("invalid window [%d, %d) of a blob of %d field elements", start, end, ctx.FieldElementsPerBlob())
+	}
+	return nil
+}
+
+// SubBlob returns the window [start, end) of the field elements of the blob, as a blob of its own: the elements of
+// the window at their positions in the blob, and zeros elsewhere. Its commitment is the sub-commitment of the window,
+// which a tenant of the window can compute from its data alone, with BlobToKZGCommitment.
+func (ctx *Context) SubBlob(blob Blob, start, end int) (Blob, error) {
+	if err := ctx.checkSubBlobWindow(start, end); err != nil {
+		return nil, err
+	}
+	if blob.Len() != ctx.FieldElementsPerBlob() {
+		return nil, fmt.Errorf("%w: blob has %d field elements, expected %d", ErrWrongBlobLength, blob.Len(), ctx.FieldElementsPerBlob())
+	}
+	out := make(blobSlice, blob.Len()*32)
+	for i := start; i < end; i++ {
+		v := blob.At(i)
+		copy(out[i*32:], v[:])
+	}
+	return out, nil
+}
+
+// SubBlob calls SubBlob on the default context.
+func SubBlob(blob Blob, start, end int) (Blob, error) {
+	return defaultContext().SubBlob(blob, start, end)
+}
+
+// ComputeSubBlobCommitment commits to the window [start, end) of the field elements of the blob, as its own polynomial
+// (see SubBlob), and proves that the blob holds the same field elements in the window, e.g. for rollups sharing a blob,
+// so that each of them has a commitment to its own data, independently verifiable, and linked to the blob commitment.
+//
+// The polynomial P of the blob and S of the window agree on the domain points of the window, so P - S is divisible
+// by their vanishing polynomial Z: the proof commits to the quotient Q, and shows P - S = Z * Q at a random point r,
+// derived from the three commitments and the window, with a single opening of P - S - Z(r) * Q to zero at r.
+// The quotient is computed over a coset of the domain, with FFTs, and committed to in evaluation form,
+// so that only the Lagrange setup is needed.
+func (ctx *Context) ComputeSubBlobCommitment(blob Blob, start, end int) (KZGCommitment, *SubBlobProof, error) {
+	sub, err := ctx.SubBlob(blob, start, end)
+	if err != nil {
+		return KZGCommitment{}, nil, err
+	}
+	poly, ok := BlobToPolynomial(blob)
+	if !ok {
+		return KZGCommitment{}, nil, ctx.blobError(blob)
+	}
+	subPoly, _ := BlobToPolynomial(sub)
+	n := len(poly)
+
+	// D = P - S, which is P outside of the window and zero in it
+	diff := make(Polynomial, n)
+	for i := range diff {
+		if i >= start && i < end {
+			bls.CopyFr(&diff[i], &bls.ZERO)
+		} else {
+			bls.CopyFr(&diff[i], &poly[i])
+		}
+	}
+	quotient, err := ctx.subBlobQuotient(diff, ctx.domain[start:end])
+	if err != nil {
+		return KZGCommitment{}, nil, err
+	}
+
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	subCommitment := ctx.PolynomialToKZGCommitment(subPoly)
+	proof := &SubBlobProof{Start: start, End: end, Quotient: ctx.PolynomialToKZGCommitment(quotient)}
+	r := ctx.subBlobChallenge(commitment, subCommitment, proof)
+	zr := subBlobVanishingEval(ctx.domain[start:end], r)
+
+	// L = D - Z(r) * Q, which is zero at r
+	var tmp bls.Fr
+	for i := range diff {
+		bls.MulModFr(&tmp, zr, &quotient[i])
+		bls.SubModFr(&diff[i], &diff[i], &tmp)
+	}
+	proof.Proof, _ = ctx.computeKZGProof(newProverScratch(n), diff, r)
+	return subCommitment, proof, nil
+}
+
+// ComputeSubBlobCommitment calls ComputeSubBlobCommitment on the default context.
+func ComputeSubBlobCommitment(blob Blob, start, end int) (KZGCommitment, *SubBlobProof, error) {
+	return defaultContext().ComputeSubBlobCommitment(blob, start, end)
+}
+
+// subBlobQuotient divides the polynomial in evaluation form, zero at the given domain points, by their vanishing
+// polynomial, and returns the quotient in evaluation form. The division is over the coset of the domain by 5,
+// where the vanishing polynomial has no root.
+func (ctx *Context) subBlobQuotient(diff Polynomial, points []bls.Fr) (Polynomial, error) {
+	n := len(diff)
+	out := make(Polynomial, n)
+	if len(points) == n {
+		// the difference vanishes over the whole domain, so it is zero
+		for i := range out {
+			bls.CopyFr(&out[i], &bls.ZERO)
+		}
+		return out, nil
+	}
+	fs := ctx.fftSettings()
+	coeffs, err := ctx.PolynomialToCoefficients(diff)
+	if err != nil {
+		return nil, err
+	}
+	vanishing := make([]bls.Fr, n)
+	for i := range vanishing {
+		bls.CopyFr(&vanishing[i], &bls.ZERO)
+	}
+	copy(vanishing, kzg.VanishingPolynomial(points))
+
+	var shift bls.Fr
+	bls.AsFr(&shift, 5)
+	if err := fs.CosetFFTInPlace(coeffs, &shift, false); err != nil {
+		return nil, err
+	}
+	if err := fs.CosetFFTInPlace(vanishing, &shift, false); err != nil {
+		return nil, err
+	}
+	bls.BatchInvModFr(vanishing, vanishing)
+	for i := range coeffs {
+		bls.MulModFr(&coeffs[i], &coeffs[i], &vanishing[i])
+	}
+	if err := fs.CosetFFTInPlace(coeffs, &shift, true); err != nil {
+		return nil, err
+	}
+	if err := fs.FFTInPlace(coeffs, false); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		bls.CopyFr(&out[i], &coeffs[reverseBits(uint64(i), uint64(n))])
+	}
+	return out, nil
+}
+
+// subBlobVanishingEval evaluates the vanishing polynomial of the points at r.
+func subBlobVanishingEval(points []bls.Fr, r *bls.Fr) *bls.Fr {
+	var out, tmp bls.Fr
+	bls.CopyFr(&out, &bls.ONE)
+	for i := range points {
+		bls.SubModFr(&tmp, r, &points[i])
+		bls.MulModFr(&out, &out, &tmp)
+	}
+	return &out
+}
+
+// subBlobChallenge derives the challenge of a sub-blob proof from the commitments and the window.
+func (ctx *Context) subBlobChallenge(commitment, subCommitment KZGCommitment, proof *SubBlobProof) *bls.Fr {
+	h := ctx.NewTranscript(subBlobChallengeDomain)
+	var window [16]byte
+	binary.BigEndian.PutUint64(window[:8], uint64(proof.Start))
+	binary.BigEndian.PutUint64(window[8:], uint64(proof.End))
+	h.AppendBytes("window", window[:])
+	h.AppendBytes("commitment", commitment[:])
+	h.AppendBytes("sub_commitment", subCommitment[:])
+	h.AppendBytes("quotient", proof.Quotient[:])
+	return h.ChallengeFr()
+}
+
+// VerifySubBlobCommitment verifies that the sub-commitment commits to the window of the proof of the blob
+// of the commitment, as computed by ComputeSubBlobCommitment. It costs a single pairing check,
+// and a product over the field elements of the window.
+func (ctx *Context) VerifySubBlobCommitment(commitment, subCommitment KZGCommitment, proof *SubBlobProof) (bool, error) {
+	if err := ctx.checkSubBlobWindow(proof.Start, proof.End); err != nil {
+		return false, err
+	}
+	commitmentG1, err := ctx.decodeCommitment(commitment)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	subCommitmentG1, err := ctx.decodeCommitment(subCommitment)
+	if err != nil {
+		return false, fmt.Errorf("%w: sub-commitment: %v", ErrInvalidCommitment, err)
+	}
+	quotientG1, err := ctx.decodeCommitment(proof.Quotient)
+	if err != nil {
+		return false, fmt.Errorf("%w: quotient: %v", ErrMalformedProof, err)
+	}
+	proofG1, err := ctx.decodeProof(proof.Proof)
+	if err != nil {
+		return false, err
+	}
+	r := ctx.subBlobChallenge(commitment, subCommitment, proof)
+	zr := subBlobVanishingEval(ctx.domain[proof.Start:proof.End], r)
+
+	var combined, scaled bls.G1Point
+	bls.SubG1(&combined, commitmentG1, subCommitmentG1)
+	bls.MulG1(&scaled, quotientG1, zr)
+	bls.SubG1(&combined, &combined, &scaled)
+	return ctx.VerifyKZGProofFromPoints(&combined, r, &bls.ZERO, proofG1), nil
+}
+
+// VerifySubBlobCommitment calls VerifySubBlobCommitment on the default context.
+func VerifySubBlobCommitment(commitment, subCommitment KZGCommitment, proof *SubBlobProof) (bool, error) {
+	return defaultContext().VerifySubBlobCommitment(commitment, subCommitment, proof)
+}
@@ -0,0 +1,84 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestSubBlobCommitment(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	blob := polynomialToBlob(poly)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+
+	for _, w := range []struct{ start, end int }{{0, 16}, {0, 1}, {3, 9}, {8, 16}, {15, 16}} {
+		subCommitment, proof, err := ctx.ComputeSubBlobCommitment(blob, w.start, w.end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the tenant recomputes the sub-commitment from the data of its window
+		sub, err := ctx.SubBlob(blob, w.start, w.end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		subPoly, _ := BlobToPolynomial(sub)
+		if got := ctx.PolynomialToKZGCommitment(subPoly); got != subCommitment {
+			t.Fatalf("window [%d, %d): sub-commitment does not commit to the sub-blob", w.start, w.end)
+		}
+		ok, err := ctx.VerifySubBlobCommitment(commitment, subCommitment, proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("window [%d, %d): expected sub-blob proof to verify", w.start, w.end)
+		}
+	}
+
+	subCommitment, proof, err := ctx.ComputeSubBlobCommitment(blob, 3, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a window of other data
+	other := copyPolynomial(poly)
+	bls.CopyFr(&other[5], bls.RandomFr())
+	otherCommitment, _, err := ctx.ComputeSubBlobCommitment(polynomialToBlob(other), 3, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ctx.VerifySubBlobCommitment(commitment, otherCommitment, proof); err != nil || ok {
+		t.Fatalf("expected the proof not to verify another sub-commitment, got %v, %v", ok, err)
+	}
+	// another window
+	moved := *proof
+	moved.Start, moved.End = 2, 8
+	if ok, err := ctx.VerifySubBlobCommitment(commitment, subCommitment, &moved); err != nil || ok {
+		t.Fatalf("expected the proof not to verify another window, got %v, %v", ok, err)
+	}
+	// another blob, with the same window
+	outside := copyPolynomial(poly)
+	bls.CopyFr(&outside[12], bls.RandomFr())
+	outsideCommitment := ctx.PolynomialToKZGCommitment(outside)
+	if ok, err := ctx.VerifySubBlobCommitment(outsideCommitment, subCommitment, proof); err != nil || ok {
+		t.Fatalf("expected the proof not to verify another blob, got %v, %v", ok, err)
+	}
+	sameCommitment, outsideProof, err := ctx.ComputeSubBlobCommitment(polynomialToBlob(outside), 3, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sameCommitment != subCommitment {
+		t.Fatal("expected the sub-commitment to only depend on the window")
+	}
+	if ok, err := ctx.VerifySubBlobCommitment(outsideCommitment, subCommitment, outsideProof); err != nil || !ok {
+		t.Fatalf("expected the proof of the other blob to verify, got %v, %v", ok, err)
+	}
+
+	for _, w := range []struct{ start, end int }{{-1, 3}, {4, 4}, {5, 3}, {0, 17}} {
+		if _, _, err := ctx.ComputeSubBlobCommitment(blob, w.start, w.end); err == nil {
+			t.Errorf("window [%d, %d): expected an error", w.start, w.end)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package testvectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/protolambda/go-kzg/bls"
+	"github.com/protolambda/go-kzg/eth"
+)
+
+// Output formats of WriteTestCases: data.json, as LoadTestCases reads it,
+// and data.yaml, as in the consensus-spec-tests.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// generator builds test cases, with the outputs computed by the context.
+type generator struct {
+	ctx   *eth.Context
+	cases []*TestCase
+	err   error
+}
+
+// add adds a test case of the handler and returns its output. valid tells whether the operation is expected to
+// succeed on the input, and the generation fails if it does not, so that the invalid-input cases are known to
+// cover the failure they are named after, and the valid ones do not silently become failure cases.
+func (g *generator) add(handler, name string, input interface{}, valid bool) interface{} {
+	if g.err != nil {
+		return nil
+	}
+	run, err := runner(handler)
+	if err != nil {
+		g.err = err
+		return nil
+	}
+	tc := &TestCase{Handler: handler, Name: name}
+	if tc.Input, err = json.Marshal(input); err != nil {
+		g.err = err
+		return nil
+	}
+	out, err := run(g.ctx, tc.Input)
+	if valid != (err == nil) {
+		g.err = fmt.Errorf("%s/%s: expected valid input: %v, got error: %v", handler, name, valid, err)
+		return nil
+	}
+	if err != nil {
+		// the operations may return a value with their error, the fixtures do not
+		out = nil
+	}
+	if tc.Output, err = json.Marshal(out); err != nil {
+		g.err = err
+		return nil
+	}
+	g.cases = append(g.cases, tc)
+	return out
+}
+
+// namedValue is a test input, encoded as in the fixtures.
+type namedValue struct {
+	name  string
+	value string
+}
+
+// Generate generates test vectors for all the handlers, with valid and invalid inputs, and the outputs computed
+// by the context: the results of the operations, or null where they are expected to fail. The random inputs
+// are derived from the seed, so that a generation can be reproduced. The cases are sorted by handler and name,
+// as LoadTestCases sorts them.
+func Generate(ctx *eth.Context, seed int64) ([]*TestCase, error) {
+	g := &generator{ctx: ctx}
+	rng := rand.New(rand.NewSource(seed))
+	n := ctx.FieldElementsPerBlob()
+
+	randomScalar := func() [32]byte {
+		var v [32]byte
+		rng.Read(v[1:])
+		return v
+	}
+	blobOf := func(element func(i int) [32]byte) string {
+		b := make([]byte, n*32)
+		for i := 0; i < n; i++ {
+			v := element(i)
+			copy(b[i*32:], v[:])
+		}
+		return encodeHex(b)
+	}
+	zero := [32]byte{}
+	one := bls.FrTo32BE(&bls.ONE)
+	maxScalar := bls.FrTo32BE(&bls.MODULUS_MINUS1)
+	modulus := maxScalar
+	modulus[31]++
+
+	blobs := []namedValue{
+		{"blob_zero", blobOf(func(int) [32]byte { return zero })},
+		{"blob_max", blobOf(func(int) [32]byte { return maxScalar })},
+	}
+	for i := 0; i < 3; i++ {
+		blobs = append(blobs, namedValue{fmt.Sprintf("blob_random_%d", i), blobOf(func(int) [32]byte { return randomScalar() })})
+	}
+	invalidBlobs := []namedValue{
+		{"blob_non_canonical", blobOf(func(i int) [32]byte {
+			if i == 1 {
+				return modulus
+			}
+			return randomScalar()
+		})},
+		{"blob_invalid_length", blobs[2].value[:len(blobs[2].value)-2]},
+	}
+	rootOfUnity := bls.FrTo32BE(&ctx.Domain()[1])
+	randomZ := randomScalar()
+	zs := []namedValue{
+		{"z_zero", encodeHex(zero[:])},
+		{"z_one", encodeHex(one[:])},
+		{"z_max", encodeHex(maxScalar[:])},
+		{"z_root_of_unity", encodeHex(rootOfUnity[:])},
+		{"z_random", encodeHex(randomZ[:])},
+	}
+	invalidScalars := []namedValue{
+		{"non_canonical", encodeHex(modulus[:])},
+		{"invalid_length", encodeHex(one[1:])},
+	}
+
+	commitments := make([]string, len(blobs))
+	for i, blob := range blobs {
+		out := g.add(BlobToKZGCommitment, "valid_"+blob.name, map[string]string{"blob": blob.value}, true)
+		commitments[i], _ = out.(string)
+	}
+	for _, blob := range invalidBlobs {
+		g.add(BlobToKZGCommitment, "invalid_"+blob.name, map[string]string{"blob": blob.value}, false)
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+	invalidG1s := []namedValue{
+		{"point_not_in_g1", notInG1(commitments[2])},
+		{"point_infinity_with_bits", "0xc0" + strings.Repeat("00", 46) + "01"},
+		{"point_invalid_length", commitments[2][:len(commitments[2])-2]},
+	}
+
+	// compute_kzg_proof, and verify_kzg_proof with the proofs
+	type opening struct{ commitment, z, y, proof string }
+	var openings []opening
+	for i, blob := range blobs {
+		for _, z := range zs {
+			out := g.add(ComputeKZGProof, fmt.Sprintf("valid_%s_%s", blob.name, z.name),
+				map[string]string{"blob": blob.value, "z": z.value}, true)
+			if proofAndY, ok := out.([]string); ok {
+				openings = append(openings, opening{commitments[i], z.value, proofAndY[1], proofAndY[0]})
+				g.add(VerifyKZGProof, fmt.Sprintf("correct_proof_%s_%s", blob.name, z.name), map[string]string{
+					"commitment": commitments[i], "z": z.value, "y": proofAndY[1], "proof": proofAndY[0],
+				}, true)
+			}
+		}
+	}
+	for _, blob := range invalidBlobs {
+		g.add(ComputeKZGProof, "invalid_"+blob.name, map[string]string{"blob": blob.value, "z": zs[4].value}, false)
+	}
+	for _, z := range invalidScalars {
+		g.add(ComputeKZGProof, "invalid_z_"+z.name, map[string]string{"blob": blobs[2].value, "z": z.value}, false)
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	// verify_kzg_proof with wrong or invalid inputs, from the opening of the random z of the first random blob
+	o := openings[len(zs)*2+4]
+	other := openings[len(zs)*3+4]
+	verifyInput := func(commitment, z, y, proof string) map[string]string {
+		return map[string]string{"commitment": commitment, "z": z, "y": y, "proof": proof}
+	}
+	g.add(VerifyKZGProof, "incorrect_proof", verifyInput(o.commitment, o.z, o.y, other.proof), true)
+	g.add(VerifyKZGProof, "incorrect_y", verifyInput(o.commitment, o.z, other.y, o.proof), true)
+	g.add(VerifyKZGProof, "incorrect_commitment", verifyInput(other.commitment, o.z, o.y, o.proof), true)
+	for _, p := range invalidG1s {
+		g.add(VerifyKZGProof, "invalid_commitment_"+p.name, verifyInput(p.value, o.z, o.y, o.proof), false)
+		g.add(VerifyKZGProof, "invalid_proof_"+p.name, verifyInput(o.commitment, o.z, o.y, p.value), false)
+	}
+	for _, s := range invalidScalars {
+		g.add(VerifyKZGProof, "invalid_z_"+s.name, verifyInput(o.commitment, s.value, o.y, o.proof), false)
+		g.add(VerifyKZGProof, "invalid_y_"+s.name, verifyInput(o.commitment, o.z, s.value, o.proof), false)
+	}
+
+	// compute_blob_kzg_proof, and verify_blob_kzg_proof with the proofs
+	blobProofs := make([]string, len(blobs))
+	for i, blob := range blobs {
+		out := g.add(ComputeBlobKZGProof, "valid_"+blob.name,
+			map[string]string{"blob": blob.value, "commitment": commitments[i]}, true)
+		blobProofs[i], _ = out.(string)
+		g.add(VerifyBlobKZGProof, "correct_proof_"+blob.name, map[string]string{
+			"blob": blob.value, "commitment": commitments[i], "proof": blobProofs[i],
+		}, true)
+	}
+	for _, blob := range invalidBlobs {
+		g.add(ComputeBlobKZGProof, "invalid_"+blob.name,
+			map[string]string{"blob": blob.value, "commitment": commitments[2]}, false)
+		g.add(VerifyBlobKZGProof, "invalid_"+blob.name, map[string]string{
+			"blob": blob.value, "commitment": commitments[2], "proof": blobProofs[2],
+		}, false)
+	}
+	for _, p := range invalidG1s {
+		g.add(ComputeBlobKZGProof, "invalid_commitment_"+p.name,
+			map[string]string{"blob": blobs[2].value, "commitment": p.value}, false)
+		g.add(VerifyBlobKZGProof, "invalid_commitment_"+p.name, map[string]string{
+			"blob": blobs[2].value, "commitment": p.value, "proof": blobProofs[2],
+		}, false)
+		g.add(VerifyBlobKZGProof, "invalid_proof_"+p.name, map[string]string{
+			"blob": blobs[2].value, "commitment": commitments[2], "proof": p.value,
+		}, false)
+	}
+	g.add(VerifyBlobKZGProof, "incorrect_proof", map[string]string{
+		"blob": blobs[2].value, "commitment": commitments[2], "proof": blobProofs[3],
+	}, true)
+
+	// verify_blob_kzg_proof_batch
+	batchInput := func(blobValues, commitments, proofs []string) map[string][]string {
+		return map[string][]string{"blobs": blobValues, "commitments": commitments, "proofs": proofs}
+	}
+	blobValues := make([]string, len(blobs))
+	for i := range blobs {
+		blobValues[i] = blobs[i].value
+	}
+	for count := 0; count <= len(blobs); count++ {
+		g.add(VerifyBlobKZGProofBatch, fmt.Sprintf("correct_proofs_%d", count),
+			batchInput(blobValues[:count], commitments[:count], blobProofs[:count]), true)
+	}
+	swapped := append([]string{}, blobProofs...)
+	swapped[2], swapped[3] = swapped[3], swapped[2]
+	g.add(VerifyBlobKZGProofBatch, "incorrect_proof", batchInput(blobValues, commitments, swapped), true)
+	for _, blob := range invalidBlobs {
+		values := append([]string{}, blobValues...)
+		values[2] = blob.value
+		g.add(VerifyBlobKZGProofBatch, "invalid_"+blob.name, batchInput(values, commitments, blobProofs), false)
+	}
+	for _, p := range invalidG1s {
+		values := append([]string{}, commitments...)
+		values[2] = p.value
+		g.add(VerifyBlobKZGProofBatch, "invalid_commitment_"+p.name, batchInput(blobValues, values, blobProofs), false)
+		values = append([]string{}, blobProofs...)
+		values[2] = p.value
+		g.add(VerifyBlobKZGProofBatch, "invalid_proof_"+p.name, batchInput(blobValues, commitments, values), false)
+	}
+	g.add(VerifyBlobKZGProofBatch, "length_mismatch",
+		batchInput(blobValues, commitments[:len(blobs)-1], blobProofs), false)
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	sort.Slice(g.cases, func(i, j int) bool {
+		if g.cases[i].Handler != g.cases[j].Handler {
+			return g.cases[i].Handler < g.cases[j].Handler
+		}
+		return g.cases[i].Name < g.cases[j].Name
+	})
+	return g.cases, nil
+}
+
+// notInG1 returns a compressed G1 point of the same flags as the given one, with an x coordinate of no point
+// of G1: most x coordinates are not, so the first few changes of the last byte find one.
+func notInG1(point string) string {
+	b, _ := decodeHex(point, 48)
+	for {
+		b[47]++
+		if _, err := bls.FromCompressedG1(b); err != nil {
+			return encodeHex(b)
+		}
+	}
+}
+
+// WriteTestCases writes the test cases to <dir>/<handler>/<suite>/<case>/, the layout of the consensus-spec-tests
+// that LoadTestCases reads, in each of the given formats. The suite is e.g. "kzg-mainnet".
+func WriteTestCases(dir, suite string, cases []*TestCase, formats ...string) error {
+	for _, format := range formats {
+		if format != FormatJSON && format != FormatYAML {
+			return fmt.Errorf("unsupported format %q", format)
+		}
+	}
+	for _, tc := range cases {
+		caseDir := filepath.Join(dir, tc.Handler, suite, tc.Name)
+		if err := os.MkdirAll(caseDir, 0o755); err != nil {
+			return err
+		}
+		for _, format := range formats {
+			var data []byte
+			var err error
+			if format == FormatJSON {
+				data, err = json.MarshalIndent(tc, "", "  ")
+			} else {
+				data, err = tc.yaml()
+			}
+			if err != nil {
+				return fmt.Errorf("%s/%s: %v", tc.Handler, tc.Name, err)
+			}
+			if err := os.WriteFile(filepath.Join(caseDir, "data."+format), append(data, '\n'), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// yaml encodes the test case in the YAML of the consensus-spec-tests: the input is a map of hex strings
+// or lists of hex strings, and the output a hex string, a list of them, a boolean or null.
+func (tc *TestCase) yaml() ([]byte, error) {
+	var input map[string]interface{}
+	if err := json.Unmarshal(tc.Input, &input); err != nil {
+		return nil, err
+	}
+	var output interface{}
+	if len(tc.Output) != 0 {
+		if err := json.Unmarshal(tc.Output, &output); err != nil {
+			return nil, err
+		}
+	}
+	keys := make([]string, 0, len(input))
+	for k := range input {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("input:\n")
+	for _, k := range keys {
+		if err := writeYAMLValue(&b, "  "+k+":", input[k]); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeYAMLValue(&b, "output:", output); err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimSuffix(b.String(), "\n")), nil
+}
+
+func writeYAMLValue(b *strings.Builder, key string, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		fmt.Fprintf(b, "%s null\n", key)
+	case bool:
+		fmt.Fprintf(b, "%s %v\n", key, v)
+	case string:
+		fmt.Fprintf(b, "%s '%s'\n", key, v)
+	case []interface{}:
+		if len(v) == 0 {
+			fmt.Fprintf(b, "%s []\n", key)
+			return nil
+		}
+		fmt.Fprintf(b, "%s\n", key)
+		indent := key[:len(key)-len(strings.TrimLeft(key, " "))]
+		for _, elem := range v {
+			s, ok := elem.(string)
+			if !ok {
+				return fmt.Errorf("unsupported list element %v", elem)
+			}
+			fmt.Fprintf(b, "%s- '%s'\n", indent, s)
+		}
+	default:
+		return fmt.Errorf("unsupported value %v", v)
+	}
+	return nil
+}
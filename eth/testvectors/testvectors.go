@@ -0,0 +1,378 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+// Package testvectors loads and runs the KZG reference test vectors of the consensus-spec-tests
+// (general/deneb/kzg) and c-kzg-4844 (tests/) repositories against an eth.Context.
+//
+// The upstream fixtures are data.yaml files. Since this module does not depend on a YAML parser,
+// they must be converted to JSON first (e.g. with `yq -o=json`), and saved as data.json next to the originals.
+// Blobs and scalars in the fixtures are big-endian, as in the Deneb spec, and are converted to the
+// little-endian encoding of the eth package by the runner.
+//
+// The vectors are generated with the mainnet trusted setup, so the context must be loaded with that setup.
+//
+// Generate and WriteTestCases produce fresh vectors in the same layout, with the outputs computed by this module,
+// see cmd/genvectors.
+package testvectors
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/protolambda/go-kzg/bls"
+	"github.com/protolambda/go-kzg/eth"
+)
+
+// Handler names of the supported test vectors.
+const (
+	BlobToKZGCommitment     = "blob_to_kzg_commitment"
+	ComputeKZGProof         = "compute_kzg_proof"
+	ComputeBlobKZGProof     = "compute_blob_kzg_proof"
+	VerifyKZGProof          = "verify_kzg_proof"
+	VerifyBlobKZGProof      = "verify_blob_kzg_proof"
+	VerifyBlobKZGProofBatch = "verify_blob_kzg_proof_batch"
+)
+
+// TestCase is a single test vector. A null Output means that the operation is expected to fail.
+type TestCase struct {
+	Handler string          `json:"-"`
+	Name    string          `json:"-"`
+	Input   json.RawMessage `json:"input"`
+	Output  json.RawMessage `json:"output"`
+}
+
+// LoadTestCases walks dir for <handler>/<suite>/<case>/data.json fixtures and parses them,
+// sorted by handler and name.
+func LoadTestCases(dir string) ([]*TestCase, error) {
+	var cases []*TestCase
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != "data.json" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var tc TestCase
+		if err := json.Unmarshal(data, &tc); err != nil {
+			return fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		caseDir := filepath.Dir(path)
+		tc.Name = filepath.Base(caseDir)
+		tc.Handler = filepath.Base(filepath.Dir(filepath.Dir(caseDir)))
+		cases = append(cases, &tc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		if cases[i].Handler != cases[j].Handler {
+			return cases[i].Handler < cases[j].Handler
+		}
+		return cases[i].Name < cases[j].Name
+	})
+	return cases, nil
+}
+
+// runner returns the function running the test cases of the handler, which returns the output of a case.
+func runner(handler string) (func(ctx *eth.Context, input json.RawMessage) (interface{}, error), error) {
+	switch handler {
+	case BlobToKZGCommitment:
+		return runBlobToKZGCommitment, nil
+	case ComputeKZGProof:
+		return runComputeKZGProof, nil
+	case ComputeBlobKZGProof:
+		return runComputeBlobKZGProof, nil
+	case VerifyKZGProof:
+		return runVerifyKZGProof, nil
+	case VerifyBlobKZGProof:
+		return runVerifyBlobKZGProof, nil
+	case VerifyBlobKZGProofBatch:
+		return runVerifyBlobKZGProofBatch, nil
+	default:
+		return nil, fmt.Errorf("unsupported handler %q", handler)
+	}
+}
+
+// Run executes the test case against the context, and returns an error if the result does not match the expected output.
+func (tc *TestCase) Run(ctx *eth.Context) error {
+	run, err := runner(tc.Handler)
+	if err != nil {
+		return err
+	}
+	out, err := run(ctx, tc.Input)
+	if len(tc.Output) == 0 || string(tc.Output) == "null" {
+		if err == nil {
+			return fmt.Errorf("%s/%s: expected failure, got %v", tc.Handler, tc.Name, out)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s/%s: unexpected error: %v", tc.Handler, tc.Name, err)
+	}
+	got, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	if !jsonEqual(got, tc.Output) {
+		return fmt.Errorf("%s/%s: expected %s, got %s", tc.Handler, tc.Name, tc.Output, got)
+	}
+	return nil
+}
+
+// jsonEqual compares two JSON documents, ignoring formatting and hex case.
+func jsonEqual(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	na, _ := json.Marshal(va)
+	nb, _ := json.Marshal(vb)
+	return strings.EqualFold(string(na), string(nb))
+}
+
+// beBlob is a blob of big-endian field elements, presented with the little-endian encoding of the eth package.
+type beBlob []byte
+
+func (b beBlob) Len() int {
+	return len(b) / 32
+}
+
+func (b beBlob) At(i int) [32]byte {
+	var v [32]byte
+	copy(v[:], b[i*32:(i+1)*32])
+	return reverse32(v)
+}
+
+func reverse32(v [32]byte) (out [32]byte) {
+	for i := range v {
+		out[i] = v[31-i]
+	}
+	return out
+}
+
+func decodeHex(s string, size int) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if size >= 0 && len(b) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(b))
+	}
+	return b, nil
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func decodeBlob(ctx *eth.Context, s string) (eth.Blob, error) {
+	b, err := decodeHex(s, ctx.FieldElementsPerBlob()*32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob: %v", err)
+	}
+	blob := beBlob(b)
+	if err := ctx.ValidateBlob(blob); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// decodeScalar decodes a big-endian scalar into the little-endian encoding of the eth package.
+func decodeScalar(s string) ([32]byte, error) {
+	var v [32]byte
+	b, err := decodeHex(s, 32)
+	if err != nil {
+		return v, err
+	}
+	copy(v[:], b)
+	return reverse32(v), nil
+}
+
+func decodeG1(s string) ([48]byte, error) {
+	var v [48]byte
+	b, err := decodeHex(s, 48)
+	if err != nil {
+		return v, err
+	}
+	copy(v[:], b)
+	return v, nil
+}
+
+func runBlobToKZGCommitment(ctx *eth.Context, input json.RawMessage) (interface{}, error) {
+	var in struct {
+		Blob string `json:"blob"`
+	}
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, err
+	}
+	blob, err := decodeBlob(ctx, in.Blob)
+	if err != nil {
+		return nil, err
+	}
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		return nil, err
+	}
+	return encodeHex(commitment[:]), nil
+}
+
+func runComputeKZGProof(ctx *eth.Context, input json.RawMessage) (interface{}, error) {
+	var in struct {
+		Blob string `json:"blob"`
+		Z    string `json:"z"`
+	}
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, err
+	}
+	blob, err := decodeBlob(ctx, in.Blob)
+	if err != nil {
+		return nil, err
+	}
+	zBytes, err := decodeScalar(in.Z)
+	if err != nil {
+		return nil, err
+	}
+	var z bls.Fr
+	if !bls.FrFrom32(&z, zBytes) {
+		return nil, errors.New("invalid evaluation point")
+	}
+	poly, ok := eth.BlobToPolynomial(blob)
+	if !ok {
+		return nil, errors.New("could not convert blob to polynomial")
+	}
+	proof, err := ctx.ComputeKZGProof(poly, &z)
+	if err != nil {
+		return nil, err
+	}
+	y := bls.FrTo32BE(ctx.EvaluatePolynomialInEvaluationForm(poly, &z))
+	return []string{encodeHex(proof[:]), encodeHex(y[:])}, nil
+}
+
+func runComputeBlobKZGProof(ctx *eth.Context, input json.RawMessage) (interface{}, error) {
+	var in struct {
+		Blob       string `json:"blob"`
+		Commitment string `json:"commitment"`
+	}
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, err
+	}
+	blob, err := decodeBlob(ctx, in.Blob)
+	if err != nil {
+		return nil, err
+	}
+	commitment, err := decodeG1(in.Commitment)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		return nil, err
+	}
+	return encodeHex(proof[:]), nil
+}
+
+func runVerifyKZGProof(ctx *eth.Context, input json.RawMessage) (interface{}, error) {
+	var in struct {
+		Commitment string `json:"commitment"`
+		Z          string `json:"z"`
+		Y          string `json:"y"`
+		Proof      string `json:"proof"`
+	}
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, err
+	}
+	commitment, err := decodeG1(in.Commitment)
+	if err != nil {
+		return nil, err
+	}
+	z, err := decodeScalar(in.Z)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeScalar(in.Y)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := decodeG1(in.Proof)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.VerifyKZGProof(commitment, z, y, proof)
+}
+
+func runVerifyBlobKZGProof(ctx *eth.Context, input json.RawMessage) (interface{}, error) {
+	var in struct {
+		Blob       string `json:"blob"`
+		Commitment string `json:"commitment"`
+		Proof      string `json:"proof"`
+	}
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, err
+	}
+	blob, err := decodeBlob(ctx, in.Blob)
+	if err != nil {
+		return nil, err
+	}
+	commitment, err := decodeG1(in.Commitment)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := decodeG1(in.Proof)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.VerifyBlobKZGProof(blob, commitment, proof)
+}
+
+func runVerifyBlobKZGProofBatch(ctx *eth.Context, input json.RawMessage) (interface{}, error) {
+	var in struct {
+		Blobs       []string `json:"blobs"`
+		Commitments []string `json:"commitments"`
+		Proofs      []string `json:"proofs"`
+	}
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, err
+	}
+	if len(in.Blobs) != len(in.Commitments) || len(in.Blobs) != len(in.Proofs) {
+		return nil, fmt.Errorf("mismatched input lengths: %d blobs, %d commitments, %d proofs",
+			len(in.Blobs), len(in.Commitments), len(in.Proofs))
+	}
+	// decode everything first: any invalid input fails the batch, even after an invalid proof
+	blobs := make([]eth.Blob, len(in.Blobs))
+	commitments := make([]eth.KZGCommitment, len(in.Blobs))
+	proofs := make([]eth.KZGProof, len(in.Blobs))
+	for i := range in.Blobs {
+		var err error
+		if blobs[i], err = decodeBlob(ctx, in.Blobs[i]); err != nil {
+			return nil, err
+		}
+		if commitments[i], err = decodeG1(in.Commitments[i]); err != nil {
+			return nil, err
+		}
+		if proofs[i], err = decodeG1(in.Proofs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ctx.VerifyBlobKZGProofBatch(blobSequence(blobs), eth.KZGCommitmentSequenceImpl(commitments), eth.KZGProofSequenceImpl(proofs))
+}
+
+type blobSequence []eth.Blob
+
+func (s blobSequence) Len() int {
+	return len(s)
+}
+
+func (s blobSequence) At(i int) eth.Blob {
+	return s[i]
+}
@@ -0,0 +1,149 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package testvectors
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/protolambda/go-kzg/eth"
+)
+
+func writeTestCase(t *testing.T, dir, handler, name string, input interface{}, output interface{}) {
+	caseDir := filepath.Join(dir, handler, "kzg-mainnet", name)
+	if err := os.MkdirAll(caseDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]interface{}{"input": input, "output": output})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(caseDir, "data.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunTestCases(t *testing.T) {
+	ctx := eth.DefaultContext()
+	rng := rand.New(rand.NewSource(1))
+	blobBytes := make([]byte, ctx.FieldElementsPerBlob()*32)
+	rng.Read(blobBytes)
+	for i := 0; i < len(blobBytes); i += 32 {
+		blobBytes[i] = 0
+	}
+	blob := beBlob(blobBytes)
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobHex := encodeHex(blobBytes)
+	commitmentHex := encodeHex(commitment[:])
+	proofHex := encodeHex(proof[:])
+
+	dir := t.TempDir()
+	writeTestCase(t, dir, BlobToKZGCommitment, "valid", map[string]string{"blob": blobHex}, commitmentHex)
+	writeTestCase(t, dir, BlobToKZGCommitment, "short_blob", map[string]string{"blob": "0x00"}, nil)
+	writeTestCase(t, dir, ComputeBlobKZGProof, "valid",
+		map[string]string{"blob": blobHex, "commitment": commitmentHex}, proofHex)
+	writeTestCase(t, dir, VerifyBlobKZGProof, "valid",
+		map[string]string{"blob": blobHex, "commitment": commitmentHex, "proof": proofHex}, true)
+	writeTestCase(t, dir, VerifyBlobKZGProof, "wrong_proof",
+		map[string]string{"blob": blobHex, "commitment": commitmentHex, "proof": commitmentHex}, false)
+	writeTestCase(t, dir, VerifyBlobKZGProofBatch, "valid", map[string][]string{
+		"blobs": {blobHex, blobHex}, "commitments": {commitmentHex, commitmentHex}, "proofs": {proofHex, proofHex},
+	}, true)
+	writeTestCase(t, dir, VerifyBlobKZGProofBatch, "length_mismatch", map[string][]string{
+		"blobs": {blobHex}, "commitments": {}, "proofs": {proofHex},
+	}, nil)
+
+	cases, err := LoadTestCases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 7 {
+		t.Fatalf("expected 7 test cases, got %d", len(cases))
+	}
+	for _, tc := range cases {
+		if err := tc.Run(ctx); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// a wrong expected output must be reported
+	bad := &TestCase{Handler: VerifyBlobKZGProof, Name: "bad", Output: json.RawMessage("false")}
+	bad.Input, _ = json.Marshal(map[string]string{"blob": blobHex, "commitment": commitmentHex, "proof": proofHex})
+	if err := bad.Run(ctx); err == nil {
+		t.Fatal("expected mismatch error")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ctx := eth.DefaultContext()
+	cases, err := Generate(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlers := make(map[string]int)
+	for _, tc := range cases {
+		handlers[tc.Handler]++
+		// the verification cases must check what they are named after
+		if strings.HasPrefix(tc.Name, "correct_") && string(tc.Output) != "true" {
+			t.Errorf("%s/%s: expected true, got %s", tc.Handler, tc.Name, tc.Output)
+		}
+		if strings.HasPrefix(tc.Name, "incorrect_") && string(tc.Output) != "false" {
+			t.Errorf("%s/%s: expected false, got %s", tc.Handler, tc.Name, tc.Output)
+		}
+	}
+	if len(handlers) != 6 {
+		t.Fatalf("expected cases of 6 handlers, got %v", handlers)
+	}
+
+	dir := t.TempDir()
+	if err := WriteTestCases(dir, "kzg-mainnet", cases, FormatJSON, FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadTestCases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(cases) {
+		t.Fatalf("expected %d test cases, got %d", len(cases), len(loaded))
+	}
+	for i, tc := range loaded {
+		if tc.Handler != cases[i].Handler || tc.Name != cases[i].Name {
+			t.Fatalf("case %d: expected %s/%s, got %s/%s", i, cases[i].Handler, cases[i].Name, tc.Handler, tc.Name)
+		}
+		if err := tc.Run(ctx); err != nil {
+			t.Error(err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, VerifyBlobKZGProofBatch, "kzg-mainnet", "correct_proofs_0", "data.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "input:\n  blobs: []\n  commitments: []\n  proofs: []\noutput: true\n"
+	if string(data) != expected {
+		t.Fatalf("expected yaml:\n%s\ngot:\n%s", expected, data)
+	}
+
+	// the generation is reproducible
+	again, err := Generate(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range cases {
+		if string(cases[i].Input) != string(again[i].Input) || string(cases[i].Output) != string(again[i].Output) {
+			t.Fatalf("%s/%s differs between generations", cases[i].Handler, cases[i].Name)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strings"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// TranscriptEntry is a single input absorbed into a Fiat-Shamir challenge.
+type TranscriptEntry struct {
+	Label string
+	Data  []byte
+}
+
+// ChallengeTranscript records every input absorbed into a Fiat-Shamir challenge, in order,
+// with the resulting hash and challenge. It is meant to be dumped and diffed against another
+// implementation when challenges do not match.
+type ChallengeTranscript struct {
+	Entries []TranscriptEntry
+	// digest of all the entries with the challenge hash (see SetChallengeHash),
+	// the first block of expand_message_xmd with ChallengeModeHashToField
+	Hash [32]byte
+	// challenge derived from the hash, little-endian
+	Challenge [32]byte
+}
+
+// String formats the transcript with one "label: hex" line per entry, followed by the hash and challenge.
+func (t *ChallengeTranscript) String() string {
+	var sb strings.Builder
+	for _, e := range t.Entries {
+		sb.WriteString(e.Label)
+		sb.WriteString(": ")
+		sb.WriteString(hex.EncodeToString(e.Data))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("hash: ")
+	sb.WriteString(hex.EncodeToString(t.Hash[:]))
+	sb.WriteString("\nchallenge: ")
+	sb.WriteString(hex.EncodeToString(t.Challenge[:]))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FirstMismatch returns the index of the first entry that differs from the other transcript,
+// len(Entries) if only the number of entries differs, or -1 if the transcripts are equal.
+func (t *ChallengeTranscript) FirstMismatch(other *ChallengeTranscript) int {
+	for i := range t.Entries {
+		if i >= len(other.Entries) {
+			return i
+		}
+		a, b := &t.Entries[i], &other.Entries[i]
+		if a.Label != b.Label || string(a.Data) != string(b.Data) {
+			return i
+		}
+	}
+	if len(t.Entries) != len(other.Entries) {
+		return len(t.Entries)
+	}
+	return -1
+}
+
+// SetFiatShamirDomains sets the domain separators hashed first into the challenges of the aggregate proofs
+// (HashToBLSField) and of the blob proofs (ComputeChallenge). An empty domain restores the default,
+// FIAT_SHAMIR_PROTOCOL_DOMAIN. Proofs only verify against contexts with the same domains.
+func (ctx *Context) SetFiatShamirDomains(aggregate, blob string) {
+	ctx.aggregateChallengeDomain = aggregate
+	ctx.blobChallengeDomain = blob
+}
+
+// SetFiatShamirDomains calls SetFiatShamirDomains on a copy of the default context, which then replaces it, see Context.
+func SetFiatShamirDomains(aggregate, blob string) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetFiatShamirDomains(aggregate, blob)
+		return nil
+	})
+}
+
+// fiatShamirDomains returns the domain separators of the aggregate and blob challenges.
+func (ctx *Context) fiatShamirDomains() (aggregate, blob string) {
+	aggregate, blob = ctx.aggregateChallengeDomain, ctx.blobChallengeDomain
+	if aggregate == "" {
+		aggregate = FIAT_SHAMIR_PROTOCOL_DOMAIN
+	}
+	if blob == "" {
+		blob = FIAT_SHAMIR_PROTOCOL_DOMAIN
+	}
+	return aggregate, blob
+}
+
+// Transcript is a Fiat-Shamir transcript: it absorbs the public inputs of a protocol in order, and squeezes
+// challenges from them, with the hash and challenge mode of its context (see SetChallengeHash and
+// SetChallengeMode). The challenges of this package are derived with transcripts, so that protocols composing
+// KZG openings into larger arguments can derive theirs the same way.
+//
+// Every challenge depends on all the inputs absorbed before it: after a challenge is squeezed, the transcript
+// restarts from the challenge. The labels are not hashed, as the consensus specs do not hash any: they only name
+// the inputs in debug transcripts. Domain separation comes from the domain absorbed first, see NewTranscript,
+// and the order of the inputs, which must be fixed by the protocol.
+// A Transcript must not be used concurrently.
+type Transcript struct {
+	newHash func() hash.Hash
+	hasher  hash.Hash
+	mode    ChallengeMode
+	dst     []byte
+	// last challenge squeezed, absorbed first by the next use of the transcript
+	last *bls.Fr
+	// recorded inputs, only when debugging
+	record *ChallengeTranscript
+}
+
+func (ctx *Context) newTranscript(debug bool) *Transcript {
+	newHash := ctx.challengeHash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	h := &Transcript{newHash: newHash, mode: ctx.challengeMode, dst: ctx.hashToFieldDST}
+	h.reset()
+	if debug {
+		h.record = new(ChallengeTranscript)
+	}
+	return h
+}
+
+// NewTranscript creates a transcript with the challenge hash and mode of the context, absorbing the domain first.
+func (ctx *Context) NewTranscript(domain string) *Transcript {
+	h := ctx.newTranscript(false)
+	h.AppendBytes("domain", []byte(domain))
+	return h
+}
+
+// NewTranscript calls NewTranscript on the default context.
+func NewTranscript(domain string) *Transcript {
+	return defaultContext().NewTranscript(domain)
+}
+
+// reset starts hashing from scratch.
+func (h *Transcript) reset() {
+	h.hasher = h.newHash()
+	if h.mode == ChallengeModeHashToField {
+		// expand_message_xmd starts with a zero block: Z_pad = I2OSP(0, s_in_bytes)
+		h.hasher.Write(make([]byte, h.hasher.BlockSize()))
+	}
+}
+
+// resume restarts from the last challenge, if one was squeezed since the last input.
+func (h *Transcript) resume() {
+	if h.last == nil {
+		return
+	}
+	b32 := bls.FrTo32(h.last)
+	h.last = nil
+	h.reset()
+	h.absorb(func() string { return "challenge" }, b32[:])
+}
+
+// absorb hashes the data. The label is only used for the transcript, and is computed lazily
+// to not slow down the non-debug path.
+func (h *Transcript) absorb(label func() string, data []byte) {
+	h.resume()
+	// writes to a hash never fail
+	h.hasher.Write(data)
+	if h.record != nil {
+		h.record.Entries = append(h.record.Entries, TranscriptEntry{
+			Label: label(),
+			Data:  append([]byte(nil), data...),
+		})
+	}
+}
+
+// AppendBytes absorbs the data.
+func (h *Transcript) AppendBytes(label string, data []byte) {
+	h.absorb(func() string { return label }, data)
+}
+
+// AppendFr absorbs the 32-byte little-endian encoding of the field element.
+func (h *Transcript) AppendFr(label string, v *bls.Fr) {
+	b32 := bls.FrTo32(v)
+	h.absorb(func() string { return label }, b32[:])
+}
+
+// AppendG1 absorbs the 48-byte compressed encoding of the point.
+func (h *Transcript) AppendG1(label string, p *bls.G1Point) {
+	h.absorb(func() string { return label }, bls.ToCompressedG1(p))
+}
+
+// ChallengeFr squeezes a challenge from everything absorbed so far.
+func (h *Transcript) ChallengeFr() *bls.Fr {
+	return h.challenge(false)
+}
+
+// challenge derives the challenge from everything absorbed so far. With ChallengeModeReduce the digest
+// is reduced modulo the BLS modulus, read big-endian if bigEndian is set, and little-endian otherwise.
+// With ChallengeModeHashToField the absorbed inputs are the message of hash_to_field, and bigEndian is ignored.
+func (h *Transcript) challenge(bigEndian bool) *bls.Fr {
+	h.resume()
+	var out *bls.Fr
+	if h.mode == ChallengeModeHashToField {
+		out = h.hashToField()
+	} else {
+		var digest [32]byte
+		copy(digest[:], h.hasher.Sum(nil))
+		if h.record != nil {
+			h.record.Hash = digest
+		}
+		if bigEndian {
+			digest = reverse32(digest)
+		}
+		out = BytesToBLSField(digest)
+	}
+	if h.record != nil {
+		h.record.Challenge = bls.FrTo32(out)
+	}
+	h.last = new(bls.Fr)
+	bls.CopyFr(h.last, out)
+	return out
+}
@@ -0,0 +1,125 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestChallengeTranscript(t *testing.T) {
+	ctx := newTestContext(t, 2)
+	polys := Polynomials{randomPolynomialN(4), randomPolynomialN(4)}
+	comms := KZGCommitmentSequenceImpl{
+		ctx.PolynomialToKZGCommitment(polys[0]),
+		ctx.PolynomialToKZGCommitment(polys[1]),
+	}
+	expected, err := ctx.HashToBLSField(polys, comms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, transcript := ctx.HashToBLSFieldDebug(polys, comms)
+	if !bls.EqualFr(expected, challenge) {
+		t.Fatal("debug challenge differs from the regular one")
+	}
+	// domain, width, count, 8 field elements, 2 commitments
+	if len(transcript.Entries) != 3+8+2 {
+		t.Fatalf("unexpected number of transcript entries: %d", len(transcript.Entries))
+	}
+	if transcript.Challenge != bls.FrTo32(challenge) {
+		t.Fatal("transcript does not record the challenge")
+	}
+	if !strings.Contains(transcript.String(), "polynomial[1][3]: ") {
+		t.Fatalf("unexpected transcript dump:\n%s", transcript)
+	}
+	if i := transcript.FirstMismatch(transcript); i != -1 {
+		t.Fatalf("transcript differs from itself at %d", i)
+	}
+
+	bls.AddModFr(&polys[1][2], &polys[1][2], &bls.ONE)
+	_, other := ctx.HashToBLSFieldDebug(polys, comms)
+	if i := transcript.FirstMismatch(other); i != 3+4+2 {
+		t.Fatalf("expected mismatch at entry %d, got %d", 3+4+2, i)
+	}
+
+	blobChallenge, blobTranscript := ctx.ComputeChallengeDebug(polys[0], comms[0])
+	if !bls.EqualFr(blobChallenge, ctx.ComputeChallenge(polys[0], comms[0])) {
+		t.Fatal("debug blob challenge differs from the regular one")
+	}
+	if len(blobTranscript.Entries) != 2+4+1 {
+		t.Fatalf("unexpected number of blob transcript entries: %d", len(blobTranscript.Entries))
+	}
+}
+
+func TestFiatShamirDomains(t *testing.T) {
+	ctx := newTestContext(t, 2)
+	poly := randomPolynomialN(4)
+	comms := KZGCommitmentSequenceImpl{ctx.PolynomialToKZGCommitment(poly)}
+	aggregate, err := ctx.HashToBLSField(Polynomials{poly}, comms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := ctx.ComputeChallenge(poly, comms[0])
+
+	ctx.SetFiatShamirDomains("", "OTHER_BLOB_V1___")
+	aggregate2, err := ctx.HashToBLSField(Polynomials{poly}, comms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.EqualFr(aggregate, aggregate2) {
+		t.Fatal("expected default aggregate domain to be kept")
+	}
+	blob2, transcript := ctx.ComputeChallengeDebug(poly, comms[0])
+	if bls.EqualFr(blob, blob2) {
+		t.Fatal("expected blob challenge to change with its domain")
+	}
+	if string(transcript.Entries[0].Data) != "OTHER_BLOB_V1___" {
+		t.Fatalf("unexpected domain %q", transcript.Entries[0].Data)
+	}
+
+	ctx.SetFiatShamirDomains("", "")
+	if !bls.EqualFr(blob, ctx.ComputeChallenge(poly, comms[0])) {
+		t.Fatal("expected default blob domain to be restored")
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	ctx := newTestContext(t, 2)
+	x := bls.RandomFr()
+	var p bls.G1Point
+	bls.MulG1(&p, &bls.GenG1, x)
+
+	tr := ctx.NewTranscript("TEST_PROTOCOL")
+	tr.AppendBytes("data", []byte{1, 2, 3})
+	tr.AppendFr("x", x)
+	tr.AppendG1("p", &p)
+	first := tr.ChallengeFr()
+	second := tr.ChallengeFr()
+	tr.AppendBytes("more", []byte{4})
+	third := tr.ChallengeFr()
+
+	xBytes := bls.FrTo32(x)
+	digest := sha256.Sum256(append(append(append([]byte("TEST_PROTOCOL"), 1, 2, 3), xBytes[:]...), bls.ToCompressedG1(&p)...))
+	if !bls.EqualFr(first, BytesToBLSField(digest)) {
+		t.Fatal("unexpected first challenge")
+	}
+	// every challenge restarts the transcript from the previous one
+	firstBytes := bls.FrTo32(first)
+	if !bls.EqualFr(second, BytesToBLSField(sha256.Sum256(firstBytes[:]))) {
+		t.Fatal("unexpected second challenge")
+	}
+	secondBytes := bls.FrTo32(second)
+	if !bls.EqualFr(third, BytesToBLSField(sha256.Sum256(append(secondBytes[:], 4)))) {
+		t.Fatal("unexpected third challenge")
+	}
+
+	other := ctx.NewTranscript("OTHER_PROTOCOL")
+	other.AppendBytes("data", []byte{1, 2, 3})
+	if bls.EqualFr(first, other.ChallengeFr()) {
+		t.Fatal("expected another domain to derive another challenge")
+	}
+}
@@ -0,0 +1,127 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// UncompressedKZGCommitment is the 96-byte (x, y) encoding of a KZGCommitment.
+// Decoding it skips the square root of the compressed form, for points that were already validated once.
+type UncompressedKZGCommitment [96]byte
+
+// UncompressedKZGProof is the 96-byte (x, y) encoding of a KZGProof.
+type UncompressedKZGProof [96]byte
+
+// ToUncompressed decodes the commitment and returns its uncompressed encoding.
+func (c KZGCommitment) ToUncompressed() (UncompressedKZGCommitment, error) {
+	var out UncompressedKZGCommitment
+	p, err := bls.FromCompressedG1(c[:])
+	if err != nil {
+		return out, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	copy(out[:], bls.ToUncompressedG1(p))
+	return out, nil
+}
+
+// FromUncompressed sets the commitment to the compressed encoding of the given uncompressed point.
+func (c *KZGCommitment) FromUncompressed(v UncompressedKZGCommitment) error {
+	p, err := bls.FromUncompressedG1(v[:])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	copy(c[:], bls.ToCompressedG1(p))
+	return nil
+}
+
+// ToUncompressed decodes the proof and returns its uncompressed encoding.
+func (p KZGProof) ToUncompressed() (UncompressedKZGProof, error) {
+	out, err := KZGCommitment(p).ToUncompressed()
+	if err != nil {
+		return UncompressedKZGProof{}, fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	return UncompressedKZGProof(out), nil
+}
+
+// FromUncompressed sets the proof to the compressed encoding of the given uncompressed point.
+func (p *KZGProof) FromUncompressed(v UncompressedKZGProof) error {
+	if err := (*KZGCommitment)(p).FromUncompressed(UncompressedKZGCommitment(v)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	return nil
+}
+
+// VerifyKZGProofUncompressed is VerifyKZGProof with uncompressed commitment and proof.
+func (ctx *Context) VerifyKZGProofUncompressed(commitment UncompressedKZGCommitment, z, y [32]byte, proof UncompressedKZGProof) (bool, error) {
+	var zFr, yFr bls.Fr
+	if !bls.FrFrom32(&zFr, z) {
+		return false, fmt.Errorf("invalid evaluation point: %w", ErrNonCanonicalScalar)
+	}
+	if !bls.FrFrom32(&yFr, y) {
+		return false, fmt.Errorf("invalid expected output: %w", ErrNonCanonicalScalar)
+	}
+	commitmentG1, err := bls.FromUncompressedG1(commitment[:])
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	proofG1, err := bls.FromUncompressedG1(proof[:])
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	return ctx.VerifyKZGProofFromPoints(commitmentG1, &zFr, &yFr, proofG1), nil
+}
+
+// VerifyKZGProofUncompressed calls VerifyKZGProofUncompressed on the default context.
+func VerifyKZGProofUncompressed(commitment UncompressedKZGCommitment, z, y [32]byte, proof UncompressedKZGProof) (bool, error) {
+	return defaultContext().VerifyKZGProofUncompressed(commitment, z, y, proof)
+}
+
+// VerifyKZGProofBatchUncompressed verifies many (commitment, z, y, proof) openings at once with uncompressed points.
+// It returns true only if all of the proofs are valid.
+func (ctx *Context) VerifyKZGProofBatchUncompressed(commitments []UncompressedKZGCommitment, zs, ys [][32]byte, proofs []UncompressedKZGProof) (bool, error) {
+	n := len(commitments)
+	if len(zs) != n || len(ys) != n || len(proofs) != n {
+		return false, fmt.Errorf("%w of the batch", ErrLengthMismatch)
+	}
+	if err := ctx.checkOpeningLimit(n); err != nil {
+		return false, err
+	}
+	defer ctx.reportUsage("VerifyKZGProofBatchUncompressed", 256*n, 3*n+1)
+	commitmentsG1 := make([]bls.G1Point, n)
+	proofsG1 := make([]bls.G1Point, n)
+	zsFr := make([]bls.Fr, n)
+	ysFr := make([]bls.Fr, n)
+	first := firstOccurrences(n, func(i int) interface{} { return commitments[i] })
+	for i := 0; i < n; i++ {
+		if !bls.FrFrom32(&zsFr[i], zs[i]) {
+			return false, fmt.Errorf("proof %d: invalid evaluation point: %w", i, ErrNonCanonicalScalar)
+		}
+		if !bls.FrFrom32(&ysFr[i], ys[i]) {
+			return false, fmt.Errorf("proof %d: invalid expected output: %w", i, ErrNonCanonicalScalar)
+		}
+		if j := first[i]; j != i {
+			// repeated commitments are only decoded once
+			bls.CopyG1(&commitmentsG1[i], &commitmentsG1[j])
+		} else {
+			c, err := bls.FromUncompressedG1(commitments[i][:])
+			if err != nil {
+				return false, fmt.Errorf("proof %d: %w: %v", i, ErrInvalidCommitment, err)
+			}
+			bls.CopyG1(&commitmentsG1[i], c)
+		}
+		p, err := bls.FromUncompressedG1(proofs[i][:])
+		if err != nil {
+			return false, fmt.Errorf("proof %d: %w: %v", i, ErrMalformedProof, err)
+		}
+		bls.CopyG1(&proofsG1[i], p)
+	}
+	return ctx.verifyKZGProofBatch(commitmentsG1, zsFr, ysFr, proofsG1, first)
+}
+
+// VerifyKZGProofBatchUncompressed calls VerifyKZGProofBatchUncompressed on the default context.
+func VerifyKZGProofBatchUncompressed(commitments []UncompressedKZGCommitment, zs, ys [][32]byte, proofs []UncompressedKZGProof) (bool, error) {
+	return defaultContext().VerifyKZGProofBatchUncompressed(commitments, zs, ys, proofs)
+}
@@ -0,0 +1,77 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestUncompressedRoundTrip(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	commitment := ctx.PolynomialToKZGCommitment(randomPolynomialN(16))
+	u, err := commitment.ToUncompressed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back KZGCommitment
+	if err := back.FromUncompressed(u); err != nil {
+		t.Fatal(err)
+	}
+	if back != commitment {
+		t.Fatal("round trip mismatch")
+	}
+	u[95] ^= 1
+	if err := back.FromUncompressed(u); err == nil {
+		t.Fatal("expected invalid point to be rejected")
+	}
+	if _, err := InfinityKZGCommitment.ToUncompressed(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyKZGProofBatchUncompressed(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	n := 3
+	commitments := make([]UncompressedKZGCommitment, n)
+	proofs := make([]UncompressedKZGProof, n)
+	zs := make([][32]byte, n)
+	ys := make([][32]byte, n)
+	for i := 0; i < n; i++ {
+		poly := randomPolynomialN(16)
+		z := bls.RandomFr()
+		proof, err := ctx.ComputeKZGProof(poly, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commitments[i], err = ctx.PolynomialToKZGCommitment(poly).ToUncompressed(); err != nil {
+			t.Fatal(err)
+		}
+		if proofs[i], err = proof.ToUncompressed(); err != nil {
+			t.Fatal(err)
+		}
+		zs[i] = bls.FrTo32(z)
+		ys[i] = bls.FrTo32(ctx.EvaluatePolynomialInEvaluationForm(poly, z))
+	}
+	ok, err := ctx.VerifyKZGProofUncompressed(commitments[0], zs[0], ys[0], proofs[0])
+	if err != nil || !ok {
+		t.Fatalf("expected proof to verify: %v", err)
+	}
+	ok, err = ctx.VerifyKZGProofBatchUncompressed(commitments, zs, ys, proofs)
+	if err != nil || !ok {
+		t.Fatalf("expected batch to verify: %v", err)
+	}
+	ys[1], ys[2] = ys[2], ys[1]
+	ok, err = ctx.VerifyKZGProofBatchUncompressed(commitments, zs, ys, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected batch with swapped outputs to fail")
+	}
+	if _, err := ctx.VerifyKZGProofBatchUncompressed(commitments, zs[:1], ys, proofs); err == nil {
+		t.Fatal("expected length mismatch to be rejected")
+	}
+}
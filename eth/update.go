@@ -0,0 +1,38 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// UpdateCommitment returns the commitment to a blob after its field element at index changed from oldValue to
+// newValue, given the commitment old to the blob before the change. Commitments are linear in the blob,
+// so the new commitment is old + (newValue - oldValue) * L_index, with the Lagrange setup point L_index:
+// a single scalar multiplication instead of the MSM over the whole blob.
+// The index is the position of the field element in the blob, in the bit-reversed order of the blob.
+func (ctx *Context) UpdateCommitment(old KZGCommitment, index uint64, oldValue, newValue *bls.Fr) (KZGCommitment, error) {
+	if index >= uint64(len(ctx.setupLagrange)) {
+		return KZGCommitment{}, fmt.Errorf("index %d is out of range of a blob of %d field elements", index, len(ctx.setupLagrange))
+	}
+	oldG1, err := ctx.decodeCommitment(old)
+	if err != nil {
+		return KZGCommitment{}, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	var delta bls.Fr
+	bls.SubModFr(&delta, newValue, oldValue)
+	var diff, updated bls.G1Point
+	bls.MulG1(&diff, &ctx.setupLagrange[index], &delta)
+	bls.AddG1(&updated, oldG1, &diff)
+	var out KZGCommitment
+	copy(out[:], bls.ToCompressedG1(&updated))
+	return out, nil
+}
+
+// UpdateCommitment calls UpdateCommitment on the default context.
+func UpdateCommitment(old KZGCommitment, index uint64, oldValue, newValue *bls.Fr) (KZGCommitment, error) {
+	return defaultContext().UpdateCommitment(old, index, oldValue, newValue)
+}
@@ -0,0 +1,31 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestUpdateCommitment(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	poly := randomPolynomialN(16)
+	commitment := ctx.PolynomialToKZGCommitment(poly)
+	for _, index := range []uint64{0, 5, 15} {
+		newValue := bls.RandomFr()
+		updated, err := ctx.UpdateCommitment(commitment, index, &poly[index], newValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bls.CopyFr(&poly[index], newValue)
+		commitment = ctx.PolynomialToKZGCommitment(poly)
+		if updated != commitment {
+			t.Fatalf("updated commitment at index %d does not match the commitment to the new blob", index)
+		}
+	}
+	if _, err := ctx.UpdateCommitment(commitment, 16, &bls.ZERO, &bls.ONE); err == nil {
+		t.Fatal("expected an index out of the blob to be rejected")
+	}
+}
@@ -0,0 +1,112 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"time"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// preparedSetup holds the fixed G2 inputs of the verification pairings, [1]_2 and [tau]_2,
+// prepared once for all the pairings they take part in.
+type preparedSetup struct {
+	genG2   *bls.PreparedG2
+	setupG2 *bls.PreparedG2
+}
+
+// pairingsVerify checks e(a1, [1]_2) == e(b1, [tau]_2), with the prepared setup if not nil.
+func (ctx *Context) pairingsVerify(prepared *preparedSetup, a1, b1 *bls.G1Point) bool {
+	if prepared != nil {
+		return bls.PairingsVerifyPrepared(a1, prepared.genG2, b1, prepared.setupG2)
+	}
+	return bls.PairingsVerify(a1, &bls.GenG2, b1, &ctx.setupG2[1])
+}
+
+// Verifier verifies proofs like the context does, but with the fixed G2 inputs of the pairings
+// prepared in advance, and with buffers reused across batch verifications,
+// to amortize these fixed costs over the many verifications a node performs.
+// A Verifier is not safe for concurrent use: use one per worker.
+type Verifier struct {
+	ctx      *Context
+	prepared preparedSetup
+	batch    batchScratch
+}
+
+// NewVerifier creates a Verifier for the context.
+func (ctx *Context) NewVerifier() *Verifier {
+	return &Verifier{
+		ctx: ctx,
+		prepared: preparedSetup{
+			genG2:   bls.PrepareG2(&bls.GenG2),
+			setupG2: bls.PrepareG2(&ctx.setupG2[1]),
+		},
+	}
+}
+
+// NewVerifier calls NewVerifier on the default context.
+func NewVerifier() *Verifier {
+	return defaultContext().NewVerifier()
+}
+
+// VerifyKZGProofFromPoints is Context.VerifyKZGProofFromPoints, with the check
+// e(C - [y]_1, [1]_2) == e(proof, [tau - z]_2) rewritten as
+// e(C - [y]_1 + z * proof, [1]_2) == e(proof, [tau]_2), so that both G2 inputs are the prepared ones.
+func (v *Verifier) VerifyKZGProofFromPoints(polynomialKZG *bls.G1Point, z *bls.Fr, y *bls.Fr, kzgProof *bls.G1Point) bool {
+	defer bls.StartTraceOperation("kzg verify").End()
+	start := time.Now()
+	var yG1, zProof, lhs bls.G1Point
+	bls.MulG1(&yG1, &bls.GenG1, y)
+	bls.MulG1(&zProof, kzgProof, z)
+	bls.SubG1(&lhs, polynomialKZG, &yG1)
+	bls.AddG1(&lhs, &lhs, &zProof)
+
+	pairingStart := time.Now()
+	ok := v.ctx.pairingsVerify(&v.prepared, &lhs, kzgProof)
+	v.ctx.metrics.Pairing(time.Since(pairingStart))
+	v.ctx.metrics.ProofVerified(time.Since(start), ok)
+	return ok
+}
+
+// VerifyKZGProof is Context.VerifyKZGProof, using the prepared pairing inputs.
+func (v *Verifier) VerifyKZGProof(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof) (bool, error) {
+	polynomialKZGG1, zFr, yFr, kzgProofG1, err := v.ctx.decodeKZGOpening(polynomialKZG, z, y, kzgProof, nil)
+	if err != nil {
+		return false, err
+	}
+	return v.VerifyKZGProofFromPoints(polynomialKZGG1, zFr, yFr, kzgProofG1), nil
+}
+
+// VerifyBlobKZGProof is Context.VerifyBlobKZGProof, using the prepared pairing inputs.
+func (v *Verifier) VerifyBlobKZGProof(blob Blob, commitment KZGCommitment, proof KZGProof) (bool, error) {
+	commitmentG1, z, y, proofG1, err := v.ctx.blobKZGProofOpening(blob, commitment, proof, nil)
+	if err != nil {
+		return false, err
+	}
+	return v.VerifyKZGProofFromPoints(commitmentG1, z, y, proofG1), nil
+}
+
+// VerifyBlobKZGProofBatch is Context.VerifyBlobKZGProofBatch, using the prepared pairing inputs
+// and reusing the buffers of the previous batches. The chunks of a batch larger than the maximum batch size
+// of the context are verified one after the other, as a Verifier is used by a single worker.
+func (v *Verifier) VerifyBlobKZGProofBatch(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (bool, error) {
+	n, err := v.ctx.checkBlobKZGProofBatch(blobs, commitments, proofs, nil)
+	if err != nil {
+		return false, err
+	}
+	valid := true
+	for _, chunk := range v.ctx.batchChunks(n) {
+		o, err := v.ctx.blobKZGProofBatchOpenings(blobs, commitments, proofs, chunk[0], chunk[1], nil)
+		if err != nil {
+			return false, err
+		}
+		ok, err := v.ctx.verifyKZGProofBatchWith(&v.batch, &v.prepared, o.commitments, o.zs, o.ys, o.proofs, o.first)
+		if err != nil {
+			return false, err
+		}
+		// the remaining chunks are still decoded, so that malformed inputs are reported as with a single batch
+		valid = valid && ok
+	}
+	return valid, nil
+}
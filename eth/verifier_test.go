@@ -0,0 +1,77 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestVerifier(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	verifier := ctx.NewVerifier()
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	var proofs KZGProofSequenceImpl
+	for i := 0; i < 3; i++ {
+		poly := randomPolynomialN(16)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		z := bls.RandomFr()
+		y := ctx.EvaluatePolynomialInEvaluationForm(poly, z)
+		proof, err := ctx.ComputeKZGProof(poly, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := verifier.VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(y), proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected proof to verify")
+		}
+		ok, err = verifier.VerifyKZGProof(commitment, bls.FrTo32(z), bls.FrTo32(bls.RandomFr()), proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal("expected proof of another evaluation to fail")
+		}
+
+		blob := polynomialToBlob(poly)
+		blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err = verifier.VerifyBlobKZGProof(blob, commitment, blobProof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected blob proof to verify")
+		}
+		blobs = append(blobs, blob)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, blobProof)
+	}
+
+	// the buffers are reused by the next batches, of other sizes
+	for n := len(blobs); n >= 0; n-- {
+		ok, err := verifier.VerifyBlobKZGProofBatch(blobs[:n], commitments[:n], proofs[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("expected batch of %d to verify", n)
+		}
+	}
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	ok, err := verifier.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected batch with swapped proofs to fail")
+	}
+}
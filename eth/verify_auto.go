@@ -0,0 +1,205 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// VerifyStrategy is a way to verify a batch of blob proofs, see ChooseVerifyStrategy.
+type VerifyStrategy int
+
+const (
+	// VerifyIndividually checks every proof with its own pairing check, spread over the pool of workers.
+	VerifyIndividually VerifyStrategy = iota
+	// VerifyBatched checks all the proofs with a single random linear combination and pairing check.
+	VerifyBatched
+	// VerifyChunked splits the proofs into chunks, each checked with its own random linear combination,
+	// spread over the pool of workers.
+	VerifyChunked
+)
+
+func (s VerifyStrategy) String() string {
+	switch s {
+	case VerifyIndividually:
+		return "individual"
+	case VerifyBatched:
+		return "batched"
+	case VerifyChunked:
+		return "chunked"
+	default:
+		return fmt.Sprintf("VerifyStrategy(%d)", int(s))
+	}
+}
+
+// VerifyCosts are the costs of the operations of a proof verification on the backend, which decide
+// the verification strategy of VerifyAuto. Only their ratio matters.
+type VerifyCosts struct {
+	// PairingCheck is the cost of the check of a product of two pairings, done once per proof when verifying
+	// individually, and once per chunk when batching.
+	PairingCheck time.Duration
+	// MSMPoint is the cost per point of a multi-scalar multiplication in G1, of which a batch computes two,
+	// over its commitments and over its proofs.
+	MSMPoint time.Duration
+}
+
+// msmCostPoints is the number of points of the multi-scalar multiplication MeasureVerifyCosts times.
+const msmCostPoints = 64
+
+// MeasureVerifyCosts times the operations of a proof verification on the backend, see VerifyCosts.
+// It takes a few milliseconds, the best of three runs of a pairing check and of a multi-scalar multiplication.
+func (ctx *Context) MeasureVerifyCosts() VerifyCosts {
+	k := msmCostPoints
+	if k > len(ctx.setupLagrange) {
+		k = len(ctx.setupLagrange)
+	}
+	scalars := make([]bls.Fr, k)
+	for i := range scalars {
+		bls.CopyFr(&scalars[i], bls.RandomFr())
+	}
+	var costs VerifyCosts
+	for run := 0; run < 3; run++ {
+		start := time.Now()
+		bls.PairingsVerify(&ctx.setupLagrange[0], &bls.GenG2, &ctx.setupLagrange[0], &bls.GenG2)
+		if d := time.Since(start); run == 0 || d < costs.PairingCheck {
+			costs.PairingCheck = d
+		}
+		start = time.Now()
+		bls.LinCombG1(ctx.setupLagrange[:k], scalars)
+		if d := time.Since(start) / time.Duration(k); run == 0 || d < costs.MSMPoint {
+			costs.MSMPoint = d
+		}
+	}
+	return costs
+}
+
+// MeasureVerifyCosts calls MeasureVerifyCosts on the default context.
+func MeasureVerifyCosts() VerifyCosts {
+	return defaultContext().MeasureVerifyCosts()
+}
+
+// SetVerifyCosts sets the costs VerifyAuto chooses its strategy with, e.g. measured once with MeasureVerifyCosts
+// and shared between the contexts of a process. By default, they are measured on the first use of VerifyAuto.
+// It must not be called concurrently with verifications.
+func (ctx *Context) SetVerifyCosts(costs VerifyCosts) {
+	ctx.verifyCosts = costs
+}
+
+// SetVerifyCosts calls SetVerifyCosts on a copy of the default context, which then replaces it, see Context.
+func SetVerifyCosts(costs VerifyCosts) {
+	updateDefaultContext(func(ctx *Context) error {
+		ctx.SetVerifyCosts(costs)
+		return nil
+	})
+}
+
+// verifyCostsOrMeasure returns the costs of the context, measured on first use unless set.
+func (ctx *Context) verifyCostsOrMeasure() VerifyCosts {
+	if ctx.verifyCosts != (VerifyCosts{}) {
+		return ctx.verifyCosts
+	}
+	ctx.verifyCostsOnce.Do(func() {
+		ctx.measuredVerifyCosts = ctx.MeasureVerifyCosts()
+	})
+	return ctx.measuredVerifyCosts
+}
+
+// ChooseVerifyStrategy returns the strategy VerifyAuto verifies n blob proofs with, and the number of proofs
+// of the chunks of the chunked strategy. It estimates the latency of each strategy, with the costs of the context
+// (see SetVerifyCosts) and the pool of workers (see SetMaxWorkers):
+//
+//	individually: ceil(n / workers) * PairingCheck
+//	batched:      2 * n * MSMPoint + PairingCheck
+//	chunked:      ceil(chunks / workers) * (2 * chunk * MSMPoint + PairingCheck)
+//
+// with chunk = ceil(n / workers), bounded by SetMaxBatchSize, and picks the fastest, the simplest on ties.
+// A batch larger than SetMaxBatchSize is never batched as a whole. The work of decoding the blobs and computing their evaluations, the same for all
+// the strategies, is left out.
+func (ctx *Context) ChooseVerifyStrategy(n int) (VerifyStrategy, int) {
+	return chooseVerifyStrategy(n, MaxWorkers(), ctx.maxBatchSize, ctx.verifyCostsOrMeasure())
+}
+
+// ChooseVerifyStrategy calls ChooseVerifyStrategy on the default context.
+func ChooseVerifyStrategy(n int) (VerifyStrategy, int) {
+	return defaultContext().ChooseVerifyStrategy(n)
+}
+
+func chooseVerifyStrategy(n, workers, maxBatchSize int, costs VerifyCosts) (VerifyStrategy, int) {
+	if n <= 1 {
+		return VerifyIndividually, n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	perWorker := (n + workers - 1) / workers
+	chunk := perWorker
+	if maxBatchSize > 0 && chunk > maxBatchSize {
+		chunk = maxBatchSize
+	}
+	rounds := ((n+chunk-1)/chunk + workers - 1) / workers
+	individual := time.Duration(perWorker) * costs.PairingCheck
+	chunked := time.Duration(rounds) * (2*time.Duration(chunk)*costs.MSMPoint + costs.PairingCheck)
+	best, size, cost := VerifyIndividually, 1, individual
+	if maxBatchSize <= 0 || n <= maxBatchSize {
+		if batched := 2*time.Duration(n)*costs.MSMPoint + costs.PairingCheck; batched < cost {
+			best, size, cost = VerifyBatched, n, batched
+		}
+	}
+	if chunk < n && chunked < cost {
+		best, size = VerifyChunked, chunk
+	}
+	return best, size
+}
+
+// VerifyAuto is VerifyBlobKZGProofBatch, with the proofs verified individually, batched, or in chunks,
+// whichever is estimated to be the fastest for the size of the batch on the backend, see ChooseVerifyStrategy.
+// The result, and the class of the error of an invalid input, do not depend on the strategy.
+func (ctx *Context) VerifyAuto(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (bool, error) {
+	n, err := ctx.checkBlobKZGProofBatch(blobs, commitments, proofs, nil)
+	if err != nil {
+		return false, err
+	}
+	strategy, size := ctx.ChooseVerifyStrategy(n)
+	switch strategy {
+	case VerifyIndividually:
+		return ctx.verifyBlobKZGProofsIndividually(blobs, commitments, proofs)
+	default:
+		return ctx.verifyBlobKZGProofChunks(blobs, commitments, proofs, splitChunks(n, size), nil)
+	}
+}
+
+// VerifyAuto calls VerifyAuto on the default context.
+func VerifyAuto(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (bool, error) {
+	return defaultContext().VerifyAuto(blobs, commitments, proofs)
+}
+
+// verifyBlobKZGProofsIndividually verifies the blob proofs of a batch, of which the lengths were checked
+// with checkBlobKZGProofBatch, each with its own pairing check, in parallel. The first error,
+// by the order of the blobs, is returned.
+func (ctx *Context) verifyBlobKZGProofsIndividually(blobs BlobSequence, commitments KZGCommitmentSequence,
+	proofs KZGProofSequence) (bool, error) {
+	n := blobs.Len()
+	valid := make([]bool, n)
+	errs := make([]error, n)
+	parallelFor(n, func(i int) {
+		valid[i], errs[i] = ctx.VerifyBlobKZGProof(blobs.At(i), commitments.At(i), proofs.At(i))
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("blob %d: %w", i, errs[i])
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return false, err
+		}
+	}
+	for _, ok := range valid {
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
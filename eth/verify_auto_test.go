@@ -0,0 +1,100 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChooseVerifyStrategy(t *testing.T) {
+	// a pairing check costing as much as a hundred MSM points, as on the kilic backend
+	costs := VerifyCosts{PairingCheck: 100 * time.Microsecond, MSMPoint: time.Microsecond}
+	for _, c := range []struct {
+		n, workers, maxBatchSize int
+		strategy                 VerifyStrategy
+		size                     int
+	}{
+		{1, 1, 0, VerifyIndividually, 1},
+		{2, 1, 0, VerifyBatched, 2},
+		{4, 4, 0, VerifyIndividually, 1},
+		{6, 4, 0, VerifyChunked, 2},
+		{1000, 1, 0, VerifyBatched, 1000},
+		{1000, 8, 0, VerifyChunked, 125},
+		{1000, 8, 100, VerifyChunked, 100},
+		{1000, 1, 100, VerifyChunked, 100},
+	} {
+		strategy, size := chooseVerifyStrategy(c.n, c.workers, c.maxBatchSize, costs)
+		if strategy != c.strategy || size != c.size {
+			t.Errorf("%d proofs, %d workers, max batch %d: got %v of %d, expected %v of %d",
+				c.n, c.workers, c.maxBatchSize, strategy, size, c.strategy, c.size)
+		}
+	}
+	// with pairings as cheap as the MSM points, individual checks win
+	if strategy, _ := chooseVerifyStrategy(16, 4, 0, VerifyCosts{PairingCheck: 2, MSMPoint: 1}); strategy != VerifyIndividually {
+		t.Fatalf("expected individual checks with cheap pairings, got %v", strategy)
+	}
+}
+
+func TestVerifyAuto(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	if costs := ctx.MeasureVerifyCosts(); costs.PairingCheck <= 0 || costs.MSMPoint <= 0 {
+		t.Fatalf("unexpected measured costs: %+v", costs)
+	}
+	var blobs testBlobs
+	var commitments KZGCommitmentSequenceImpl
+	var proofs KZGProofSequenceImpl
+	for i := 0; i < 6; i++ {
+		poly := randomPolynomialN(16)
+		blob := polynomialToBlob(poly)
+		commitment := ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, blob)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	invalid := append(KZGProofSequenceImpl(nil), proofs...)
+	invalid[5] = proofs[4]
+	malformed := append(testBlobs(nil), blobs...)
+	malformed[3] = polynomialToBlob(randomPolynomialN(16))
+	malformed[3].(testBlob)[5] = [32]byte{0: 0xff, 31: 0xff}
+
+	defer SetMaxWorkers(0)
+	SetMaxWorkers(2)
+	for _, c := range []struct {
+		costs    VerifyCosts
+		strategy VerifyStrategy
+	}{
+		{VerifyCosts{PairingCheck: 1, MSMPoint: 1}, VerifyIndividually},
+		{VerifyCosts{PairingCheck: 1000, MSMPoint: 1}, VerifyChunked},
+	} {
+		ctx.SetVerifyCosts(c.costs)
+		if strategy, _ := ctx.ChooseVerifyStrategy(blobs.Len()); strategy != c.strategy {
+			t.Fatalf("expected %v, got %v", c.strategy, strategy)
+		}
+		if ok, err := ctx.VerifyAuto(blobs, commitments, proofs); err != nil || !ok {
+			t.Fatalf("%v: expected batch to verify: %v", c.strategy, err)
+		}
+		if ok, err := ctx.VerifyAuto(blobs, commitments, invalid); err != nil || ok {
+			t.Fatalf("%v: expected batch with an invalid proof to fail: %v", c.strategy, err)
+		}
+		if _, err := ctx.VerifyAuto(malformed, commitments, proofs); !errors.Is(err, ErrNonCanonicalScalar) {
+			t.Fatalf("%v: expected non-canonical blob to be rejected, got %v", c.strategy, err)
+		}
+		if _, err := ctx.VerifyAuto(blobs, commitments[:5], proofs); !errors.Is(err, ErrLengthMismatch) {
+			t.Fatalf("%v: expected length mismatch, got %v", c.strategy, err)
+		}
+	}
+	SetMaxWorkers(1)
+	if strategy, _ := ctx.ChooseVerifyStrategy(blobs.Len()); strategy != VerifyBatched {
+		t.Fatalf("expected a single worker to batch, got %v", strategy)
+	}
+	if ok, err := ctx.VerifyAuto(blobs, commitments, invalid); err != nil || ok {
+		t.Fatalf("expected batch with an invalid proof to fail: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// VerifyOpts tunes the validation of the inputs of the verification functions, e.g. strict for blobs from gossip,
+// and relaxed for blobs of the own builder. The zero value is the validation of the spec, or of the decode policy
+// of the context (see SetDecodePolicy), which the options apply on top of.
+type VerifyOpts struct {
+	// SkipSubgroupCheck only checks that commitments and proofs are on the curve, not that they are in the G1
+	// subgroup. The pairing check is not sound for points off the subgroup, so this is only for points
+	// from a trusted source, e.g. computed locally. The Herumi backend always checks the subgroup.
+	SkipSubgroupCheck bool
+	// RejectInfinity rejects commitments and proofs at infinity, which the spec accepts (the commitment
+	// to the zero blob, the proof of a constant polynomial), for callers that never expect them.
+	RejectInfinity bool
+	// MaxBlobs, if positive, limits the number of blobs of a batch, checked before decoding any of them.
+	MaxBlobs int
+}
+
+// decodeCommitment decodes a commitment with the options, nil for the defaults, and the decode policy
+// of the context, and returns an error wrapping ErrInvalidCommitment if it is rejected.
+func (opts *VerifyOpts) decodeCommitment(ctx *Context, c KZGCommitment) (*bls.G1Point, error) {
+	var p *bls.G1Point
+	var err error
+	if opts != nil && opts.SkipSubgroupCheck {
+		p, err = ctx.decodeCommitmentUnchecked(c)
+	} else {
+		p, err = ctx.decodeCommitment(c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCommitment, err)
+	}
+	if ((opts != nil && opts.RejectInfinity) || ctx.decodePolicy.RejectInfinityCommitments) && bls.IsZeroG1(p) {
+		return nil, fmt.Errorf("%w: point at infinity", ErrInvalidCommitment)
+	}
+	return p, nil
+}
+
+// decodeProof decodes a proof with the options, nil for the defaults, and the decode policy of the context,
+// and returns an error wrapping ErrMalformedProof if it is rejected.
+func (opts *VerifyOpts) decodeProof(ctx *Context, proof KZGProof) (*bls.G1Point, error) {
+	var p *bls.G1Point
+	var err error
+	if opts != nil && opts.SkipSubgroupCheck {
+		p, err = bls.FromCompressedG1Unchecked(proof[:])
+	} else {
+		p, err = bls.FromCompressedG1(proof[:])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	if ((opts != nil && opts.RejectInfinity) || ctx.decodePolicy.RejectInfinityProofs) && bls.IsZeroG1(p) {
+		return nil, fmt.Errorf("%w: point at infinity", ErrMalformedProof)
+	}
+	return p, nil
+}
+
+// decodeProof decodes a proof with the decode policy of the context, see VerifyOpts.decodeProof.
+func (ctx *Context) decodeProof(proof KZGProof) (*bls.G1Point, error) {
+	return (*VerifyOpts)(nil).decodeProof(ctx, proof)
+}
+
+// VerifyKZGProofWithOpts is VerifyKZGProof, with the inputs validated with the options.
+func (ctx *Context) VerifyKZGProofWithOpts(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof, opts VerifyOpts) (bool, error) {
+	polynomialKZGG1, zFr, yFr, kzgProofG1, err := ctx.decodeKZGOpening(polynomialKZG, z, y, kzgProof, &opts)
+	if err != nil {
+		return false, err
+	}
+	return ctx.VerifyKZGProofFromPoints(polynomialKZGG1, zFr, yFr, kzgProofG1), nil
+}
+
+// VerifyKZGProofWithOpts calls VerifyKZGProofWithOpts on the default context.
+func VerifyKZGProofWithOpts(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof, opts VerifyOpts) (bool, error) {
+	return defaultContext().VerifyKZGProofWithOpts(polynomialKZG, z, y, kzgProof, opts)
+}
+
+// VerifyBlobKZGProofWithOpts is VerifyBlobKZGProof, with the inputs validated with the options.
+func (ctx *Context) VerifyBlobKZGProofWithOpts(blob Blob, commitment KZGCommitment, proof KZGProof, opts VerifyOpts) (bool, error) {
+	commitmentG1, z, y, proofG1, err := ctx.blobKZGProofOpening(blob, commitment, proof, &opts)
+	if err != nil {
+		return false, err
+	}
+	return ctx.VerifyKZGProofFromPoints(commitmentG1, z, y, proofG1), nil
+}
+
+// VerifyBlobKZGProofWithOpts calls VerifyBlobKZGProofWithOpts on the default context.
+func VerifyBlobKZGProofWithOpts(blob Blob, commitment KZGCommitment, proof KZGProof, opts VerifyOpts) (bool, error) {
+	return defaultContext().VerifyBlobKZGProofWithOpts(blob, commitment, proof, opts)
+}
+
+// VerifyBlobKZGProofBatchWithOpts is VerifyBlobKZGProofBatch, with the inputs validated with the options.
+func (ctx *Context) VerifyBlobKZGProofBatchWithOpts(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence,
+	opts VerifyOpts) (bool, error) {
+	return ctx.verifyBlobKZGProofBatch(blobs, commitments, proofs, &opts)
+}
+
+// VerifyBlobKZGProofBatchWithOpts calls VerifyBlobKZGProofBatchWithOpts on the default context.
+func VerifyBlobKZGProofBatchWithOpts(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence,
+	opts VerifyOpts) (bool, error) {
+	return defaultContext().VerifyBlobKZGProofBatchWithOpts(blobs, commitments, proofs, opts)
+}
@@ -0,0 +1,81 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestVerifyOpts(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	blobs := make([]Blob, 3)
+	commitments := make(KZGCommitmentSequenceImpl, 3)
+	proofs := make(KZGProofSequenceImpl, 3)
+	for i := range blobs {
+		poly := randomPolynomialN(16)
+		if i == 0 {
+			// the zero blob commits to the point at infinity
+			poly = make(Polynomial, 16)
+		}
+		blobs[i] = polynomialToBlob(poly)
+		commitments[i] = ctx.PolynomialToKZGCommitment(poly)
+		proof, err := ctx.ComputeBlobKZGProof(blobs[i], commitments[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofs[i] = proof
+	}
+	batch := testBlobs(blobs)
+
+	for _, opts := range []VerifyOpts{{}, {SkipSubgroupCheck: true}, {MaxBlobs: 3}} {
+		if ok, err := ctx.VerifyBlobKZGProofBatchWithOpts(batch, commitments, proofs, opts); err != nil || !ok {
+			t.Fatalf("%+v: expected batch to verify: %v", opts, err)
+		}
+		for i := range blobs {
+			if ok, err := ctx.VerifyBlobKZGProofWithOpts(blobs[i], commitments[i], proofs[i], opts); err != nil || !ok {
+				t.Fatalf("%+v: expected proof %d to verify: %v", opts, i, err)
+			}
+		}
+	}
+
+	strict := VerifyOpts{RejectInfinity: true, MaxBlobs: 2}
+	if _, err := ctx.VerifyBlobKZGProofBatchWithOpts(batch, commitments, proofs, strict); !errors.Is(err, ErrTooManyBlobs) {
+		t.Fatalf("expected too many blobs error, got %v", err)
+	}
+	if _, err := ctx.VerifyBlobKZGProofWithOpts(blobs[0], commitments[0], proofs[0], strict); !errors.Is(err, ErrInvalidCommitment) {
+		t.Fatalf("expected invalid commitment error, got %v", err)
+	}
+	var infinity KZGProof
+	copy(infinity[:], bls.ToCompressedG1(&bls.ZeroG1))
+	var z, y [32]byte
+	if _, err := ctx.VerifyKZGProofWithOpts(commitments[1], z, y, infinity, strict); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("expected malformed proof error, got %v", err)
+	}
+	if _, err := ctx.VerifyKZGProofWithOpts(commitments[1], z, y, infinity, VerifyOpts{}); err != nil {
+		t.Fatalf("expected the point at infinity to be accepted by default: %v", err)
+	}
+
+	// a point on the curve, off the subgroup, is only decoded without the subgroup check
+	offSubgroup := proofs[1]
+	for {
+		offSubgroup[47]++
+		_, errChecked := bls.FromCompressedG1(offSubgroup[:])
+		_, errUnchecked := bls.FromCompressedG1Unchecked(offSubgroup[:])
+		if errChecked != nil && errUnchecked == nil {
+			break
+		}
+		if offSubgroup[47] == proofs[1][47]-1 {
+			t.Skip("the backend always checks the subgroup")
+		}
+	}
+	if _, err := ctx.VerifyBlobKZGProofWithOpts(blobs[1], commitments[1], offSubgroup, VerifyOpts{}); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("expected malformed proof error, got %v", err)
+	}
+	if ok, err := ctx.VerifyBlobKZGProofWithOpts(blobs[1], commitments[1], offSubgroup, VerifyOpts{SkipSubgroupCheck: true}); err != nil || ok {
+		t.Fatalf("expected the proof to be decoded, and not to verify: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// UpdateKey holds the precomputed points to maintain the proof of one blob element as the blob changes,
+// following the aSVC construction (Tomescu et al., "Aggregatable Subvector Commitments for Stateless
+// Cryptocurrencies"). With A(X) = X^n - 1 the vanishing polynomial of the domain, and w the domain point of the
+// index:
+//
+//	A = [A(tau) / (tau - w)]_1
+//	U = [(L_index(tau) - 1) / (tau - w)]_1
+type UpdateKey struct {
+	// Index of the field element in the blob, in the bit-reversed order of the blob.
+	Index uint64
+	A     bls.G1Point
+	U     bls.G1Point
+}
+
+// updateKeyA computes [A(tau) / (tau - w)]_1 = A'(w) * [L_index(tau)]_1, where A'(w) = n / w.
+func (ctx *Context) updateKeyA(dst *bls.G1Point, index uint64) {
+	var factor bls.Fr
+	bls.AsFr(&factor, uint64(len(ctx.domain)))
+	bls.DivModFr(&factor, &factor, &ctx.domain[index])
+	bls.MulG1(dst, &ctx.setupLagrange[index], &factor)
+}
+
+// ComputeUpdateKey computes the update key of the blob element at index, with an MSM over the Lagrange setup.
+func (ctx *Context) ComputeUpdateKey(index uint64) (*UpdateKey, error) {
+	n := len(ctx.domain)
+	if index >= uint64(n) {
+		return nil, fmt.Errorf("index %d is out of range of a blob of %d field elements", index, n)
+	}
+	key := &UpdateKey{Index: index}
+	ctx.updateKeyA(&key.A, index)
+
+	// (L_index(X) - 1) / (X - w) in evaluation form: 1 / (w - DOMAIN[k]) at the other points of the domain,
+	// and the derivative L_index'(w) = (n - 1) / (2w) at w itself.
+	w := &ctx.domain[index]
+	denominators := make([]bls.Fr, n)
+	for k := range denominators {
+		if uint64(k) == index {
+			var two bls.Fr
+			bls.AsFr(&two, 2)
+			bls.MulModFr(&denominators[k], &two, w)
+			continue
+		}
+		bls.SubModFr(&denominators[k], w, &ctx.domain[k])
+	}
+	scalars := make([]bls.Fr, n)
+	bls.BatchInvModFr(scalars, denominators)
+	var nMinusOne bls.Fr
+	bls.AsFr(&nMinusOne, uint64(n-1))
+	bls.MulModFr(&scalars[index], &scalars[index], &nMinusOne)
+	bls.CopyG1(&key.U, bls.LinCombG1(ctx.setupLagrange, scalars))
+	return key, nil
+}
+
+// ComputeUpdateKey calls ComputeUpdateKey on the default context.
+func ComputeUpdateKey(index uint64) (*UpdateKey, error) {
+	return defaultContext().ComputeUpdateKey(index)
+}
+
+// ComputeUpdateKeys computes the update keys of all the blob elements, in parallel (see SetMaxWorkers).
+// This is an MSM over the whole blob per element: it is meant to be done once, and the keys stored.
+func (ctx *Context) ComputeUpdateKeys() []UpdateKey {
+	keys := make([]UpdateKey, len(ctx.domain))
+	parallelFor(len(keys), func(i int) {
+		key, _ := ctx.ComputeUpdateKey(uint64(i))
+		keys[i] = *key
+	})
+	return keys
+}
+
+// ComputeUpdateKeys calls ComputeUpdateKeys on the default context.
+func ComputeUpdateKeys() []UpdateKey {
+	return defaultContext().ComputeUpdateKeys()
+}
+
+// UpdateProof updates the proof of the blob element at key.Index, as computed by ComputeKZGProof at its domain
+// point, after the field element at changedIndex changed from oldValue to newValue, in constant time:
+//
+//	changedIndex == key.Index: proof + (new - old) * U
+//	otherwise:                 proof + (new - old) / (A'(w_j) * (w_j - w_i)) * (A_j - A_i)
+//
+// where i is key.Index and j is changedIndex. A_j is derived from the Lagrange setup, so only the key of the
+// proven element is needed. The commitment can be updated alike with UpdateCommitment.
+func (ctx *Context) UpdateProof(proof KZGProof, key *UpdateKey, changedIndex uint64, oldValue, newValue *bls.Fr) (KZGProof, error) {
+	n := uint64(len(ctx.domain))
+	if key.Index >= n {
+		return KZGProof{}, fmt.Errorf("key index %d is out of range of a blob of %d field elements", key.Index, n)
+	}
+	if changedIndex >= n {
+		return KZGProof{}, fmt.Errorf("index %d is out of range of a blob of %d field elements", changedIndex, n)
+	}
+	proofG1, err := bls.FromCompressedG1(proof[:])
+	if err != nil {
+		return KZGProof{}, fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	var delta bls.Fr
+	bls.SubModFr(&delta, newValue, oldValue)
+	var diff bls.G1Point
+	if changedIndex == key.Index {
+		bls.MulG1(&diff, &key.U, &delta)
+	} else {
+		// A'(w_j) * (w_j - w_i) = n / w_j * (w_j - w_i)
+		wi, wj := &ctx.domain[key.Index], &ctx.domain[changedIndex]
+		var factor, denominator bls.Fr
+		bls.SubModFr(&denominator, wj, wi)
+		bls.AsFr(&factor, n)
+		bls.MulModFr(&denominator, &denominator, &factor)
+		bls.DivModFr(&factor, wj, &denominator)
+		bls.MulModFr(&factor, &factor, &delta)
+		var aj bls.G1Point
+		ctx.updateKeyA(&aj, changedIndex)
+		bls.SubG1(&aj, &aj, &key.A)
+		bls.MulG1(&diff, &aj, &factor)
+	}
+	var updated bls.G1Point
+	bls.AddG1(&updated, proofG1, &diff)
+	var out KZGProof
+	copy(out[:], bls.ToCompressedG1(&updated))
+	return out, nil
+}
+
+// UpdateProof calls UpdateProof on the default context.
+func UpdateProof(proof KZGProof, key *UpdateKey, changedIndex uint64, oldValue, newValue *bls.Fr) (KZGProof, error) {
+	return defaultContext().UpdateProof(proof, key, changedIndex, oldValue, newValue)
+}
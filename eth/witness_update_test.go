@@ -0,0 +1,39 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestUpdateProof(t *testing.T) {
+	ctx := newTestContext(t, 4)
+	keys := ctx.ComputeUpdateKeys()
+	poly := randomPolynomialN(16)
+	const proven = 3
+	proof, err := ctx.ComputeKZGProof(poly, &ctx.domain[proven])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, changed := range []uint64{proven, 0, 11, proven} {
+		newValue := bls.RandomFr()
+		proof, err = ctx.UpdateProof(proof, &keys[proven], changed, &poly[changed], newValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bls.CopyFr(&poly[changed], newValue)
+		expected, err := ctx.ComputeKZGProof(poly, &ctx.domain[proven])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof != expected {
+			t.Fatalf("updated proof after a change at index %d does not match the recomputed proof", changed)
+		}
+	}
+	if _, err := ctx.UpdateProof(proof, &keys[proven], 16, &bls.ZERO, &bls.ONE); err == nil {
+		t.Fatal("expected an index out of the blob to be rejected")
+	}
+}
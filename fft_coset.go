@@ -0,0 +1,69 @@
+package kzg
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// CosetFFT is FFT over the coset gH of the domain H of the roots of unity of the same size:
+// it evaluates the polynomial with the given coefficients at g*w^i, or with inv, interpolates the evaluations
+// at g*w^i back into coefficients. As with FFT, the values are padded with zeros up to a power of two.
+// The evaluations over a coset that does not intersect H extend those over H without zero-padding,
+// and do not cancel the vanishing polynomial of H, so that it can be divided by, as in recovery.
+func (fs *FFTSettings) CosetFFT(vals []bls.Fr, g *bls.Fr, inv bool) ([]bls.Fr, error) {
+	n := uint64(len(vals))
+	if n > fs.MaxWidth {
+		return nil, fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
+	}
+	n = nextPowOf2(n)
+	out := make([]bls.Fr, n, n)
+	for i := 0; i < len(vals); i++ {
+		bls.CopyFr(&out[i], &vals[i])
+	}
+	for i := uint64(len(vals)); i < n; i++ {
+		bls.CopyFr(&out[i], &bls.ZERO)
+	}
+	if err := fs.CosetFFTInPlace(out, g, inv); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CosetFFTInPlace is CosetFFT without any allocation, see FFTInPlace.
+func (fs *FFTSettings) CosetFFTInPlace(vals []bls.Fr, g *bls.Fr, inv bool) error {
+	if bls.EqualZero(g) {
+		return errors.New("coset generator must not be zero")
+	}
+	n := uint64(len(vals))
+	if n > fs.MaxWidth {
+		return fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
+	}
+	if !bls.IsPowerOfTwo(n) {
+		return fmt.Errorf("got %d values but not a power of two", n)
+	}
+	if inv {
+		// p(g*X) has the coefficients c_i * g^i, interpolated over H, then scaled back
+		fs.fftInPlace(vals, true)
+		var gInv bls.Fr
+		bls.InvModFr(&gInv, g)
+		scaleByPowers(vals, &gInv)
+	} else {
+		scaleByPowers(vals, g)
+		fs.fftInPlace(vals, false)
+	}
+	return nil
+}
+
+// scaleByPowers multiplies each value by factor^i, in place.
+func scaleByPowers(vals []bls.Fr, factor *bls.Fr) {
+	var power, tmp bls.Fr
+	bls.CopyFr(&power, &bls.ONE)
+	for i := range vals {
+		bls.MulModFr(&tmp, &vals[i], &power)
+		bls.CopyFr(&vals[i], &tmp)
+		bls.MulModFr(&tmp, &power, factor)
+		bls.CopyFr(&power, &tmp)
+	}
+}
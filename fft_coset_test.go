@@ -0,0 +1,49 @@
+package kzg
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestCosetFFT(t *testing.T) {
+	fs := NewFFTSettings(5)
+	var g bls.Fr
+	bls.AsFr(&g, 7)
+	coeffs := make([]bls.Fr, 20)
+	for i := range coeffs {
+		bls.CopyFr(&coeffs[i], bls.RandomFr())
+	}
+	evals, err := fs.CosetFFT(coeffs, &g, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evals) != 32 {
+		t.Fatalf("expected padding to 32 values, got %d", len(evals))
+	}
+	var x bls.Fr
+	for i := range evals {
+		bls.MulModFr(&x, &g, &fs.ExpandedRootsOfUnity[i])
+		var expected bls.Fr
+		bls.EvalPolyAt(&expected, coeffs, &x)
+		if !bls.EqualFr(&evals[i], &expected) {
+			t.Fatalf("evaluation %d mismatch: got %s, expected %s", i, bls.FrStr(&evals[i]), bls.FrStr(&expected))
+		}
+	}
+	back, err := fs.CosetFFT(evals, &g, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range back {
+		expected := &bls.ZERO
+		if i < len(coeffs) {
+			expected = &coeffs[i]
+		}
+		if !bls.EqualFr(&back[i], expected) {
+			t.Fatalf("coefficient %d mismatch", i)
+		}
+	}
+	if _, err := fs.CosetFFT(coeffs, &bls.ZERO, false); err == nil {
+		t.Fatal("expected a zero coset generator to be rejected")
+	}
+}
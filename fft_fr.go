@@ -2,6 +2,8 @@ package kzg
 
 import (
 	"fmt"
+	"sync"
+
 	"github.com/protolambda/go-kzg/bls"
 )
 
@@ -59,21 +61,76 @@ func (fs *FFTSettings) FFT(vals []bls.Fr, inv bool) ([]bls.Fr, error) {
 	}
 	n = nextPowOf2(n)
 	// We make a copy so we can mutate it during the work.
-	valsCopy := make([]bls.Fr, n, n)
+	out := make([]bls.Fr, n, n)
 	for i := 0; i < len(vals); i++ {
-		bls.CopyFr(&valsCopy[i], &vals[i])
+		bls.CopyFr(&out[i], &vals[i])
 	}
 	for i := uint64(len(vals)); i < n; i++ {
-		bls.CopyFr(&valsCopy[i], &bls.ZERO)
+		bls.CopyFr(&out[i], &bls.ZERO)
+	}
+	fs.fftInPlace(out, inv)
+	return out, nil
+}
+
+// InplaceFFT writes the transform of vals to out, of the same length, without allocating.
+// Unlike FFTInPlace, the input is left unchanged.
+func (fs *FFTSettings) InplaceFFT(vals []bls.Fr, out []bls.Fr, inv bool) error {
+	n := uint64(len(vals))
+	if n > fs.MaxWidth {
+		return fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
+	}
+	if !bls.IsPowerOfTwo(n) {
+		return fmt.Errorf("got %d values but not a power of two", n)
+	}
+	if uint64(len(out)) != n {
+		return fmt.Errorf("got %d values but %d outputs", n, len(out))
+	}
+	for i := range vals {
+		bls.CopyFr(&out[i], &vals[i])
 	}
+	fs.fftInPlace(out, inv)
+	return nil
+}
+
+// FFTInPlace is FFT without any allocation: the values are replaced by their transform.
+// The number of values must be a power of two, as no room is left for padding.
+func (fs *FFTSettings) FFTInPlace(vals []bls.Fr, inv bool) error {
+	n := uint64(len(vals))
+	if n > fs.MaxWidth {
+		return fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
+	}
+	if !bls.IsPowerOfTwo(n) {
+		return fmt.Errorf("got %d values but not a power of two", n)
+	}
+	fs.fftInPlace(vals, inv)
+	return nil
+}
+
+// ParallelFFTThreshold is the smallest number of values for which FFTParallel and FFTInPlaceParallel
+// use several goroutines: below it, the synchronization of every layer costs more than its butterflies.
+const ParallelFFTThreshold = 1 << 12
+
+// FFTParallel is FFT, with the butterflies of every layer split across MaxWorkers goroutines
+// for at least ParallelFFTThreshold values.
+func (fs *FFTSettings) FFTParallel(vals []bls.Fr, inv bool) ([]bls.Fr, error) {
+	n := uint64(len(vals))
+	if n > fs.MaxWidth {
+		return nil, fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
+	}
+	n = nextPowOf2(n)
 	out := make([]bls.Fr, n, n)
-	if err := fs.InplaceFFT(valsCopy, out, inv); err != nil {
-		return nil, err
+	for i := 0; i < len(vals); i++ {
+		bls.CopyFr(&out[i], &vals[i])
 	}
+	for i := uint64(len(vals)); i < n; i++ {
+		bls.CopyFr(&out[i], &bls.ZERO)
+	}
+	fs.fftInPlaceWorkers(out, inv, fftWorkers(n))
 	return out, nil
 }
 
-func (fs *FFTSettings) InplaceFFT(vals []bls.Fr, out []bls.Fr, inv bool) error {
+// FFTInPlaceParallel is FFTInPlace, parallelized like FFTParallel.
+func (fs *FFTSettings) FFTInPlaceParallel(vals []bls.Fr, inv bool) error {
 	n := uint64(len(vals))
 	if n > fs.MaxWidth {
 		return fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
@@ -81,26 +138,115 @@ func (fs *FFTSettings) InplaceFFT(vals []bls.Fr, out []bls.Fr, inv bool) error {
 	if !bls.IsPowerOfTwo(n) {
 		return fmt.Errorf("got %d values but not a power of two", n)
 	}
+	fs.fftInPlaceWorkers(vals, inv, fftWorkers(n))
+	return nil
+}
+
+// fftWorkers returns the number of goroutines to transform n values with.
+func fftWorkers(n uint64) int {
+	if n < ParallelFFTThreshold {
+		return 1
+	}
+	return MaxWorkers()
+}
+
+func (fs *FFTSettings) fftInPlace(vals []bls.Fr, inv bool) {
+	fs.fftInPlaceWorkers(vals, inv, 1)
+}
+
+// fftInPlaceWorkers is an iterative transform of a power of two number of values: the values are bit-reversed
+// first, then combined bottom-up, two layers at a time with radix-4 butterflies, halving the passes over the values
+// compared to radix-2. With an odd number of layers, the first one is a radix-2 layer.
+// The butterflies of a layer are independent, and are split across the given number of goroutines.
+func (fs *FFTSettings) fftInPlaceWorkers(vals []bls.Fr, inv bool, workers int) {
+	defer bls.StartTraceRegion("kzg fft").End()
+	n := uint64(len(vals))
+	rootz := fs.ExpandedRootsOfUnity
+	if inv {
+		rootz = fs.ReverseRootsOfUnity
+	}
+	reverseBitOrderFr(vals)
+	m := uint64(1)
+	if n&0xAAAAAAAAAAAAAAAA != 0 {
+		// odd number of layers
+		fftSplit(n/2, workers, func(lo, hi uint64) {
+			var x bls.Fr
+			for k := 2 * lo; k < 2*hi; k += 2 {
+				bls.CopyFr(&x, &vals[k])
+				bls.AddModFr(&vals[k], &x, &vals[k+1])
+				bls.SubModFr(&vals[k+1], &x, &vals[k+1])
+			}
+		})
+		m = 2
+	}
+	for ; m < n; m <<= 2 {
+		fftSplit(n/4, workers, func(lo, hi uint64) {
+			radix4Layer(vals, rootz, fs.MaxWidth/(m<<2), m, lo, hi)
+		})
+	}
 	if inv {
 		var invLen bls.Fr
 		bls.AsFr(&invLen, n)
 		bls.InvModFr(&invLen, &invLen)
-		rootz := fs.ReverseRootsOfUnity[:fs.MaxWidth]
-		stride := fs.MaxWidth / n
-
-		fs._fft(vals, 0, 1, rootz, stride, out)
-		var tmp bls.Fr
-		for i := 0; i < len(out); i++ {
-			bls.MulModFr(&tmp, &out[i], &invLen)
-			bls.CopyFr(&out[i], &tmp) // TODO: depending on Fr implementation, allow to directly write back to an input
+		fftSplit(n, workers, func(lo, hi uint64) {
+			var tmp bls.Fr
+			for i := lo; i < hi; i++ {
+				bls.MulModFr(&tmp, &vals[i], &invLen)
+				bls.CopyFr(&vals[i], &tmp)
+			}
+		})
+	}
+}
+
+// fftSplit runs fn over [0, count) split in as many contiguous ranges as workers, concurrently if more than one.
+func fftSplit(count uint64, workers int, fn func(lo, hi uint64)) {
+	if workers <= 1 || count < uint64(workers) {
+		fn(0, count)
+		return
+	}
+	var wg sync.WaitGroup
+	w := uint64(workers)
+	for i := uint64(0); i < w; i++ {
+		wg.Add(1)
+		go func(lo, hi uint64) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(count*i/w, count*(i+1)/w)
+	}
+	wg.Wait()
+}
+
+// radix4Layer runs the butterflies lo to hi (of n/4) of the layer combining four blocks of size m
+// into a block of size 4m, with the roots of unity of that size at the given stride.
+func radix4Layer(vals []bls.Fr, rootz []bls.Fr, stride uint64, m uint64, lo, hi uint64) {
+	// the fourth root of unity, or its inverse
+	quarter := &rootz[m*stride]
+	var b1, b2, b3, s0, d0, s1, d1, x bls.Fr
+	k, j := (lo/m)*(m<<2), lo%m
+	for t := lo; t < hi; t, j = t+1, j+1 {
+		if j == m {
+			k, j = k+(m<<2), 0
+		}
+		a0, a1, a2, a3 := &vals[k+j], &vals[k+j+m], &vals[k+j+2*m], &vals[k+j+3*m]
+		if j == 0 {
+			bls.CopyFr(&b1, a1)
+			bls.CopyFr(&b2, a2)
+			bls.CopyFr(&b3, a3)
+		} else {
+			bls.MulModFr(&b1, a1, &rootz[2*j*stride])
+			bls.MulModFr(&b2, a2, &rootz[j*stride])
+			bls.MulModFr(&b3, a3, &rootz[3*j*stride])
 		}
-		return nil
-	} else {
-		rootz := fs.ExpandedRootsOfUnity[:fs.MaxWidth]
-		stride := fs.MaxWidth / n
-		// Regular FFT
-		fs._fft(vals, 0, 1, rootz, stride, out)
-		return nil
+		// the two radix-2 layers: first (a0, a1) and (a2, a3), then the results crosswise
+		bls.AddModFr(&s0, a0, &b1)
+		bls.SubModFr(&d0, a0, &b1)
+		bls.AddModFr(&s1, &b2, &b3)
+		bls.SubModFr(&x, &b2, &b3)
+		bls.MulModFr(&d1, &x, quarter)
+		bls.AddModFr(a0, &s0, &s1)
+		bls.SubModFr(a2, &s0, &s1)
+		bls.AddModFr(a1, &d0, &d1)
+		bls.SubModFr(a3, &d0, &d1)
 	}
 }
 
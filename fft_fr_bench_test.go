@@ -31,3 +31,39 @@ func BenchmarkFFTSettings_FFT(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkFFTSettings_FFTInPlace(b *testing.B) {
+	for scale := uint8(4); scale < 16; scale++ {
+		b.Run(fmt.Sprintf("scale_%d", scale), func(b *testing.B) {
+			fs := NewFFTSettings(scale)
+			data := make([]bls.Fr, fs.MaxWidth, fs.MaxWidth)
+			for i := uint64(0); i < fs.MaxWidth; i++ {
+				bls.CopyFr(&data[i], bls.RandomFr())
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := fs.FFTInPlace(data, i%2 == 1); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFFTSettings_FFTParallel(b *testing.B) {
+	for scale := uint8(10); scale < 16; scale++ {
+		b.Run(fmt.Sprintf("scale_%d", scale), func(b *testing.B) {
+			fs := NewFFTSettings(scale)
+			data := make([]bls.Fr, fs.MaxWidth, fs.MaxWidth)
+			for i := uint64(0); i < fs.MaxWidth; i++ {
+				bls.CopyFr(&data[i], bls.RandomFr())
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := fs.FFTInPlaceParallel(data, i%2 == 1); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
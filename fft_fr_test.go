@@ -29,6 +29,83 @@ func TestFFTRoundtrip(t *testing.T) {
 	t.Log("zero", bls.FrStr(&bls.ONE))
 }
 
+func TestFFTInPlace(t *testing.T) {
+	fs := NewFFTSettings(6)
+	for _, n := range []uint64{1, 2, 4, 8, 16, 32, fs.MaxWidth} {
+		for _, inv := range []bool{false, true} {
+			data := make([]bls.Fr, n, n)
+			for i := range data {
+				bls.CopyFr(&data[i], bls.RandomFr())
+			}
+			// reference: the recursive radix-2 transform
+			rootz, stride := fs.ExpandedRootsOfUnity, fs.MaxWidth/n
+			if inv {
+				rootz = fs.ReverseRootsOfUnity
+			}
+			expected := make([]bls.Fr, n, n)
+			fs._fft(data, 0, 1, rootz, stride, expected)
+			if inv {
+				var invLen bls.Fr
+				bls.AsFr(&invLen, n)
+				bls.InvModFr(&invLen, &invLen)
+				for i := range expected {
+					bls.MulModFr(&expected[i], &expected[i], &invLen)
+				}
+			}
+			if err := fs.FFTInPlace(data, inv); err != nil {
+				t.Fatal(err)
+			}
+			for i := range data {
+				if !bls.EqualFr(&data[i], &expected[i]) {
+					t.Fatalf("n %d inv %v: difference at %d: got: %s  expected: %s", n, inv, i, bls.FrStr(&data[i]), bls.FrStr(&expected[i]))
+				}
+			}
+		}
+	}
+	if err := fs.FFTInPlace(make([]bls.Fr, 3), false); err == nil {
+		t.Fatal("expected a length that is not a power of two to be rejected")
+	}
+}
+
+func TestFFTParallel(t *testing.T) {
+	fs := NewFFTSettings(13)
+	for _, scale := range []uint8{5, 6, 12, 13} {
+		n := uint64(1) << scale
+		for _, inv := range []bool{false, true} {
+			data := make([]bls.Fr, n, n)
+			for i := range data {
+				bls.CopyFr(&data[i], bls.RandomFr())
+			}
+			expected, err := fs.FFT(data, inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := fs.FFTParallel(data, inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// an uneven split, whatever the number of CPUs
+			fs.fftInPlaceWorkers(data, inv, 3)
+			for i := range data {
+				if !bls.EqualFr(&got[i], &expected[i]) || !bls.EqualFr(&data[i], &expected[i]) {
+					t.Fatalf("n %d inv %v: difference at %d", n, inv, i)
+				}
+			}
+		}
+	}
+}
+
+func TestFFTMaxWorkers(t *testing.T) {
+	defer SetMaxWorkers(0)
+	if fftWorkers(ParallelFFTThreshold) != MaxWorkers() || fftWorkers(ParallelFFTThreshold/2) != 1 {
+		t.Fatal("expected MaxWorkers goroutines from the threshold, and one below")
+	}
+	SetMaxWorkers(2)
+	if fftWorkers(ParallelFFTThreshold) != 2 {
+		t.Fatalf("expected the transform to be capped to 2 workers, got %d", fftWorkers(ParallelFFTThreshold))
+	}
+}
+
 func TestInvFFT(t *testing.T) {
 	fs := NewFFTSettings(4)
 	data := make([]bls.Fr, fs.MaxWidth, fs.MaxWidth)
@@ -1,59 +1,17 @@
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package kzg
 
 import (
 	"fmt"
+
 	"github.com/protolambda/go-kzg/bls"
 )
 
-func (fs *FFTSettings) simpleFTG1(vals []bls.G1Point, valsOffset uint64, valsStride uint64, rootsOfUnity []bls.Fr, rootsOfUnityStride uint64, out []bls.G1Point) {
-	l := uint64(len(out))
-	var v bls.G1Point
-	var tmp bls.G1Point
-	var last bls.G1Point
-	for i := uint64(0); i < l; i++ {
-		jv := &vals[valsOffset]
-		r := &rootsOfUnity[0]
-		bls.MulG1(&v, jv, r)
-		bls.CopyG1(&last, &v)
-
-		for j := uint64(1); j < l; j++ {
-			jv := &vals[valsOffset+j*valsStride]
-			r := &rootsOfUnity[((i*j)%l)*rootsOfUnityStride]
-			bls.MulG1(&v, jv, r)
-			bls.CopyG1(&tmp, &last)
-			bls.AddG1(&last, &tmp, &v)
-		}
-		bls.CopyG1(&out[i], &last)
-	}
-}
-
-func (fs *FFTSettings) _fftG1(vals []bls.G1Point, valsOffset uint64, valsStride uint64, rootsOfUnity []bls.Fr, rootsOfUnityStride uint64, out []bls.G1Point) {
-	if len(out) <= 4 { // if the value count is small, run the unoptimized version instead. // TODO tune threshold. (can be different for G1)
-		fs.simpleFTG1(vals, valsOffset, valsStride, rootsOfUnity, rootsOfUnityStride, out)
-		return
-	}
-
-	half := uint64(len(out)) >> 1
-	// L will be the left half of out
-	fs._fftG1(vals, valsOffset, valsStride<<1, rootsOfUnity, rootsOfUnityStride<<1, out[:half])
-	// R will be the right half of out
-	fs._fftG1(vals, valsOffset+valsStride, valsStride<<1, rootsOfUnity, rootsOfUnityStride<<1, out[half:]) // just take even again
-
-	var yTimesRoot bls.G1Point
-	var x, y bls.G1Point
-	for i := uint64(0); i < half; i++ {
-		// temporary copies, so that writing to output doesn't conflict with input
-		bls.CopyG1(&x, &out[i])
-		bls.CopyG1(&y, &out[i+half])
-		root := &rootsOfUnity[i*rootsOfUnityStride]
-		bls.MulG1(&yTimesRoot, &y, root)
-		bls.AddG1(&out[i], &x, &yTimesRoot)
-		bls.SubG1(&out[i+half], &x, &yTimesRoot)
-	}
-}
+// ParallelFFTG1Threshold is the smallest number of points for which the G1 transforms use several goroutines.
+// A G1 butterfly costs a scalar multiplication, so even small transforms are worth splitting.
+const ParallelFFTG1Threshold = 32
 
 func (fs *FFTSettings) FFTG1(vals []bls.G1Point, inv bool) ([]bls.G1Point, error) {
 	n := uint64(len(vals))
@@ -64,32 +22,90 @@ func (fs *FFTSettings) FFTG1(vals []bls.G1Point, inv bool) ([]bls.G1Point, error
 		return nil, fmt.Errorf("got %d values but not a power of two", n)
 	}
 	// We make a copy so we can mutate it during the work.
-	valsCopy := make([]bls.G1Point, n, n)
-	for i := 0; i < len(vals); i++ { // TODO: maybe optimize this away, and write back to original input array?
-		bls.CopyG1(&valsCopy[i], &vals[i])
+	out := make([]bls.G1Point, n, n)
+	for i := 0; i < len(vals); i++ {
+		bls.CopyG1(&out[i], &vals[i])
+	}
+	fs.fftG1InPlace(out, inv)
+	return out, nil
+}
+
+// FFTG1InPlace is FFTG1 without any allocation: the points are replaced by their transform.
+// See FFTInPlace.
+func (fs *FFTSettings) FFTG1InPlace(vals []bls.G1Point, inv bool) error {
+	n := uint64(len(vals))
+	if n > fs.MaxWidth {
+		return fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
+	}
+	if !bls.IsPowerOfTwo(n) {
+		return fmt.Errorf("got %d values but not a power of two", n)
 	}
+	fs.fftG1InPlace(vals, inv)
+	return nil
+}
+
+// fftG1InPlace is an iterative radix-2 transform of a power of two number of points: the points are bit-reversed
+// first, then combined bottom-up. The butterflies of every layer are independent, and are split across
+// MaxWorkers goroutines from ParallelFFTG1Threshold points, as FK20 spends most of its time here.
+// The butterflies with a unit root, a sixth of them for 4096 points, skip their scalar multiplication.
+// The other scalar multiplications are left to the curve backend: doublings are not shared between butterflies.
+func (fs *FFTSettings) fftG1InPlace(vals []bls.G1Point, inv bool) {
+	workers := fftG1Workers(uint64(len(vals)))
+	fs.fftG1Layers(vals, inv, workers)
 	if inv {
+		n := uint64(len(vals))
 		var invLen bls.Fr
 		bls.AsFr(&invLen, n)
 		bls.InvModFr(&invLen, &invLen)
-		rootz := fs.ReverseRootsOfUnity[:fs.MaxWidth]
-		stride := fs.MaxWidth / n
+		fftSplit(n, workers, func(lo, hi uint64) {
+			var tmp bls.G1Point
+			for i := lo; i < hi; i++ {
+				bls.MulG1(&tmp, &vals[i], &invLen)
+				bls.CopyG1(&vals[i], &tmp)
+			}
+		})
+	}
+}
+
+// fftG1Workers returns the number of goroutines to transform n points with.
+func fftG1Workers(n uint64) int {
+	if n < ParallelFFTG1Threshold {
+		return 1
+	}
+	return MaxWorkers()
+}
 
-		out := make([]bls.G1Point, n, n)
-		fs._fftG1(valsCopy, 0, 1, rootz, stride, out)
-		var tmp bls.G1Point
-		for i := 0; i < len(out); i++ {
-			bls.MulG1(&tmp, &out[i], &invLen)
-			bls.CopyG1(&out[i], &tmp)
-		}
-		return out, nil
-	} else {
-		out := make([]bls.G1Point, n, n)
-		rootz := fs.ExpandedRootsOfUnity[:fs.MaxWidth]
-		stride := fs.MaxWidth / n
-		// Regular FFT
-		fs._fftG1(valsCopy, 0, 1, rootz, stride, out)
-		return out, nil
+// fftG1Layers is fftG1InPlace without the 1/n scaling of the inverse transform,
+// for callers that can apply it more cheaply to field elements beforehand.
+func (fs *FFTSettings) fftG1Layers(vals []bls.G1Point, inv bool, workers int) {
+	defer bls.StartTraceRegion("kzg fft g1").End()
+	n := uint64(len(vals))
+	rootz := fs.ExpandedRootsOfUnity
+	if inv {
+		rootz = fs.ReverseRootsOfUnity
+	}
+	reverseBitOrderG1(vals)
+	for m := uint64(1); m < n; m <<= 1 {
+		// combine two blocks of size m into a block of size 2m
+		stride := fs.MaxWidth / (m << 1)
+		fftSplit(n/2, workers, func(lo, hi uint64) {
+			var yTimesRoot, x bls.G1Point
+			k, j := (lo/m)*(m<<1), lo%m
+			for t := lo; t < hi; t, j = t+1, j+1 {
+				if j == m {
+					k, j = k+(m<<1), 0
+				}
+				a, b := &vals[k+j], &vals[k+j+m]
+				if j == 0 {
+					bls.CopyG1(&yTimesRoot, b)
+				} else {
+					bls.MulG1(&yTimesRoot, b, &rootz[j*stride])
+				}
+				bls.CopyG1(&x, a)
+				bls.AddG1(a, &x, &yTimesRoot)
+				bls.SubG1(b, &x, &yTimesRoot)
+			}
+		})
 	}
 }
 
@@ -0,0 +1,70 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// TestFFTG1 checks the G1 transform of multiples of the generator against the field transform of the scalars.
+func TestFFTG1(t *testing.T) {
+	fs := NewFFTSettings(6)
+	for _, n := range []uint64{1, 2, 8, fs.MaxWidth} {
+		for _, inv := range []bool{false, true} {
+			scalars := make([]bls.Fr, n, n)
+			points := make([]bls.G1Point, n, n)
+			for i := range scalars {
+				bls.CopyFr(&scalars[i], bls.RandomFr())
+				bls.MulG1(&points[i], &bls.GenG1, &scalars[i])
+			}
+			expected, err := fs.FFT(scalars, inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := fs.FFTG1(points, inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var p bls.G1Point
+			for i := range got {
+				bls.MulG1(&p, &bls.GenG1, &expected[i])
+				if !bls.EqualG1(&got[i], &p) {
+					t.Fatalf("n %d inv %v: difference at %d", n, inv, i)
+				}
+			}
+		}
+	}
+}
+
+func TestFFTG1InPlace(t *testing.T) {
+	fs := NewFFTSettings(4)
+	for _, inv := range []bool{false, true} {
+		data := make([]bls.G1Point, fs.MaxWidth, fs.MaxWidth)
+		for i := range data {
+			bls.MulG1(&data[i], &bls.GenG1, bls.RandomFr())
+		}
+		expected, err := fs.FFTG1(data, inv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.FFTG1InPlace(data, inv); err != nil {
+			t.Fatal(err)
+		}
+		for i := range data {
+			if !bls.EqualG1(&data[i], &expected[i]) {
+				t.Fatalf("inv %v: difference at %d", inv, i)
+			}
+		}
+	}
+}
+
+func TestFFTG1MaxWorkers(t *testing.T) {
+	defer SetMaxWorkers(0)
+	SetMaxWorkers(2)
+	if fftG1Workers(ParallelFFTG1Threshold) != 2 || fftG1Workers(ParallelFFTG1Threshold/2) != 1 {
+		t.Fatalf("expected the G1 transform to be capped to 2 workers, got %d", fftG1Workers(ParallelFFTG1Threshold))
+	}
+}
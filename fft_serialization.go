@@ -0,0 +1,86 @@
+package kzg
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Header of serialized FFT settings: magic (8 bytes), max width (8 bytes, little-endian).
+const fftSettingsMagic = "KZGFFT01"
+
+// WriteTo writes the FFT settings, so that large domains can be loaded with ReadFFTSettings instead of
+// being computed again. Only the expanded roots of unity are written, as 32-byte little-endian field elements:
+// the reverse roots are the same values, in reverse order.
+func (fs *FFTSettings) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var header [16]byte
+	copy(header[:8], fftSettingsMagic)
+	binary.LittleEndian.PutUint64(header[8:], fs.MaxWidth)
+	n, err := bw.Write(header[:])
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+	for i := range fs.ExpandedRootsOfUnity {
+		b := bls.FrTo32(&fs.ExpandedRootsOfUnity[i])
+		n, err := bw.Write(b[:])
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, bw.Flush()
+}
+
+// ReadFFTSettings reads FFT settings previously written with WriteTo.
+// The root of unity is checked to be the one NewFFTSettings would use, and the expanded roots to start and end
+// with one, but checking every root would cost as much as computing them:
+// callers should only load settings from trusted storage.
+func ReadFFTSettings(r io.Reader) (*FFTSettings, error) {
+	br := bufio.NewReader(r)
+	var header [16]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return nil, fmt.Errorf("failed to read FFT settings header: %v", err)
+	}
+	if string(header[:8]) != fftSettingsMagic {
+		return nil, errors.New("not serialized FFT settings")
+	}
+	width := binary.LittleEndian.Uint64(header[8:])
+	if width == 0 || width&(width-1) != 0 {
+		return nil, fmt.Errorf("width must be a power of two, got %d", width)
+	}
+	scale := bits.TrailingZeros64(width)
+	if scale >= len(bls.Scale2RootOfUnity) {
+		return nil, fmt.Errorf("width %d is too large", width)
+	}
+	rootz := make([]bls.Fr, width+1)
+	var b [32]byte
+	for i := range rootz {
+		if _, err := io.ReadFull(br, b[:]); err != nil {
+			return nil, fmt.Errorf("failed to read root of unity %d: %v", i, err)
+		}
+		if !bls.FrFrom32(&rootz[i], b) {
+			return nil, fmt.Errorf("root of unity %d is not a canonical field element", i)
+		}
+	}
+	root := &bls.Scale2RootOfUnity[scale]
+	if !bls.EqualOne(&rootz[0]) || !bls.EqualOne(&rootz[width]) || (width > 1 && !bls.EqualFr(&rootz[1], root)) {
+		return nil, errors.New("roots of unity do not match the domain")
+	}
+	rootzReverse := make([]bls.Fr, len(rootz))
+	for i := range rootz {
+		rootzReverse[i] = rootz[len(rootz)-1-i]
+	}
+	return &FFTSettings{
+		MaxWidth:             width,
+		RootOfUnity:          root,
+		ExpandedRootsOfUnity: rootz,
+		ReverseRootsOfUnity:  rootzReverse,
+	}, nil
+}
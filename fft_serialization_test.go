@@ -0,0 +1,51 @@
+package kzg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestFFTSettingsSerialization(t *testing.T) {
+	for _, scale := range []uint8{0, 4, 10} {
+		fs := NewFFTSettings(scale)
+		var buf bytes.Buffer
+		n, err := fs.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(buf.Len()) {
+			t.Fatalf("reported %d bytes, wrote %d", n, buf.Len())
+		}
+		data := buf.Bytes()
+		read, err := ReadFFTSettings(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read.MaxWidth != fs.MaxWidth || !bls.EqualFr(read.RootOfUnity, fs.RootOfUnity) {
+			t.Fatal("settings mismatch")
+		}
+		for i := range fs.ExpandedRootsOfUnity {
+			if !bls.EqualFr(&read.ExpandedRootsOfUnity[i], &fs.ExpandedRootsOfUnity[i]) ||
+				!bls.EqualFr(&read.ReverseRootsOfUnity[i], &fs.ReverseRootsOfUnity[i]) {
+				t.Fatalf("roots of unity mismatch at %d", i)
+			}
+		}
+		if _, err := ReadFFTSettings(bytes.NewReader(data[:len(data)-1])); err == nil {
+			t.Fatal("expected truncated settings to be rejected")
+		}
+		if scale > 0 {
+			// the roots of another domain
+			corrupt := append([]byte{}, data...)
+			other := bls.FrTo32(&bls.Scale2RootOfUnity[scale+1])
+			copy(corrupt[16+32:], other[:])
+			if _, err := ReadFFTSettings(bytes.NewReader(corrupt)); err == nil {
+				t.Fatal("expected roots of another domain to be rejected")
+			}
+		}
+	}
+	if _, err := ReadFFTSettings(bytes.NewReader(make([]byte, 16))); err == nil {
+		t.Fatal("expected missing magic to be rejected")
+	}
+}
@@ -0,0 +1,47 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func benchFK20Multi(b *testing.B, chunkLen uint64, chunkCount uint64, streaming bool) {
+	n := chunkLen * chunkCount
+	fs := NewFFTSettings(uint8(bitIndex(uint32(n))) + 1)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", n*2)
+	ks := NewKZGSettings(fs, s1, s2)
+	newSettings := NewFK20MultiSettings
+	if streaming {
+		newSettings = NewFK20MultiSettingsStreaming
+	}
+	fk := newSettings(ks, n*2, chunkLen)
+	polynomial := make([]bls.Fr, n, n)
+	for i := range polynomial {
+		bls.CopyFr(&polynomial[i], bls.RandomFr())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fk.DAUsingFK20Multi(polynomial)
+	}
+}
+
+func BenchmarkFK20Multi(b *testing.B) {
+	for _, chunkLen := range []uint64{1, 16, 64} {
+		b.Run(fmt.Sprintf("chunk_%d", chunkLen), func(b *testing.B) {
+			benchFK20Multi(b, chunkLen, 64, false)
+		})
+	}
+}
+
+func BenchmarkFK20MultiStreaming(b *testing.B) {
+	for _, chunkLen := range []uint64{1, 16, 64} {
+		b.Run(fmt.Sprintf("chunk_%d", chunkLen), func(b *testing.B) {
+			benchFK20Multi(b, chunkLen, 64, true)
+		})
+	}
+}
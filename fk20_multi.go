@@ -1,7 +1,7 @@
 // Original: https://github.com/ethereum/research/blob/master/kzg_data_availability/fk20_multi.py
 
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package kzg
 
@@ -23,6 +23,7 @@ import (
 // 	   proof[i]: w^(i*l + 0), w^(i*l + 1), ... w^(i*l + l - 1)
 // 	   ...
 func (ks *FK20MultiSettings) FK20Multi(polynomial []bls.Fr) []bls.G1Point {
+	defer bls.StartTraceOperation("kzg fk20").End()
 	n := uint64(len(polynomial))
 	n2 := n * 2
 	if ks.MaxWidth < n2 {
@@ -30,21 +31,11 @@ func (ks *FK20MultiSettings) FK20Multi(polynomial []bls.Fr) []bls.G1Point {
 			ks.MaxWidth, n))
 	}
 
-	hExtFFT := make([]bls.G1Point, n2, n2)
-	for i := uint64(0); i < n2; i++ {
-		bls.CopyG1(&hExtFFT[i], &bls.ZeroG1)
-	}
-
-	var tmp bls.G1Point
+	toeplitzCoeffs := make([][]bls.Fr, ks.chunkLen, ks.chunkLen)
 	for i := uint64(0); i < ks.chunkLen; i++ {
-		toeplitzCoeffs := ks.toeplitzCoeffsStepStrided(polynomial, i, ks.chunkLen)
-		hExtFFTFile := ks.ToeplitzPart2(toeplitzCoeffs, ks.xExtFFTFiles[i])
-		for j := uint64(0); j < n2; j++ {
-			bls.AddG1(&tmp, &hExtFFT[j], &hExtFFTFile[j])
-			bls.CopyG1(&hExtFFT[j], &tmp)
-		}
+		toeplitzCoeffs[i] = ks.toeplitzCoeffsStepStrided(polynomial, i, ks.chunkLen)
 	}
-	h := ks.ToeplitzPart3(hExtFFT)
+	h := ks.toeplitzProducts(toeplitzCoeffs)
 
 	out, err := ks.FFTG1(h, false)
 	if err != nil {
@@ -56,6 +47,7 @@ func (ks *FK20MultiSettings) FK20Multi(polynomial []bls.Fr) []bls.G1Point {
 // FK20 multi-proof method, optimized for dava availability where the top half of polynomial
 // coefficients == 0
 func (ks *FK20MultiSettings) FK20MultiDAOptimized(polynomial []bls.Fr) []bls.G1Point {
+	defer bls.StartTraceOperation("kzg fk20").End()
 	n2 := uint64(len(polynomial))
 	if ks.MaxWidth < n2 {
 		panic(fmt.Errorf("KZGSettings are set to MaxWidth %d but got polynomial of length %d",
@@ -70,27 +62,14 @@ func (ks *FK20MultiSettings) FK20MultiDAOptimized(polynomial []bls.Fr) []bls.G1P
 
 	k := n / ks.chunkLen
 	k2 := k * 2
-	hExtFFT := make([]bls.G1Point, k2, k2)
-	for i := uint64(0); i < k2; i++ {
-		bls.CopyG1(&hExtFFT[i], &bls.ZeroG1)
-	}
 
 	reducedPoly := polynomial[:n]
-	var tmp bls.G1Point
+	toeplitzCoeffs := make([][]bls.Fr, ks.chunkLen, ks.chunkLen)
 	for i := uint64(0); i < ks.chunkLen; i++ {
-		toeplitzCoeffs := ks.toeplitzCoeffsStepStrided(reducedPoly, i, ks.chunkLen)
-		//debugFrs(fmt.Sprintf("toeplitz_coefficients %d:", i), toeplitzCoeffs)
-		//DebugG1s(fmt.Sprintf("xext_fft file %d:", i), ks.xExtFFTFiles[i])
-		hExtFFTFile := ks.ToeplitzPart2(toeplitzCoeffs, ks.xExtFFTFiles[i])
-		//DebugG1s(fmt.Sprintf("hext_fft file %d:", i), hExtFFTFile)
-		for j := uint64(0); j < k2; j++ {
-			bls.AddG1(&tmp, &hExtFFT[j], &hExtFFTFile[j])
-			bls.CopyG1(&hExtFFT[j], &tmp)
-		}
-		//DebugG1s(fmt.Sprintf("hext_fft %d:", i), hExtFFT)
+		toeplitzCoeffs[i] = ks.toeplitzCoeffsStepStrided(reducedPoly, i, ks.chunkLen)
+		//debugFrs(fmt.Sprintf("toeplitz_coefficients %d:", i), toeplitzCoeffs[i])
 	}
-	//DebugG1s("hext_fft final", hExtFFT)
-	h := ks.ToeplitzPart3(hExtFFT)
+	h := ks.toeplitzProducts(toeplitzCoeffs)
 	//DebugG1s("h", h)
 
 	// TODO: maybe use a G1 version of the DAS extension FFT to perform the h -> output conversion?
@@ -101,11 +80,49 @@ func (ks *FK20MultiSettings) FK20MultiDAOptimized(polynomial []bls.Fr) []bls.G1P
 	for i := k; i < k2; i++ {
 		bls.CopyG1(&h[i], &bls.ZeroG1)
 	}
-	out, err := ks.FFTG1(h, false)
-	if err != nil {
+	if err := ks.FFTG1InPlace(h, false); err != nil {
 		panic(err)
 	}
-	return out
+	return h
+}
+
+// toeplitzProducts computes the sum of the Toeplitz products of the chunks, with the precomputed files,
+// or for streaming settings with files recomputed one at a time.
+func (ks *FK20MultiSettings) toeplitzProducts(toeplitzCoeffs [][]bls.Fr) []bls.G1Point {
+	if ks.xExtFFTFiles != nil {
+		return ks.toeplitzProductSum(toeplitzCoeffs, ks.xExtFFTFiles)
+	}
+	n2 := uint64(len(toeplitzCoeffs[0]))
+	if n2 != ks.n2/ks.chunkLen {
+		panic("expected toeplitz coeffs to match xExtFFT length")
+	}
+	var invLen bls.Fr
+	bls.AsFr(&invLen, n2)
+	bls.InvModFr(&invLen, &invLen)
+	workers := fftG1Workers(n2)
+	hExtFFT := make([]bls.G1Point, n2, n2)
+	for j := range hExtFFT {
+		bls.CopyG1(&hExtFFT[j], &bls.ZeroG1)
+	}
+	file := make([]bls.G1Point, n2, n2)
+	for i, coeffs := range toeplitzCoeffs {
+		ks.xExtFFTFile(file, uint64(i))
+		if err := ks.FFTInPlace(coeffs, false); err != nil {
+			panic(fmt.Errorf("FFT failed in toeplitz part 2: %v", err))
+		}
+		fftSplit(n2, workers, func(lo, hi uint64) {
+			var f bls.Fr
+			var tmp bls.G1Point
+			for j := lo; j < hi; j++ {
+				bls.MulModFr(&f, &coeffs[j], &invLen)
+				bls.MulG1(&tmp, &file[j], &f)
+				bls.AddG1(&hExtFFT[j], &hExtFFT[j], &tmp)
+			}
+		})
+	}
+	ks.fftG1Layers(hExtFFT, true, workers)
+	// Only the top half is the Toeplitz product, the rest is padding
+	return hExtFFT[:n2/2]
 }
 
 // Computes all the KZG proofs for data availability checks. This involves sampling on the double domain
@@ -1,5 +1,5 @@
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package kzg
 
@@ -89,3 +89,25 @@ func TestKZGSettings_DAUsingFK20Multi(t *testing.T) {
 		t.Logf("Data availability check %d passed", pos)
 	}
 }
+
+func TestKZGSettings_FK20MultiStreaming(t *testing.T) {
+	fs := NewFFTSettings(4 + 5 + 1)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 1<<10)
+	ks := NewKZGSettings(fs, s1, s2)
+	for _, chunkLen := range []uint64{1, 4, 16} {
+		n := uint64(1 << 8)
+		fk := NewFK20MultiSettings(ks, n*2, chunkLen)
+		streaming := NewFK20MultiSettingsStreaming(ks, n*2, chunkLen)
+		polynomial := make([]bls.Fr, n, n)
+		for i := range polynomial {
+			bls.AsFr(&polynomial[i], uint64(i*i+7))
+		}
+		expected := fk.DAUsingFK20Multi(polynomial)
+		got := streaming.DAUsingFK20Multi(polynomial)
+		for i := range expected {
+			if !bls.EqualG1(&expected[i], &got[i]) {
+				t.Fatalf("chunk length %d: proof %d differs from the precomputed settings", chunkLen, i)
+			}
+		}
+	}
+}
@@ -1,7 +1,7 @@
 // Original: https://github.com/ethereum/research/blob/master/kzg_data_availability/fk20_single.py
 
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package kzg
 
@@ -86,6 +86,65 @@ func (ks *KZGSettings) ToeplitzPart3(hExtFFT []bls.G1Point) []bls.G1Point {
 	return out[:len(out)/2]
 }
 
+// toeplitzMSMThreshold is the number of chunks from which the points of toeplitzProductSum are computed
+// as a linear combination, rather than with a scalar multiplication per chunk.
+const toeplitzMSMThreshold = 16
+
+// toeplitzProductSum computes the sum of the Toeplitz matrix-vector products of the chunks, as ToeplitzPart2 for
+// every chunk, their sum, and ToeplitzPart3 do, given the transforms of the extended vectors (see toeplitzPart1).
+// The products are accumulated in Fourier space in one pass, every point as a linear combination over the chunks
+// rather than a scalar multiplication and an intermediate vector per chunk, and transformed back once.
+// The 1/n of the inverse transform is folded into the field coefficients, saving a G1 scalar multiplication
+// per point. The Toeplitz coefficients are transformed in place.
+func (ks *KZGSettings) toeplitzProductSum(toeplitzCoeffs [][]bls.Fr, xExtFFTs [][]bls.G1Point) []bls.G1Point {
+	l := uint64(len(toeplitzCoeffs))
+	n2 := uint64(len(toeplitzCoeffs[0]))
+	var invLen bls.Fr
+	bls.AsFr(&invLen, n2)
+	bls.InvModFr(&invLen, &invLen)
+	// the factors of the point j are contiguous, at factors[j*l:(j+1)*l]
+	factors := make([]bls.Fr, n2*l, n2*l)
+	for i, coeffs := range toeplitzCoeffs {
+		if uint64(len(coeffs)) != n2 || uint64(len(xExtFFTs[i])) != n2 {
+			panic("expected toeplitz coeffs to match xExtFFT length")
+		}
+		if err := ks.FFTInPlace(coeffs, false); err != nil {
+			panic(fmt.Errorf("FFT failed in toeplitz part 2: %v", err))
+		}
+		for j := uint64(0); j < n2; j++ {
+			bls.MulModFr(&factors[j*l+uint64(i)], &coeffs[j], &invLen)
+		}
+	}
+	workers := fftG1Workers(n2)
+	hExtFFT := make([]bls.G1Point, n2, n2)
+	fftSplit(n2, workers, func(lo, hi uint64) {
+		var bases []bls.G1Point
+		if l >= toeplitzMSMThreshold {
+			bases = make([]bls.G1Point, l, l)
+		}
+		var tmp bls.G1Point
+		for j := lo; j < hi; j++ {
+			f := factors[j*l : (j+1)*l]
+			if bases != nil {
+				// the bases are copied, as some backends normalize them in place
+				for i := range bases {
+					bls.CopyG1(&bases[i], &xExtFFTs[i][j])
+				}
+				bls.CopyG1(&hExtFFT[j], bls.LinCombG1(bases, f))
+				continue
+			}
+			bls.MulG1(&hExtFFT[j], &xExtFFTs[0][j], &f[0])
+			for i := uint64(1); i < l; i++ {
+				bls.MulG1(&tmp, &xExtFFTs[i][j], &f[i])
+				bls.AddG1(&hExtFFT[j], &hExtFFT[j], &tmp)
+			}
+		}
+	})
+	ks.fftG1Layers(hExtFFT, true, workers)
+	// Only the top half is the Toeplitz product, the rest is padding
+	return hExtFFT[:n2/2]
+}
+
 func (ks *KZGSettings) toeplitzCoeffsStepStrided(polynomial []bls.Fr, offset uint64, stride uint64) []bls.Fr {
 	n := uint64(len(polynomial))
 	k := n / stride
@@ -120,10 +179,10 @@ func (ks *KZGSettings) toeplitzCoeffsStep(polynomial []bls.Fr) []bls.Fr {
 
 // Compute all n (single) proofs according to FK20 method
 func (fk *FK20SingleSettings) FK20Single(polynomial []bls.Fr) []bls.G1Point {
+	defer bls.StartTraceOperation("kzg fk20").End()
 	toeplitzCoeffs := fk.toeplitzCoeffsStep(polynomial)
 	// Compute the vector h from the paper using a Toeplitz matrix multiplication
-	hExtFFT := fk.ToeplitzPart2(toeplitzCoeffs, fk.xExtFFT)
-	h := fk.ToeplitzPart3(hExtFFT)
+	h := fk.toeplitzProductSum([][]bls.Fr{toeplitzCoeffs}, [][]bls.G1Point{fk.xExtFFT})
 
 	// TODO: correct? It will pad up implicitly again, but
 	out, err := fk.FFTG1(h, false)
@@ -137,6 +196,7 @@ func (fk *FK20SingleSettings) FK20Single(polynomial []bls.Fr) []bls.G1Point {
 // The upper half of the polynomial coefficients is always 0, so we do not need to extend to twice the size
 // for Toeplitz matrix multiplication
 func (fk *FK20SingleSettings) FK20SingleDAOptimized(polynomial []bls.Fr) []bls.G1Point {
+	defer bls.StartTraceOperation("kzg fk20").End()
 	if uint64(len(polynomial)) > fk.MaxWidth {
 		panic(fmt.Errorf(
 			"expected input of length %d (incl half of zeroes) to not exceed precomputed settings length %d",
@@ -155,8 +215,7 @@ func (fk *FK20SingleSettings) FK20SingleDAOptimized(polynomial []bls.Fr) []bls.G
 	reducedPoly := polynomial[:n]
 	toeplitzCoeffs := fk.toeplitzCoeffsStep(reducedPoly)
 	// Compute the vector h from the paper using a Toeplitz matrix multiplication
-	hExtFFT := fk.ToeplitzPart2(toeplitzCoeffs, fk.xExtFFT)
-	h := fk.ToeplitzPart3(hExtFFT)
+	h := fk.toeplitzProductSum([][]bls.Fr{toeplitzCoeffs}, [][]bls.G1Point{fk.xExtFFT})
 
 	// Now redo the padding before final step.
 	// Instead of copying h into a new extended array, just reuse the old capacity.
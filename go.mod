@@ -6,6 +6,5 @@ require (
 	github.com/herumi/bls-eth-go-binary v1.28.1
 	github.com/holiman/uint256 v1.2.1
 	github.com/kilic/bls12-381 v0.1.1-0.20220929213557-ca162e8a70f4
+	golang.org/x/sys v0.0.0-20220818161305-2296e01440c6
 )
-
-require golang.org/x/sys v0.0.0-20220818161305-2296e01440c6 // indirect
@@ -1,5 +1,5 @@
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package kzg
 
@@ -66,11 +66,34 @@ func NewFK20SingleSettings(ks *KZGSettings, n2 uint64) *FK20SingleSettings {
 type FK20MultiSettings struct {
 	*KZGSettings
 	chunkLen uint64
-	// chunkLen files, each of size MaxWidth
+	// the extended size the settings were made for
+	n2 uint64
+	// chunkLen files, each of size MaxWidth, or nil for streaming settings
 	xExtFFTFiles [][]bls.G1Point
 }
 
 func NewFK20MultiSettings(ks *KZGSettings, n2 uint64, chunkLen uint64) *FK20MultiSettings {
+	fk := newFK20MultiSettings(ks, n2, chunkLen)
+	fk.xExtFFTFiles = make([][]bls.G1Point, chunkLen, chunkLen)
+	// Every file is transformed in the memory it is kept in, so the precomputation
+	// needs no more than the files themselves.
+	k2 := n2 / chunkLen
+	for i := uint64(0); i < chunkLen; i++ {
+		fk.xExtFFTFiles[i] = make([]bls.G1Point, k2, k2)
+		fk.xExtFFTFile(fk.xExtFFTFiles[i], i)
+	}
+	return fk
+}
+
+// NewFK20MultiSettingsStreaming creates FK20 multi settings that keep no precomputed files: every proving
+// recomputes them one at a time, into a single buffer. This bounds the G1 memory of proving to a few arrays of
+// n2/chunkLen points, instead of the n2 points of the files of NewFK20MultiSettings, which is what large domains
+// need on modest hardware, at the cost of a G1 FFT per chunk per proving.
+func NewFK20MultiSettingsStreaming(ks *KZGSettings, n2 uint64, chunkLen uint64) *FK20MultiSettings {
+	return newFK20MultiSettings(ks, n2, chunkLen)
+}
+
+func newFK20MultiSettings(ks *KZGSettings, n2 uint64, chunkLen uint64) *FK20MultiSettings {
 	if n2 > ks.MaxWidth {
 		panic("extended size is larger than kzg settings supports")
 	}
@@ -89,28 +112,28 @@ func NewFK20MultiSettings(ks *KZGSettings, n2 uint64, chunkLen uint64) *FK20Mult
 	if chunkLen < 1 {
 		panic("chunk length is too small")
 	}
-	fk := &FK20MultiSettings{
-		KZGSettings:  ks,
-		chunkLen:     chunkLen,
-		xExtFFTFiles: make([][]bls.G1Point, chunkLen, chunkLen),
+	return &FK20MultiSettings{
+		KZGSettings: ks,
+		chunkLen:    chunkLen,
+		n2:          n2,
 	}
+}
+
+// xExtFFTFile computes the file of the given offset into out, of n2/chunkLen points.
+func (fk *FK20MultiSettings) xExtFFTFile(out []bls.G1Point, offset uint64) {
 	// xext_fft = []
 	// for i in range(l):
 	//   x = setup[0][n - l - 1 - i::-l] + [b.Z1]
 	//   xext_fft.append(toeplitz_part1(x))
-	n := n2 / 2
-	k := n / chunkLen
-	xExtFFTPrecompute := func(offset uint64) []bls.G1Point {
-		x := make([]bls.G1Point, k, k)
-		start := n - chunkLen - 1 - offset
-		for i, j := uint64(0), start; i+1 < k; i, j = i+1, j-chunkLen {
-			bls.CopyG1(&x[i], &ks.SecretG1[j])
-		}
-		bls.CopyG1(&x[k-1], &bls.ZeroG1)
-		return ks.toeplitzPart1(x)
+	n := fk.n2 / 2
+	k := n / fk.chunkLen
+	start := n - fk.chunkLen - 1 - offset
+	for i, j := uint64(0), start; i+1 < k; i, j = i+1, j-fk.chunkLen {
+		bls.CopyG1(&out[i], &fk.SecretG1[j])
 	}
-	for i := uint64(0); i < chunkLen; i++ {
-		fk.xExtFFTFiles[i] = xExtFFTPrecompute(i)
+	// the last element of x, and the extension of toeplitz_part1, are zero
+	for i := k - 1; i < 2*k; i++ {
+		bls.CopyG1(&out[i], &bls.ZeroG1)
 	}
-	return fk
+	fk.fftG1InPlace(out, false)
 }
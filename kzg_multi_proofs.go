@@ -1,7 +1,7 @@
 // Original: https://github.com/ethereum/research/blob/master/kzg_data_availability/kzg_proofs.py
 
-//go:build !bignum_pure && !bignum_hol256
-// +build !bignum_pure,!bignum_hol256
+//go:build !bignum_hol256
+// +build !bignum_hol256
 
 package kzg
 
@@ -0,0 +1,26 @@
+package kzg
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Maximum number of goroutines of the parallel transforms, GOMAXPROCS when zero. Accessed atomically.
+var maxWorkers int32
+
+// SetMaxWorkers caps the number of goroutines of the parallel transforms (FFTParallel, FFTInPlaceParallel,
+// and the G1 transforms of FK20). A value of zero or less restores the default, GOMAXPROCS.
+func SetMaxWorkers(n int) {
+	if n < 0 {
+		n = 0
+	}
+	atomic.StoreInt32(&maxWorkers, int32(n))
+}
+
+// MaxWorkers returns the number of goroutines the parallel transforms use, see SetMaxWorkers.
+func MaxWorkers() int {
+	if n := atomic.LoadInt32(&maxWorkers); n > 0 {
+		return int(n)
+	}
+	return runtime.GOMAXPROCS(0)
+}
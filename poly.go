@@ -1,6 +1,10 @@
 package kzg
 
-import "github.com/protolambda/go-kzg/bls"
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
 
 // invert the divisor, then multiply
 func polyFactorDiv(dst *bls.Fr, a *bls.Fr, b *bls.Fr) {
@@ -38,3 +42,56 @@ func polyLongDiv(dividend []bls.Fr, divisor []bls.Fr) []bls.Fr {
 	}
 	return out
 }
+
+// VanishingPolynomial returns the coefficients of the polynomial with the given roots, prod(X - r_i),
+// len(roots)+1 of them. The product is computed directly, see ZeroPolynomial for large domains.
+func VanishingPolynomial(roots []bls.Fr) []bls.Fr {
+	out := make([]bls.Fr, len(roots)+1, len(roots)+1)
+	bls.CopyFr(&out[0], &bls.ONE)
+	var tmp bls.Fr
+	for i := range roots {
+		// multiply the degree i polynomial by (X - r_i), from the top coefficient down
+		bls.CopyFr(&out[i+1], &out[i])
+		for j := i; j > 0; j-- {
+			bls.MulModFr(&tmp, &out[j], &roots[i])
+			bls.SubModFr(&out[j], &out[j-1], &tmp)
+		}
+		bls.MulModFr(&tmp, &out[0], &roots[i])
+		bls.SubModFr(&out[0], &bls.ZERO, &tmp)
+	}
+	return out
+}
+
+// InterpolatePolynomial returns the coefficients of the polynomial of degree < len(points) through the points
+// and values, I(x_k) = y_k, by Lagrange interpolation. The points must be distinct.
+func InterpolatePolynomial(points []bls.Fr, values []bls.Fr) ([]bls.Fr, error) {
+	if len(points) != len(values) {
+		return nil, fmt.Errorf("%d points and %d values", len(points), len(values))
+	}
+	vanishing := VanishingPolynomial(points)
+	out := make([]bls.Fr, len(points), len(points))
+	for i := range out {
+		bls.CopyFr(&out[i], &bls.ZERO)
+	}
+	quotient := make([]bls.Fr, len(points), len(points))
+	var denominator, factor, tmp bls.Fr
+	for k := range points {
+		// vanishing / (X - x_k), by synthetic division from the top coefficient down
+		bls.CopyFr(&quotient[len(points)-1], &vanishing[len(points)])
+		for j := len(points) - 1; j > 0; j-- {
+			bls.MulModFr(&tmp, &quotient[j], &points[k])
+			bls.AddModFr(&quotient[j-1], &vanishing[j], &tmp)
+		}
+		// prod_{j != k} (x_k - x_j), which is zero only if x_k is a root of the quotient, i.e. a duplicate point
+		bls.EvalPolyAt(&denominator, quotient, &points[k])
+		if bls.EqualZero(&denominator) {
+			return nil, fmt.Errorf("duplicate evaluation point %d", k)
+		}
+		bls.DivModFr(&factor, &values[k], &denominator)
+		for j := range out {
+			bls.MulModFr(&tmp, &quotient[j], &factor)
+			bls.AddModFr(&out[j], &out[j], &tmp)
+		}
+	}
+	return out, nil
+}
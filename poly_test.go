@@ -0,0 +1,39 @@
+package kzg
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestInterpolatePolynomial(t *testing.T) {
+	points := make([]bls.Fr, 5, 5)
+	values := make([]bls.Fr, 5, 5)
+	for i := range points {
+		bls.CopyFr(&points[i], bls.RandomFr())
+		bls.CopyFr(&values[i], bls.RandomFr())
+	}
+	interpolation, err := InterpolatePolynomial(points, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vanishing := VanishingPolynomial(points)
+	if len(interpolation) != len(points) || len(vanishing) != len(points)+1 {
+		t.Fatal("unexpected polynomial sizes")
+	}
+	var y bls.Fr
+	for i := range points {
+		bls.EvalPolyAt(&y, interpolation, &points[i])
+		if !bls.EqualFr(&y, &values[i]) {
+			t.Fatalf("interpolation does not go through point %d", i)
+		}
+		bls.EvalPolyAt(&y, vanishing, &points[i])
+		if !bls.EqualZero(&y) {
+			t.Fatalf("vanishing polynomial is not zero at point %d", i)
+		}
+	}
+	bls.CopyFr(&points[3], &points[1])
+	if _, err := InterpolatePolynomial(points, values); err == nil {
+		t.Fatal("expected duplicate points to be rejected")
+	}
+}
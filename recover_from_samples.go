@@ -40,8 +40,6 @@ func (fs *FFTSettings) UnshiftPoly(poly []bls.Fr) {
 }
 
 func (fs *FFTSettings) RecoverPolyFromSamples(samples []*bls.Fr, zeroPolyFn ZeroPolyFn) ([]bls.Fr, error) {
-	// TODO: using a single additional temporary array, all the FFTs can run in-place.
-
 	missingIndices := make([]uint64, 0, len(samples))
 	for i, s := range samples {
 		if s == nil {
@@ -57,6 +55,7 @@ func (fs *FFTSettings) RecoverPolyFromSamples(samples []*bls.Fr, zeroPolyFn Zero
 		}
 	}
 
+	// all but the transform of the zero poly, which may be shorter than the samples, run in-place in this array
 	polyEvaluationsWithZero := make([]bls.Fr, len(samples), len(samples))
 	for i, s := range samples {
 		if s == nil {
@@ -65,41 +64,40 @@ func (fs *FFTSettings) RecoverPolyFromSamples(samples []*bls.Fr, zeroPolyFn Zero
 			bls.MulModFr(&polyEvaluationsWithZero[i], s, &zeroEval[i])
 		}
 	}
-	polyWithZero, err := fs.FFT(polyEvaluationsWithZero, true)
-	if err != nil {
+	if err := fs.FFTInPlace(polyEvaluationsWithZero, true); err != nil {
 		return nil, err
 	}
-	// shift in-place
-	fs.ShiftPoly(polyWithZero)
-	shiftedPolyWithZero := polyWithZero
-
-	fs.ShiftPoly(zeroPoly)
-	shiftedZeroPoly := zeroPoly
+	polyWithZero := polyEvaluationsWithZero
 
-	evalShiftedPolyWithZero, err := fs.FFT(shiftedPolyWithZero, false)
-	if err != nil {
+	// the division by the zero poly is done over the coset H/5 of the domain, where it does not vanish:
+	// this is ShiftPoly, and UnshiftPoly for the way back
+	var cosetShift bls.Fr
+	bls.AsFr(&cosetShift, 5)
+	bls.InvModFr(&cosetShift, &cosetShift)
+	if err := fs.CosetFFTInPlace(polyWithZero, &cosetShift, false); err != nil {
 		return nil, err
 	}
-	evalShiftedZeroPoly, err := fs.FFT(shiftedZeroPoly, false)
+	evalShiftedPolyWithZero := polyWithZero
+	evalShiftedZeroPoly, err := fs.CosetFFT(zeroPoly, &cosetShift, false)
 	if err != nil {
 		return nil, err
 	}
 
+	// one batch inversion instead of an inversion per division
+	bls.BatchInvModFr(evalShiftedZeroPoly, evalShiftedZeroPoly)
 	evalShiftedReconstructedPoly := evalShiftedPolyWithZero
 	for i := 0; i < len(evalShiftedReconstructedPoly); i++ {
-		bls.DivModFr(&evalShiftedReconstructedPoly[i], &evalShiftedPolyWithZero[i], &evalShiftedZeroPoly[i])
+		bls.MulModFr(&evalShiftedReconstructedPoly[i], &evalShiftedPolyWithZero[i], &evalShiftedZeroPoly[i])
 	}
-	shiftedReconstructedPoly, err := fs.FFT(evalShiftedReconstructedPoly, true)
-	if err != nil {
+	if err := fs.CosetFFTInPlace(evalShiftedReconstructedPoly, &cosetShift, true); err != nil {
 		return nil, err
 	}
-	fs.UnshiftPoly(shiftedReconstructedPoly)
-	reconstructedPoly := shiftedReconstructedPoly
+	reconstructedPoly := evalShiftedReconstructedPoly
 
-	reconstructedData, err := fs.FFT(reconstructedPoly, false)
-	if err != nil {
+	if err := fs.FFTInPlace(reconstructedPoly, false); err != nil {
 		return nil, err
 	}
+	reconstructedData := reconstructedPoly
 	for i, s := range samples {
 		if s != nil && !bls.EqualFr(&reconstructedData[i], s) {
 			return nil, fmt.Errorf("failed to reconstruct data correctly, changed value at index %d. Expected: %s, got: %s", i, bls.FrStr(s), bls.FrStr(&reconstructedData[i]))
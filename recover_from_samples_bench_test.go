@@ -31,7 +31,7 @@ func benchRecoverPolyFromSamples(scale uint8, seed int64, b *testing.B) {
 	b.ResetTimer()
 
 	for bi := 0; bi < b.N; bi++ {
-		recovered, err := fs.RecoverPolyFromSamples(samples, fs.ZeroPolyViaMultiplication)
+		recovered, err := fs.RecoverPolyFromSamples(samples, fs.ZeroPolynomial)
 		if err != nil {
 			b.Fatal(err)
 		}
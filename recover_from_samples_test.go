@@ -104,7 +104,7 @@ func TestFFTSettings_RecoverPolyFromSamples(t *testing.T) {
 				subset := randomSubset(known, uint64(i))
 
 				debugFrPtrs("subset", subset)
-				recovered, err := fs.RecoverPolyFromSamples(subset, fs.ZeroPolyViaMultiplication)
+				recovered, err := fs.RecoverPolyFromSamples(subset, fs.ZeroPolynomial)
 				if err != nil {
 					t.Fatal(err)
 				}
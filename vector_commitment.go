@@ -0,0 +1,175 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// VectorCommitment commits to vectors of field elements, and opens them at single or multiple positions.
+// A vector of length l is padded with zeros to the next power of two m, and committed to as the polynomial
+// of degree < m that evaluates to the element i at w**i, for the primitive m-th root of unity w.
+// Vectors of lengths padded to the same m are not told apart by their commitment: the verifier must know
+// the length of the vector, which determines the points of its elements.
+type VectorCommitment struct {
+	ks *KZGSettings
+}
+
+// NewVectorCommitment creates a vector commitment scheme over the KZG settings. Vectors may be as long
+// as both the FFT settings and the G1 setup allow, see MaxLength, and multi-openings may open as many positions
+// as the G2 setup has powers, minus one.
+func NewVectorCommitment(ks *KZGSettings) *VectorCommitment {
+	return &VectorCommitment{ks: ks}
+}
+
+// MaxLength returns the length of the longest vector that can be committed to.
+func (vc *VectorCommitment) MaxLength() uint64 {
+	maxLength := vc.ks.MaxWidth
+	for maxLength > uint64(len(vc.ks.SecretG1)) {
+		maxLength /= 2
+	}
+	return maxLength
+}
+
+// domainSize returns the size of the domain of the vectors of the given length, checking that it is supported.
+func (vc *VectorCommitment) domainSize(length uint64) (uint64, error) {
+	if length == 0 {
+		return 0, fmt.Errorf("empty vector")
+	}
+	m := nextPowOf2(length)
+	if m > vc.MaxLength() {
+		return 0, fmt.Errorf("vector length %d exceeds the maximum length %d", length, vc.MaxLength())
+	}
+	return m, nil
+}
+
+// point returns the evaluation point of the element of the given index, in the domain of the given size.
+func (vc *VectorCommitment) point(index uint64, m uint64) *bls.Fr {
+	return &vc.ks.ExpandedRootsOfUnity[index*(vc.ks.MaxWidth/m)]
+}
+
+// coefficients returns the coefficients of the polynomial of the vector.
+func (vc *VectorCommitment) coefficients(values []bls.Fr) ([]bls.Fr, error) {
+	m, err := vc.domainSize(uint64(len(values)))
+	if err != nil {
+		return nil, err
+	}
+	evals := make([]bls.Fr, m, m)
+	for i := range values {
+		bls.CopyFr(&evals[i], &values[i])
+	}
+	return vc.ks.FFT(evals, true)
+}
+
+// Commit commits to the vector.
+func (vc *VectorCommitment) Commit(values []bls.Fr) (*bls.G1Point, error) {
+	coeffs, err := vc.coefficients(values)
+	if err != nil {
+		return nil, err
+	}
+	return vc.ks.CommitToPoly(coeffs), nil
+}
+
+// Open computes the proof of the element of the given index of the vector.
+func (vc *VectorCommitment) Open(values []bls.Fr, index uint64) (*bls.G1Point, error) {
+	return vc.OpenMulti(values, []uint64{index})
+}
+
+// Verify verifies the proof that the element of the given index of the vector of the given length
+// committed to is value.
+func (vc *VectorCommitment) Verify(commitment *bls.G1Point, length uint64, index uint64, value *bls.Fr, proof *bls.G1Point) (bool, error) {
+	m, err := vc.domainSize(length)
+	if err != nil {
+		return false, err
+	}
+	if index >= length {
+		return false, fmt.Errorf("index %d is out of range for a vector of length %d", index, length)
+	}
+	return vc.ks.CheckProofSingle(commitment, proof, vc.point(index, m), value), nil
+}
+
+// openingPoints checks the indices of an opening, and returns their evaluation points.
+func (vc *VectorCommitment) openingPoints(length uint64, m uint64, indices []uint64) ([]bls.Fr, error) {
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("no indices to open")
+	}
+	if len(indices) >= len(vc.ks.SecretG2) {
+		return nil, fmt.Errorf("cannot open %d indices with %d G2 setup powers", len(indices), len(vc.ks.SecretG2))
+	}
+	seen := make(map[uint64]struct{}, len(indices))
+	points := make([]bls.Fr, len(indices), len(indices))
+	for i, index := range indices {
+		if index >= length {
+			return nil, fmt.Errorf("index %d is out of range for a vector of length %d", index, length)
+		}
+		if _, ok := seen[index]; ok {
+			return nil, fmt.Errorf("duplicate index %d", index)
+		}
+		seen[index] = struct{}{}
+		bls.CopyFr(&points[i], vc.point(index, m))
+	}
+	return points, nil
+}
+
+// OpenMulti computes a single proof of the elements of the given indices of the vector.
+func (vc *VectorCommitment) OpenMulti(values []bls.Fr, indices []uint64) (*bls.G1Point, error) {
+	coeffs, err := vc.coefficients(values)
+	if err != nil {
+		return nil, err
+	}
+	points, err := vc.openingPoints(uint64(len(values)), uint64(len(coeffs)), indices)
+	if err != nil {
+		return nil, err
+	}
+	ys := make([]bls.Fr, len(indices), len(indices))
+	for i, index := range indices {
+		bls.CopyFr(&ys[i], &values[index])
+	}
+	// the quotient (p - I) / Z, with I the interpolation of the opened values and Z the vanishing polynomial
+	vanishing := vc.ks.vanishingProduct(points)
+	interpolation, err := InterpolatePolynomial(points, ys)
+	if err != nil {
+		return nil, err
+	}
+	for i := range interpolation {
+		bls.SubModFr(&coeffs[i], &coeffs[i], &interpolation[i])
+	}
+	if len(coeffs) < len(vanishing) {
+		// opening all the positions of the domain: p - I is zero. The identity is built with ClearG1,
+		// the form the pairing of the Herumi backend expects, which ZeroG1 is not.
+		var out bls.G1Point
+		bls.ClearG1(&out)
+		return &out, nil
+	}
+	quotient := polyLongDiv(coeffs, vanishing)
+	return vc.ks.CommitToPoly(quotient), nil
+}
+
+// VerifyMulti verifies the proof that the elements of the given indices of the vector of the given length
+// committed to are the values, checking e(commitment - [I(s)]_1, [1]_2) == e(proof, [Z(s)]_2), with I the
+// interpolation of the values and Z the vanishing polynomial of the points of the indices.
+func (vc *VectorCommitment) VerifyMulti(commitment *bls.G1Point, length uint64, indices []uint64, values []bls.Fr, proof *bls.G1Point) (bool, error) {
+	m, err := vc.domainSize(length)
+	if err != nil {
+		return false, err
+	}
+	if len(values) != len(indices) {
+		return false, fmt.Errorf("got %d values for %d indices", len(values), len(indices))
+	}
+	points, err := vc.openingPoints(length, m, indices)
+	if err != nil {
+		return false, err
+	}
+	vanishing := vc.ks.vanishingProduct(points)
+	interpolation, err := InterpolatePolynomial(points, values)
+	if err != nil {
+		return false, err
+	}
+	var commitmentMinusI bls.G1Point
+	bls.SubG1(&commitmentMinusI, commitment, vc.ks.CommitToPoly(interpolation))
+	vanishingG2 := bls.LinCombG2(vc.ks.SecretG2[:len(vanishing)], vanishing)
+	return bls.PairingsVerify(&commitmentMinusI, &bls.GenG2, proof, vanishingG2), nil
+}
@@ -0,0 +1,77 @@
+//go:build !bignum_hol256
+// +build !bignum_hol256
+
+package kzg
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestVectorCommitment(t *testing.T) {
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	vc := NewVectorCommitment(NewKZGSettings(NewFFTSettings(4), s1, s2))
+	if vc.MaxLength() != 16 {
+		t.Fatalf("expected max length 16, got %d", vc.MaxLength())
+	}
+	for _, length := range []int{1, 5, 8, 16} {
+		values := make([]bls.Fr, length)
+		for i := range values {
+			bls.CopyFr(&values[i], bls.RandomFr())
+		}
+		commitment, err := vc.Commit(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range values {
+			proof, err := vc.Open(values, uint64(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, err := vc.Verify(commitment, uint64(length), uint64(i), &values[i], proof); err != nil || !ok {
+				t.Fatalf("length %d: opening %d does not verify: %v", length, i, err)
+			}
+			if ok, _ := vc.Verify(commitment, uint64(length), uint64(i), bls.RandomFr(), proof); ok {
+				t.Fatalf("length %d: opening %d verifies a wrong value", length, i)
+			}
+		}
+
+		indices := []uint64{uint64(length - 1)}
+		if length > 2 {
+			indices = append(indices, 0, 2)
+		}
+		if length == 16 {
+			indices = indices[:0]
+			for i := 0; i < 16; i++ {
+				indices = append(indices, uint64(i))
+			}
+		}
+		ys := make([]bls.Fr, len(indices))
+		for i, index := range indices {
+			bls.CopyFr(&ys[i], &values[index])
+		}
+		proof, err := vc.OpenMulti(values, indices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := vc.VerifyMulti(commitment, uint64(length), indices, ys, proof); err != nil || !ok {
+			t.Fatalf("length %d: multi-opening does not verify: %v", length, err)
+		}
+		bls.AddModFr(&ys[0], &ys[0], &bls.ONE)
+		if ok, _ := vc.VerifyMulti(commitment, uint64(length), indices, ys, proof); ok {
+			t.Fatalf("length %d: multi-opening verifies a wrong value", length)
+		}
+	}
+
+	values := make([]bls.Fr, 4)
+	if _, err := vc.Commit(make([]bls.Fr, 17)); err == nil {
+		t.Fatal("expected error for a too long vector")
+	}
+	if _, err := vc.Open(values, 4); err == nil {
+		t.Fatal("expected error for an out of range index")
+	}
+	if _, err := vc.OpenMulti(values, []uint64{1, 1}); err == nil {
+		t.Fatal("expected error for duplicate indices")
+	}
+}
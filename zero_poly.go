@@ -9,6 +9,8 @@ package kzg
 
 import (
 	"fmt"
+	"sync"
+
 	"github.com/protolambda/go-kzg/bls"
 )
 
@@ -215,3 +217,97 @@ func (fs *FFTSettings) ZeroPolyViaMultiplication(missingIndices []uint64, length
 
 	return zeroEval, zeroPoly
 }
+
+// zeroPolyLeafSize is the number of roots up to which the product tree of ZeroPolynomial
+// multiplies the factors directly, instead of multiplying the products of two halves with FFTs.
+const zeroPolyLeafSize = 64
+
+// zeroPolyParallelSize is the number of roots from which the two halves of the product tree are computed concurrently.
+const zeroPolyParallelSize = 1024
+
+// ZeroPolynomial is ZeroPolyViaMultiplication, computed with a balanced product tree: the vanishing polynomials
+// of the two halves of the missing indices are computed recursively, concurrently for large halves,
+// and multiplied with FFTs, down to zeroPolyLeafSize roots which are multiplied directly.
+// This needs fewer and smaller transforms than the reduction of ZeroPolyViaMultiplication.
+// It returns the evaluations over the domain of the given size and the coefficients, padded to the domain size.
+// Fewer missing indices than the domain size are expected, as nothing could be recovered from no samples.
+// It can be used as the ZeroPolyFn of RecoverPolyFromSamples.
+func (fs *FFTSettings) ZeroPolynomial(missingIndices []uint64, domainSize uint64) ([]bls.Fr, []bls.Fr) {
+	if domainSize > fs.MaxWidth {
+		panic("domain too small for requested length")
+	}
+	if !bls.IsPowerOfTwo(domainSize) {
+		panic("length not a power of two")
+	}
+	if uint64(len(missingIndices)) >= domainSize {
+		panic(fmt.Sprintf("expected less than %d missing indices, got %d", domainSize, len(missingIndices)))
+	}
+	domainStride := fs.MaxWidth / domainSize
+	roots := make([]bls.Fr, len(missingIndices), len(missingIndices))
+	for i, v := range missingIndices {
+		if v >= domainSize {
+			panic(fmt.Sprintf("missing index %d out of range", v))
+		}
+		bls.CopyFr(&roots[i], &fs.ExpandedRootsOfUnity[v*domainStride])
+	}
+	zeroPoly := make([]bls.Fr, domainSize, domainSize)
+	padPoly(zeroPoly, fs.vanishingProduct(roots))
+	zeroEval := make([]bls.Fr, domainSize, domainSize)
+	if err := fs.InplaceFFT(zeroPoly, zeroEval, false); err != nil {
+		panic(err)
+	}
+	return zeroEval, zeroPoly
+}
+
+// vanishingProduct returns the coefficients of the product of (X - r) over the roots, len(roots)+1 of them.
+func (fs *FFTSettings) vanishingProduct(roots []bls.Fr) []bls.Fr {
+	if len(roots) <= zeroPolyLeafSize {
+		return VanishingPolynomial(roots)
+	}
+	mid := len(roots) / 2
+	var a, b []bls.Fr
+	if len(roots) >= zeroPolyParallelSize {
+		// the halves are independent
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a = fs.vanishingProduct(roots[:mid])
+		}()
+		b = fs.vanishingProduct(roots[mid:])
+		wg.Wait()
+	} else {
+		a = fs.vanishingProduct(roots[:mid])
+		b = fs.vanishingProduct(roots[mid:])
+	}
+	// The product is computed modulo X^n - 1, with n the power of two of at least len(roots):
+	// only when there are exactly n roots does the top coefficient, which is 1, wrap around to the constant term.
+	degree := uint64(len(roots))
+	n := nextPowOf2(degree)
+	x := make([]bls.Fr, n, n+1)
+	y := make([]bls.Fr, n, n)
+	fs.wrapPoly(x, a)
+	fs.wrapPoly(y, b)
+	fs.fftInPlace(x, false)
+	fs.fftInPlace(y, false)
+	for i := range x {
+		bls.MulModFr(&x[i], &x[i], &y[i])
+	}
+	fs.fftInPlace(x, true)
+	if degree == n {
+		bls.SubModFr(&x[0], &x[0], &bls.ONE)
+		return append(x, bls.ONE)
+	}
+	return x[:degree+1]
+}
+
+// wrapPoly reduces the polynomial modulo X^len(out) - 1 into out.
+func (fs *FFTSettings) wrapPoly(out []bls.Fr, poly []bls.Fr) {
+	for i := range out {
+		bls.CopyFr(&out[i], &bls.ZERO)
+	}
+	for i := range poly {
+		j := i % len(out)
+		bls.AddModFr(&out[j], &out[j], &poly[i])
+	}
+}
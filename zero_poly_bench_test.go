@@ -6,8 +6,9 @@ import (
 	"testing"
 )
 
-func benchZeroPoly(scale uint8, seed int64, b *testing.B) {
+func benchZeroPoly(scale uint8, seed int64, b *testing.B, zeroPolyFn func(fs *FFTSettings) ZeroPolyFn) {
 	fs := NewFFTSettings(scale)
+	fn := zeroPolyFn(fs)
 	missing := make([]uint64, fs.MaxWidth, fs.MaxWidth)
 	for i := uint64(0); i < uint64(len(missing)); i++ {
 		missing[i] = i
@@ -21,7 +22,7 @@ func benchZeroPoly(scale uint8, seed int64, b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		zeroEval, zeroPoly := fs.ZeroPolyViaMultiplication(missing, fs.MaxWidth)
+		zeroEval, zeroPoly := fn(missing, fs.MaxWidth)
 		if len(zeroEval) != len(zeroPoly) {
 			panic("sanity check failed, length mismatch")
 		}
@@ -31,7 +32,15 @@ func benchZeroPoly(scale uint8, seed int64, b *testing.B) {
 func BenchmarkFFTSettings_ZeroPolyViaMultiplication(b *testing.B) {
 	for scale := uint8(5); scale < 16; scale++ {
 		b.Run(fmt.Sprintf("scale_%d", scale), func(b *testing.B) {
-			benchZeroPoly(scale, int64(scale), b)
+			benchZeroPoly(scale, int64(scale), b, func(fs *FFTSettings) ZeroPolyFn { return fs.ZeroPolyViaMultiplication })
+		})
+	}
+}
+
+func BenchmarkFFTSettings_ZeroPolynomial(b *testing.B) {
+	for scale := uint8(5); scale < 16; scale++ {
+		b.Run(fmt.Sprintf("scale_%d", scale), func(b *testing.B) {
+			benchZeroPoly(scale, int64(scale), b, func(fs *FFTSettings) ZeroPolyFn { return fs.ZeroPolynomial })
 		})
 	}
 }
@@ -199,6 +199,10 @@ func TestFFTSettings_ZeroPolyViaMultiplication_Python(t *testing.T) {
 
 func testZeroPoly(t *testing.T, scale uint8, seed int64) {
 	fs := NewFFTSettings(scale)
+	testZeroPolyFn(t, fs, fs.ZeroPolyViaMultiplication, seed)
+}
+
+func testZeroPolyFn(t *testing.T, fs *FFTSettings, zeroPolyFn ZeroPolyFn, seed int64) {
 
 	rng := rand.New(rand.NewSource(seed))
 
@@ -215,7 +219,7 @@ func testZeroPoly(t *testing.T, scale uint8, seed int64) {
 	}
 	//t.Logf("missing indices:%s", missingStr)
 
-	zeroEval, zeroPoly := fs.ZeroPolyViaMultiplication(missingIndices, uint64(len(exists)))
+	zeroEval, zeroPoly := zeroPolyFn(missingIndices, uint64(len(exists)))
 
 	//debugFrs("zero eval", zeroEval)
 	//debugFrs("zero poly", zeroPoly)
@@ -259,3 +263,36 @@ func TestFFTSettings_ZeroPolyViaMultiplication_Parametrized(t *testing.T) {
 		})
 	}
 }
+
+func TestFFTSettings_ZeroPolynomial(t *testing.T) {
+	for i := uint8(3); i < 12; i++ {
+		t.Run(fmt.Sprintf("scale_%d", i), func(t *testing.T) {
+			fs := NewFFTSettings(i)
+			for j := int64(0); j < 3; j++ {
+				t.Run(fmt.Sprintf("case_%d", j), func(t *testing.T) {
+					testZeroPolyFn(t, fs, fs.ZeroPolynomial, int64(i)*1000+j)
+				})
+			}
+			// the same polynomial as the reduction of leaves, which is monic as well
+			rng := rand.New(rand.NewSource(int64(i)))
+			var missing []uint64
+			for k := uint64(0); k < fs.MaxWidth; k++ {
+				if rng.Intn(4) != 0 {
+					missing = append(missing, k)
+				}
+			}
+			_, expected := fs.ZeroPolyViaMultiplication(missing, fs.MaxWidth)
+			_, got := fs.ZeroPolynomial(missing, fs.MaxWidth)
+			for k := range got {
+				if !bls.EqualFr(&got[k], &expected[k]) {
+					t.Fatalf("coefficient %d mismatch", k)
+				}
+			}
+		})
+	}
+	fs := NewFFTSettings(4)
+	zeroEval, zeroPoly := fs.ZeroPolynomial(nil, fs.MaxWidth)
+	if !bls.EqualOne(&zeroPoly[0]) || !bls.EqualOne(&zeroEval[3]) {
+		t.Fatal("expected the constant 1 without missing indices")
+	}
+}